@@ -0,0 +1,111 @@
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+	k8sappsv1 "k8s.io/api/apps/v1"
+)
+
+var (
+	registryMirror   string
+	operatorManifest string
+)
+
+// imagesCmd represents the images command
+var imagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "Images of the 3scale release",
+}
+
+// imagesListCmd represents the images list command
+var imagesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the images the 3scale release needs",
+	Long: `Print, one per line, every image the 3scale release needs.
+
+The images are overridden through the same environment variables as the
+operator, like BACKEND_IMAGE. With --operator-manifest they are read from
+the environment of the operator Deployment manifest, like
+deploy/operator.yaml, instead.
+
+With --registry-mirror each line has the image followed by the image
+pulled from the mirror. The output can be used as the mapping file of
+'oc image mirror -f' to populate the mirror of a disconnected cluster.`,
+	Args: cobra.NoArgs,
+	RunE: runImagesListCommand,
+}
+
+func runImagesListCommand(cmd *cobra.Command, args []string) error {
+	if operatorManifest != "" {
+		err := setOperatorManifestEnv(operatorManifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, imageURL := range operator.ReleaseImageURLs() {
+		if registryMirror == "" {
+			fmt.Println(imageURL)
+			continue
+		}
+		fmt.Printf("%s %s\n", imageURL, component.RegistryMirrorImageURL(imageURL, registryMirror))
+	}
+	return nil
+}
+
+// setOperatorManifestEnv sets the environment variables with a value of
+// the containers of the operator Deployment manifest
+func setOperatorManifestEnv(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	deployment := &k8sappsv1.Deployment{}
+	err = yaml.Unmarshal(data, deployment)
+	if err != nil {
+		return fmt.Errorf("%s is not a Deployment manifest: %s", path, err)
+	}
+	if deployment.Kind != "Deployment" {
+		return fmt.Errorf("%s is not a Deployment manifest: kind is '%s'", path, deployment.Kind)
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil {
+				continue
+			}
+			err = os.Setenv(env.Name, env.Value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(imagesCmd)
+	imagesCmd.AddCommand(imagesListCmd)
+
+	imagesListCmd.Flags().StringVar(&registryMirror, "registry-mirror", "", "Registry, and optional repository path, the images are mirrored to")
+	imagesListCmd.Flags().StringVar(&operatorManifest, "operator-manifest", "", "Operator Deployment manifest whose environment overrides the images, like deploy/operator.yaml")
+}
@@ -309,7 +309,7 @@ func (ampImages *AmpImages) DeploymentsServiceAccount() *v1.ServiceAccount {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "amp",
 		},
-		ImagePullSecrets: []v1.LocalObjectReference{
+		ImagePullSecrets: append([]v1.LocalObjectReference{
 			v1.LocalObjectReference{
-				Name: "threescale-registry-auth"}}}
+				Name: "threescale-registry-auth"}}, ampImages.Options.imagePullSecrets...)}
 }
@@ -1,6 +1,10 @@
 package component
 
-import "fmt"
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
 
 type AmpImagesOptions struct {
 	appLabel                    string
@@ -14,6 +18,7 @@ type AmpImagesOptions struct {
 	systemMemcachedImage        string
 	systemMySQLImage            string
 	insecureImportPolicy        bool
+	imagePullSecrets            []v1.LocalObjectReference
 }
 
 type AmpImagesOptionsBuilder struct {
@@ -56,6 +61,10 @@ func (ampImages *AmpImagesOptionsBuilder) InsecureImportPolicy(insecureImportPol
 	ampImages.options.insecureImportPolicy = insecureImportPolicy
 }
 
+func (ampImages *AmpImagesOptionsBuilder) ImagePullSecrets(imagePullSecrets []v1.LocalObjectReference) {
+	ampImages.options.imagePullSecrets = imagePullSecrets
+}
+
 func (ampImages *AmpImagesOptionsBuilder) Build() (*AmpImagesOptions, error) {
 	if ampImages.options.appLabel == "" {
 		return nil, fmt.Errorf("no AppLabel has been provided")
@@ -1,15 +1,52 @@
 package component
 
 import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
 	"github.com/3scale/3scale-operator/pkg/common"
 	"k8s.io/api/policy/v1beta1"
 
 	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+const (
+	// ApicastCustomPoliciesMountBasePath is the APIcast policy load path
+	// custom policies are mounted in, under <name>/<version>
+	ApicastCustomPoliciesMountBasePath = "/opt/app-root/src/policies"
+	// ApicastCustomPolicyDefaultImagePath is the directory of the policy
+	// files in custom policy images when no path is given
+	ApicastCustomPolicyDefaultImagePath = "/policy"
+	// ApicastCustomPoliciesHashAnnotation is the pod template annotation
+	// holding the hash of the custom policies content
+	ApicastCustomPoliciesHashAnnotation = "apps.3scale.net/custom-policies-hash"
+	// ApicastCustomEnvironmentsMountBasePath is the directory custom
+	// environment ConfigMaps are mounted in, under the ConfigMap name
+	ApicastCustomEnvironmentsMountBasePath = "/opt/app-root/src/environments"
+	// ApicastCustomEnvironmentsHashAnnotation is the pod template annotation
+	// holding the hash of the custom environments content
+	ApicastCustomEnvironmentsHashAnnotation = "apps.3scale.net/custom-environments-hash"
+
+	// ApicastHTTPSCertificateMountPath is the directory the HTTPS
+	// certificate secret is mounted in
+	ApicastHTTPSCertificateMountPath = "/var/run/secrets/apicast-https"
+	// ApicastHTTPSCertificateHashAnnotation is the pod template annotation
+	// holding the hash of the HTTPS certificate secret content
+	ApicastHTTPSCertificateHashAnnotation = "apps.3scale.net/https-certificate-hash"
+
+	apicastCustomPolicyInitMountPath     = "/custom-policy"
+	apicastHTTPSVolumeName               = "https-certificate"
+	apicastCustomEnvironmentVolumePrefix = "custom-environment-"
+	apicastCustomPolicyVolumePrefix      = "custom-policy-"
+)
+
 type Apicast struct {
 	Options *ApicastOptions
 }
@@ -59,20 +96,7 @@ func (apicast *Apicast) StagingService() *v1.Service {
 			},
 		},
 		Spec: v1.ServiceSpec{
-			Ports: []v1.ServicePort{
-				v1.ServicePort{
-					Name:       "gateway",
-					Protocol:   v1.ProtocolTCP,
-					Port:       8080,
-					TargetPort: intstr.FromInt(8080),
-				},
-				v1.ServicePort{
-					Name:       "management",
-					Protocol:   v1.ProtocolTCP,
-					Port:       8090,
-					TargetPort: intstr.FromInt(8090),
-				},
-			},
+			Ports:    apicast.stagingGateway().servicePorts(),
 			Selector: map[string]string{"deploymentConfig": "apicast-staging"},
 		},
 	}
@@ -93,20 +117,7 @@ func (apicast *Apicast) ProductionService() *v1.Service {
 			},
 		},
 		Spec: v1.ServiceSpec{
-			Ports: []v1.ServicePort{
-				v1.ServicePort{
-					Name:       "gateway",
-					Protocol:   v1.ProtocolTCP,
-					Port:       8080,
-					TargetPort: intstr.FromInt(8080),
-				},
-				v1.ServicePort{
-					Name:       "management",
-					Protocol:   v1.ProtocolTCP,
-					Port:       8090,
-					TargetPort: intstr.FromInt(8090),
-				},
-			},
+			Ports:    apicast.productionGateway().servicePorts(),
 			Selector: map[string]string{"deploymentConfig": "apicast-production"},
 		},
 	}
@@ -170,35 +181,25 @@ func (apicast *Apicast) StagingDeploymentConfig() *appsv1.DeploymentConfig {
 						"threescale_component":         "apicast",
 						"threescale_component_element": "staging",
 					},
-					Annotations: map[string]string{
-						"prometheus.io/scrape": "true",
-						"prometheus.io/port":   "9421",
-					},
+					Annotations: apicast.stagingGateway().podTemplateAnnotations(),
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       apicast.Options.stagingPodPlacement.NodeSelector,
+					Tolerations:        apicast.Options.stagingPodPlacement.Tolerations,
+					Affinity:           apicast.Options.stagingPodPlacement.Affinity,
+					PriorityClassName:  apicast.Options.stagingPodPlacement.PriorityClassName,
 					ServiceAccountName: "amp",
+					InitContainers:     apicast.stagingGateway().customPolicyInitContainers(),
+					Volumes:            apicast.stagingGateway().volumes(),
 					Containers: []v1.Container{
 						v1.Container{
-							Ports: []v1.ContainerPort{
-								v1.ContainerPort{
-									ContainerPort: 8080,
-									Protocol:      v1.ProtocolTCP,
-								},
-								v1.ContainerPort{
-									ContainerPort: 8090,
-									Protocol:      v1.ProtocolTCP,
-								},
-								v1.ContainerPort{
-									ContainerPort: 9421,
-									Protocol:      v1.ProtocolTCP,
-									Name:          "metrics",
-								},
-							},
+							Ports:           apicast.stagingGateway().containerPorts(),
 							Env:             apicast.buildApicastStagingEnv(),
 							Image:           "amp-apicast:latest",
 							ImagePullPolicy: v1.PullIfNotPresent,
 							Name:            "apicast-staging",
 							Resources:       *apicast.Options.stagingResourceRequirements,
+							VolumeMounts:    apicast.stagingGateway().volumeMounts(),
 							LivenessProbe: &v1.Probe{
 								Handler: v1.Handler{HTTPGet: &v1.HTTPGetAction{
 									Path: "/status/live",
@@ -284,14 +285,15 @@ func (apicast *Apicast) ProductionDeploymentConfig() *appsv1.DeploymentConfig {
 						"threescale_component":         "apicast",
 						"threescale_component_element": "production",
 					},
-					Annotations: map[string]string{
-						"prometheus.io/scrape": "true",
-						"prometheus.io/port":   "9421",
-					},
+					Annotations: apicast.productionGateway().podTemplateAnnotations(),
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       apicast.Options.productionPodPlacement.NodeSelector,
+					Tolerations:        apicast.Options.productionPodPlacement.Tolerations,
+					Affinity:           apicast.Options.productionPodPlacement.Affinity,
+					PriorityClassName:  apicast.Options.productionPodPlacement.PriorityClassName,
 					ServiceAccountName: "amp",
-					InitContainers: []v1.Container{
+					InitContainers: append([]v1.Container{
 						v1.Container{
 							Name:    "system-master-svc",
 							Image:   "amp-apicast:latest",
@@ -303,29 +305,17 @@ func (apicast *Apicast) ProductionDeploymentConfig() *appsv1.DeploymentConfig {
 								},
 							},
 						},
-					},
+					}, apicast.productionGateway().customPolicyInitContainers()...),
+					Volumes: apicast.productionGateway().volumes(),
 					Containers: []v1.Container{
 						v1.Container{
-							Ports: []v1.ContainerPort{
-								v1.ContainerPort{
-									ContainerPort: 8080,
-									Protocol:      v1.ProtocolTCP,
-								},
-								v1.ContainerPort{
-									ContainerPort: 8090,
-									Protocol:      v1.ProtocolTCP,
-								},
-								v1.ContainerPort{
-									ContainerPort: 9421,
-									Protocol:      v1.ProtocolTCP,
-									Name:          "metrics",
-								},
-							},
+							Ports:           apicast.productionGateway().containerPorts(),
 							Env:             apicast.buildApicastProductionEnv(),
 							Image:           "amp-apicast:latest",
 							ImagePullPolicy: v1.PullIfNotPresent,
 							Name:            "apicast-production",
 							Resources:       *apicast.Options.productionResourceRequirements,
+							VolumeMounts:    apicast.productionGateway().volumeMounts(),
 							LivenessProbe: &v1.Probe{
 								Handler: v1.Handler{HTTPGet: &v1.HTTPGetAction{
 									Path: "/status/live",
@@ -352,6 +342,223 @@ func (apicast *Apicast) ProductionDeploymentConfig() *appsv1.DeploymentConfig {
 	}
 }
 
+// apicastGateway holds the settings shared by the APIcast deployments of
+// an APIManager and the standalone APIcast gateways
+type apicastGateway struct {
+	customPolicies     []ApicastCustomPolicy
+	customPoliciesHash string
+	runtimeOptions     *ApicastRuntimeOptions
+	https              *ApicastHTTPSOptions
+}
+
+func (apicast *Apicast) stagingGateway() *apicastGateway {
+	return &apicastGateway{
+		customPolicies:     apicast.Options.customPolicies,
+		customPoliciesHash: apicast.Options.customPoliciesHash,
+		runtimeOptions:     apicast.Options.stagingRuntimeOptions,
+		https:              apicast.Options.https,
+	}
+}
+
+func (apicast *Apicast) productionGateway() *apicastGateway {
+	return &apicastGateway{
+		customPolicies:     apicast.Options.customPolicies,
+		customPoliciesHash: apicast.Options.customPoliciesHash,
+		runtimeOptions:     apicast.Options.productionRuntimeOptions,
+		https:              apicast.Options.https,
+	}
+}
+
+func (gateway *apicastGateway) podTemplateAnnotations() map[string]string {
+	annotations := map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "9421",
+	}
+	if gateway.customPoliciesHash != "" {
+		annotations[ApicastCustomPoliciesHashAnnotation] = gateway.customPoliciesHash
+	}
+	if gateway.runtimeOptions.CustomEnvironmentsHash != "" {
+		annotations[ApicastCustomEnvironmentsHashAnnotation] = gateway.runtimeOptions.CustomEnvironmentsHash
+	}
+	if gateway.https != nil {
+		annotations[ApicastHTTPSCertificateHashAnnotation] = gateway.https.CertificateHash
+	}
+	return annotations
+}
+
+func (gateway *apicastGateway) servicePorts() []v1.ServicePort {
+	ports := []v1.ServicePort{
+		v1.ServicePort{
+			Name:       "gateway",
+			Protocol:   v1.ProtocolTCP,
+			Port:       8080,
+			TargetPort: intstr.FromInt(8080),
+		},
+		v1.ServicePort{
+			Name:       "management",
+			Protocol:   v1.ProtocolTCP,
+			Port:       8090,
+			TargetPort: intstr.FromInt(8090),
+		},
+	}
+	if gateway.https != nil {
+		ports = append(ports, v1.ServicePort{
+			Name:       "https",
+			Protocol:   v1.ProtocolTCP,
+			Port:       gateway.https.Port,
+			TargetPort: intstr.FromInt(int(gateway.https.Port)),
+		})
+	}
+	return ports
+}
+
+func (gateway *apicastGateway) containerPorts() []v1.ContainerPort {
+	ports := []v1.ContainerPort{
+		v1.ContainerPort{
+			ContainerPort: 8080,
+			Protocol:      v1.ProtocolTCP,
+		},
+		v1.ContainerPort{
+			ContainerPort: 8090,
+			Protocol:      v1.ProtocolTCP,
+		},
+		v1.ContainerPort{
+			ContainerPort: 9421,
+			Protocol:      v1.ProtocolTCP,
+			Name:          "metrics",
+		},
+	}
+	if gateway.https != nil {
+		ports = append(ports, v1.ContainerPort{
+			ContainerPort: gateway.https.Port,
+			Protocol:      v1.ProtocolTCP,
+			Name:          "https",
+		})
+	}
+	return ports
+}
+
+func (gateway *apicastGateway) volumes() []v1.Volume {
+	volumes := gateway.customPolicyVolumes()
+	for idx, customEnvironment := range gateway.runtimeOptions.CustomEnvironments {
+		volumes = append(volumes, v1.Volume{
+			Name: customEnvironmentVolumeName(idx),
+			VolumeSource: v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: customEnvironment.ConfigMapName},
+				},
+			},
+		})
+	}
+	if gateway.https != nil {
+		volumes = append(volumes, v1.Volume{
+			Name: apicastHTTPSVolumeName,
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{
+					SecretName: gateway.https.CertificateSecretName,
+				},
+			},
+		})
+	}
+	return volumes
+}
+
+func (gateway *apicastGateway) volumeMounts() []v1.VolumeMount {
+	volumeMounts := gateway.customPolicyVolumeMounts()
+	for idx, customEnvironment := range gateway.runtimeOptions.CustomEnvironments {
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      customEnvironmentVolumeName(idx),
+			MountPath: path.Join(ApicastCustomEnvironmentsMountBasePath, customEnvironment.ConfigMapName),
+			ReadOnly:  true,
+		})
+	}
+	if gateway.https != nil {
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      apicastHTTPSVolumeName,
+			MountPath: ApicastHTTPSCertificateMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return volumeMounts
+}
+
+func customEnvironmentVolumeName(idx int) string {
+	return fmt.Sprintf("%s%d", apicastCustomEnvironmentVolumePrefix, idx)
+}
+
+func customPolicyVolumeName(idx int) string {
+	return fmt.Sprintf("%s%d", apicastCustomPolicyVolumePrefix, idx)
+}
+
+// IsApicastCustomFilesVolume returns true when the volume, or the init
+// container filling it, of the given name holds APIcast custom policies,
+// custom environments or the HTTPS certificate
+func IsApicastCustomFilesVolume(name string) bool {
+	return strings.HasPrefix(name, apicastCustomPolicyVolumePrefix) ||
+		strings.HasPrefix(name, apicastCustomEnvironmentVolumePrefix) ||
+		name == apicastHTTPSVolumeName
+}
+
+// customPolicyVolumes returns a ConfigMap volume for each ConfigMap policy
+// and an emptyDir volume, filled by an init container, for each image
+// policy
+func (gateway *apicastGateway) customPolicyVolumes() []v1.Volume {
+	var volumes []v1.Volume
+	for idx, policy := range gateway.customPolicies {
+		volume := v1.Volume{Name: customPolicyVolumeName(idx)}
+		if policy.ConfigMapName != "" {
+			volume.VolumeSource = v1.VolumeSource{
+				ConfigMap: &v1.ConfigMapVolumeSource{
+					LocalObjectReference: v1.LocalObjectReference{Name: policy.ConfigMapName},
+				},
+			}
+		} else {
+			volume.VolumeSource = v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}
+		}
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+func (gateway *apicastGateway) customPolicyVolumeMounts() []v1.VolumeMount {
+	var volumeMounts []v1.VolumeMount
+	for idx, policy := range gateway.customPolicies {
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      customPolicyVolumeName(idx),
+			MountPath: path.Join(ApicastCustomPoliciesMountBasePath, policy.Name, policy.Version),
+			ReadOnly:  true,
+		})
+	}
+	return volumeMounts
+}
+
+// customPolicyInitContainers returns the init containers copying the files
+// of the image policies into their volumes
+func (gateway *apicastGateway) customPolicyInitContainers() []v1.Container {
+	var containers []v1.Container
+	for idx, policy := range gateway.customPolicies {
+		if policy.ConfigMapName != "" {
+			continue
+		}
+		imagePath := policy.ImagePath
+		if imagePath == "" {
+			imagePath = ApicastCustomPolicyDefaultImagePath
+		}
+		containers = append(containers, v1.Container{
+			Name:    customPolicyVolumeName(idx),
+			Image:   policy.Image,
+			Command: []string{"sh", "-c", fmt.Sprintf("cp -R %s/. %s/", imagePath, apicastCustomPolicyInitMountPath)},
+			VolumeMounts: []v1.VolumeMount{
+				v1.VolumeMount{
+					Name:      customPolicyVolumeName(idx),
+					MountPath: apicastCustomPolicyInitMountPath,
+				},
+			},
+		})
+	}
+	return containers
+}
+
 func (apicast *Apicast) buildApicastCommonEnv() []v1.EnvVar {
 	return []v1.EnvVar{
 		envVarFromSecret("THREESCALE_PORTAL_ENDPOINT", "system-master-apicast", "PROXY_CONFIGS_ENDPOINT"),
@@ -363,27 +570,87 @@ func (apicast *Apicast) buildApicastCommonEnv() []v1.EnvVar {
 }
 
 func (apicast *Apicast) buildApicastStagingEnv() []v1.EnvVar {
+	runtimeOptions := apicast.Options.stagingRuntimeOptions
 	result := []v1.EnvVar{}
 	result = append(result, apicast.buildApicastCommonEnv()...)
 	result = append(result,
-		envVarFromValue("APICAST_CONFIGURATION_LOADER", "lazy"),
-		envVarFromValue("APICAST_CONFIGURATION_CACHE", "0"),
+		envVarFromValue("APICAST_CONFIGURATION_LOADER", valueOrDefault(runtimeOptions.ConfigurationLoader, "lazy")),
+		envVarFromValue("APICAST_CONFIGURATION_CACHE", int32ValueOrDefault(runtimeOptions.ConfigurationCacheTTL, "0")),
 		envVarFromValue("THREESCALE_DEPLOYMENT_ENV", "staging"),
 	)
+	result = append(result, apicast.stagingGateway().runtimeEnv()...)
 	return result
 }
 
 func (apicast *Apicast) buildApicastProductionEnv() []v1.EnvVar {
+	runtimeOptions := apicast.Options.productionRuntimeOptions
 	result := []v1.EnvVar{}
 	result = append(result, apicast.buildApicastCommonEnv()...)
 	result = append(result,
-		envVarFromValue("APICAST_CONFIGURATION_LOADER", "boot"),
-		envVarFromValue("APICAST_CONFIGURATION_CACHE", "300"),
+		envVarFromValue("APICAST_CONFIGURATION_LOADER", valueOrDefault(runtimeOptions.ConfigurationLoader, "boot")),
+		envVarFromValue("APICAST_CONFIGURATION_CACHE", int32ValueOrDefault(runtimeOptions.ConfigurationCacheTTL, "300")),
 		envVarFromValue("THREESCALE_DEPLOYMENT_ENV", "production"),
 	)
+	result = append(result, apicast.productionGateway().runtimeEnv()...)
 	return result
 }
 
+// runtimeEnv returns the environment variables of the runtime settings that
+// are set and of the HTTPS listener when it is enabled
+func (gateway *apicastGateway) runtimeEnv() []v1.EnvVar {
+	runtimeOptions := gateway.runtimeOptions
+	result := []v1.EnvVar{}
+	if runtimeOptions.LogLevel != "" {
+		result = append(result, envVarFromValue("APICAST_LOG_LEVEL", runtimeOptions.LogLevel))
+	}
+	if runtimeOptions.Workers != nil {
+		result = append(result, envVarFromValue("APICAST_WORKERS", strconv.FormatInt(int64(*runtimeOptions.Workers), 10)))
+	}
+	if runtimeOptions.PathRouting != nil {
+		result = append(result, envVarFromValue("APICAST_PATH_ROUTING", strconv.FormatBool(*runtimeOptions.PathRouting)))
+	}
+	if runtimeOptions.ServicesFilterByURL != "" {
+		result = append(result, envVarFromValue("APICAST_SERVICES_FILTER_BY_URL", runtimeOptions.ServicesFilterByURL))
+	}
+	if len(runtimeOptions.ServicesList) > 0 {
+		result = append(result, envVarFromValue("APICAST_SERVICES_LIST", strings.Join(runtimeOptions.ServicesList, ",")))
+	}
+
+	// APICAST_ENVIRONMENT is a colon separated list of environment files
+	environmentFiles := []string{}
+	for _, customEnvironment := range runtimeOptions.CustomEnvironments {
+		for _, file := range customEnvironment.Files {
+			environmentFiles = append(environmentFiles, path.Join(ApicastCustomEnvironmentsMountBasePath, customEnvironment.ConfigMapName, file))
+		}
+	}
+	if len(environmentFiles) > 0 {
+		result = append(result, envVarFromValue("APICAST_ENVIRONMENT", strings.Join(environmentFiles, ":")))
+	}
+
+	if gateway.https != nil {
+		result = append(result,
+			envVarFromValue("APICAST_HTTPS_PORT", strconv.FormatInt(int64(gateway.https.Port), 10)),
+			envVarFromValue("APICAST_HTTPS_CERTIFICATE", path.Join(ApicastHTTPSCertificateMountPath, v1.TLSCertKey)),
+			envVarFromValue("APICAST_HTTPS_CERTIFICATE_KEY", path.Join(ApicastHTTPSCertificateMountPath, v1.TLSPrivateKeyKey)),
+		)
+	}
+	return result
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func int32ValueOrDefault(value *int32, defaultValue string) string {
+	if value == nil {
+		return defaultValue
+	}
+	return strconv.FormatInt(int64(*value), 10)
+}
+
 func (apicast *Apicast) EnvironmentConfigMap() *v1.ConfigMap {
 	return &v1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -447,3 +714,96 @@ func (apicast *Apicast) ProductionPodDisruptionBudget() *v1beta1.PodDisruptionBu
 		},
 	}
 }
+
+func (apicast *Apicast) StagingIngress() *extensions.Ingress {
+	return ingress(
+		"apicast-staging",
+		map[string]string{"app": apicast.Options.appLabel, "threescale_component": "apicast", "threescale_component_element": "staging"},
+		"api-"+apicast.Options.tenantName+"-apicast-staging."+apicast.Options.wildcardDomain,
+		"apicast-staging",
+		apicast.stagingGateway().exposedServicePort(),
+	)
+}
+
+func (apicast *Apicast) ProductionIngress() *extensions.Ingress {
+	return ingress(
+		"apicast-production",
+		map[string]string{"app": apicast.Options.appLabel, "threescale_component": "apicast", "threescale_component_element": "production"},
+		"api-"+apicast.Options.tenantName+"-apicast-production."+apicast.Options.wildcardDomain,
+		"apicast-production",
+		apicast.productionGateway().exposedServicePort(),
+	)
+}
+
+// exposedServicePort returns the service port the gateways are exposed
+// with. The HTTPS port when the HTTPS listener is enabled, so traffic is
+// encrypted up to APIcast
+func (gateway *apicastGateway) exposedServicePort() intstr.IntOrString {
+	if gateway.https != nil {
+		return intstr.FromString("https")
+	}
+	return intstr.FromString("gateway")
+}
+
+// StagingHTTPSRoute returns the Route exposing the apicast-staging HTTPS
+// listener. It returns nil when the HTTPS listener is not enabled
+func (apicast *Apicast) StagingHTTPSRoute() *routev1.Route {
+	return apicast.httpsRoute("staging")
+}
+
+// ProductionHTTPSRoute returns the Route exposing the apicast-production
+// HTTPS listener. It returns nil when the HTTPS listener is not enabled
+func (apicast *Apicast) ProductionHTTPSRoute() *routev1.Route {
+	return apicast.httpsRoute("production")
+}
+
+func (apicast *Apicast) httpsRoute(element string) *routev1.Route {
+	gateway := apicast.productionGateway()
+	if gateway.https == nil {
+		return nil
+	}
+
+	return &routev1.Route{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Route",
+			APIVersion: "route.openshift.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "apicast-" + element + "-https",
+			Labels: map[string]string{"app": apicast.Options.appLabel, "threescale_component": "apicast", "threescale_component_element": element},
+		},
+		Spec: routev1.RouteSpec{
+			Host: "api-" + apicast.Options.tenantName + "-apicast-" + element + "." + apicast.Options.wildcardDomain,
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: "apicast-" + element,
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: gateway.exposedServicePort(),
+			},
+			TLS: gateway.routeTLSConfig(),
+		},
+	}
+}
+
+// routeTLSConfig returns the TLS configuration of the Routes exposing the
+// gateway. TLS is terminated at the router unless the HTTPS listener is
+// enabled
+func (gateway *apicastGateway) routeTLSConfig() *routev1.TLSConfig {
+	if gateway.https == nil {
+		return &routev1.TLSConfig{
+			Termination:                   routev1.TLSTerminationEdge,
+			InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyAllow,
+		}
+	}
+
+	tlsConfig := &routev1.TLSConfig{
+		Termination:                   routev1.TLSTerminationPassthrough,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyNone,
+	}
+	if gateway.https.RouteTermination == string(routev1.TLSTerminationReencrypt) {
+		tlsConfig.Termination = routev1.TLSTerminationReencrypt
+		tlsConfig.DestinationCACertificate = gateway.https.DestinationCACertificate
+	}
+	return tlsConfig
+}
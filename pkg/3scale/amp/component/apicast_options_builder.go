@@ -19,8 +19,65 @@ type ApicastOptions struct {
 	// non required options
 	productionResourceRequirements *v1.ResourceRequirements
 	stagingResourceRequirements    *v1.ResourceRequirements
+	productionPodPlacement         *PodPlacement
+	stagingPodPlacement            *PodPlacement
 	productionReplicas             *int32
 	stagingReplicas                *int32
+	customPolicies                 []ApicastCustomPolicy
+	customPoliciesHash             string
+	productionRuntimeOptions       *ApicastRuntimeOptions
+	stagingRuntimeOptions          *ApicastRuntimeOptions
+	https                          *ApicastHTTPSOptions
+}
+
+// ApicastHTTPSOptions holds the settings of the HTTPS listener of the
+// APIcast gateways
+type ApicastHTTPSOptions struct {
+	Port                  int32
+	CertificateSecretName string
+	// RouteTermination is the TLS termination of the HTTPS Routes, either
+	// passthrough or reencrypt
+	RouteTermination string
+	// DestinationCACertificate is the CA certificate the router verifies
+	// APIcast with on reencrypt Routes
+	DestinationCACertificate string
+	// CertificateHash is the hash of the certificate secret content. The
+	// deployments are rolled out when it changes
+	CertificateHash string
+}
+
+// ApicastRuntimeOptions holds the APIcast runtime settings of an APIcast
+// deployment. Settings with empty values are not set
+type ApicastRuntimeOptions struct {
+	LogLevel              string
+	Workers               *int32
+	ConfigurationLoader   string
+	ConfigurationCacheTTL *int32
+	PathRouting           *bool
+	ServicesFilterByURL   string
+	ServicesList          []string
+	CustomEnvironments    []ApicastCustomEnvironment
+	// CustomEnvironmentsHash is the hash of the custom environments
+	// content. The deployment is rolled out when it changes
+	CustomEnvironmentsHash string
+}
+
+// ApicastCustomEnvironment is a ConfigMap holding APIcast environment
+// files. Files are the ConfigMap keys
+type ApicastCustomEnvironment struct {
+	ConfigMapName string
+	Files         []string
+}
+
+// ApicastCustomPolicy is a custom policy mounted into the APIcast
+// containers. The policy files come from ConfigMapName when set, and from
+// the ImagePath directory of Image otherwise
+type ApicastCustomPolicy struct {
+	Name          string
+	Version       string
+	ConfigMapName string
+	Image         string
+	ImagePath     string
 }
 
 type ApicastOptionsBuilder struct {
@@ -59,6 +116,14 @@ func (a *ApicastOptionsBuilder) StagingResourceRequirements(resourceRequirements
 	a.options.stagingResourceRequirements = &resourceRequirements
 }
 
+func (a *ApicastOptionsBuilder) ProductionPodPlacement(podPlacement PodPlacement) {
+	a.options.productionPodPlacement = &podPlacement
+}
+
+func (a *ApicastOptionsBuilder) StagingPodPlacement(podPlacement PodPlacement) {
+	a.options.stagingPodPlacement = &podPlacement
+}
+
 func (a *ApicastOptionsBuilder) StagingReplicas(replicas int32) {
 	a.options.stagingReplicas = &replicas
 }
@@ -67,6 +132,29 @@ func (a *ApicastOptionsBuilder) ProductionReplicas(replicas int32) {
 	a.options.productionReplicas = &replicas
 }
 
+func (a *ApicastOptionsBuilder) CustomPolicies(customPolicies []ApicastCustomPolicy) {
+	a.options.customPolicies = customPolicies
+}
+
+// CustomPoliciesHash sets the hash of the custom policies content. The
+// APIcast deployments are rolled out when it changes
+func (a *ApicastOptionsBuilder) CustomPoliciesHash(hash string) {
+	a.options.customPoliciesHash = hash
+}
+
+func (a *ApicastOptionsBuilder) ProductionRuntimeOptions(runtimeOptions ApicastRuntimeOptions) {
+	a.options.productionRuntimeOptions = &runtimeOptions
+}
+
+func (a *ApicastOptionsBuilder) StagingRuntimeOptions(runtimeOptions ApicastRuntimeOptions) {
+	a.options.stagingRuntimeOptions = &runtimeOptions
+}
+
+// HTTPS enables the HTTPS listener of the APIcast gateways
+func (a *ApicastOptionsBuilder) HTTPS(httpsOptions ApicastHTTPSOptions) {
+	a.options.https = &httpsOptions
+}
+
 func (a *ApicastOptionsBuilder) Build() (*ApicastOptions, error) {
 	err := a.setRequiredOptions()
 	if err != nil {
@@ -119,6 +207,22 @@ func (a *ApicastOptionsBuilder) setNonRequiredOptions() {
 		var defaultProductionReplicas int32 = 1
 		a.options.productionReplicas = &defaultProductionReplicas
 	}
+
+	if a.options.productionPodPlacement == nil {
+		a.options.productionPodPlacement = &PodPlacement{}
+	}
+
+	if a.options.stagingPodPlacement == nil {
+		a.options.stagingPodPlacement = &PodPlacement{}
+	}
+
+	if a.options.productionRuntimeOptions == nil {
+		a.options.productionRuntimeOptions = &ApicastRuntimeOptions{}
+	}
+
+	if a.options.stagingRuntimeOptions == nil {
+		a.options.stagingRuntimeOptions = &ApicastRuntimeOptions{}
+	}
 }
 
 func (a *ApicastOptionsBuilder) defaultProductionResourceRequirements() *v1.ResourceRequirements {
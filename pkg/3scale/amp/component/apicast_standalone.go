@@ -0,0 +1,199 @@
+package component
+
+import (
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// StandaloneApicast is an APIcast gateway deployed independently from the
+// 3scale API Management components. It loads its configuration from the
+// admin portal of a remote 3scale installation
+type StandaloneApicast struct {
+	Options *StandaloneApicastOptions
+}
+
+func NewStandaloneApicast(options *StandaloneApicastOptions) *StandaloneApicast {
+	return &StandaloneApicast{Options: options}
+}
+
+// ObjectName returns the name of the objects of the gateway
+func (apicast *StandaloneApicast) ObjectName() string {
+	return "apicast-" + apicast.Options.name
+}
+
+func (apicast *StandaloneApicast) gateway() *apicastGateway {
+	return &apicastGateway{
+		customPolicies:     apicast.Options.customPolicies,
+		customPoliciesHash: apicast.Options.customPoliciesHash,
+		runtimeOptions:     apicast.Options.runtimeOptions,
+		https:              apicast.Options.https,
+	}
+}
+
+func (apicast *StandaloneApicast) labels() map[string]string {
+	return map[string]string{
+		"app":                          "apicast",
+		"threescale_component":         "apicast",
+		"threescale_component_element": apicast.Options.name,
+	}
+}
+
+func (apicast *StandaloneApicast) selector() map[string]string {
+	return map[string]string{"deployment": apicast.ObjectName()}
+}
+
+func (apicast *StandaloneApicast) Deployment() *k8sappsv1.Deployment {
+	podLabels := apicast.labels()
+	for k, v := range apicast.selector() {
+		podLabels[k] = v
+	}
+
+	return &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   apicast.ObjectName(),
+			Labels: apicast.labels(),
+		},
+		Spec: k8sappsv1.DeploymentSpec{
+			Replicas: apicast.Options.replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: apicast.selector()},
+			Strategy: k8sappsv1.DeploymentStrategy{
+				Type: k8sappsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &k8sappsv1.RollingUpdateDeployment{
+					MaxSurge:       &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+					MaxUnavailable: &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+				},
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: apicast.gateway().podTemplateAnnotations(),
+				},
+				Spec: v1.PodSpec{
+					NodeSelector:      apicast.Options.podPlacement.NodeSelector,
+					Tolerations:       apicast.Options.podPlacement.Tolerations,
+					Affinity:          apicast.Options.podPlacement.Affinity,
+					PriorityClassName: apicast.Options.podPlacement.PriorityClassName,
+					InitContainers:    apicast.gateway().customPolicyInitContainers(),
+					Volumes:           apicast.gateway().volumes(),
+					Containers: []v1.Container{
+						v1.Container{
+							Name:            "apicast",
+							Image:           apicast.Options.image,
+							ImagePullPolicy: v1.PullIfNotPresent,
+							Ports:           apicast.gateway().containerPorts(),
+							Env:             apicast.buildEnv(),
+							Resources:       *apicast.Options.resourceRequirements,
+							VolumeMounts:    apicast.gateway().volumeMounts(),
+							LivenessProbe: &v1.Probe{
+								Handler: v1.Handler{HTTPGet: &v1.HTTPGetAction{
+									Path: "/status/live",
+									Port: intstr.FromInt(8090),
+								}},
+								InitialDelaySeconds: 10,
+								TimeoutSeconds:      5,
+								PeriodSeconds:       10,
+							},
+							ReadinessProbe: &v1.Probe{
+								Handler: v1.Handler{HTTPGet: &v1.HTTPGetAction{
+									Path: "/status/ready",
+									Port: intstr.FromInt(8090),
+								}},
+								InitialDelaySeconds: 15,
+								TimeoutSeconds:      5,
+								PeriodSeconds:       30,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (apicast *StandaloneApicast) buildEnv() []v1.EnvVar {
+	// Staging gateways load the latest configuration on every request
+	defaultConfigurationLoader, defaultConfigurationCacheTTL := "boot", "300"
+	if apicast.Options.deploymentEnvironment == appsv1alpha1.APIcastDeploymentEnvironmentStaging {
+		defaultConfigurationLoader, defaultConfigurationCacheTTL = "lazy", "0"
+	}
+
+	runtimeOptions := apicast.Options.runtimeOptions
+	result := []v1.EnvVar{
+		envVarFromSecret("THREESCALE_PORTAL_ENDPOINT", apicast.Options.adminPortalCredentialsRef, appsv1alpha1.APIcastAdminPortalURLSecretKey),
+		envVarFromValue("THREESCALE_DEPLOYMENT_ENV", apicast.Options.deploymentEnvironment),
+		envVarFromValue("OPENSSL_VERIFY", apicast.Options.openSSLVerify),
+		envVarFromValue("APICAST_CONFIGURATION_LOADER", valueOrDefault(runtimeOptions.ConfigurationLoader, defaultConfigurationLoader)),
+		envVarFromValue("APICAST_CONFIGURATION_CACHE", int32ValueOrDefault(runtimeOptions.ConfigurationCacheTTL, defaultConfigurationCacheTTL)),
+	}
+	result = append(result, apicast.gateway().runtimeEnv()...)
+	return result
+}
+
+func (apicast *StandaloneApicast) Service() *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   apicast.ObjectName(),
+			Labels: apicast.labels(),
+		},
+		Spec: v1.ServiceSpec{
+			Ports:    apicast.gateway().servicePorts(),
+			Selector: apicast.selector(),
+		},
+	}
+}
+
+// Route returns the Route exposing the gateway on the exposed host. It
+// returns nil when the gateway is not exposed
+func (apicast *StandaloneApicast) Route() *routev1.Route {
+	if apicast.Options.exposedHost == "" {
+		return nil
+	}
+
+	return &routev1.Route{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Route",
+			APIVersion: "route.openshift.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   apicast.ObjectName(),
+			Labels: apicast.labels(),
+		},
+		Spec: routev1.RouteSpec{
+			Host: apicast.Options.exposedHost,
+			To: routev1.RouteTargetReference{
+				Kind: "Service",
+				Name: apicast.ObjectName(),
+			},
+			Port: &routev1.RoutePort{
+				TargetPort: apicast.gateway().exposedServicePort(),
+			},
+			TLS: apicast.gateway().routeTLSConfig(),
+		},
+	}
+}
+
+// Ingress returns the Ingress exposing the gateway on the exposed host. It
+// returns nil when the gateway is not exposed
+func (apicast *StandaloneApicast) Ingress() *extensions.Ingress {
+	if apicast.Options.exposedHost == "" {
+		return nil
+	}
+
+	return ingress(
+		apicast.ObjectName(),
+		apicast.labels(),
+		apicast.Options.exposedHost,
+		apicast.ObjectName(),
+		apicast.gateway().exposedServicePort(),
+	)
+}
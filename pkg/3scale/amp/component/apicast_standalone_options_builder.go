@@ -0,0 +1,155 @@
+package component
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type StandaloneApicastOptions struct {
+	// required options
+	name                      string
+	image                     string
+	adminPortalCredentialsRef string
+	deploymentEnvironment     string
+	openSSLVerify             string
+
+	// non required options
+	replicas             *int32
+	resourceRequirements *v1.ResourceRequirements
+	podPlacement         *PodPlacement
+	exposedHost          string
+	customPolicies       []ApicastCustomPolicy
+	customPoliciesHash   string
+	runtimeOptions       *ApicastRuntimeOptions
+	https                *ApicastHTTPSOptions
+}
+
+type StandaloneApicastOptionsBuilder struct {
+	options StandaloneApicastOptions
+}
+
+// Name sets the name of the APIcast resource. The gateway objects are
+// named after it
+func (a *StandaloneApicastOptionsBuilder) Name(name string) {
+	a.options.name = name
+}
+
+func (a *StandaloneApicastOptionsBuilder) Image(image string) {
+	a.options.image = image
+}
+
+// AdminPortalCredentialsRef sets the name of the secret with the admin
+// portal endpoint the gateway loads its configuration from
+func (a *StandaloneApicastOptionsBuilder) AdminPortalCredentialsRef(secretName string) {
+	a.options.adminPortalCredentialsRef = secretName
+}
+
+func (a *StandaloneApicastOptionsBuilder) DeploymentEnvironment(deploymentEnvironment string) {
+	a.options.deploymentEnvironment = deploymentEnvironment
+}
+
+func (a *StandaloneApicastOptionsBuilder) OpenSSLVerify(openSSLVerify string) {
+	a.options.openSSLVerify = openSSLVerify
+}
+
+func (a *StandaloneApicastOptionsBuilder) Replicas(replicas int32) {
+	a.options.replicas = &replicas
+}
+
+func (a *StandaloneApicastOptionsBuilder) ResourceRequirements(resourceRequirements v1.ResourceRequirements) {
+	a.options.resourceRequirements = &resourceRequirements
+}
+
+func (a *StandaloneApicastOptionsBuilder) PodPlacement(podPlacement PodPlacement) {
+	a.options.podPlacement = &podPlacement
+}
+
+// ExposedHost sets the host the gateway is exposed on. The gateway is not
+// exposed out of the cluster when it is empty
+func (a *StandaloneApicastOptionsBuilder) ExposedHost(host string) {
+	a.options.exposedHost = host
+}
+
+func (a *StandaloneApicastOptionsBuilder) CustomPolicies(customPolicies []ApicastCustomPolicy) {
+	a.options.customPolicies = customPolicies
+}
+
+// CustomPoliciesHash sets the hash of the custom policies content. The
+// gateway is rolled out when it changes
+func (a *StandaloneApicastOptionsBuilder) CustomPoliciesHash(hash string) {
+	a.options.customPoliciesHash = hash
+}
+
+func (a *StandaloneApicastOptionsBuilder) RuntimeOptions(runtimeOptions ApicastRuntimeOptions) {
+	a.options.runtimeOptions = &runtimeOptions
+}
+
+// HTTPS enables the HTTPS listener of the gateway
+func (a *StandaloneApicastOptionsBuilder) HTTPS(httpsOptions ApicastHTTPSOptions) {
+	a.options.https = &httpsOptions
+}
+
+func (a *StandaloneApicastOptionsBuilder) Build() (*StandaloneApicastOptions, error) {
+	err := a.setRequiredOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	a.setNonRequiredOptions()
+
+	return &a.options, nil
+}
+
+func (a *StandaloneApicastOptionsBuilder) setRequiredOptions() error {
+	if a.options.name == "" {
+		return fmt.Errorf("no name has been provided")
+	}
+	if a.options.image == "" {
+		return fmt.Errorf("no image has been provided")
+	}
+	if a.options.adminPortalCredentialsRef == "" {
+		return fmt.Errorf("no admin portal credentials secret has been provided")
+	}
+	if a.options.deploymentEnvironment == "" {
+		return fmt.Errorf("no deployment environment has been provided")
+	}
+	if a.options.openSSLVerify == "" {
+		return fmt.Errorf("no OpenSSLVerify option has been provided")
+	}
+
+	return nil
+}
+
+func (a *StandaloneApicastOptionsBuilder) setNonRequiredOptions() {
+	if a.options.replicas == nil {
+		var defaultReplicas int32 = 1
+		a.options.replicas = &defaultReplicas
+	}
+
+	if a.options.resourceRequirements == nil {
+		a.options.resourceRequirements = a.defaultResourceRequirements()
+	}
+
+	if a.options.podPlacement == nil {
+		a.options.podPlacement = &PodPlacement{}
+	}
+
+	if a.options.runtimeOptions == nil {
+		a.options.runtimeOptions = &ApicastRuntimeOptions{}
+	}
+}
+
+func (a *StandaloneApicastOptionsBuilder) defaultResourceRequirements() *v1.ResourceRequirements {
+	return &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000m"),
+			v1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("500m"),
+			v1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+}
@@ -1,12 +1,15 @@
 package component
 
 import (
+	"fmt"
+
 	"github.com/3scale/3scale-operator/pkg/common"
 	"k8s.io/api/policy/v1beta1"
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -79,7 +82,7 @@ func (backend *Backend) PDBObjects() []common.KubernetesObject {
 }
 
 func (backend *Backend) WorkerDeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -123,6 +126,10 @@ func (backend *Backend) WorkerDeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "backend", "threescale_component_element": "worker", "app": backend.Options.appLabel, "deploymentConfig": "backend-worker"},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:      backend.Options.workerPodPlacement.NodeSelector,
+					Tolerations:       backend.Options.workerPodPlacement.Tolerations,
+					Affinity:          backend.Options.workerPodPlacement.Affinity,
+					PriorityClassName: backend.Options.workerPodPlacement.PriorityClassName,
 					InitContainers: []v1.Container{
 						v1.Container{
 							Name:  "backend-redis-svc",
@@ -148,10 +155,18 @@ func (backend *Backend) WorkerDeploymentConfig() *appsv1.DeploymentConfig {
 					ServiceAccountName: "amp"}},
 		},
 	}
+	backend.addRedisTLS(dc)
+	if backend.Options.metricsEnabled {
+		addMetricsPort(dc, BackendWorkerMetricsPort,
+			envVarFromValue("CONFIG_WORKER_PROMETHEUS_METRICS_ENABLED", "true"),
+			envVarFromValue("CONFIG_WORKER_PROMETHEUS_METRICS_PORT", fmt.Sprint(BackendWorkerMetricsPort)),
+		)
+	}
+	return dc
 }
 
 func (backend *Backend) CronDeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -195,6 +210,10 @@ func (backend *Backend) CronDeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "backend", "threescale_component_element": "cron", "app": backend.Options.appLabel, "deploymentConfig": "backend-cron"},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:      backend.Options.cronPodPlacement.NodeSelector,
+					Tolerations:       backend.Options.cronPodPlacement.Tolerations,
+					Affinity:          backend.Options.cronPodPlacement.Affinity,
+					PriorityClassName: backend.Options.cronPodPlacement.PriorityClassName,
 					InitContainers: []v1.Container{
 						v1.Container{
 							Name:  "backend-redis-svc",
@@ -221,10 +240,12 @@ func (backend *Backend) CronDeploymentConfig() *appsv1.DeploymentConfig {
 				}},
 		},
 	}
+	backend.addRedisTLS(dc)
+	return dc
 }
 
 func (backend *Backend) ListenerDeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -308,9 +329,21 @@ func (backend *Backend) ListenerDeploymentConfig() *appsv1.DeploymentConfig {
 					},
 				},
 					ServiceAccountName: "amp",
+					NodeSelector:       backend.Options.listenerPodPlacement.NodeSelector,
+					Tolerations:        backend.Options.listenerPodPlacement.Tolerations,
+					Affinity:           backend.Options.listenerPodPlacement.Affinity,
+					PriorityClassName:  backend.Options.listenerPodPlacement.PriorityClassName,
 				}},
 		},
 	}
+	backend.addRedisTLS(dc)
+	if backend.Options.metricsEnabled {
+		addMetricsPort(dc, BackendListenerMetricsPort,
+			envVarFromValue("CONFIG_LISTENER_PROMETHEUS_METRICS_ENABLED", "true"),
+			envVarFromValue("CONFIG_LISTENER_PROMETHEUS_METRICS_PORT", fmt.Sprint(BackendListenerMetricsPort)),
+		)
+	}
+	return dc
 }
 
 func (backend *Backend) ListenerService() *v1.Service {
@@ -411,6 +444,16 @@ func (backend *Backend) RedisSecret() *v1.Secret {
 	}
 }
 
+// addRedisTLS mounts the TLS files of the external backend redis into the
+// DeploymentConfig pods
+func (backend *Backend) addRedisTLS(dc *appsv1.DeploymentConfig) {
+	tls := backend.Options.redisTLS
+	envVars := []v1.EnvVar{}
+	envVars = append(envVars, datastoreTLSEnvVars(tls, "CONFIG_REDIS_SSL", "CONFIG_REDIS_CA_FILE", "CONFIG_REDIS_CERT", "CONFIG_REDIS_PRIVATE_KEY")...)
+	envVars = append(envVars, datastoreTLSEnvVars(tls, "CONFIG_QUEUES_SSL", "CONFIG_QUEUES_CA_FILE", "CONFIG_QUEUES_CERT", "CONFIG_QUEUES_PRIVATE_KEY")...)
+	addDatastoreTLS(dc, envVars, tls)
+}
+
 func (backend *Backend) buildBackendCommonEnv() []v1.EnvVar {
 	return []v1.EnvVar{
 		envVarFromSecret("CONFIG_REDIS_PROXY", BackendSecretBackendRedisSecretName, BackendSecretBackendRedisStorageURLFieldName),
@@ -560,3 +603,13 @@ func (backend *Backend) ListenerPodDisruptionBudget() *v1beta1.PodDisruptionBudg
 		},
 	}
 }
+
+func (backend *Backend) ListenerIngress() *extensions.Ingress {
+	return ingress(
+		"backend",
+		map[string]string{"app": backend.Options.appLabel, "threescale_component": "backend"},
+		"backend-"+backend.Options.tenantName+"."+backend.Options.wildcardDomain,
+		"backend-listener",
+		intstr.FromString("http"),
+	)
+}
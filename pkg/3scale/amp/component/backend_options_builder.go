@@ -20,9 +20,14 @@ type BackendOptions struct {
 	listenerResourceRequirements *v1.ResourceRequirements
 	workerResourceRequirements   *v1.ResourceRequirements
 	cronResourceRequirements     *v1.ResourceRequirements
+	listenerPodPlacement         *PodPlacement
+	workerPodPlacement           *PodPlacement
+	cronPodPlacement             *PodPlacement
 	listenerReplicas             *int32
 	workerReplicas               *int32
 	cronReplicas                 *int32
+	redisTLS                     *DatastoreTLSOptions
+	metricsEnabled               bool
 
 	// required Options
 	appLabel              string
@@ -100,6 +105,18 @@ func (m *BackendOptionsBuilder) CronResourceRequirements(resourceRequirements v1
 	m.options.cronResourceRequirements = &resourceRequirements
 }
 
+func (m *BackendOptionsBuilder) ListenerPodPlacement(podPlacement PodPlacement) {
+	m.options.listenerPodPlacement = &podPlacement
+}
+
+func (m *BackendOptionsBuilder) WorkerPodPlacement(podPlacement PodPlacement) {
+	m.options.workerPodPlacement = &podPlacement
+}
+
+func (m *BackendOptionsBuilder) CronPodPlacement(podPlacement PodPlacement) {
+	m.options.cronPodPlacement = &podPlacement
+}
+
 func (m *BackendOptionsBuilder) ListenerReplicas(replicas int32) {
 	m.options.listenerReplicas = &replicas
 }
@@ -112,6 +129,18 @@ func (m *BackendOptionsBuilder) CronReplicas(replicas int32) {
 	m.options.cronReplicas = &replicas
 }
 
+// RedisTLS sets the TLS files of the connection to the external backend
+// redis, both storage and queues
+func (m *BackendOptionsBuilder) RedisTLS(tls DatastoreTLSOptions) {
+	m.options.redisTLS = &tls
+}
+
+// MetricsEnabled exposes the Prometheus metrics of backend-listener and
+// backend-worker
+func (m *BackendOptionsBuilder) MetricsEnabled(enabled bool) {
+	m.options.metricsEnabled = enabled
+}
+
 func (m *BackendOptionsBuilder) Build() (*BackendOptions, error) {
 	err := m.setRequiredOptions()
 	if err != nil {
@@ -206,6 +235,18 @@ func (m *BackendOptionsBuilder) setNonRequiredOptions() {
 		var cronDefaultReplicas int32 = 1
 		m.options.cronReplicas = &cronDefaultReplicas
 	}
+
+	if m.options.listenerPodPlacement == nil {
+		m.options.listenerPodPlacement = &PodPlacement{}
+	}
+
+	if m.options.workerPodPlacement == nil {
+		m.options.workerPodPlacement = &PodPlacement{}
+	}
+
+	if m.options.cronPodPlacement == nil {
+		m.options.cronPodPlacement = &PodPlacement{}
+	}
 }
 
 func (m *BackendOptionsBuilder) defaultListenerResourceRequirements() *v1.ResourceRequirements {
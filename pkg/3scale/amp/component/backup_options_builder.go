@@ -0,0 +1,188 @@
+package component
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// System database types of the backups. They are read from the scheme of
+// the system-database secret URL
+const (
+	BackupSystemDatabaseTypeMySQL      = "mysql"
+	BackupSystemDatabaseTypePostgreSQL = "postgresql"
+)
+
+// BackupOptions are the options of the APIManager backup and restore Jobs
+type BackupOptions struct {
+	// required options
+	name                  string
+	persistentVolumeClaim string
+
+	// non required options
+	appLabel                   string
+	systemDatabaseType         string
+	systemDatabaseImage        string
+	systemRedisImage           string
+	backendRedisImage          string
+	zyncDatabaseImage          string
+	systemImage                string
+	cliImage                   string
+	secretNames                []string
+	systemStoragePVC           bool
+	backendRedisQueuesInstance bool
+	storageClassName           *string
+	volumeSize                 *resource.Quantity
+}
+
+type BackupOptionsBuilder struct {
+	options BackupOptions
+}
+
+// Name sets the name of the APIManagerBackup or APIManagerRestore resource.
+// The Jobs are named after it
+func (b *BackupOptionsBuilder) Name(name string) {
+	b.options.name = name
+}
+
+func (b *BackupOptionsBuilder) AppLabel(appLabel string) {
+	b.options.appLabel = appLabel
+}
+
+// PersistentVolumeClaim sets the name of the PersistentVolumeClaim the
+// artifacts are stored in
+func (b *BackupOptionsBuilder) PersistentVolumeClaim(claimName string) {
+	b.options.persistentVolumeClaim = claimName
+}
+
+// SystemDatabaseType sets the system database type. One of mysql or
+// postgresql
+func (b *BackupOptionsBuilder) SystemDatabaseType(databaseType string) {
+	b.options.systemDatabaseType = databaseType
+}
+
+// SystemDatabaseImage sets the image with the system database client
+func (b *BackupOptionsBuilder) SystemDatabaseImage(image string) {
+	b.options.systemDatabaseImage = image
+}
+
+func (b *BackupOptionsBuilder) SystemRedisImage(image string) {
+	b.options.systemRedisImage = image
+}
+
+func (b *BackupOptionsBuilder) BackendRedisImage(image string) {
+	b.options.backendRedisImage = image
+}
+
+func (b *BackupOptionsBuilder) ZyncDatabaseImage(image string) {
+	b.options.zyncDatabaseImage = image
+}
+
+// SystemImage sets the image used to archive the system file storage
+func (b *BackupOptionsBuilder) SystemImage(image string) {
+	b.options.systemImage = image
+}
+
+// CLIImage sets the image with the oc client used to restore the secrets
+func (b *BackupOptionsBuilder) CLIImage(image string) {
+	b.options.cliImage = image
+}
+
+// SecretNames sets the secrets backed up and restored
+func (b *BackupOptionsBuilder) SecretNames(secretNames []string) {
+	b.options.secretNames = secretNames
+}
+
+// SystemStoragePVC enables the system file storage step. The file storage
+// is only backed up when it is a PersistentVolumeClaim
+func (b *BackupOptionsBuilder) SystemStoragePVC(enabled bool) {
+	b.options.systemStoragePVC = enabled
+}
+
+// BackendRedisQueuesInstance enables the backend-redis-queues step. It is
+// only needed when the backend queues have their own redis instance
+func (b *BackupOptionsBuilder) BackendRedisQueuesInstance(enabled bool) {
+	b.options.backendRedisQueuesInstance = enabled
+}
+
+func (b *BackupOptionsBuilder) StorageClassName(storageClassName *string) {
+	b.options.storageClassName = storageClassName
+}
+
+// VolumeSize sets the size of the backup PersistentVolumeClaim created
+// for the artifacts
+func (b *BackupOptionsBuilder) VolumeSize(volumeSize resource.Quantity) {
+	b.options.volumeSize = &volumeSize
+}
+
+func (b *BackupOptionsBuilder) Build() (*BackupOptions, error) {
+	err := b.setRequiredOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	b.setNonRequiredOptions()
+
+	return &b.options, nil
+}
+
+func (b *BackupOptionsBuilder) setRequiredOptions() error {
+	if b.options.name == "" {
+		return fmt.Errorf("no name has been provided")
+	}
+	if b.options.persistentVolumeClaim == "" {
+		return fmt.Errorf("no persistent volume claim has been provided")
+	}
+	if b.options.systemDatabaseType != "" &&
+		b.options.systemDatabaseType != BackupSystemDatabaseTypeMySQL &&
+		b.options.systemDatabaseType != BackupSystemDatabaseTypePostgreSQL {
+		return fmt.Errorf("unsupported system database type '%s'", b.options.systemDatabaseType)
+	}
+
+	return nil
+}
+
+// setNonRequiredOptions defaults to the images of the release. The
+// operator sets the images it deploys, pulled from the registry mirror
+// of the APIManager when set
+func (b *BackupOptionsBuilder) setNonRequiredOptions() {
+	if b.options.appLabel == "" {
+		b.options.appLabel = "3scale-api-management"
+	}
+
+	if b.options.systemDatabaseType == "" {
+		b.options.systemDatabaseType = BackupSystemDatabaseTypeMySQL
+	}
+
+	if b.options.systemDatabaseImage == "" {
+		b.options.systemDatabaseImage = SystemMySQLImageURL()
+		if b.options.systemDatabaseType == BackupSystemDatabaseTypePostgreSQL {
+			b.options.systemDatabaseImage = SystemPostgreSQLImageURL()
+		}
+	}
+
+	if b.options.systemRedisImage == "" {
+		b.options.systemRedisImage = SystemRedisImageURL()
+	}
+
+	if b.options.backendRedisImage == "" {
+		b.options.backendRedisImage = BackendRedisImageURL()
+	}
+
+	if b.options.zyncDatabaseImage == "" {
+		b.options.zyncDatabaseImage = ZyncPostgreSQLImageURL()
+	}
+
+	if b.options.systemImage == "" {
+		b.options.systemImage = SystemImageURL()
+	}
+
+	if b.options.cliImage == "" {
+		b.options.cliImage = CLIImageURL()
+	}
+
+	if b.options.volumeSize == nil {
+		tmpVolumeSize := resource.MustParse("10Gi")
+		b.options.volumeSize = &tmpVolumeSize
+	}
+}
@@ -0,0 +1,179 @@
+package component
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	SystemSecretSystemDatabaseSSLCAFieldName   = "DB_SSL_CA"
+	SystemSecretSystemDatabaseSSLCertFieldName = "DB_SSL_CERT"
+	SystemSecretSystemDatabaseSSLKeyFieldName  = "DB_SSL_KEY"
+)
+
+const (
+	BackendSecretBackendRedisSSLCAFieldName   = "REDIS_SSL_CA"
+	BackendSecretBackendRedisSSLCertFieldName = "REDIS_SSL_CERT"
+	BackendSecretBackendRedisSSLKeyFieldName  = "REDIS_SSL_KEY"
+)
+
+const (
+	SystemSecretSystemRedisSSLCAFieldName   = "REDIS_SSL_CA"
+	SystemSecretSystemRedisSSLCertFieldName = "REDIS_SSL_CERT"
+	SystemSecretSystemRedisSSLKeyFieldName  = "REDIS_SSL_KEY"
+)
+
+const (
+	// DatastoreTLSMountBasePath is the directory the TLS files of the
+	// external datastores are mounted in, under the datastore name
+	DatastoreTLSMountBasePath = "/var/run/secrets"
+	// DatastoreTLSHashAnnotation is the pod template annotation holding
+	// the hash of the TLS files mounted into the pods
+	DatastoreTLSHashAnnotation = "apps.3scale.net/datastore-tls-hash"
+)
+
+// DatastoreTLSOptions holds the TLS files of the connection to an external
+// datastore. The files are read from the datastore secret
+type DatastoreTLSOptions struct {
+	// Name identifies the datastore. It names the volume and the mount
+	// directory of the files
+	Name       string
+	SecretName string
+	// CAKey is the secret key of the CA certificate
+	CAKey string
+	// CertKey and PrivateKeyKey are the secret keys of the client
+	// certificate. Empty when no client certificate is used
+	CertKey       string
+	PrivateKeyKey string
+	// Hash of the TLS files, rolling out the pods when they change
+	Hash string
+}
+
+func (t *DatastoreTLSOptions) mountPath() string {
+	return path.Join(DatastoreTLSMountBasePath, t.Name)
+}
+
+// CAFile returns the path of the mounted CA certificate
+func (t *DatastoreTLSOptions) CAFile() string {
+	return path.Join(t.mountPath(), "ca.crt")
+}
+
+// CertFile returns the path of the mounted client certificate, empty when
+// no client certificate is used
+func (t *DatastoreTLSOptions) CertFile() string {
+	if t.CertKey == "" {
+		return ""
+	}
+	return path.Join(t.mountPath(), v1.TLSCertKey)
+}
+
+// PrivateKeyFile returns the path of the mounted client private key,
+// empty when no client certificate is used
+func (t *DatastoreTLSOptions) PrivateKeyFile() string {
+	if t.PrivateKeyKey == "" {
+		return ""
+	}
+	return path.Join(t.mountPath(), v1.TLSPrivateKeyKey)
+}
+
+func (t *DatastoreTLSOptions) volume() v1.Volume {
+	items := []v1.KeyToPath{
+		v1.KeyToPath{Key: t.CAKey, Path: path.Base(t.CAFile())},
+	}
+	if t.CertKey != "" {
+		items = append(items, v1.KeyToPath{Key: t.CertKey, Path: path.Base(t.CertFile())})
+	}
+	if t.PrivateKeyKey != "" {
+		items = append(items, v1.KeyToPath{Key: t.PrivateKeyKey, Path: path.Base(t.PrivateKeyFile())})
+	}
+
+	return v1.Volume{
+		Name: t.Name,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{
+				SecretName: t.SecretName,
+				Items:      items,
+			},
+		},
+	}
+}
+
+func (t *DatastoreTLSOptions) volumeMount() v1.VolumeMount {
+	return v1.VolumeMount{
+		Name:      t.Name,
+		ReadOnly:  true,
+		MountPath: t.mountPath(),
+	}
+}
+
+// datastoreTLSEnvVars returns the environment variables pointing to the
+// TLS files of a datastore. The variables of the client certificate are
+// only set when it is used
+func datastoreTLSEnvVars(tls *DatastoreTLSOptions, sslEnvVar, caEnvVar, certEnvVar, privateKeyEnvVar string) []v1.EnvVar {
+	if tls == nil {
+		return nil
+	}
+
+	result := []v1.EnvVar{}
+	if sslEnvVar != "" {
+		result = append(result, envVarFromValue(sslEnvVar, "true"))
+	}
+	result = append(result, envVarFromValue(caEnvVar, tls.CAFile()))
+	if tls.CertFile() != "" {
+		result = append(result,
+			envVarFromValue(certEnvVar, tls.CertFile()),
+			envVarFromValue(privateKeyEnvVar, tls.PrivateKeyFile()),
+		)
+	}
+	return result
+}
+
+// addDatastoreTLS mounts the TLS files of the given datastores into all the
+// containers of the DeploymentConfig and adds the given environment
+// variables to them. The pre lifecycle hook gets them too. Nil options are
+// skipped, the DeploymentConfig is left untouched when all of them are nil
+func addDatastoreTLS(dc *appsv1.DeploymentConfig, envVars []v1.EnvVar, tlsOptions ...*DatastoreTLSOptions) {
+	hash := sha256.New()
+	volumes := []v1.Volume{}
+	volumeMounts := []v1.VolumeMount{}
+	for _, tls := range tlsOptions {
+		if tls == nil {
+			continue
+		}
+		fmt.Fprintf(hash, "%s\x00", tls.Hash)
+		volumes = append(volumes, tls.volume())
+		volumeMounts = append(volumeMounts, tls.volumeMount())
+	}
+	if len(volumes) == 0 {
+		return
+	}
+
+	podTemplate := dc.Spec.Template
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = map[string]string{}
+	}
+	podTemplate.Annotations[DatastoreTLSHashAnnotation] = fmt.Sprintf("%x", hash.Sum(nil))
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, volumes...)
+	for idx := range podTemplate.Spec.InitContainers {
+		container := &podTemplate.Spec.InitContainers[idx]
+		container.VolumeMounts = append(container.VolumeMounts, volumeMounts...)
+		container.Env = append(container.Env, envVars...)
+	}
+	for idx := range podTemplate.Spec.Containers {
+		container := &podTemplate.Spec.Containers[idx]
+		container.VolumeMounts = append(container.VolumeMounts, volumeMounts...)
+		container.Env = append(container.Env, envVars...)
+	}
+
+	if dc.Spec.Strategy.RollingParams != nil && dc.Spec.Strategy.RollingParams.Pre != nil && dc.Spec.Strategy.RollingParams.Pre.ExecNewPod != nil {
+		hook := dc.Spec.Strategy.RollingParams.Pre.ExecNewPod
+		hook.Env = append(hook.Env, envVars...)
+		for _, volume := range volumes {
+			hook.Volumes = append(hook.Volumes, volume.Name)
+		}
+	}
+}
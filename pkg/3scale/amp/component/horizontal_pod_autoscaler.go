@@ -0,0 +1,84 @@
+package component
+
+import (
+	appsv1 "github.com/openshift/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// HPA_DEFAULT_TARGET_CPU_UTILIZATION_PERCENTAGE is the CPU utilization
+	// target used when no target is given
+	HPA_DEFAULT_TARGET_CPU_UTILIZATION_PERCENTAGE = 80
+)
+
+type HorizontalPodAutoscalerOptions struct {
+	MinReplicas                       *int32
+	MaxReplicas                       int32
+	TargetCPUUtilizationPercentage    *int32
+	TargetMemoryUtilizationPercentage *int32
+}
+
+// HorizontalPodAutoscaler builds the HorizontalPodAutoscaler scaling the
+// given DeploymentConfig. It has the name and labels of the
+// DeploymentConfig
+func HorizontalPodAutoscaler(dc *appsv1.DeploymentConfig, options HorizontalPodAutoscalerOptions) *autoscalingv2beta1.HorizontalPodAutoscaler {
+	var minReplicas int32 = 1
+	if options.MinReplicas != nil {
+		minReplicas = *options.MinReplicas
+	}
+
+	labels := map[string]string{}
+	for k, v := range dc.Labels {
+		labels[k] = v
+	}
+
+	return &autoscalingv2beta1.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HorizontalPodAutoscaler",
+			APIVersion: "autoscaling/v2beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   dc.Name,
+			Labels: labels,
+		},
+		Spec: autoscalingv2beta1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta1.CrossVersionObjectReference{
+				Kind:       "DeploymentConfig",
+				Name:       dc.Name,
+				APIVersion: "apps.openshift.io/v1",
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: options.MaxReplicas,
+			Metrics:     horizontalPodAutoscalerMetrics(options),
+		},
+	}
+}
+
+func horizontalPodAutoscalerMetrics(options HorizontalPodAutoscalerOptions) []autoscalingv2beta1.MetricSpec {
+	targetCPUUtilizationPercentage := options.TargetCPUUtilizationPercentage
+	if targetCPUUtilizationPercentage == nil && options.TargetMemoryUtilizationPercentage == nil {
+		var defaultTargetCPUUtilizationPercentage int32 = HPA_DEFAULT_TARGET_CPU_UTILIZATION_PERCENTAGE
+		targetCPUUtilizationPercentage = &defaultTargetCPUUtilizationPercentage
+	}
+
+	metrics := []autoscalingv2beta1.MetricSpec{}
+	if targetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(v1.ResourceCPU, *targetCPUUtilizationPercentage))
+	}
+	if options.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(v1.ResourceMemory, *options.TargetMemoryUtilizationPercentage))
+	}
+	return metrics
+}
+
+func resourceMetric(resourceName v1.ResourceName, targetAverageUtilization int32) autoscalingv2beta1.MetricSpec {
+	return autoscalingv2beta1.MetricSpec{
+		Type: autoscalingv2beta1.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta1.ResourceMetricSource{
+			Name:                     resourceName,
+			TargetAverageUtilization: &targetAverageUtilization,
+		},
+	}
+}
@@ -1,5 +1,7 @@
 package component
 
+import "strings"
+
 func ApicastImageURL() string {
 	return "quay.io/3scale/apicast:nightly"
 }
@@ -39,3 +41,24 @@ func SystemMemcachedImageURL() string {
 func ZyncPostgreSQLImageURL() string {
 	return "centos/postgresql-10-centos7"
 }
+
+func CLIImageURL() string {
+	return "quay.io/openshift/origin-cli:4.2"
+}
+
+// RegistryMirrorImageURL replaces the registry of imageURL with mirror.
+// Images without registry are Docker Hub images, the mirror is prefixed.
+// Images already pulled from the mirror are not changed
+func RegistryMirrorImageURL(imageURL, mirror string) string {
+	mirror = strings.TrimSuffix(mirror, "/")
+	if strings.HasPrefix(imageURL, mirror+"/") {
+		return imageURL
+	}
+
+	repository := imageURL
+	parts := strings.SplitN(imageURL, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		repository = parts[1]
+	}
+	return mirror + "/" + repository
+}
@@ -0,0 +1,43 @@
+package component
+
+import (
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ingress returns an Ingress exposing the given service port on the
+// given host. It is the Kubernetes counterpart of the OpenShift Routes
+// exposing the components
+func ingress(name string, labels map[string]string, host, serviceName string, servicePort intstr.IntOrString) *extensions.Ingress {
+	return &extensions.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: extensions.IngressSpec{
+			Rules: []extensions.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{
+								{
+									Path: "/",
+									Backend: extensions.IngressBackend{
+										ServiceName: serviceName,
+										ServicePort: servicePort,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -76,6 +76,10 @@ func (m *Memcached) DeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "system", "threescale_component_element": "memcache", "app": m.Options.appLabel, "deploymentConfig": "system-memcache"},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       m.Options.podPlacement.NodeSelector,
+					Tolerations:        m.Options.podPlacement.Tolerations,
+					Affinity:           m.Options.podPlacement.Affinity,
+					PriorityClassName:  m.Options.podPlacement.PriorityClassName,
 					ServiceAccountName: "amp", //TODO make this configurable via flag
 					Containers: []v1.Container{
 						v1.Container{
@@ -13,6 +13,7 @@ type MemcachedOptions struct {
 
 	// memcached non-required options
 	resourceRequirements *v1.ResourceRequirements
+	podPlacement         *PodPlacement
 }
 
 type MemcachedOptionsBuilder struct {
@@ -27,6 +28,10 @@ func (m *MemcachedOptionsBuilder) ResourceRequirements(resourceRequirements v1.R
 	m.options.resourceRequirements = &resourceRequirements
 }
 
+func (m *MemcachedOptionsBuilder) PodPlacement(podPlacement PodPlacement) {
+	m.options.podPlacement = &podPlacement
+}
+
 func (m *MemcachedOptionsBuilder) Build() (*MemcachedOptions, error) {
 	err := m.setRequiredOptions()
 	if err != nil {
@@ -50,6 +55,10 @@ func (m *MemcachedOptionsBuilder) setNonRequiredOptions() {
 	if m.options.resourceRequirements == nil {
 		m.options.resourceRequirements = m.defaultResourceRequirements()
 	}
+
+	if m.options.podPlacement == nil {
+		m.options.podPlacement = &PodPlacement{}
+	}
 }
 
 func (m *MemcachedOptionsBuilder) defaultResourceRequirements() *v1.ResourceRequirements {
@@ -0,0 +1,277 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	MetricsPortName = "metrics"
+
+	BackendListenerMetricsPort int32 = 9394
+	BackendWorkerMetricsPort   int32 = 9421
+	SystemSidekiqMetricsPort   int32 = 9394
+	ZyncMetricsPort            int32 = 9393
+)
+
+const (
+	// The Prometheus operator objects are handled as unstructured objects,
+	// the operator does not depend on the monitoring stack being installed
+	MonitoringAPIVersion = "monitoring.coreos.com/v1"
+	PodMonitorKind       = "PodMonitor"
+	PrometheusRuleKind   = "PrometheusRule"
+
+	PrometheusRuleName = "threescale-alerts"
+
+	// GrafanaDashboardLabel is the label the Grafana dashboard sidecars look
+	// for in ConfigMaps
+	GrafanaDashboardLabel = "grafana_dashboard"
+)
+
+// MonitoredDeploymentConfigs are the DeploymentConfigs exposing a metrics
+// port when monitoring is enabled, by component
+var MonitoredDeploymentConfigs = []struct {
+	Component        string
+	DeploymentConfig string
+}{
+	{"apicast", "apicast-production"},
+	{"apicast", "apicast-staging"},
+	{"backend", "backend-listener"},
+	{"backend", "backend-worker"},
+	{"system", "system-sidekiq"},
+	{"zync", "zync"},
+	{"zync", "zync-que"},
+}
+
+type Monitoring struct {
+	Options *MonitoringOptions
+}
+
+func NewMonitoring(options *MonitoringOptions) *Monitoring {
+	return &Monitoring{Options: options}
+}
+
+// addMetricsPort exposes the named metrics port in the container of the
+// DeploymentConfig, with the environment variables enabling the metrics
+// endpoint
+func addMetricsPort(dc *appsv1.DeploymentConfig, port int32, envVars ...v1.EnvVar) {
+	container := &dc.Spec.Template.Spec.Containers[0]
+	container.Ports = append(container.Ports, v1.ContainerPort{
+		Name:          MetricsPortName,
+		ContainerPort: port,
+		Protocol:      v1.ProtocolTCP,
+	})
+	container.Env = append(container.Env, envVars...)
+}
+
+func (m *Monitoring) labels(component string) map[string]string {
+	labels := map[string]string{}
+	for k, v := range m.Options.labels {
+		labels[k] = v
+	}
+	labels["app"] = m.Options.appLabel
+	labels["threescale_component"] = component
+	return labels
+}
+
+func (m *Monitoring) unstructuredLabels(component string) map[string]interface{} {
+	labels := map[string]interface{}{}
+	for k, v := range m.labels(component) {
+		labels[k] = v
+	}
+	return labels
+}
+
+// PodMonitors returns a PodMonitor for each one of the
+// MonitoredDeploymentConfigs
+func (m *Monitoring) PodMonitors() []*unstructured.Unstructured {
+	podMonitors := []*unstructured.Unstructured{}
+	for _, monitored := range MonitoredDeploymentConfigs {
+		podMonitors = append(podMonitors, m.podMonitor(monitored.Component, monitored.DeploymentConfig))
+	}
+	return podMonitors
+}
+
+func (m *Monitoring) podMonitor(component, deploymentConfigName string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": MonitoringAPIVersion,
+			"kind":       PodMonitorKind,
+			"metadata": map[string]interface{}{
+				"name":   deploymentConfigName,
+				"labels": m.unstructuredLabels(component),
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"deploymentConfig": deploymentConfigName,
+					},
+				},
+				"podMetricsEndpoints": []interface{}{
+					map[string]interface{}{
+						"port": MetricsPortName,
+						"path": "/metrics",
+					},
+				},
+			},
+		},
+	}
+}
+
+// PrometheusRule returns the alerts of the APIManager components
+func (m *Monitoring) PrometheusRule() *unstructured.Unstructured {
+	ns := m.Options.namespace
+	rules := []interface{}{
+		alertingRule("ThreescaleApicastHigh5xxRate",
+			fmt.Sprintf(`sum(rate(apicast_status{namespace="%[1]s",status=~"5.."}[5m])) by (job) / sum(rate(apicast_status{namespace="%[1]s"}[5m])) by (job) > 0.05`, ns),
+			"5m", "warning",
+			"More than 5% of the requests served by {{ $labels.job }} return a 5xx status code"),
+		alertingRule("ThreescaleBackendWorkerQueueBacklog",
+			fmt.Sprintf(`sum(rate(apisonator_listener_response_codes{namespace="%[1]s"}[5m])) > 0 and sum(rate(apisonator_worker_job_count{namespace="%[1]s"}[5m])) == 0`, ns),
+			"10m", "critical",
+			"backend-listener is enqueueing jobs but backend-worker is not processing any, the backend-redis queue is growing"),
+		alertingRule("ThreescaleSidekiqJobsFailing",
+			fmt.Sprintf(`sum(increase(sidekiq_jobs_failed_total{namespace="%s"}[10m])) by (queue) > 10`, ns),
+			"5m", "warning",
+			"More than 10 jobs of the sidekiq queue {{ $labels.queue }} failed in the last 10 minutes"),
+		alertingRule("ThreescalePodNotReady",
+			fmt.Sprintf(`sum(kube_pod_status_ready{namespace="%s",condition="false"}) by (pod) > 0`, ns),
+			"10m", "critical",
+			"Pod {{ $labels.pod }} has not been ready for more than 10 minutes"),
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": MonitoringAPIVersion,
+			"kind":       PrometheusRuleKind,
+			"metadata": map[string]interface{}{
+				"name":   PrometheusRuleName,
+				"labels": m.unstructuredLabels("threescale"),
+			},
+			"spec": map[string]interface{}{
+				"groups": []interface{}{
+					map[string]interface{}{
+						"name":  "threescale.rules",
+						"rules": rules,
+					},
+				},
+			},
+		},
+	}
+}
+
+func alertingRule(name, expr, duration, severity, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"alert": name,
+		"expr":  expr,
+		"for":   duration,
+		"labels": map[string]interface{}{
+			"severity": severity,
+		},
+		"annotations": map[string]interface{}{
+			"message": message,
+		},
+	}
+}
+
+// GrafanaDashboards returns a ConfigMap with the Grafana dashboard of each
+// one of the components
+func (m *Monitoring) GrafanaDashboards() []*v1.ConfigMap {
+	ns := m.Options.namespace
+	return []*v1.ConfigMap{
+		m.grafanaDashboard("apicast", "APIcast", []dashboardPanel{
+			{"Requests by status", fmt.Sprintf(`sum(rate(apicast_status{namespace="%s"}[1m])) by (status)`, ns)},
+			{"Upstream response time p99", fmt.Sprintf(`histogram_quantile(0.99, sum(rate(upstream_response_time_seconds_bucket{namespace="%s"}[5m])) by (le))`, ns)},
+			{"Total response time p99", fmt.Sprintf(`histogram_quantile(0.99, sum(rate(total_response_time_seconds_bucket{namespace="%s"}[5m])) by (le))`, ns)},
+		}),
+		m.grafanaDashboard("backend", "Backend", []dashboardPanel{
+			{"Listener responses by code", fmt.Sprintf(`sum(rate(apisonator_listener_response_codes{namespace="%s"}[1m])) by (resp_code)`, ns)},
+			{"Listener response time p99", fmt.Sprintf(`histogram_quantile(0.99, sum(rate(apisonator_listener_response_times_bucket{namespace="%s"}[5m])) by (le))`, ns)},
+			{"Worker jobs by type", fmt.Sprintf(`sum(rate(apisonator_worker_job_count{namespace="%s"}[1m])) by (type)`, ns)},
+			{"Worker job runtime p99", fmt.Sprintf(`histogram_quantile(0.99, sum(rate(apisonator_worker_job_runtime_seconds_bucket{namespace="%s"}[5m])) by (le))`, ns)},
+		}),
+		m.grafanaDashboard("system", "System", []dashboardPanel{
+			{"Sidekiq executed jobs by queue", fmt.Sprintf(`sum(rate(sidekiq_jobs_executed_total{namespace="%s"}[1m])) by (queue)`, ns)},
+			{"Sidekiq failed jobs by queue", fmt.Sprintf(`sum(rate(sidekiq_jobs_failed_total{namespace="%s"}[1m])) by (queue)`, ns)},
+			{"Sidekiq enqueued jobs by queue", fmt.Sprintf(`sum(sidekiq_jobs_waiting_count{namespace="%s"}) by (queue)`, ns)},
+		}),
+		m.grafanaDashboard("zync", "Zync", []dashboardPanel{
+			{"Requests by status", fmt.Sprintf(`sum(rate(rails_requests_total{namespace="%s"}[1m])) by (status)`, ns)},
+			{"Que jobs by name", fmt.Sprintf(`sum(rate(que_jobs_processed_total{namespace="%s"}[1m])) by (job_name)`, ns)},
+		}),
+	}
+}
+
+type dashboardPanel struct {
+	Title string
+	Expr  string
+}
+
+func (m *Monitoring) grafanaDashboard(component, title string, panels []dashboardPanel) *v1.ConfigMap {
+	ns := m.Options.namespace
+	podRegex := fmt.Sprintf("%s-.*", component)
+	panels = append(panels,
+		dashboardPanel{"CPU usage by pod", fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace="%s",pod=~"%s",container!=""}[5m])) by (pod)`, ns, podRegex)},
+		dashboardPanel{"Memory usage by pod", fmt.Sprintf(`sum(container_memory_working_set_bytes{namespace="%s",pod=~"%s",container!=""}) by (pod)`, ns, podRegex)},
+	)
+
+	labels := m.labels(component)
+	labels[GrafanaDashboardLabel] = "1"
+
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   fmt.Sprintf("%s-grafana-dashboard", component),
+			Labels: labels,
+		},
+		Data: map[string]string{
+			fmt.Sprintf("%s.json", component): dashboardJSON(fmt.Sprintf("3scale %s (%s)", title, ns), panels),
+		},
+	}
+}
+
+func dashboardJSON(title string, panels []dashboardPanel) string {
+	grafanaPanels := []interface{}{}
+	for idx, panel := range panels {
+		grafanaPanels = append(grafanaPanels, map[string]interface{}{
+			"id":    idx + 1,
+			"type":  "graph",
+			"title": panel.Title,
+			"gridPos": map[string]interface{}{
+				"h": 8,
+				"w": 12,
+				"x": (idx % 2) * 12,
+				"y": (idx / 2) * 8,
+			},
+			"targets": []interface{}{
+				map[string]interface{}{
+					"expr":  panel.Expr,
+					"refId": "A",
+				},
+			},
+		})
+	}
+
+	dashboard := map[string]interface{}{
+		"title":         title,
+		"schemaVersion": 16,
+		"refresh":       "30s",
+		"time": map[string]interface{}{
+			"from": "now-6h",
+			"to":   "now",
+		},
+		"panels": grafanaPanels,
+	}
+
+	// marshalling maps of basic types cannot fail
+	data, _ := json.Marshal(dashboard)
+	return string(data)
+}
@@ -0,0 +1,61 @@
+package component
+
+import (
+	"fmt"
+)
+
+type MonitoringOptions struct {
+	// monitoringRequiredOptions
+	appLabel  string
+	namespace string
+
+	// monitoring non-required options
+	labels map[string]string
+}
+
+type MonitoringOptionsBuilder struct {
+	options MonitoringOptions
+}
+
+func (m *MonitoringOptionsBuilder) AppLabel(appLabel string) {
+	m.options.appLabel = appLabel
+}
+
+// Namespace sets the namespace the alerts and dashboards queries are
+// restricted to
+func (m *MonitoringOptionsBuilder) Namespace(namespace string) {
+	m.options.namespace = namespace
+}
+
+// Labels sets the labels added to all the monitoring objects
+func (m *MonitoringOptionsBuilder) Labels(labels map[string]string) {
+	m.options.labels = labels
+}
+
+func (m *MonitoringOptionsBuilder) Build() (*MonitoringOptions, error) {
+	err := m.setRequiredOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	m.setNonRequiredOptions()
+
+	return &m.options, nil
+}
+
+func (m *MonitoringOptionsBuilder) setRequiredOptions() error {
+	if m.options.appLabel == "" {
+		return fmt.Errorf("no AppLabel has been provided")
+	}
+	if m.options.namespace == "" {
+		return fmt.Errorf("no namespace has been provided")
+	}
+
+	return nil
+}
+
+func (m *MonitoringOptionsBuilder) setNonRequiredOptions() {
+	if m.options.labels == nil {
+		m.options.labels = map[string]string{}
+	}
+}
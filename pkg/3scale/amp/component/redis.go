@@ -138,6 +138,10 @@ func (redis *Redis) buildDeploymentConfigTriggers() appsv1.DeploymentTriggerPoli
 func (redis *Redis) buildPodTemplateSpec() *v1.PodTemplateSpec {
 	return &v1.PodTemplateSpec{
 		Spec: v1.PodSpec{
+			NodeSelector:       redis.Options.backendRedisPodPlacement.NodeSelector,
+			Tolerations:        redis.Options.backendRedisPodPlacement.Tolerations,
+			Affinity:           redis.Options.backendRedisPodPlacement.Affinity,
+			PriorityClassName:  redis.Options.backendRedisPodPlacement.PriorityClassName,
 			ServiceAccountName: "amp", //TODO make this configurable via flag
 			Volumes:            redis.buildPodVolumes(),
 			Containers:         redis.buildPodContainers(),
@@ -352,8 +356,13 @@ func (redis *Redis) buildConfigMapData() map[string]string {
 	}
 }
 
-func (redis *Redis) getRedisConfData() string { // TODO read this from a real file
-	return `protected-mode no
+func (redis *Redis) getRedisConfData() string {
+	return redisConfData
+}
+
+// redisConfData is the configuration of the backend and system redis
+// instances. TODO read this from a real file
+const redisConfData = `protected-mode no
 
 port 6379
 
@@ -399,7 +408,6 @@ activerehashing no
 aof-rewrite-incremental-fsync yes
 dir /var/lib/redis/data
 `
-}
 
 func (redis *Redis) BackendPVC() *v1.PersistentVolumeClaim {
 	return &v1.PersistentVolumeClaim{
@@ -544,6 +552,10 @@ func (redis *Redis) SystemDeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "system", "threescale_component_element": "redis", "app": redis.Options.appLabel, "deploymentConfig": "system-redis"},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       redis.Options.systemRedisPodPlacement.NodeSelector,
+					Tolerations:        redis.Options.systemRedisPodPlacement.Tolerations,
+					Affinity:           redis.Options.systemRedisPodPlacement.Affinity,
+					PriorityClassName:  redis.Options.systemRedisPodPlacement.PriorityClassName,
 					ServiceAccountName: "amp", //TODO make this configurable via flag
 					Volumes: []v1.Volume{
 						v1.Volume{
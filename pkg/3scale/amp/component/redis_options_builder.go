@@ -17,6 +17,8 @@ type RedisOptions struct {
 	// non-required options
 	backendRedisContainerResourceRequirements *v1.ResourceRequirements
 	systemRedisContainerResourceRequirements  *v1.ResourceRequirements
+	backendRedisPodPlacement                  *PodPlacement
+	systemRedisPodPlacement                   *PodPlacement
 	insecureImportPolicy                      bool
 }
 
@@ -36,6 +38,14 @@ func (r *RedisOptionsBuilder) BackendRedisContainerResourceRequirements(resource
 	r.options.backendRedisContainerResourceRequirements = &resourceRequirements
 }
 
+func (r *RedisOptionsBuilder) BackendRedisPodPlacement(podPlacement PodPlacement) {
+	r.options.backendRedisPodPlacement = &podPlacement
+}
+
+func (r *RedisOptionsBuilder) SystemRedisPodPlacement(podPlacement PodPlacement) {
+	r.options.systemRedisPodPlacement = &podPlacement
+}
+
 func (r *RedisOptionsBuilder) AMPRelease(ampRelease string) {
 	r.options.ampRelease = ampRelease
 }
@@ -91,6 +101,14 @@ func (r *RedisOptionsBuilder) setNonRequiredOptions() {
 	if r.options.systemRedisContainerResourceRequirements == nil {
 		r.options.systemRedisContainerResourceRequirements = r.defaultSystemRedisContainerResourceRequirements()
 	}
+
+	if r.options.backendRedisPodPlacement == nil {
+		r.options.backendRedisPodPlacement = &PodPlacement{}
+	}
+
+	if r.options.systemRedisPodPlacement == nil {
+		r.options.systemRedisPodPlacement = &PodPlacement{}
+	}
 }
 
 func (r *RedisOptionsBuilder) defaultBackendRedisContainerResourceRequirements() *v1.ResourceRequirements {
@@ -0,0 +1,476 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	RedisSentinelName         = "redis-sentinel"
+	RedisSentinelPort         = 26379
+	RedisSentinelRole         = "master"
+	redisSentinelConfigMapKey = "redis.conf"
+	redisSentinelConfigName   = "redis-sentinel-config"
+	redisSentinelDataDir      = "/var/lib/redis/sentinel"
+	redisServerCommand        = "/opt/rh/rh-redis32/root/usr/bin/redis-server"
+)
+
+// Connection URLs of the redis instances in Sentinel mode. The host is the
+// name of the master monitored by the sentinels
+const (
+	RedisSentinelBackendStorageURL = "redis://backend-redis/0"
+	RedisSentinelBackendQueuesURL  = "redis://backend-redis/1"
+	RedisSentinelSystemURL         = "redis://system-redis/1"
+	RedisSentinelMessageBusURL     = "redis://system-redis/8"
+)
+
+// RedisSentinel deploys backend-redis and system-redis as redis
+// master/replica StatefulSets and a set of sentinels monitoring both. The
+// sentinels promote a replica when a master fails
+type RedisSentinel struct {
+	Options *RedisSentinelOptions
+}
+
+// redisSentinelInstance describes one of the redis master/replica sets
+type redisSentinelInstance struct {
+	name                 string
+	component            string
+	image                string
+	resourceRequirements *v1.ResourceRequirements
+	podPlacement         *PodPlacement
+}
+
+func NewRedisSentinel(options *RedisSentinelOptions) *RedisSentinel {
+	return &RedisSentinel{Options: options}
+}
+
+func (redis *RedisSentinel) Objects() []common.KubernetesObject {
+	return []common.KubernetesObject{
+		redis.ConfigMap(),
+		redis.BackendStatefulSet(),
+		redis.BackendHeadlessService(),
+		redis.BackendService(),
+		redis.SystemStatefulSet(),
+		redis.SystemHeadlessService(),
+		redis.SystemService(),
+		redis.SentinelStatefulSet(),
+		redis.SentinelService(),
+	}
+}
+
+// SentinelHosts returns the sentinel URLs set in the backend-redis and
+// system-redis secrets
+func (redis *RedisSentinel) SentinelHosts() string {
+	hosts := []string{}
+	for i := int32(0); i < *redis.Options.sentinelReplicas; i++ {
+		hosts = append(hosts, fmt.Sprintf("redis://%s-%d.%s:%d", RedisSentinelName, i, RedisSentinelName, RedisSentinelPort))
+	}
+	return strings.Join(hosts, ",")
+}
+
+func (redis *RedisSentinel) backendInstance() redisSentinelInstance {
+	return redisSentinelInstance{
+		name:                 "backend-redis",
+		component:            "backend",
+		image:                redis.Options.backendImage,
+		resourceRequirements: redis.Options.backendRedisContainerResourceRequirements,
+		podPlacement:         redis.Options.backendRedisPodPlacement,
+	}
+}
+
+func (redis *RedisSentinel) systemInstance() redisSentinelInstance {
+	return redisSentinelInstance{
+		name:                 "system-redis",
+		component:            "system",
+		image:                redis.Options.systemImage,
+		resourceRequirements: redis.Options.systemRedisContainerResourceRequirements,
+		podPlacement:         redis.Options.systemRedisPodPlacement,
+	}
+}
+
+func (redis *RedisSentinel) BackendStatefulSet() *k8sappsv1.StatefulSet {
+	return redis.serverStatefulSet(redis.backendInstance())
+}
+
+func (redis *RedisSentinel) BackendHeadlessService() *v1.Service {
+	return redis.headlessService(redis.backendInstance())
+}
+
+func (redis *RedisSentinel) BackendService() *v1.Service {
+	return redis.service(redis.backendInstance())
+}
+
+func (redis *RedisSentinel) SystemStatefulSet() *k8sappsv1.StatefulSet {
+	return redis.serverStatefulSet(redis.systemInstance())
+}
+
+func (redis *RedisSentinel) SystemHeadlessService() *v1.Service {
+	return redis.headlessService(redis.systemInstance())
+}
+
+func (redis *RedisSentinel) SystemService() *v1.Service {
+	return redis.service(redis.systemInstance())
+}
+
+func (redis *RedisSentinel) ConfigMap() *v1.ConfigMap {
+	return &v1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   redisSentinelConfigName,
+			Labels: redis.labels("redis", "sentinel"),
+		},
+		Data: map[string]string{
+			redisSentinelConfigMapKey: redisConfData,
+		},
+	}
+}
+
+func (redis *RedisSentinel) labels(component, element string) map[string]string {
+	return map[string]string{
+		"app":                          redis.Options.appLabel,
+		"threescale_component":         component,
+		"threescale_component_element": element,
+	}
+}
+
+// RedisSentinelHeadlessServiceName returns the name of the governing
+// Service of the given redis master/replica set
+func RedisSentinelHeadlessServiceName(name string) string {
+	return name + "-headless"
+}
+
+// serverStatefulSet returns the redis master/replica set of the given
+// instance. On start, an instance asks the sentinels for the current
+// master and replicates it. When no sentinel knows the master yet, the
+// first pod of the set is the master
+func (redis *RedisSentinel) serverStatefulSet(instance redisSentinelInstance) *k8sappsv1.StatefulSet {
+	script := fmt.Sprintf(`set -e
+CONF=/tmp/redis.conf
+cp /etc/redis.d/%[5]s "$CONF"
+MASTER=""
+for SENTINEL in $(getent ahostsv4 %[2]s | awk '{print $1}' | sort -u); do
+  MASTER=$(redis-cli -h "$SENTINEL" -p %[3]d sentinel get-master-addr-by-name %[1]s | head -n 1 || true)
+  if [ -n "$MASTER" ]; then break; fi
+done
+if [ -z "$MASTER" ] && [ "${HOSTNAME##*-}" != "0" ]; then
+  until [ -n "$MASTER" ]; do
+    MASTER=$(getent ahostsv4 %[1]s-0.%[4]s | awk 'NR==1 {print $1}')
+    if [ -z "$MASTER" ]; then sleep 2; fi
+  done
+fi
+if [ -n "$MASTER" ] && [ "$MASTER" != "$POD_IP" ]; then
+  echo "slaveof $MASTER 6379" >> "$CONF"
+fi
+exec %[6]s "$CONF" --daemonize no
+`, instance.name, RedisSentinelName, RedisSentinelPort, RedisSentinelHeadlessServiceName(instance.name), redisSentinelConfigMapKey, redisServerCommand)
+
+	storageVolumeName := instance.name + "-storage"
+	labels := redis.labels(instance.component, "redis")
+	podLabels := redis.labels(instance.component, "redis")
+	podLabels["deploymentConfig"] = instance.name
+
+	return &k8sappsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   instance.name,
+			Labels: labels,
+		},
+		Spec: k8sappsv1.StatefulSetSpec{
+			Replicas:            redis.Options.replicas,
+			Selector:            &metav1.LabelSelector{MatchLabels: map[string]string{"deploymentConfig": instance.name}},
+			ServiceName:         RedisSentinelHeadlessServiceName(instance.name),
+			PodManagementPolicy: k8sappsv1.OrderedReadyPodManagement,
+			UpdateStrategy: k8sappsv1.StatefulSetUpdateStrategy{
+				Type: k8sappsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: v1.PodSpec{
+					NodeSelector:       instance.podPlacement.NodeSelector,
+					Tolerations:        instance.podPlacement.Tolerations,
+					Affinity:           instance.podPlacement.Affinity,
+					PriorityClassName:  instance.podPlacement.PriorityClassName,
+					ServiceAccountName: "amp",
+					Volumes: []v1.Volume{
+						redisSentinelConfigVolume(),
+					},
+					Containers: []v1.Container{
+						v1.Container{
+							Name:            instance.name,
+							Image:           instance.image,
+							ImagePullPolicy: v1.PullIfNotPresent,
+							Command:         []string{"container-entrypoint", "bash", "-c", script},
+							Env: []v1.EnvVar{
+								envVarFromFieldPath("POD_IP", "status.podIP"),
+							},
+							Ports: []v1.ContainerPort{
+								v1.ContainerPort{Name: "redis", ContainerPort: 6379, Protocol: v1.ProtocolTCP},
+							},
+							Resources: *instance.resourceRequirements,
+							VolumeMounts: []v1.VolumeMount{
+								v1.VolumeMount{
+									Name:      storageVolumeName,
+									MountPath: "/var/lib/redis/data",
+								},
+								v1.VolumeMount{
+									Name:      redisSentinelConfigName,
+									MountPath: "/etc/redis.d/",
+								},
+							},
+							LivenessProbe: &v1.Probe{
+								Handler: v1.Handler{TCPSocket: &v1.TCPSocketAction{
+									Port: intstr.FromInt(6379),
+								}},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+							},
+							ReadinessProbe: &v1.Probe{
+								Handler: v1.Handler{Exec: &v1.ExecAction{
+									Command: []string{"container-entrypoint", "bash", "-c", "redis-cli ping | grep PONG"},
+								}},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+								TimeoutSeconds:      5,
+							},
+						},
+					},
+				},
+			},
+			VolumeClaimTemplates: []v1.PersistentVolumeClaim{
+				v1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   storageVolumeName,
+						Labels: labels,
+					},
+					Spec: v1.PersistentVolumeClaimSpec{
+						AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+						Resources: v1.ResourceRequirements{
+							Requests: v1.ResourceList{
+								v1.ResourceStorage: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// headlessService returns the governing Service of the instance
+// StatefulSet. Not ready addresses are published so the replicas can
+// resolve the first pod while it starts
+func (redis *RedisSentinel) headlessService(instance redisSentinelInstance) *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   RedisSentinelHeadlessServiceName(instance.name),
+			Labels: redis.labels(instance.component, "redis"),
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP:                v1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Ports: []v1.ServicePort{
+				v1.ServicePort{
+					Name:       "redis",
+					Protocol:   v1.ProtocolTCP,
+					Port:       6379,
+					TargetPort: intstr.FromInt(6379),
+				},
+			},
+			Selector: map[string]string{"deploymentConfig": instance.name},
+		},
+	}
+}
+
+// service returns the Service balancing between all the instances of the
+// set, replicas included. The clients deployed by the operator, including
+// the backup Jobs and the backend-worker queue scaler, ask the sentinels
+// for the master instead
+func (redis *RedisSentinel) service(instance redisSentinelInstance) *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   instance.name,
+			Labels: redis.labels(instance.component, "redis"),
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				v1.ServicePort{
+					Name:       "redis",
+					Protocol:   v1.ProtocolTCP,
+					Port:       6379,
+					TargetPort: intstr.FromInt(6379),
+				},
+			},
+			Selector: map[string]string{"deploymentConfig": instance.name},
+		},
+	}
+}
+
+// SentinelStatefulSet returns the sentinels monitoring backend-redis and
+// system-redis. On start, a sentinel asks the other sentinels for the
+// current masters, defaulting to the first pod of each set
+func (redis *RedisSentinel) SentinelStatefulSet() *k8sappsv1.StatefulSet {
+	var script strings.Builder
+	fmt.Fprintf(&script, `set -e
+CONF=%[1]s/sentinel.conf
+echo "port %[2]d" > "$CONF"
+echo "dir /tmp" >> "$CONF"
+`, redisSentinelDataDir, RedisSentinelPort)
+	for _, instance := range []redisSentinelInstance{redis.backendInstance(), redis.systemInstance()} {
+		fmt.Fprintf(&script, `MASTER=""
+for SENTINEL in $(getent ahostsv4 %[2]s | awk '{print $1}' | sort -u); do
+  if [ "$SENTINEL" = "$POD_IP" ]; then continue; fi
+  MASTER=$(redis-cli -h "$SENTINEL" -p %[3]d sentinel get-master-addr-by-name %[1]s | head -n 1 || true)
+  if [ -n "$MASTER" ]; then break; fi
+done
+until [ -n "$MASTER" ]; do
+  MASTER=$(getent ahostsv4 %[1]s-0.%[4]s | awk 'NR==1 {print $1}')
+  if [ -z "$MASTER" ]; then sleep 2; fi
+done
+echo "sentinel monitor %[1]s $MASTER 6379 %[5]d" >> "$CONF"
+echo "sentinel down-after-milliseconds %[1]s 5000" >> "$CONF"
+echo "sentinel failover-timeout %[1]s 60000" >> "$CONF"
+echo "sentinel parallel-syncs %[1]s 1" >> "$CONF"
+`, instance.name, RedisSentinelName, RedisSentinelPort, RedisSentinelHeadlessServiceName(instance.name), *redis.Options.quorum)
+	}
+	fmt.Fprintf(&script, `exec %s "$CONF" --sentinel
+`, redisServerCommand)
+
+	podLabels := redis.labels("redis", "sentinel")
+	podLabels["deploymentConfig"] = RedisSentinelName
+
+	return &k8sappsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   RedisSentinelName,
+			Labels: redis.labels("redis", "sentinel"),
+		},
+		Spec: k8sappsv1.StatefulSetSpec{
+			Replicas:            redis.Options.sentinelReplicas,
+			Selector:            &metav1.LabelSelector{MatchLabels: map[string]string{"deploymentConfig": RedisSentinelName}},
+			ServiceName:         RedisSentinelName,
+			PodManagementPolicy: k8sappsv1.OrderedReadyPodManagement,
+			UpdateStrategy: k8sappsv1.StatefulSetUpdateStrategy{
+				Type: k8sappsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: v1.PodSpec{
+					NodeSelector:       redis.Options.sentinelPodPlacement.NodeSelector,
+					Tolerations:        redis.Options.sentinelPodPlacement.Tolerations,
+					Affinity:           redis.Options.sentinelPodPlacement.Affinity,
+					PriorityClassName:  redis.Options.sentinelPodPlacement.PriorityClassName,
+					ServiceAccountName: "amp",
+					Volumes: []v1.Volume{
+						v1.Volume{
+							Name:         RedisSentinelName,
+							VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+						},
+					},
+					Containers: []v1.Container{
+						v1.Container{
+							Name:            RedisSentinelName,
+							Image:           redis.Options.backendImage,
+							ImagePullPolicy: v1.PullIfNotPresent,
+							Command:         []string{"container-entrypoint", "bash", "-c", script.String()},
+							Env: []v1.EnvVar{
+								envVarFromFieldPath("POD_IP", "status.podIP"),
+							},
+							Ports: []v1.ContainerPort{
+								v1.ContainerPort{Name: "sentinel", ContainerPort: RedisSentinelPort, Protocol: v1.ProtocolTCP},
+							},
+							Resources: *redis.Options.sentinelContainerResourceRequirements,
+							VolumeMounts: []v1.VolumeMount{
+								v1.VolumeMount{
+									Name:      RedisSentinelName,
+									MountPath: redisSentinelDataDir,
+								},
+							},
+							LivenessProbe: &v1.Probe{
+								Handler: v1.Handler{TCPSocket: &v1.TCPSocketAction{
+									Port: intstr.FromInt(RedisSentinelPort),
+								}},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+							},
+							ReadinessProbe: &v1.Probe{
+								Handler: v1.Handler{Exec: &v1.ExecAction{
+									Command: []string{"container-entrypoint", "bash", "-c", fmt.Sprintf("redis-cli -p %d ping | grep PONG", RedisSentinelPort)},
+								}},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       10,
+								TimeoutSeconds:      5,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// SentinelService returns the governing Service of the sentinels. The
+// sentinel hosts of the secrets are the DNS names of its pods
+func (redis *RedisSentinel) SentinelService() *v1.Service {
+	return &v1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   RedisSentinelName,
+			Labels: redis.labels("redis", "sentinel"),
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Ports: []v1.ServicePort{
+				v1.ServicePort{
+					Name:       "sentinel",
+					Protocol:   v1.ProtocolTCP,
+					Port:       RedisSentinelPort,
+					TargetPort: intstr.FromInt(RedisSentinelPort),
+				},
+			},
+			Selector: map[string]string{"deploymentConfig": RedisSentinelName},
+		},
+	}
+}
+
+func redisSentinelConfigVolume() v1.Volume {
+	return v1.Volume{
+		Name: redisSentinelConfigName,
+		VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{
+			LocalObjectReference: v1.LocalObjectReference{
+				Name: redisSentinelConfigName,
+			},
+		}},
+	}
+}
@@ -0,0 +1,165 @@
+package component
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+type RedisSentinelOptions struct {
+	// required options
+	appLabel     string
+	backendImage string
+	systemImage  string
+
+	// non-required options
+	replicas                                  *int32
+	sentinelReplicas                          *int32
+	quorum                                    *int32
+	backendRedisContainerResourceRequirements *v1.ResourceRequirements
+	systemRedisContainerResourceRequirements  *v1.ResourceRequirements
+	sentinelContainerResourceRequirements     *v1.ResourceRequirements
+	backendRedisPodPlacement                  *PodPlacement
+	systemRedisPodPlacement                   *PodPlacement
+	sentinelPodPlacement                      *PodPlacement
+}
+
+type RedisSentinelOptionsBuilder struct {
+	options RedisSentinelOptions
+}
+
+func (r *RedisSentinelOptionsBuilder) AppLabel(appLabel string) {
+	r.options.appLabel = appLabel
+}
+
+func (r *RedisSentinelOptionsBuilder) BackendImage(image string) {
+	r.options.backendImage = image
+}
+
+func (r *RedisSentinelOptionsBuilder) SystemImage(image string) {
+	r.options.systemImage = image
+}
+
+// Replicas sets the number of redis instances of backend-redis and
+// system-redis, the master included
+func (r *RedisSentinelOptionsBuilder) Replicas(replicas int32) {
+	r.options.replicas = &replicas
+}
+
+func (r *RedisSentinelOptionsBuilder) SentinelReplicas(replicas int32) {
+	r.options.sentinelReplicas = &replicas
+}
+
+// Quorum sets the number of sentinels that need to agree a master is down
+func (r *RedisSentinelOptionsBuilder) Quorum(quorum int32) {
+	r.options.quorum = &quorum
+}
+
+func (r *RedisSentinelOptionsBuilder) BackendRedisContainerResourceRequirements(resourceRequirements v1.ResourceRequirements) {
+	r.options.backendRedisContainerResourceRequirements = &resourceRequirements
+}
+
+func (r *RedisSentinelOptionsBuilder) SystemRedisContainerResourceRequirements(resourceRequirements v1.ResourceRequirements) {
+	r.options.systemRedisContainerResourceRequirements = &resourceRequirements
+}
+
+func (r *RedisSentinelOptionsBuilder) SentinelContainerResourceRequirements(resourceRequirements v1.ResourceRequirements) {
+	r.options.sentinelContainerResourceRequirements = &resourceRequirements
+}
+
+func (r *RedisSentinelOptionsBuilder) BackendRedisPodPlacement(podPlacement PodPlacement) {
+	r.options.backendRedisPodPlacement = &podPlacement
+}
+
+func (r *RedisSentinelOptionsBuilder) SystemRedisPodPlacement(podPlacement PodPlacement) {
+	r.options.systemRedisPodPlacement = &podPlacement
+}
+
+func (r *RedisSentinelOptionsBuilder) SentinelPodPlacement(podPlacement PodPlacement) {
+	r.options.sentinelPodPlacement = &podPlacement
+}
+
+func (r *RedisSentinelOptionsBuilder) Build() (*RedisSentinelOptions, error) {
+	err := r.setRequiredOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	r.setNonRequiredOptions()
+
+	return &r.options, nil
+}
+
+func (r *RedisSentinelOptionsBuilder) setRequiredOptions() error {
+	if r.options.appLabel == "" {
+		return fmt.Errorf("no AppLabel has been provided")
+	}
+
+	if r.options.backendImage == "" {
+		return fmt.Errorf("no Backend Redis image has been provided")
+	}
+
+	if r.options.systemImage == "" {
+		return fmt.Errorf("no System Redis image has been provided")
+	}
+
+	return nil
+}
+
+func (r *RedisSentinelOptionsBuilder) setNonRequiredOptions() {
+	var defaultReplicas int32 = 3
+	var defaultSentinelReplicas int32 = 3
+	var defaultQuorum int32 = 2
+
+	if r.options.replicas == nil {
+		r.options.replicas = &defaultReplicas
+	}
+
+	if r.options.sentinelReplicas == nil {
+		r.options.sentinelReplicas = &defaultSentinelReplicas
+	}
+
+	if r.options.quorum == nil {
+		r.options.quorum = &defaultQuorum
+	}
+
+	// The redis instances default to the resources of the standalone ones
+	redisOptionsBuilder := RedisOptionsBuilder{}
+	if r.options.backendRedisContainerResourceRequirements == nil {
+		r.options.backendRedisContainerResourceRequirements = redisOptionsBuilder.defaultBackendRedisContainerResourceRequirements()
+	}
+
+	if r.options.systemRedisContainerResourceRequirements == nil {
+		r.options.systemRedisContainerResourceRequirements = redisOptionsBuilder.defaultSystemRedisContainerResourceRequirements()
+	}
+
+	if r.options.sentinelContainerResourceRequirements == nil {
+		r.options.sentinelContainerResourceRequirements = r.defaultSentinelContainerResourceRequirements()
+	}
+
+	if r.options.backendRedisPodPlacement == nil {
+		r.options.backendRedisPodPlacement = &PodPlacement{}
+	}
+
+	if r.options.systemRedisPodPlacement == nil {
+		r.options.systemRedisPodPlacement = &PodPlacement{}
+	}
+
+	if r.options.sentinelPodPlacement == nil {
+		r.options.sentinelPodPlacement = &PodPlacement{}
+	}
+}
+
+func (r *RedisSentinelOptionsBuilder) defaultSentinelContainerResourceRequirements() *v1.ResourceRequirements {
+	return &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("100m"),
+			v1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("50m"),
+			v1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+}
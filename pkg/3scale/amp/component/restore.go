@@ -0,0 +1,185 @@
+package component
+
+import (
+	"fmt"
+	"path"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// restoreRedisReplicaPort is the port of the temporary redis server
+	// loading the backup. The restored instance replicates from it
+	restoreRedisReplicaPort = 6380
+)
+
+// RestoredDeploymentConfigs are the deployments writing to the data
+// stores. They are scaled down while the data stores are restored and
+// scaled back up afterwards, so they read the restored data
+var RestoredDeploymentConfigs = []string{
+	"system-app",
+	"system-sidekiq",
+	"system-sphinx",
+	"backend-listener",
+	"backend-worker",
+	"backend-cron",
+	"zync",
+	"zync-que",
+}
+
+// APIManagerRestore restores the data stores and secrets of an APIManager
+// from the artifacts of an APIManagerBackup. Each step is run by its own Job
+type APIManagerRestore struct {
+	Options *BackupOptions
+}
+
+func NewAPIManagerRestore(options *BackupOptions) *APIManagerRestore {
+	return &APIManagerRestore{Options: options}
+}
+
+// ServiceAccountName returns the service account of the secrets restore
+// Job, allowed to create and update the secrets
+func (restore *APIManagerRestore) ServiceAccountName() string {
+	return "apimanager-restore-" + restore.Options.name
+}
+
+func (restore *APIManagerRestore) labels() map[string]string {
+	return map[string]string{
+		"app":                  restore.Options.appLabel,
+		"threescale_component": "apimanager-restore",
+		"apimanager-restore":   restore.Options.name,
+	}
+}
+
+func (restore *APIManagerRestore) ServiceAccount() *v1.ServiceAccount {
+	return &v1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   restore.ServiceAccountName(),
+			Labels: restore.labels(),
+		},
+	}
+}
+
+func (restore *APIManagerRestore) Role() *rbacv1.Role {
+	return &rbacv1.Role{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Role",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   restore.ServiceAccountName(),
+			Labels: restore.labels(),
+		},
+		Rules: []rbacv1.PolicyRule{
+			rbacv1.PolicyRule{
+				APIGroups: []string{""},
+				Resources: []string{"secrets"},
+				Verbs:     []string{"get", "create", "update", "patch"},
+			},
+		},
+	}
+}
+
+func (restore *APIManagerRestore) RoleBinding() *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   restore.ServiceAccountName(),
+			Labels: restore.labels(),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     restore.ServiceAccountName(),
+		},
+		Subjects: []rbacv1.Subject{
+			rbacv1.Subject{
+				Kind: "ServiceAccount",
+				Name: restore.ServiceAccountName(),
+			},
+		},
+	}
+}
+
+// Job returns the Job running the given restore step
+func (restore *APIManagerRestore) Job(step string) *batchv1.Job {
+	options := restore.Options
+	artifact := path.Join(backupVolumeMountPath, BackupArtifactPath(step))
+
+	switch step {
+	case appsv1alpha1.BackupStepSecrets:
+		// The secrets are created or replaced with the keys of the backup
+		script := fmt.Sprintf(`set -e
+for dir in %s/*; do
+  [ -d "$dir" ] || continue
+  oc create secret generic "$(basename "$dir")" -n "$NAMESPACE" --from-file="$dir" --dry-run -o yaml | oc apply -n "$NAMESPACE" -f -
+done
+`, artifact)
+		env := []v1.EnvVar{
+			envVarFromFieldPath("NAMESPACE", "metadata.namespace"),
+		}
+		container := backupJobContainer(step, options.cliImage, script, env)
+		return backupStepJob(options, "restore", step, container, nil, restore.ServiceAccountName())
+	case appsv1alpha1.BackupStepSystemDatabase:
+		env := []v1.EnvVar{
+			envVarFromSecret("DATABASE_URL", SystemSecretSystemDatabaseSecretName, SystemSecretSystemDatabaseURLFieldName),
+		}
+		script := postgreSQLRestoreScript(artifact)
+		if options.systemDatabaseType == BackupSystemDatabaseTypeMySQL {
+			script = fmt.Sprintf(`set -e
+%s
+mysql -h "$host" -P "$port" -u "$user" "$database" < %s
+`, parseMySQLURLScript, artifact)
+		}
+		return backupStepJob(options, "restore", step, backupJobContainer(step, options.systemDatabaseImage, script, env), nil, "")
+	case appsv1alpha1.BackupStepZyncDatabase:
+		env := []v1.EnvVar{
+			envVarFromSecret("DATABASE_URL", ZyncSecretName, ZyncSecretDatabaseURLFieldName),
+		}
+		return backupStepJob(options, "restore", step, backupJobContainer(step, options.zyncDatabaseImage, postgreSQLRestoreScript(artifact), env), nil, "")
+	case appsv1alpha1.BackupStepSystemRedis, appsv1alpha1.BackupStepBackendRedis, appsv1alpha1.BackupStepBackendRedisQueues:
+		// The dump is loaded by a temporary redis server in the Job pod. The
+		// restored instance replicates it and is promoted back once synced.
+		// The temporary server has no password
+		image, env := redisStepImageAndEnv(options, step)
+		env = append(env, envVarFromFieldPath("POD_IP", "status.podIP"))
+		script := fmt.Sprintf(`set -e
+%[1]s
+mkdir -p /tmp/restore && cp %[2]s /tmp/restore/dump.rdb
+redis-server --port %[3]d --dir /tmp/restore --dbfilename dump.rdb --save "" --appendonly no --daemonize yes
+until REDISCLI_AUTH= redis-cli -p %[3]d ping | grep -q PONG; do sleep 1; done
+$redis_cli slaveof "$POD_IP" %[3]d
+sleep 2
+until $redis_cli info replication | grep -q "master_link_status:up" && $redis_cli info replication | grep -q "master_sync_in_progress:0"; do sleep 1; done
+$redis_cli slaveof no one
+REDISCLI_AUTH= redis-cli -p %[3]d shutdown nosave || true
+`, parseRedisURLScript, artifact, restoreRedisReplicaPort)
+		return backupStepJob(options, "restore", step, backupJobContainer(step, image, script, env), nil, "")
+	case appsv1alpha1.BackupStepSystemStorage:
+		script := fmt.Sprintf(`set -e
+tar -xzf %s -C %s
+`, artifact, systemStorageMountDir)
+		container := backupJobContainer(step, options.systemImage, script, nil)
+		container.VolumeMounts = append(container.VolumeMounts, systemStorageVolumeMount(false))
+		return backupStepJob(options, "restore", step, container, []v1.Volume{systemStorageVolume()}, "")
+	}
+
+	return nil
+}
+
+func postgreSQLRestoreScript(artifact string) string {
+	return fmt.Sprintf(`set -e
+psql -v ON_ERROR_STOP=1 -d "$DATABASE_URL" -f %s
+`, artifact)
+}
@@ -68,3 +68,15 @@ func envVarFromSecretOptional(envVarName string, secretName, secretKey string) v
 		},
 	}
 }
+
+func envVarFromFieldPath(envVarName string, fieldPath string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: envVarName,
+		ValueFrom: &v1.EnvVarSource{
+			FieldRef: &v1.ObjectFieldSelector{
+				FieldPath:  fieldPath,
+				APIVersion: "v1",
+			},
+		},
+	}
+}
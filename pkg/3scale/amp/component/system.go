@@ -8,6 +8,7 @@ import (
 
 	appsv1 "github.com/openshift/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -87,6 +88,10 @@ const (
 	SystemSecretSystemSMTPOpenSSLVerifyModeFieldName = "openssl.verify.mode"
 )
 
+// SystemSMTPHashAnnotation is the pod template annotation holding the hash
+// of the SMTP settings set in the APIManager
+const SystemSMTPHashAnnotation = "apps.3scale.net/smtp-hash"
+
 const (
 	SystemFileStoragePVCName = "system-storage"
 )
@@ -303,6 +308,29 @@ func (system *System) buildSystemAppPreHookEnv() []v1.EnvVar {
 	return result
 }
 
+// addDatastoreTLS mounts the TLS files of the given external datastores
+// into the DeploymentConfig pods
+func (system *System) addDatastoreTLS(dc *appsv1.DeploymentConfig, databaseTLS, redisTLS, backendRedisTLS *DatastoreTLSOptions) {
+	envVars := []v1.EnvVar{}
+	envVars = append(envVars, datastoreTLSEnvVars(databaseTLS, "", "DATABASE_SSL_CA", "DATABASE_SSL_CERT", "DATABASE_SSL_KEY")...)
+	envVars = append(envVars, datastoreTLSEnvVars(redisTLS, "REDIS_SSL", "REDIS_CA_FILE", "REDIS_CLIENT_CERT", "REDIS_PRIVATE_KEY")...)
+	envVars = append(envVars, datastoreTLSEnvVars(backendRedisTLS, "BACKEND_REDIS_SSL", "BACKEND_REDIS_CA_FILE", "BACKEND_REDIS_CLIENT_CERT", "BACKEND_REDIS_PRIVATE_KEY")...)
+	addDatastoreTLS(dc, envVars, databaseTLS, redisTLS, backendRedisTLS)
+}
+
+// addSMTPHash annotates the pod template with the hash of the SMTP
+// settings. The SMTP environment variables are read from the system-smtp
+// secret, so the pods are only rolled out by the annotation changes
+func (system *System) addSMTPHash(dc *appsv1.DeploymentConfig) {
+	if system.Options.smtpSecretOptions.Hash == "" {
+		return
+	}
+	if dc.Spec.Template.Annotations == nil {
+		dc.Spec.Template.Annotations = map[string]string{}
+	}
+	dc.Spec.Template.Annotations[SystemSMTPHashAnnotation] = system.Options.smtpSecretOptions.Hash
+}
+
 func (system *System) BackendRedisEnvVars() []v1.EnvVar {
 	return []v1.EnvVar{
 		envVarFromSecret("BACKEND_REDIS_URL", BackendSecretBackendRedisSecretName, BackendSecretBackendRedisStorageURLFieldName),
@@ -546,7 +574,7 @@ func (system *System) volumeNamesForSystemAppPreHookPod() []string {
 }
 
 func (system *System) AppDeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -606,7 +634,11 @@ func (system *System) AppDeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "system", "threescale_component_element": "app", "app": system.Options.appLabel, "deploymentConfig": "system-app"},
 				},
 				Spec: v1.PodSpec{
-					Volumes: system.appPodVolumes(),
+					NodeSelector:      system.Options.appPodPlacement.NodeSelector,
+					Tolerations:       system.Options.appPodPlacement.Tolerations,
+					Affinity:          system.Options.appPodPlacement.Affinity,
+					PriorityClassName: system.Options.appPodPlacement.PriorityClassName,
+					Volumes:           system.appPodVolumes(),
 					Containers: []v1.Container{
 						v1.Container{
 							Name:  "system-master",
@@ -758,6 +790,9 @@ func (system *System) AppDeploymentConfig() *appsv1.DeploymentConfig {
 				}},
 		},
 	}
+	system.addDatastoreTLS(dc, system.Options.databaseTLS, system.Options.redisTLS, system.Options.backendRedisTLS)
+	system.addSMTPHash(dc)
+	return dc
 }
 
 func (system *System) FileStorageVolume() v1.Volume {
@@ -813,7 +848,7 @@ func (system *System) SidekiqPodVolumes() []v1.Volume {
 }
 
 func (system *System) SidekiqDeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -857,7 +892,11 @@ func (system *System) SidekiqDeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "system", "threescale_component_element": "sidekiq", "app": system.Options.appLabel, "deploymentConfig": "system-sidekiq"},
 				},
 				Spec: v1.PodSpec{
-					Volumes: system.SidekiqPodVolumes(),
+					NodeSelector:      system.Options.sidekiqPodPlacement.NodeSelector,
+					Tolerations:       system.Options.sidekiqPodPlacement.Tolerations,
+					Affinity:          system.Options.sidekiqPodPlacement.Affinity,
+					PriorityClassName: system.Options.sidekiqPodPlacement.PriorityClassName,
+					Volumes:           system.SidekiqPodVolumes(),
 					InitContainers: []v1.Container{
 						v1.Container{
 							Name:  "check-svc",
@@ -885,6 +924,12 @@ func (system *System) SidekiqDeploymentConfig() *appsv1.DeploymentConfig {
 				}},
 		},
 	}
+	system.addDatastoreTLS(dc, system.Options.databaseTLS, system.Options.redisTLS, system.Options.backendRedisTLS)
+	system.addSMTPHash(dc)
+	if system.Options.metricsEnabled {
+		addMetricsPort(dc, SystemSidekiqMetricsPort)
+	}
+	return dc
 }
 
 func (system *System) systemStorageVolumeMount(readOnly bool) v1.VolumeMount {
@@ -1189,7 +1234,7 @@ func (system *System) getSystemServiceDiscoveryData() string {
 }
 
 func (system *System) SphinxDeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -1250,6 +1295,10 @@ func (system *System) SphinxDeploymentConfig() *appsv1.DeploymentConfig {
 					},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       system.Options.sphinxPodPlacement.NodeSelector,
+					Tolerations:        system.Options.sphinxPodPlacement.Tolerations,
+					Affinity:           system.Options.sphinxPodPlacement.Affinity,
+					PriorityClassName:  system.Options.sphinxPodPlacement.PriorityClassName,
 					ServiceAccountName: "amp",
 					InitContainers: []v1.Container{
 						v1.Container{
@@ -1300,6 +1349,8 @@ func (system *System) SphinxDeploymentConfig() *appsv1.DeploymentConfig {
 			},
 		},
 	}
+	system.addDatastoreTLS(dc, system.Options.databaseTLS, system.Options.redisTLS, nil)
+	return dc
 }
 
 func (system *System) AppPodDisruptionBudget() *v1beta1.PodDisruptionBudget {
@@ -1347,3 +1398,33 @@ func (system *System) SidekiqPodDisruptionBudget() *v1beta1.PodDisruptionBudget
 		},
 	}
 }
+
+func (system *System) MasterIngress() *extensions.Ingress {
+	return ingress(
+		"system-master",
+		map[string]string{"app": system.Options.appLabel, "threescale_component": "system", "threescale_component_element": "master-ui"},
+		system.Options.masterName+"."+system.Options.wildcardDomain,
+		"system-master",
+		intstr.FromString("http"),
+	)
+}
+
+func (system *System) ProviderIngress() *extensions.Ingress {
+	return ingress(
+		"system-provider-admin",
+		map[string]string{"app": system.Options.appLabel, "threescale_component": "system", "threescale_component_element": "provider-ui"},
+		system.Options.tenantName+"-admin."+system.Options.wildcardDomain,
+		"system-provider",
+		intstr.FromString("http"),
+	)
+}
+
+func (system *System) DeveloperIngress() *extensions.Ingress {
+	return ingress(
+		"system-developer",
+		map[string]string{"app": system.Options.appLabel, "threescale_component": "system", "threescale_component_element": "developer-ui"},
+		system.Options.tenantName+"."+system.Options.wildcardDomain,
+		"system-developer",
+		intstr.FromString("http"),
+	)
+}
@@ -147,6 +147,10 @@ func (mysql *SystemMysql) DeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "system", "threescale_component_element": "mysql", "app": mysql.Options.appLabel, "deploymentConfig": "system-mysql"},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       mysql.Options.podPlacement.NodeSelector,
+					Tolerations:        mysql.Options.podPlacement.Tolerations,
+					Affinity:           mysql.Options.podPlacement.Affinity,
+					PriorityClassName:  mysql.Options.podPlacement.PriorityClassName,
 					ServiceAccountName: "amp", //TODO make this configurable via flag
 					Volumes: []v1.Volume{
 						v1.Volume{
@@ -18,6 +18,7 @@ type SystemMysqlOptions struct {
 
 	// non-required options
 	containerResourceRequirements *v1.ResourceRequirements
+	podPlacement                  *PodPlacement
 }
 
 type SystemMysqlOptionsBuilder struct {
@@ -52,6 +53,10 @@ func (m *SystemMysqlOptionsBuilder) ContainerResourceRequirements(resourceRequir
 	m.options.containerResourceRequirements = &resourceRequirements
 }
 
+func (m *SystemMysqlOptionsBuilder) PodPlacement(podPlacement PodPlacement) {
+	m.options.podPlacement = &podPlacement
+}
+
 func (m *SystemMysqlOptionsBuilder) Build() (*SystemMysqlOptions, error) {
 	err := m.setRequiredOptions()
 	if err != nil {
@@ -90,6 +95,10 @@ func (m *SystemMysqlOptionsBuilder) setNonRequiredOptions() {
 	if m.options.containerResourceRequirements == nil {
 		m.options.containerResourceRequirements = m.defaultContainerResourceRequirements()
 	}
+
+	if m.options.podPlacement == nil {
+		m.options.podPlacement = &PodPlacement{}
+	}
 }
 
 func (m *SystemMysqlOptionsBuilder) defaultContainerResourceRequirements() *v1.ResourceRequirements {
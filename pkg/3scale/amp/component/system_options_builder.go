@@ -19,6 +19,9 @@ type SystemSMTPSecretOptions struct {
 	Password          string
 	Port              string
 	Username          string
+	// Hash of the settings, rolling out the pods sending mail when they
+	// change. Empty when the settings are not managed by the operator
+	Hash string
 }
 
 type PVCFileStorageOptions struct {
@@ -46,6 +49,9 @@ type SystemOptions struct {
 	appDeveloperContainerResourceRequirements *v1.ResourceRequirements
 	sidekiqContainerResourceRequirements      *v1.ResourceRequirements
 	sphinxContainerResourceRequirements       *v1.ResourceRequirements
+	appPodPlacement                           *PodPlacement
+	sidekiqPodPlacement                       *PodPlacement
+	sphinxPodPlacement                        *PodPlacement
 
 	s3FileStorageOptions  *S3FileStorageOptions
 	pvcFileStorageOptions *PVCFileStorageOptions
@@ -53,6 +59,12 @@ type SystemOptions struct {
 	appReplicas     *int32
 	sidekiqReplicas *int32
 
+	databaseTLS     *DatastoreTLSOptions
+	redisTLS        *DatastoreTLSOptions
+	backendRedisTLS *DatastoreTLSOptions
+
+	metricsEnabled bool
+
 	// systemRequiredOptions
 	adminAccessToken    string
 	adminPassword       string
@@ -218,6 +230,41 @@ func (s *SystemOptionsBuilder) SphinxContainerResourceRequirements(resourceRequi
 	s.options.sphinxContainerResourceRequirements = &resourceRequirements
 }
 
+func (s *SystemOptionsBuilder) AppPodPlacement(podPlacement PodPlacement) {
+	s.options.appPodPlacement = &podPlacement
+}
+
+func (s *SystemOptionsBuilder) SidekiqPodPlacement(podPlacement PodPlacement) {
+	s.options.sidekiqPodPlacement = &podPlacement
+}
+
+func (s *SystemOptionsBuilder) SphinxPodPlacement(podPlacement PodPlacement) {
+	s.options.sphinxPodPlacement = &podPlacement
+}
+
+// DatabaseTLS sets the TLS files of the connection to the external system
+// database
+func (s *SystemOptionsBuilder) DatabaseTLS(tls DatastoreTLSOptions) {
+	s.options.databaseTLS = &tls
+}
+
+// RedisTLS sets the TLS files of the connection to the external system
+// redis
+func (s *SystemOptionsBuilder) RedisTLS(tls DatastoreTLSOptions) {
+	s.options.redisTLS = &tls
+}
+
+// BackendRedisTLS sets the TLS files of the connection to the external
+// backend redis
+func (s *SystemOptionsBuilder) BackendRedisTLS(tls DatastoreTLSOptions) {
+	s.options.backendRedisTLS = &tls
+}
+
+// MetricsEnabled exposes the Prometheus metrics of system-sidekiq
+func (s *SystemOptionsBuilder) MetricsEnabled(enabled bool) {
+	s.options.metricsEnabled = enabled
+}
+
 func (s *SystemOptionsBuilder) S3FileStorageOptions(options S3FileStorageOptions) {
 	s.options.s3FileStorageOptions = &options
 }
@@ -358,6 +405,18 @@ func (s *SystemOptionsBuilder) setNonRequiredOptions() {
 		var defaultSidekiqReplicas int32 = 1
 		s.options.sidekiqReplicas = &defaultSidekiqReplicas
 	}
+
+	if s.options.appPodPlacement == nil {
+		s.options.appPodPlacement = &PodPlacement{}
+	}
+
+	if s.options.sidekiqPodPlacement == nil {
+		s.options.sidekiqPodPlacement = &PodPlacement{}
+	}
+
+	if s.options.sphinxPodPlacement == nil {
+		s.options.sphinxPodPlacement = &PodPlacement{}
+	}
 }
 
 func (s *SystemOptionsBuilder) setRedisDefaultsOptions() {
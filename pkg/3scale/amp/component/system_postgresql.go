@@ -123,6 +123,10 @@ func (p *SystemPostgreSQL) DeploymentConfig() *appsv1.DeploymentConfig {
 					Labels: map[string]string{"threescale_component": "system", "threescale_component_element": "postgresql", "app": p.Options.appLabel, "deploymentConfig": "system-postgresql"},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       p.Options.podPlacement.NodeSelector,
+					Tolerations:        p.Options.podPlacement.Tolerations,
+					Affinity:           p.Options.podPlacement.Affinity,
+					PriorityClassName:  p.Options.podPlacement.PriorityClassName,
 					ServiceAccountName: "amp", //TODO make this configurable via flag
 					Volumes: []v1.Volume{
 						v1.Volume{
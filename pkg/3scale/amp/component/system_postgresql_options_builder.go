@@ -10,6 +10,7 @@ import (
 type SystemPostgreSQLOptions struct {
 	//systemPostgreSQLNonRequiredOptions
 	containerResourceRequirements *v1.ResourceRequirements
+	podPlacement                  *PodPlacement
 
 	//systemPostgreSQLRequiredOptions
 	ampRelease   string
@@ -49,6 +50,10 @@ func (b *SystemPostgreSQLOptionsBuilder) ContainerResourceRequirements(resourceR
 	b.options.containerResourceRequirements = &resourceRequirements
 }
 
+func (b *SystemPostgreSQLOptionsBuilder) PodPlacement(podPlacement PodPlacement) {
+	b.options.podPlacement = &podPlacement
+}
+
 func (b *SystemPostgreSQLOptionsBuilder) Build() (*SystemPostgreSQLOptions, error) {
 	err := b.setRequiredOptions()
 	if err != nil {
@@ -84,6 +89,10 @@ func (b *SystemPostgreSQLOptionsBuilder) setNonRequiredOptions() {
 	if b.options.containerResourceRequirements == nil {
 		b.options.containerResourceRequirements = b.defaultContainerResourceRequirements()
 	}
+
+	if b.options.podPlacement == nil {
+		b.options.podPlacement = &PodPlacement{}
+	}
 }
 
 func (m *SystemPostgreSQLOptionsBuilder) defaultContainerResourceRequirements() *v1.ResourceRequirements {
@@ -0,0 +1,224 @@
+package component
+
+import (
+	"fmt"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImageStreamTagImages returns the image each one of the tags of the given
+// ImageStreams points to, indexed by ImageStreamTag name ("<imagestream>:<tag>").
+// Tags referencing other tags of the same ImageStream are resolved
+func ImageStreamTagImages(imageStreams ...*imagev1.ImageStream) map[string]string {
+	images := map[string]string{}
+	for _, imageStream := range imageStreams {
+		tags := map[string]*v1.ObjectReference{}
+		for idx := range imageStream.Spec.Tags {
+			tags[imageStream.Spec.Tags[idx].Name] = imageStream.Spec.Tags[idx].From
+		}
+
+		for tagName := range tags {
+			from := tags[tagName]
+			// Follow tag references within the ImageStream. The number of hops
+			// is bounded to protect against reference cycles
+			for hops := 0; from != nil && from.Kind == "ImageStreamTag" && hops < len(tags); hops++ {
+				from = tags[from.Name]
+			}
+			if from != nil && from.Kind == "DockerImage" {
+				images[fmt.Sprintf("%s:%s", imageStream.Name, tagName)] = from.Name
+			}
+		}
+	}
+	return images
+}
+
+// IsStatefulDeploymentConfig returns true when the DeploymentConfig runs a
+// datastore. Those are deployed with the Recreate strategy
+func IsStatefulDeploymentConfig(dc *appsv1.DeploymentConfig) bool {
+	return dc.Spec.Strategy.Type == appsv1.DeploymentStrategyTypeRecreate
+}
+
+// DeploymentFromDeploymentConfig builds the Kubernetes Deployment equivalent
+// to the given DeploymentConfig. ImageStreamTag references are replaced by
+// the plain images found in the images map. The rolling strategy pre
+// lifecycle hook is run as an init container. Other lifecycle hooks have
+// no Deployment equivalent and are not kept
+func DeploymentFromDeploymentConfig(dc *appsv1.DeploymentConfig, images map[string]string) *k8sappsv1.Deployment {
+	replicas := dc.Spec.Replicas
+	deployment := &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: workloadObjectMeta(dc),
+		Spec: k8sappsv1.DeploymentSpec{
+			Replicas:        &replicas,
+			Selector:        &metav1.LabelSelector{MatchLabels: dc.Spec.Selector},
+			Template:        workloadPodTemplate(dc, images),
+			MinReadySeconds: dc.Spec.MinReadySeconds,
+		},
+	}
+
+	if dc.Spec.Strategy.Type == appsv1.DeploymentStrategyTypeRecreate {
+		deployment.Spec.Strategy = k8sappsv1.DeploymentStrategy{Type: k8sappsv1.RecreateDeploymentStrategyType}
+	} else {
+		deployment.Spec.Strategy = k8sappsv1.DeploymentStrategy{Type: k8sappsv1.RollingUpdateDeploymentStrategyType}
+		if rollingParams := dc.Spec.Strategy.RollingParams; rollingParams != nil {
+			deployment.Spec.Strategy.RollingUpdate = &k8sappsv1.RollingUpdateDeployment{
+				MaxSurge:       rollingParams.MaxSurge,
+				MaxUnavailable: rollingParams.MaxUnavailable,
+			}
+			if rollingParams.TimeoutSeconds != nil {
+				progressDeadline := int32(*rollingParams.TimeoutSeconds)
+				deployment.Spec.ProgressDeadlineSeconds = &progressDeadline
+			}
+		}
+	}
+
+	return deployment
+}
+
+// StatefulSetFromDeploymentConfig builds the Kubernetes StatefulSet
+// equivalent to the given DeploymentConfig. The StatefulSet keeps using
+// the PersistentVolumeClaims referenced by the DeploymentConfig volumes
+func StatefulSetFromDeploymentConfig(dc *appsv1.DeploymentConfig, images map[string]string) *k8sappsv1.StatefulSet {
+	replicas := dc.Spec.Replicas
+	return &k8sappsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: workloadObjectMeta(dc),
+		Spec: k8sappsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			Selector:    &metav1.LabelSelector{MatchLabels: dc.Spec.Selector},
+			Template:    workloadPodTemplate(dc, images),
+			ServiceName: dc.Name,
+			UpdateStrategy: k8sappsv1.StatefulSetUpdateStrategy{
+				Type: k8sappsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			PodManagementPolicy: k8sappsv1.OrderedReadyPodManagement,
+		},
+	}
+}
+
+func workloadObjectMeta(dc *appsv1.DeploymentConfig) metav1.ObjectMeta {
+	objectMeta := metav1.ObjectMeta{
+		Name:        dc.Name,
+		Namespace:   dc.Namespace,
+		Labels:      map[string]string{},
+		Annotations: map[string]string{},
+	}
+	for k, v := range dc.Labels {
+		objectMeta.Labels[k] = v
+	}
+	for k, v := range dc.Annotations {
+		objectMeta.Annotations[k] = v
+	}
+	return objectMeta
+}
+
+func workloadPodTemplate(dc *appsv1.DeploymentConfig, images map[string]string) v1.PodTemplateSpec {
+	template := v1.PodTemplateSpec{}
+	if dc.Spec.Template != nil {
+		dc.Spec.Template.DeepCopyInto(&template)
+	}
+
+	// Containers updated by ImageChange triggers are given the plain image
+	// the trigger ImageStreamTag points to
+	containerImages := map[string]string{}
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.Type != appsv1.DeploymentTriggerOnImageChange || trigger.ImageChangeParams == nil {
+			continue
+		}
+		image, ok := images[trigger.ImageChangeParams.From.Name]
+		if !ok {
+			continue
+		}
+		for _, containerName := range trigger.ImageChangeParams.ContainerNames {
+			containerImages[containerName] = image
+		}
+	}
+
+	setContainerImages := func(containers []v1.Container) {
+		for idx := range containers {
+			container := &containers[idx]
+			if image, ok := containerImages[container.Name]; ok {
+				container.Image = image
+			} else if image, ok := images[container.Image]; ok {
+				container.Image = image
+			}
+		}
+	}
+
+	if preHookContainer := preHookInitContainer(dc, &template); preHookContainer != nil {
+		template.Spec.InitContainers = append(template.Spec.InitContainers, *preHookContainer)
+	}
+
+	setContainerImages(template.Spec.InitContainers)
+	setContainerImages(template.Spec.Containers)
+
+	return template
+}
+
+// preHookInitContainer returns an init container running the rolling
+// strategy pre lifecycle hook of the DeploymentConfig, or nil if there is
+// no such hook
+func preHookInitContainer(dc *appsv1.DeploymentConfig, template *v1.PodTemplateSpec) *v1.Container {
+	rollingParams := dc.Spec.Strategy.RollingParams
+	if rollingParams == nil || rollingParams.Pre == nil || rollingParams.Pre.ExecNewPod == nil {
+		return nil
+	}
+	hook := rollingParams.Pre.ExecNewPod
+
+	for _, container := range template.Spec.Containers {
+		if container.Name != hook.ContainerName {
+			continue
+		}
+
+		initContainer := container.DeepCopy()
+		initContainer.Name = fmt.Sprintf("%s-pre-hook", container.Name)
+		initContainer.Command = hook.Command
+		initContainer.Args = nil
+		initContainer.Ports = nil
+		initContainer.LivenessProbe = nil
+		initContainer.ReadinessProbe = nil
+		initContainer.Env = mergeEnvVars(initContainer.Env, hook.Env)
+
+		hookVolumes := map[string]bool{}
+		for _, volumeName := range hook.Volumes {
+			hookVolumes[volumeName] = true
+		}
+		volumeMounts := []v1.VolumeMount{}
+		for _, volumeMount := range initContainer.VolumeMounts {
+			if hookVolumes[volumeMount.Name] {
+				volumeMounts = append(volumeMounts, volumeMount)
+			}
+		}
+		initContainer.VolumeMounts = volumeMounts
+
+		return initContainer
+	}
+
+	return nil
+}
+
+// mergeEnvVars returns the base env vars overridden and extended with the
+// given overrides
+func mergeEnvVars(base, overrides []v1.EnvVar) []v1.EnvVar {
+	result := []v1.EnvVar{}
+	overridden := map[string]bool{}
+	for _, envVar := range overrides {
+		overridden[envVar.Name] = true
+	}
+	for _, envVar := range base {
+		if !overridden[envVar.Name] {
+			result = append(result, envVar)
+		}
+	}
+	return append(result, overrides...)
+}
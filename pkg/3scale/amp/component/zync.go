@@ -99,11 +99,11 @@ func (zync *Zync) QueServiceAccount() *v1.ServiceAccount {
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "zync-que-sa",
 		},
-		ImagePullSecrets: []v1.LocalObjectReference{
+		ImagePullSecrets: append([]v1.LocalObjectReference{
 			v1.LocalObjectReference{
 				Name: "threescale-registry-auth",
 			},
-		},
+		}, zync.Options.imagePullSecrets...),
 	}
 }
 
@@ -198,7 +198,7 @@ func (zync *Zync) QueRole() *rbacv1.Role {
 }
 
 func (zync *Zync) DeploymentConfig() *appsv1.DeploymentConfig {
-	return &appsv1.DeploymentConfig{
+	dc := &appsv1.DeploymentConfig{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "DeploymentConfig",
 			APIVersion: "apps.openshift.io/v1",
@@ -245,6 +245,10 @@ func (zync *Zync) DeploymentConfig() *appsv1.DeploymentConfig {
 					},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       zync.Options.podPlacement.NodeSelector,
+					Tolerations:        zync.Options.podPlacement.Tolerations,
+					Affinity:           zync.Options.podPlacement.Affinity,
+					PriorityClassName:  zync.Options.podPlacement.PriorityClassName,
 					ServiceAccountName: "amp",
 					InitContainers: []v1.Container{
 						v1.Container{
@@ -318,6 +322,10 @@ func (zync *Zync) DeploymentConfig() *appsv1.DeploymentConfig {
 			},
 		},
 	}
+	if zync.Options.metricsEnabled {
+		addMetricsPort(dc, ZyncMetricsPort)
+	}
+	return dc
 }
 
 func (zync *Zync) commonZyncEnvVars() []v1.EnvVar {
@@ -409,6 +417,10 @@ func (zync *Zync) QueDeploymentConfig() *appsv1.DeploymentConfig {
 					},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:                  zync.Options.quePodPlacement.NodeSelector,
+					Tolerations:                   zync.Options.quePodPlacement.Tolerations,
+					Affinity:                      zync.Options.quePodPlacement.Affinity,
+					PriorityClassName:             zync.Options.quePodPlacement.PriorityClassName,
 					ServiceAccountName:            "zync-que-sa",
 					RestartPolicy:                 v1.RestartPolicyAlways,
 					TerminationGracePeriodSeconds: &[]int64{30}[0],
@@ -494,6 +506,10 @@ func (zync *Zync) DatabaseDeploymentConfig() *appsv1.DeploymentConfig {
 					},
 				},
 				Spec: v1.PodSpec{
+					NodeSelector:       zync.Options.databasePodPlacement.NodeSelector,
+					Tolerations:        zync.Options.databasePodPlacement.Tolerations,
+					Affinity:           zync.Options.databasePodPlacement.Affinity,
+					PriorityClassName:  zync.Options.databasePodPlacement.PriorityClassName,
 					RestartPolicy:      v1.RestartPolicyAlways,
 					ServiceAccountName: "amp",
 					Containers: []v1.Container{
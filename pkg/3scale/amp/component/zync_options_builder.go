@@ -13,8 +13,13 @@ type ZyncOptions struct {
 	containerResourceRequirements         *v1.ResourceRequirements
 	queContainerResourceRequirements      *v1.ResourceRequirements
 	databaseContainerResourceRequirements *v1.ResourceRequirements
+	podPlacement                          *PodPlacement
+	quePodPlacement                       *PodPlacement
+	databasePodPlacement                  *PodPlacement
 	zyncReplicas                          *int32
 	zyncQueReplicas                       *int32
+	metricsEnabled                        bool
+	imagePullSecrets                      []v1.LocalObjectReference
 
 	// zyncRequiredOptions
 	appLabel            string
@@ -59,6 +64,22 @@ func (z *ZyncOptionsBuilder) DatabaseContainerResourceRequirements(resourceRequi
 	z.options.databaseContainerResourceRequirements = &resourceRequirements
 }
 
+func (z *ZyncOptionsBuilder) PodPlacement(podPlacement PodPlacement) {
+	z.options.podPlacement = &podPlacement
+}
+
+func (z *ZyncOptionsBuilder) QuePodPlacement(podPlacement PodPlacement) {
+	z.options.quePodPlacement = &podPlacement
+}
+
+func (z *ZyncOptionsBuilder) DatabasePodPlacement(podPlacement PodPlacement) {
+	z.options.databasePodPlacement = &podPlacement
+}
+
+func (z *ZyncOptionsBuilder) ImagePullSecrets(imagePullSecrets []v1.LocalObjectReference) {
+	z.options.imagePullSecrets = imagePullSecrets
+}
+
 func (z *ZyncOptionsBuilder) ZyncReplicas(replicas int32) {
 	z.options.zyncReplicas = &replicas
 }
@@ -67,6 +88,11 @@ func (z *ZyncOptionsBuilder) ZyncQueReplicas(replicas int32) {
 	z.options.zyncQueReplicas = &replicas
 }
 
+// MetricsEnabled names the Prometheus metrics port of zync
+func (z *ZyncOptionsBuilder) MetricsEnabled(enabled bool) {
+	z.options.metricsEnabled = enabled
+}
+
 func (z *ZyncOptionsBuilder) Build() (*ZyncOptions, error) {
 	err := z.setRequiredOptions()
 	if err != nil {
@@ -122,6 +148,18 @@ func (z *ZyncOptionsBuilder) setNonRequiredOptions() {
 		var defaultZyncQueReplicas int32 = 1
 		z.options.zyncQueReplicas = &defaultZyncQueReplicas
 	}
+
+	if z.options.podPlacement == nil {
+		z.options.podPlacement = &PodPlacement{}
+	}
+
+	if z.options.quePodPlacement == nil {
+		z.options.quePodPlacement = &PodPlacement{}
+	}
+
+	if z.options.databasePodPlacement == nil {
+		z.options.databasePodPlacement = &PodPlacement{}
+	}
 }
 
 func (z *ZyncOptionsBuilder) defaultContainerResourceRequirements() *v1.ResourceRequirements {
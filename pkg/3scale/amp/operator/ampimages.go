@@ -13,42 +13,43 @@ func (o *OperatorAmpImagesOptionsProvider) GetAmpImagesOptions() (*component.Amp
 	optProv.AppLabel(*o.APIManagerSpec.AppLabel)
 	optProv.AMPRelease(product.ThreescaleRelease)
 	if o.APIManagerSpec.Apicast != nil && o.APIManagerSpec.Apicast.Image != nil {
-		optProv.ApicastImage(*o.APIManagerSpec.Apicast.Image)
+		optProv.ApicastImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.Apicast.Image))
 	} else {
-		optProv.ApicastImage(ApicastImageURL())
+		optProv.ApicastImage(mirroredImageURL(o.APIManagerSpec, ApicastImageURL()))
 	}
 
 	if o.APIManagerSpec.Backend != nil && o.APIManagerSpec.Backend.Image != nil {
-		optProv.BackendImage(*o.APIManagerSpec.Backend.Image)
+		optProv.BackendImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.Backend.Image))
 	} else {
-		optProv.BackendImage(BackendImageURL())
+		optProv.BackendImage(mirroredImageURL(o.APIManagerSpec, BackendImageURL()))
 	}
 
 	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.Image != nil {
-		optProv.SystemImage(*o.APIManagerSpec.System.Image)
+		optProv.SystemImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.System.Image))
 	} else {
-		optProv.SystemImage(SystemImageURL())
+		optProv.SystemImage(mirroredImageURL(o.APIManagerSpec, SystemImageURL()))
 	}
 
 	if o.APIManagerSpec.Zync != nil && o.APIManagerSpec.Zync.Image != nil {
-		optProv.ZyncImage(*o.APIManagerSpec.Zync.Image)
+		optProv.ZyncImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.Zync.Image))
 	} else {
-		optProv.ZyncImage(ZyncImageURL())
+		optProv.ZyncImage(mirroredImageURL(o.APIManagerSpec, ZyncImageURL()))
 	}
 
 	if o.APIManagerSpec.Zync != nil && o.APIManagerSpec.Zync.PostgreSQLImage != nil {
-		optProv.ZyncDatabasePostgreSQLImage(*o.APIManagerSpec.Zync.PostgreSQLImage)
+		optProv.ZyncDatabasePostgreSQLImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.Zync.PostgreSQLImage))
 	} else {
-		optProv.ZyncDatabasePostgreSQLImage(ZyncPostgreSQLImageURL())
+		optProv.ZyncDatabasePostgreSQLImage(mirroredImageURL(o.APIManagerSpec, ZyncPostgreSQLImageURL()))
 	}
 
 	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.MemcachedImage != nil {
-		optProv.SystemMemcachedImage(*o.APIManagerSpec.System.MemcachedImage)
+		optProv.SystemMemcachedImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.System.MemcachedImage))
 	} else {
-		optProv.SystemMemcachedImage(SystemMemcachedImageURL())
+		optProv.SystemMemcachedImage(mirroredImageURL(o.APIManagerSpec, SystemMemcachedImageURL()))
 	}
 
 	optProv.InsecureImportPolicy(*o.APIManagerSpec.ImageStreamTagImportInsecure)
+	optProv.ImagePullSecrets(o.APIManagerSpec.ImagePullSecrets)
 	res, err := optProv.Build()
 	if err != nil {
 		return nil, fmt.Errorf("unable to create AMPImages Options - %s", err)
@@ -1,7 +1,10 @@
 package operator
 
 import (
+	"fmt"
+
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	imagev1 "github.com/openshift/api/image/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -21,40 +24,36 @@ func NewAMPImagesReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicRec
 	}
 }
 
+// Reconcile reconciles the ImageStreams of the components. ImageStream
+// changes roll out the DeploymentConfigs triggered by them, so the
+// ImageStreams of paused components are skipped
 func (r *AMPImagesReconciler) Reconcile() (reconcile.Result, error) {
 	ampImages, err := r.ampImages()
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileBackendImageStream(ampImages.BackendImageStream())
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	err = r.reconcileZyncImageStream(ampImages.ZyncImageStream())
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	err = r.reconcileApicastImageStream(ampImages.APICastImageStream())
-	if err != nil {
-		return reconcile.Result{}, err
+	imageStreams := []struct {
+		component   appsv1alpha1.APIManagerComponentName
+		imageStream *imagev1.ImageStream
+	}{
+		{appsv1alpha1.APIManagerBackendComponent, ampImages.BackendImageStream()},
+		{appsv1alpha1.APIManagerZyncComponent, ampImages.ZyncImageStream()},
+		{appsv1alpha1.APIManagerApicastComponent, ampImages.APICastImageStream()},
+		{appsv1alpha1.APIManagerSystemComponent, ampImages.SystemImageStream()},
+		// zync-database is reconciled with zync
+		{appsv1alpha1.APIManagerZyncComponent, ampImages.ZyncDatabasePostgreSQLImageStream()},
+		{appsv1alpha1.APIManagerSystemComponent, ampImages.SystemMemcachedImageStream()},
 	}
-
-	err = r.reconcileSystemImageStream(ampImages.SystemImageStream())
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	err = r.reconcileZyncDatabasePostgreSQLImageStream(ampImages.ZyncDatabasePostgreSQLImageStream())
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	err = r.reconcileSystemMemcachedImageStream(ampImages.SystemMemcachedImageStream())
-	if err != nil {
-		return reconcile.Result{}, err
+	for _, item := range imageStreams {
+		if r.apiManager.IsComponentPaused(item.component) {
+			r.Logger().Info(fmt.Sprintf("Reconciliation of %s paused, skipping %s", item.component, ObjectInfo(item.imageStream)))
+			continue
+		}
+		err = r.reconcileImageStream(item.imageStream)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	err = r.reconcileDeploymentsServiceAccount(ampImages.DeploymentsServiceAccount())
@@ -75,37 +74,12 @@ func (r *AMPImagesReconciler) ampImages() (*component.AmpImages, error) {
 	return component.NewAmpImages(opts), nil
 }
 
-func (r *AMPImagesReconciler) reconcileBackendImageStream(desiredImageStream *imagev1.ImageStream) error {
-	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
-	return reconciler.Reconcile(desiredImageStream)
-}
-
-func (r *AMPImagesReconciler) reconcileZyncImageStream(desiredImageStream *imagev1.ImageStream) error {
-	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
-	return reconciler.Reconcile(desiredImageStream)
-}
-
-func (r *AMPImagesReconciler) reconcileApicastImageStream(desiredImageStream *imagev1.ImageStream) error {
-	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
-	return reconciler.Reconcile(desiredImageStream)
-}
-
-func (r *AMPImagesReconciler) reconcileSystemImageStream(desiredImageStream *imagev1.ImageStream) error {
-	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
-	return reconciler.Reconcile(desiredImageStream)
-}
-
-func (r *AMPImagesReconciler) reconcileZyncDatabasePostgreSQLImageStream(desiredImageStream *imagev1.ImageStream) error {
-	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
-	return reconciler.Reconcile(desiredImageStream)
-}
-
-func (r *AMPImagesReconciler) reconcileSystemMemcachedImageStream(desiredImageStream *imagev1.ImageStream) error {
+func (r *AMPImagesReconciler) reconcileImageStream(desiredImageStream *imagev1.ImageStream) error {
 	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
 	return reconciler.Reconcile(desiredImageStream)
 }
 
 func (r *AMPImagesReconciler) reconcileDeploymentsServiceAccount(desiredServiceAccount *v1.ServiceAccount) error {
-	reconciler := NewServiceAccountBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlyServiceAccountReconciler())
+	reconciler := NewServiceAccountBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImagePullSecretsServiceAccountReconciler())
 	return reconciler.Reconcile(desiredServiceAccount)
 }
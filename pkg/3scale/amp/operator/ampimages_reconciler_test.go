@@ -100,3 +100,62 @@ func TestAMPImagesReconciler(t *testing.T) {
 		})
 	}
 }
+
+func TestAMPImagesReconcilerPausedComponents(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.PausedComponentsAnnotation: "backend,zync",
+			},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "test.3scale.net",
+			},
+		},
+	}
+	_, err := apimanager.SetDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objs := []runtime.Object{apimanager}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err = imagev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := fake.NewFakeClient(objs...)
+	baseLogicReconciler := NewBaseLogicReconciler(NewBaseReconciler(cl, cl, s, log))
+	imagesReconciler := NewAMPImagesReconciler(NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager))
+	_, err = imagesReconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]bool{
+		"amp-backend":              false,
+		"amp-zync":                 false,
+		"zync-database-postgresql": false,
+		"amp-apicast":              true,
+		"amp-system":               true,
+		"system-memcached":         true,
+	}
+	for imageStreamName, reconciled := range expected {
+		err = cl.Get(context.TODO(), types.NamespacedName{Name: imageStreamName, Namespace: namespace}, &imagev1.ImageStream{})
+		if reconciled && err != nil {
+			t.Errorf("ImageStream %s not reconciled: %v", imageStreamName, err)
+		}
+		if !reconciled && err == nil {
+			t.Errorf("ImageStream %s of a paused component reconciled", imageStreamName)
+		}
+	}
+}
@@ -1,11 +1,29 @@
 package operator
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"hash"
+	"sort"
 	"strconv"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ApicastHTTPSDefaultPort is the port of the APIcast HTTPS listener when
+	// no port is given
+	ApicastHTTPSDefaultPort int32 = 8443
+	// ApicastHTTPSCACertificateKey is the optional key of the HTTPS
+	// certificate secret with the CA certificate reencrypt Routes verify
+	// APIcast with
+	ApicastHTTPSCACertificateKey = "ca.crt"
 )
 
 func (o *OperatorApicastOptionsProvider) GetApicastOptions() (*component.ApicastOptions, error) {
@@ -18,7 +36,20 @@ func (o *OperatorApicastOptionsProvider) GetApicastOptions() (*component.Apicast
 	optProv.ResponseCodes(strconv.FormatBool(*o.APIManagerSpec.Apicast.IncludeResponseCodes)) // TODO is this a good place to make the conversion?
 
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 	o.setReplicas(&optProv)
+	err := o.setCustomPoliciesOptions(&optProv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Apicast Options - %s", err)
+	}
+	err = o.setRuntimeOptions(&optProv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Apicast Options - %s", err)
+	}
+	err = o.setHTTPSOptions(&optProv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Apicast Options - %s", err)
+	}
 	res, err := optProv.Build()
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Apicast Options - %s", err)
@@ -27,9 +58,17 @@ func (o *OperatorApicastOptionsProvider) GetApicastOptions() (*component.Apicast
 }
 
 func (o *OperatorApicastOptionsProvider) setResourceRequirementsOptions(b *component.ApicastOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.StagingResourceRequirements(v1.ResourceRequirements{})
-		b.ProductionResourceRequirements(v1.ResourceRequirements{})
+	enabled := *o.APIManagerSpec.ResourceRequirementsEnabled
+	setResourceRequirements(b.StagingResourceRequirements, enabled, o.APIManagerSpec.Apicast.StagingSpec.Resources)
+	setResourceRequirements(b.ProductionResourceRequirements, enabled, o.APIManagerSpec.Apicast.ProductionSpec.Resources)
+}
+
+func (o *OperatorApicastOptionsProvider) setPodPlacementOptions(b *component.ApicastOptionsBuilder) {
+	if o.APIManagerSpec.Apicast.StagingSpec.Placement != nil {
+		b.StagingPodPlacement(podPlacement(o.APIManagerSpec.Apicast.StagingSpec.Placement, "apicast-staging"))
+	}
+	if o.APIManagerSpec.Apicast.ProductionSpec.Placement != nil {
+		b.ProductionPodPlacement(podPlacement(o.APIManagerSpec.Apicast.ProductionSpec.Placement, "apicast-production"))
 	}
 }
 
@@ -37,3 +76,223 @@ func (o *OperatorApicastOptionsProvider) setReplicas(b *component.ApicastOptions
 	b.StagingReplicas(int32(*o.APIManagerSpec.Apicast.StagingSpec.Replicas))
 	b.ProductionReplicas(int32(*o.APIManagerSpec.Apicast.ProductionSpec.Replicas))
 }
+
+func (o *OperatorApicastOptionsProvider) setCustomPoliciesOptions(b *component.ApicastOptionsBuilder) error {
+	if len(o.APIManagerSpec.Apicast.CustomPolicies) == 0 {
+		return nil
+	}
+
+	policies, policiesHash, err := apicastCustomPolicies(o.Client, o.Namespace, o.APIManagerSpec.Apicast.CustomPolicies)
+	if err != nil {
+		return err
+	}
+	// The policy images are pulled from the registry mirror like the
+	// images of the release
+	for idx := range policies {
+		if policies[idx].Image != "" {
+			policies[idx].Image = mirroredImageURL(o.APIManagerSpec, policies[idx].Image)
+		}
+	}
+
+	b.CustomPolicies(policies)
+	b.CustomPoliciesHash(policiesHash)
+	return nil
+}
+
+// apicastCustomPolicies returns the custom policies of the given specs and
+// the hash of their content
+func apicastCustomPolicies(k8sclient client.Client, namespace string, policySpecs []appsv1alpha1.CustomPolicySpec) ([]component.ApicastCustomPolicy, string, error) {
+	// The hash covers the policy definitions and the content of the
+	// ConfigMaps so the deployments are rolled out when any of them changes
+	policiesHash := sha256.New()
+	policies := []component.ApicastCustomPolicy{}
+	for _, policySpec := range policySpecs {
+		policy := component.ApicastCustomPolicy{
+			Name:    policySpec.Name,
+			Version: policySpec.Version,
+		}
+		if policySpec.ConfigMapRef != nil {
+			policy.ConfigMapName = policySpec.ConfigMapRef.Name
+		}
+		if policySpec.Image != nil {
+			policy.Image = *policySpec.Image
+		}
+		if policySpec.ImagePath != nil {
+			policy.ImagePath = *policySpec.ImagePath
+		}
+		fmt.Fprintf(policiesHash, "%s\x00%s\x00%s\x00%s\x00%s\x00", policy.Name, policy.Version, policy.ConfigMapName, policy.Image, policy.ImagePath)
+
+		if policy.ConfigMapName != "" {
+			configMap, err := getConfigMap(k8sclient, namespace, policy.ConfigMapName)
+			if err != nil {
+				return nil, "", fmt.Errorf("error reading custom policy ConfigMap %s: %s", policy.ConfigMapName, err)
+			}
+			hashConfigMapData(policiesHash, configMap)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, fmt.Sprintf("%x", policiesHash.Sum(nil)), nil
+}
+
+// MissingCustomPolicyConfigMaps returns the names of the ConfigMaps
+// referenced by the given custom policies that do not exist
+func MissingCustomPolicyConfigMaps(k8sclient client.Client, namespace string, policySpecs []appsv1alpha1.CustomPolicySpec) ([]string, error) {
+	missing := []string{}
+	for _, policySpec := range policySpecs {
+		if policySpec.ConfigMapRef == nil {
+			continue
+		}
+		_, err := getConfigMap(k8sclient, namespace, policySpec.ConfigMapRef.Name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				missing = append(missing, policySpec.ConfigMapRef.Name)
+				continue
+			}
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+func (o *OperatorApicastOptionsProvider) setRuntimeOptions(b *component.ApicastOptionsBuilder) error {
+	stagingRuntimeOptions, err := apicastRuntimeOptions(o.Client, o.Namespace, o.APIManagerSpec.Apicast.StagingSpec.ApicastRuntimeSpec)
+	if err != nil {
+		return err
+	}
+	b.StagingRuntimeOptions(*stagingRuntimeOptions)
+
+	productionRuntimeOptions, err := apicastRuntimeOptions(o.Client, o.Namespace, o.APIManagerSpec.Apicast.ProductionSpec.ApicastRuntimeSpec)
+	if err != nil {
+		return err
+	}
+	b.ProductionRuntimeOptions(*productionRuntimeOptions)
+	return nil
+}
+
+// apicastRuntimeOptions returns the runtime options of the given spec. The
+// custom environment ConfigMaps are read to hash their content
+func apicastRuntimeOptions(k8sclient client.Client, namespace string, runtimeSpec appsv1alpha1.ApicastRuntimeSpec) (*component.ApicastRuntimeOptions, error) {
+	runtimeOptions := &component.ApicastRuntimeOptions{
+		Workers:               runtimeSpec.Workers,
+		ConfigurationCacheTTL: runtimeSpec.ConfigurationCacheTTL,
+		PathRouting:           runtimeSpec.PathRouting,
+		ServicesList:          runtimeSpec.ServicesList,
+	}
+	if runtimeSpec.LogLevel != nil {
+		runtimeOptions.LogLevel = *runtimeSpec.LogLevel
+	}
+	if runtimeSpec.ConfigurationLoader != nil {
+		runtimeOptions.ConfigurationLoader = *runtimeSpec.ConfigurationLoader
+	}
+	if runtimeSpec.ServicesFilterByURL != nil {
+		runtimeOptions.ServicesFilterByURL = *runtimeSpec.ServicesFilterByURL
+	}
+
+	if len(runtimeSpec.CustomEnvironments) == 0 {
+		return runtimeOptions, nil
+	}
+
+	environmentsHash := sha256.New()
+	for _, customEnvironmentSpec := range runtimeSpec.CustomEnvironments {
+		configMapName := customEnvironmentSpec.ConfigMapRef.Name
+		configMap, err := getConfigMap(k8sclient, namespace, configMapName)
+		if err != nil {
+			return nil, fmt.Errorf("error reading custom environment ConfigMap %s: %s", configMapName, err)
+		}
+		fmt.Fprintf(environmentsHash, "%s\x00", configMapName)
+		hashConfigMapData(environmentsHash, configMap)
+
+		runtimeOptions.CustomEnvironments = append(runtimeOptions.CustomEnvironments, component.ApicastCustomEnvironment{
+			ConfigMapName: configMapName,
+			Files:         sortedKeys(configMap.Data),
+		})
+	}
+	runtimeOptions.CustomEnvironmentsHash = fmt.Sprintf("%x", environmentsHash.Sum(nil))
+	return runtimeOptions, nil
+}
+
+func (o *OperatorApicastOptionsProvider) setHTTPSOptions(b *component.ApicastOptionsBuilder) error {
+	if o.APIManagerSpec.Apicast.HTTPS == nil {
+		return nil
+	}
+
+	httpsOptions, err := apicastHTTPSOptions(o.Client, o.Namespace, o.APIManagerSpec.Apicast.HTTPS)
+	if err != nil {
+		return err
+	}
+
+	b.HTTPS(*httpsOptions)
+	return nil
+}
+
+// apicastHTTPSOptions returns the HTTPS listener options of the given spec.
+// The certificate secret is read to validate and hash its content
+func apicastHTTPSOptions(k8sclient client.Client, namespace string, httpsSpec *appsv1alpha1.ApicastHTTPSSpec) (*component.ApicastHTTPSOptions, error) {
+	httpsOptions := component.ApicastHTTPSOptions{
+		Port:                  ApicastHTTPSDefaultPort,
+		CertificateSecretName: httpsSpec.CertificateSecretRef.Name,
+		RouteTermination:      appsv1alpha1.ApicastHTTPSRouteTerminationPassthrough,
+	}
+	if httpsSpec.Port != nil {
+		httpsOptions.Port = *httpsSpec.Port
+	}
+	if httpsSpec.RouteTermination != nil {
+		httpsOptions.RouteTermination = *httpsSpec.RouteTermination
+	}
+
+	secret, err := helper.GetSecret(httpsOptions.CertificateSecretName, namespace, k8sclient)
+	if err != nil {
+		return nil, fmt.Errorf("error reading HTTPS certificate secret %s: %s", httpsOptions.CertificateSecretName, err)
+	}
+	for _, key := range []string{v1.TLSCertKey, v1.TLSPrivateKeyKey} {
+		if _, ok := secret.Data[key]; !ok {
+			return nil, fmt.Errorf("HTTPS certificate secret %s does not have a %s value", httpsOptions.CertificateSecretName, key)
+		}
+	}
+	httpsOptions.DestinationCACertificate = string(secret.Data[ApicastHTTPSCACertificateKey])
+
+	// The pods read the certificate on start, so the hash rolls them out
+	// when the certificate is renewed
+	certificateHash := sha256.New()
+	fmt.Fprintf(certificateHash, "%d\x00", httpsOptions.Port)
+	hashBinaryData(certificateHash, secret.Data)
+	httpsOptions.CertificateHash = fmt.Sprintf("%x", certificateHash.Sum(nil))
+
+	return &httpsOptions, nil
+}
+
+func getConfigMap(k8sclient client.Client, namespace, name string) (*v1.ConfigMap, error) {
+	configMap := &v1.ConfigMap{}
+	err := k8sclient.Get(context.TODO(), client.ObjectKey{Name: name, Namespace: namespace}, configMap)
+	return configMap, err
+}
+
+func hashConfigMapData(h hash.Hash, configMap *v1.ConfigMap) {
+	for _, key := range sortedKeys(configMap.Data) {
+		fmt.Fprintf(h, "%s\x00%s\x00", key, configMap.Data[key])
+	}
+
+	hashBinaryData(h, configMap.BinaryData)
+}
+
+func hashBinaryData(h hash.Hash, data map[string][]byte) {
+	keys := []string{}
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(h, "%s\x00", key)
+		h.Write(data[key])
+	}
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := []string{}
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,9 +1,17 @@
 package operator
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/go-logr/logr"
 	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -51,9 +59,147 @@ func (r *ApicastStagingDCReconciler) IsUpdateNeeded(desired, existing *appsv1.De
 	tmpUpdate = DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = ApicastReconcileCustomFiles(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerEnvVars(desired, existing, apicastReconciledEnvVars, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerPorts(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
+// apicastReconciledEnvVars are the APIcast environment variables set from
+// the APIManager runtime and HTTPS settings
+var apicastReconciledEnvVars = []string{
+	"APICAST_CONFIGURATION_LOADER",
+	"APICAST_CONFIGURATION_CACHE",
+	"APICAST_LOG_LEVEL",
+	"APICAST_WORKERS",
+	"APICAST_PATH_ROUTING",
+	"APICAST_SERVICES_FILTER_BY_URL",
+	"APICAST_SERVICES_LIST",
+	"APICAST_ENVIRONMENT",
+	"APICAST_HTTPS_PORT",
+	"APICAST_HTTPS_CERTIFICATE",
+	"APICAST_HTTPS_CERTIFICATE_KEY",
+}
+
+// apicastCustomFilesHashAnnotations are the pod template annotations
+// identifying the files mounted into the APIcast containers
+var apicastCustomFilesHashAnnotations = []string{
+	component.ApicastCustomPoliciesHashAnnotation,
+	component.ApicastCustomEnvironmentsHashAnnotation,
+	component.ApicastHTTPSCertificateHashAnnotation,
+}
+
+// ApicastReconcileCustomFiles reconciles the custom policies, custom
+// environments and HTTPS certificate mounted into an APIcast
+// DeploymentConfig. The hash annotations of the pod template identify them,
+// so their volumes, mounts and init containers are only reconciled when
+// some hash differs. They are added, updated or removed by name: other
+// volumes, mounts and init containers, and the container images, are kept.
+// Updating the pod template rolls out the DeploymentConfig
+func ApicastReconcileCustomFiles(desired, existing *appsv1.DeploymentConfig, logger logr.Logger) bool {
+	if desired.Spec.Template == nil || existing.Spec.Template == nil {
+		return false
+	}
+
+	update := false
+	for _, annotation := range apicastCustomFilesHashAnnotations {
+		desiredHash := desired.Spec.Template.Annotations[annotation]
+		existingHash := existing.Spec.Template.Annotations[annotation]
+		if desiredHash == existingHash {
+			continue
+		}
+
+		if desiredHash == "" {
+			delete(existing.Spec.Template.Annotations, annotation)
+		} else {
+			if existing.Spec.Template.Annotations == nil {
+				existing.Spec.Template.Annotations = map[string]string{}
+			}
+			existing.Spec.Template.Annotations[annotation] = desiredHash
+		}
+		update = true
+	}
+
+	if !update {
+		return false
+	}
+
+	desiredSpec := &desired.Spec.Template.Spec
+	existingSpec := &existing.Spec.Template.Spec
+	existingSpec.Volumes = apicastReconcileCustomFilesVolumes(desiredSpec.Volumes, existingSpec.Volumes)
+	existingSpec.InitContainers = apicastReconcileCustomFilesInitContainers(desiredSpec.InitContainers, existingSpec.InitContainers)
+	for _, desiredContainer := range desiredSpec.Containers {
+		for idx := range existingSpec.Containers {
+			existingContainer := &existingSpec.Containers[idx]
+			if existingContainer.Name == desiredContainer.Name {
+				existingContainer.VolumeMounts = apicastReconcileCustomFilesVolumeMounts(desiredContainer.VolumeMounts, existingContainer.VolumeMounts)
+			}
+		}
+	}
+
+	logger.Info(fmt.Sprintf("%s mounted custom files differ", ObjectInfo(desired)))
+	return true
+}
+
+// apicastReconcileCustomFilesVolumes returns the existing volumes with the
+// custom files volumes replaced by the desired ones
+func apicastReconcileCustomFilesVolumes(desired, existing []v1.Volume) []v1.Volume {
+	volumes := []v1.Volume{}
+	for _, volume := range existing {
+		if !component.IsApicastCustomFilesVolume(volume.Name) {
+			volumes = append(volumes, volume)
+		}
+	}
+	for _, volume := range desired {
+		if component.IsApicastCustomFilesVolume(volume.Name) {
+			volumes = append(volumes, volume)
+		}
+	}
+	return volumes
+}
+
+// apicastReconcileCustomFilesVolumeMounts returns the existing volume
+// mounts with the custom files mounts replaced by the desired ones
+func apicastReconcileCustomFilesVolumeMounts(desired, existing []v1.VolumeMount) []v1.VolumeMount {
+	volumeMounts := []v1.VolumeMount{}
+	for _, volumeMount := range existing {
+		if !component.IsApicastCustomFilesVolume(volumeMount.Name) {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+	for _, volumeMount := range desired {
+		if component.IsApicastCustomFilesVolume(volumeMount.Name) {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+	return volumeMounts
+}
+
+// apicastReconcileCustomFilesInitContainers returns the existing init
+// containers with the ones filling the custom files volumes replaced by the
+// desired ones. Other init containers keep their position and image, which
+// may have been resolved by an image change trigger
+func apicastReconcileCustomFilesInitContainers(desired, existing []v1.Container) []v1.Container {
+	containers := []v1.Container{}
+	for _, container := range existing {
+		if !component.IsApicastCustomFilesVolume(container.Name) {
+			containers = append(containers, container)
+		}
+	}
+	for _, container := range desired {
+		if component.IsApicastCustomFilesVolume(container.Name) {
+			containers = append(containers, container)
+		}
+	}
+	return containers
+}
+
 type ApicastReconciler struct {
 	BaseAPIManagerLogicReconciler
 }
@@ -68,19 +214,32 @@ func NewApicastReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicRecon
 }
 
 func (r *ApicastReconciler) Reconcile() (reconcile.Result, error) {
-	apicast, err := r.apicast()
+	missingConfigMaps, err := MissingCustomPolicyConfigMaps(r.Client(), r.apiManager.Namespace, r.apiManager.Spec.Apicast.CustomPolicies)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileStagingDeploymentConfig(apicast.StagingDeploymentConfig())
+	// The DeploymentConfigs mounting custom policies from missing ConfigMaps
+	// are left as they are, the rest of APIcast is reconciled. The
+	// ApicastCustomPoliciesInvalid condition of the APIManager reports them
+	deploymentConfigsReconciled := len(missingConfigMaps) == 0
+	apicast, err := r.apicast(deploymentConfigsReconciled)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileProductionDeploymentConfig(apicast.ProductionDeploymentConfig())
-	if err != nil {
-		return reconcile.Result{}, err
+	if deploymentConfigsReconciled {
+		err = r.reconcileStagingDeploymentConfig(apicast.StagingDeploymentConfig())
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+
+		err = r.reconcileProductionDeploymentConfig(apicast.ProductionDeploymentConfig())
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	} else {
+		r.Logger().Info(fmt.Sprintf("Custom policy ConfigMaps %s not found. APIcast DeploymentConfigs not reconciled", strings.Join(missingConfigMaps, ", ")))
 	}
 
 	err = r.reconcileStagingService(apicast.StagingService())
@@ -108,11 +267,44 @@ func (r *ApicastReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	err = r.reconcileIngress(apicast.StagingIngress())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileIngress(apicast.ProductionIngress())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileHTTPSRoute("apicast-staging-https", apicast.StagingHTTPSRoute())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileHTTPSRoute("apicast-production-https", apicast.ProductionHTTPSRoute())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileHorizontalPodAutoscaler(apicast.ProductionDeploymentConfig(), r.apiManager.Spec.Apicast.ProductionSpec.Autoscaling)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	return reconcile.Result{}, nil
 }
 
-func (r *ApicastReconciler) apicast() (*component.Apicast, error) {
-	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: &r.apiManager.Spec, Namespace: r.apiManager.Namespace, Client: r.Client()}
+// apicast returns the APIcast component. Custom policies are left out
+// when not requested, as their ConfigMaps may not exist
+func (r *ApicastReconciler) apicast(customPolicies bool) (*component.Apicast, error) {
+	spec := r.apiManager.Spec
+	if !customPolicies {
+		apicastSpec := *spec.Apicast
+		apicastSpec.CustomPolicies = nil
+		spec.Apicast = &apicastSpec
+	}
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: &spec, Namespace: r.apiManager.Namespace, Client: r.Client()}
 	opts, err := optsProvider.GetApicastOptions()
 	if err != nil {
 		return nil, err
@@ -126,20 +318,45 @@ func (r *ApicastReconciler) reconcileStagingDeploymentConfig(desiredDeploymentCo
 }
 
 func (r *ApicastReconciler) reconcileProductionDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
-	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewApicastDCReconciler(r.BaseAPIManagerLogicReconciler))
+	dcReconciler := NewAutoscalingDCReconciler(r.apiManager.Spec.Apicast.ProductionSpec.Autoscaling, NewApicastDCReconciler(r.BaseAPIManagerLogicReconciler))
+	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, dcReconciler)
 	return reconciler.Reconcile(desiredDeploymentConfig)
 }
 
 func (r *ApicastReconciler) reconcileStagingService(desiredService *v1.Service) error {
-	reconciler := NewServiceBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlySvcReconciler())
+	reconciler := NewServiceBaseReconciler(r.BaseAPIManagerLogicReconciler, NewPortsSvcReconciler(r.Logger()))
 	return reconciler.Reconcile(desiredService)
 }
 
 func (r *ApicastReconciler) reconcileProductionService(desiredService *v1.Service) error {
-	reconciler := NewServiceBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlySvcReconciler())
+	reconciler := NewServiceBaseReconciler(r.BaseAPIManagerLogicReconciler, NewPortsSvcReconciler(r.Logger()))
 	return reconciler.Reconcile(desiredService)
 }
 
+// reconcileHTTPSRoute reconciles the Route exposing an APIcast HTTPS
+// listener. A nil desired Route deletes the existing one
+func (r *ApicastReconciler) reconcileHTTPSRoute(name string, desiredRoute *routev1.Route) error {
+	// Components are exposed with Ingresses instead
+	if r.apiManager.IsIngressEnabled() {
+		return nil
+	}
+
+	if desiredRoute == nil {
+		existing := &routev1.Route{}
+		err := r.Client().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.apiManager.GetNamespace()}, existing)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		return r.deleteResource(existing)
+	}
+
+	reconciler := NewRouteBaseReconciler(r.BaseAPIManagerLogicReconciler, NewTLSRouteReconciler(r.Logger()))
+	return reconciler.Reconcile(desiredRoute)
+}
+
 func (r *ApicastReconciler) reconcileEnvironmentConfigMap(desiredConfigMap *v1.ConfigMap) error {
 	reconciler := NewConfigMapBaseReconciler(r.BaseAPIManagerLogicReconciler, NewApicastEnvCMReconciler())
 	return reconciler.Reconcile(desiredConfigMap)
@@ -3,8 +3,10 @@ package operator
 import (
 	"context"
 	"k8s.io/api/policy/v1beta1"
+	"reflect"
 	"testing"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	appsv1 "github.com/openshift/api/apps/v1"
 	imagev1 "github.com/openshift/api/image/v1"
@@ -117,3 +119,119 @@ func TestApicastReconciler(t *testing.T) {
 		})
 	}
 }
+
+func TestApicastReconcileCustomFiles(t *testing.T) {
+	log := logf.Log.WithName("operator_test")
+	newDC := func(hash string, volumes []v1.Volume, volumeMounts []v1.VolumeMount) *appsv1.DeploymentConfig {
+		annotations := map[string]string{"prometheus.io/scrape": "true"}
+		if hash != "" {
+			annotations[component.ApicastCustomPoliciesHashAnnotation] = hash
+		}
+		return &appsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "apicast-staging"},
+			Spec: appsv1.DeploymentConfigSpec{
+				Template: &v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+					Spec: v1.PodSpec{
+						Volumes: volumes,
+						Containers: []v1.Container{
+							v1.Container{Name: "apicast-staging", VolumeMounts: volumeMounts},
+						},
+					},
+				},
+			},
+		}
+	}
+	policyVolumes := []v1.Volume{v1.Volume{Name: "custom-policy-0"}}
+	policyVolumeMounts := []v1.VolumeMount{v1.VolumeMount{Name: "custom-policy-0", MountPath: "/opt/app-root/src/policies/my-policy/0.1"}}
+
+	desired := newDC("abc", policyVolumes, policyVolumeMounts)
+	existing := newDC("", nil, nil)
+	if !ApicastReconcileCustomFiles(desired, existing, log) {
+		t.Error("added custom policies not reconciled")
+	}
+	if existing.Spec.Template.Annotations[component.ApicastCustomPoliciesHashAnnotation] != "abc" ||
+		len(existing.Spec.Template.Spec.Volumes) != 1 ||
+		len(existing.Spec.Template.Spec.Containers[0].VolumeMounts) != 1 {
+		t.Errorf("unexpected reconciled pod template: %v", existing.Spec.Template)
+	}
+
+	if ApicastReconcileCustomFiles(desired, existing, log) {
+		t.Error("unchanged custom policies reconciled")
+	}
+
+	desired = newDC("", nil, nil)
+	if !ApicastReconcileCustomFiles(desired, existing, log) {
+		t.Error("removed custom policies not reconciled")
+	}
+	if _, ok := existing.Spec.Template.Annotations[component.ApicastCustomPoliciesHashAnnotation]; ok ||
+		len(existing.Spec.Template.Spec.Volumes) != 0 ||
+		len(existing.Spec.Template.Spec.Containers[0].VolumeMounts) != 0 {
+		t.Errorf("custom policies not removed from pod template: %v", existing.Spec.Template)
+	}
+	if existing.Spec.Template.Annotations["prometheus.io/scrape"] != "true" {
+		t.Error("unrelated pod template annotations removed")
+	}
+}
+
+func TestApicastReconcileCustomFilesKeepsOtherVolumes(t *testing.T) {
+	log := logf.Log.WithName("operator_test")
+	newDC := func(hash, initImage string, volumes []v1.Volume, volumeMounts []v1.VolumeMount, initContainers []v1.Container) *appsv1.DeploymentConfig {
+		return &appsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "apicast-production"},
+			Spec: appsv1.DeploymentConfigSpec{
+				Template: &v1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{component.ApicastCustomPoliciesHashAnnotation: hash},
+					},
+					Spec: v1.PodSpec{
+						Volumes: volumes,
+						InitContainers: append([]v1.Container{
+							v1.Container{Name: "system-master-svc", Image: initImage},
+						}, initContainers...),
+						Containers: []v1.Container{
+							v1.Container{Name: "apicast-production", VolumeMounts: volumeMounts},
+						},
+					},
+				},
+			},
+		}
+	}
+	userVolume := v1.Volume{Name: "user-volume"}
+	userVolumeMount := v1.VolumeMount{Name: "user-volume", MountPath: "/user"}
+	httpsVolume := v1.Volume{Name: "https-certificate"}
+	httpsVolumeMount := v1.VolumeMount{Name: "https-certificate", MountPath: component.ApicastHTTPSCertificateMountPath}
+	oldPolicyVolume := v1.Volume{Name: "custom-policy-0", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}
+	newPolicyVolume := v1.Volume{Name: "custom-policy-0", VolumeSource: v1.VolumeSource{
+		ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "my-policy"}},
+	}}
+	policyVolumeMount := v1.VolumeMount{Name: "custom-policy-0", MountPath: "/opt/app-root/src/policies/my-policy/0.1"}
+	policyInitContainer := v1.Container{Name: "custom-policy-0", Image: "my-policy:latest"}
+
+	resolvedImage := "docker-registry.default.svc:5000/3scale/amp-apicast@sha256:1234"
+	existing := newDC("old", resolvedImage,
+		[]v1.Volume{userVolume, httpsVolume, oldPolicyVolume},
+		[]v1.VolumeMount{userVolumeMount, httpsVolumeMount, policyVolumeMount},
+		[]v1.Container{policyInitContainer})
+	desired := newDC("new", "amp-apicast:latest",
+		[]v1.Volume{httpsVolume, newPolicyVolume},
+		[]v1.VolumeMount{httpsVolumeMount, policyVolumeMount},
+		nil)
+
+	if !ApicastReconcileCustomFiles(desired, existing, log) {
+		t.Fatal("changed custom policies not reconciled")
+	}
+
+	podSpec := existing.Spec.Template.Spec
+	expectedVolumes := []v1.Volume{userVolume, httpsVolume, newPolicyVolume}
+	if !reflect.DeepEqual(podSpec.Volumes, expectedVolumes) {
+		t.Errorf("unexpected volumes: %v", podSpec.Volumes)
+	}
+	expectedVolumeMounts := []v1.VolumeMount{userVolumeMount, httpsVolumeMount, policyVolumeMount}
+	if !reflect.DeepEqual(podSpec.Containers[0].VolumeMounts, expectedVolumeMounts) {
+		t.Errorf("unexpected volume mounts: %v", podSpec.Containers[0].VolumeMounts)
+	}
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Image != resolvedImage {
+		t.Errorf("unexpected init containers: %v", podSpec.InitContainers)
+	}
+}
@@ -0,0 +1,74 @@
+package operator
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+)
+
+func (o *OperatorStandaloneApicastOptionsProvider) GetStandaloneApicastOptions() (*component.StandaloneApicastOptions, error) {
+	spec := &o.APIcast.Spec
+
+	optProv := component.StandaloneApicastOptionsBuilder{}
+	optProv.Name(o.APIcast.Name)
+	optProv.Image(component.ApicastImageURL())
+	if spec.Image != nil {
+		optProv.Image(*spec.Image)
+	}
+	optProv.AdminPortalCredentialsRef(spec.AdminPortalCredentialsRef.Name)
+	optProv.DeploymentEnvironment(*spec.DeploymentEnvironment)
+	optProv.OpenSSLVerify(strconv.FormatBool(*spec.OpenSSLVerify))
+	optProv.Replicas(int32(*spec.Replicas))
+	if spec.Resources != nil {
+		optProv.ResourceRequirements(*spec.Resources)
+	}
+	if spec.Placement != nil {
+		optProv.PodPlacement(podPlacementWithSelector(spec.Placement, map[string]string{"deployment": "apicast-" + o.APIcast.Name}))
+	}
+	if spec.ExposedHost != nil {
+		optProv.ExposedHost(spec.ExposedHost.Host)
+	}
+
+	err := o.setCustomFilesOptions(&optProv)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create standalone Apicast Options - %s", err)
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create standalone Apicast Options - %s", err)
+	}
+	return res, nil
+}
+
+// setCustomFilesOptions sets the custom policies, the runtime settings and
+// the HTTPS listener. They are shared with the APIManager gateways
+func (o *OperatorStandaloneApicastOptionsProvider) setCustomFilesOptions(b *component.StandaloneApicastOptionsBuilder) error {
+	spec := &o.APIcast.Spec
+
+	if len(spec.CustomPolicies) > 0 {
+		policies, policiesHash, err := apicastCustomPolicies(o.Client, o.APIcast.Namespace, spec.CustomPolicies)
+		if err != nil {
+			return err
+		}
+		b.CustomPolicies(policies)
+		b.CustomPoliciesHash(policiesHash)
+	}
+
+	runtimeOptions, err := apicastRuntimeOptions(o.Client, o.APIcast.Namespace, spec.ApicastRuntimeSpec)
+	if err != nil {
+		return err
+	}
+	b.RuntimeOptions(*runtimeOptions)
+
+	if spec.HTTPS != nil {
+		httpsOptions, err := apicastHTTPSOptions(o.Client, o.APIcast.Namespace, spec.HTTPS)
+		if err != nil {
+			return err
+		}
+		b.HTTPS(*httpsOptions)
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// standaloneApicastReconciledEnvVars are the environment variables of the
+// standalone APIcast container set from the APIcast spec
+var standaloneApicastReconciledEnvVars = append([]string{
+	"THREESCALE_PORTAL_ENDPOINT",
+	"THREESCALE_DEPLOYMENT_ENV",
+	"OPENSSL_VERIFY",
+}, apicastReconciledEnvVars...)
+
+type StandaloneApicastDCReconciler struct {
+	BaseLogicReconciler
+}
+
+func NewStandaloneApicastDCReconciler(baseLogicReconciler BaseLogicReconciler) *StandaloneApicastDCReconciler {
+	return &StandaloneApicastDCReconciler{
+		BaseLogicReconciler: baseLogicReconciler,
+	}
+}
+
+func (r *StandaloneApicastDCReconciler) IsUpdateNeeded(desired, existing *appsv1.DeploymentConfig) bool {
+	update := false
+
+	tmpUpdate := DeploymentConfigReconcileReplicas(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerImage(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = ApicastReconcileCustomFiles(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerEnvVars(desired, existing, standaloneApicastReconciledEnvVars, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerPorts(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	return update
+}
+
+// StandaloneApicastReconciler reconciles the objects of a standalone APIcast
+// gateway. The objects are owned by the APIcast resource
+type StandaloneApicastReconciler struct {
+	BaseLogicReconciler
+	apicast *appsv1alpha1.APIcast
+}
+
+// blank assignment to verify that StandaloneApicastReconciler implements LogicReconciler
+var _ LogicReconciler = &StandaloneApicastReconciler{}
+
+func NewStandaloneApicastReconciler(baseLogicReconciler BaseLogicReconciler, apicast *appsv1alpha1.APIcast) *StandaloneApicastReconciler {
+	return &StandaloneApicastReconciler{
+		BaseLogicReconciler: baseLogicReconciler,
+		apicast:             apicast,
+	}
+}
+
+func (r *StandaloneApicastReconciler) Reconcile() (reconcile.Result, error) {
+	optsProvider := OperatorStandaloneApicastOptionsProvider{APIcast: r.apicast, Client: r.Client()}
+	opts, err := optsProvider.GetStandaloneApicastOptions()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	apicast := component.NewStandaloneApicast(opts)
+
+	err = r.reconcileDeployment(apicast.Deployment())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileService(apicast.Service())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// The gateway is exposed either with a Route or with an Ingress. The
+	// other one is deleted when switching the exposure type
+	desiredRoute, desiredIngress := apicast.Route(), apicast.Ingress()
+	if r.apicast.IsIngressEnabled() {
+		desiredRoute = nil
+	} else {
+		desiredIngress = nil
+	}
+
+	err = r.reconcileRoute(apicast.ObjectName(), desiredRoute)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileIngress(apicast.ObjectName(), desiredIngress)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+func (r *StandaloneApicastReconciler) reconcileDeployment(desired *k8sappsv1.Deployment) error {
+	adapter := deploymentConfigAdapterReconciler{reconciler: NewStandaloneApicastDCReconciler(r.BaseLogicReconciler), logger: r.Logger()}
+	existing := &k8sappsv1.Deployment{}
+	return r.reconcileResource(desired, existing, func() bool {
+		update := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+		return adapter.IsUpdateNeeded(desired, existing) || update
+	})
+}
+
+func (r *StandaloneApicastReconciler) reconcileService(desired *v1.Service) error {
+	reconciler := NewPortsSvcReconciler(r.Logger())
+	existing := &v1.Service{}
+	return r.reconcileResource(desired, existing, func() bool {
+		update := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+		return reconciler.IsUpdateNeeded(desired, existing) || update
+	})
+}
+
+func (r *StandaloneApicastReconciler) reconcileRoute(name string, desired *routev1.Route) error {
+	existing := &routev1.Route{}
+	if desired == nil {
+		return r.deleteResourceIfExists(name, existing)
+	}
+
+	reconciler := NewTLSRouteReconciler(r.Logger())
+	return r.reconcileResource(desired, existing, func() bool {
+		update := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+		update = reconciler.IsUpdateNeeded(desired, existing) || update
+		if desired.Spec.Host != existing.Spec.Host {
+			r.Logger().Info(fmt.Sprintf("%s spec.host differs", ObjectInfo(desired)))
+			existing.Spec.Host = desired.Spec.Host
+			update = true
+		}
+		return update
+	})
+}
+
+func (r *StandaloneApicastReconciler) reconcileIngress(name string, desired *extensions.Ingress) error {
+	existing := &extensions.Ingress{}
+	if desired == nil {
+		return r.deleteResourceIfExists(name, existing)
+	}
+
+	applyIngressSpec(desired, r.apicast.Spec.ExposedHost.Ingress)
+	return r.reconcileResource(desired, existing, func() bool {
+		update := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+		return ingressReconcileSpec(desired, existing, r.Logger()) || update
+	})
+}
+
+// reconcileResource creates the desired object when it does not exist.
+// Otherwise, the existing object is read into existing and updated when
+// isUpdateNeeded, which also merges the object metadata, reports changes
+func (r *StandaloneApicastReconciler) reconcileResource(desired, existing common.KubernetesObject, isUpdateNeeded func() bool) error {
+	objectInfo := ObjectInfo(desired)
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: r.apicast.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			createErr := r.createResource(desired)
+			if createErr != nil {
+				r.Logger().Error(createErr, fmt.Sprintf("Error creating object %s. Requeuing request...", objectInfo))
+				return createErr
+			}
+			return nil
+		}
+		return err
+	}
+
+	update, err := r.ensureOwnerReference(existing)
+	if err != nil {
+		return err
+	}
+
+	updateTmp := isUpdateNeeded()
+	update = update || updateTmp
+
+	if update {
+		r.Logger().Info(fmt.Sprintf("Updated object %s", objectInfo))
+		return r.Client().Update(context.TODO(), existing)
+	}
+
+	return nil
+}
+
+// deleteResourceIfExists deletes the object with the given name when it
+// exists. Missing APIs, like Routes out of OpenShift, are ignored
+func (r *StandaloneApicastReconciler) deleteResourceIfExists(name string, existing common.KubernetesObject) error {
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.apicast.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+
+	r.Logger().Info(fmt.Sprintf("Delete object %s", ObjectInfo(existing)))
+	return r.Client().Delete(context.TODO(), existing)
+}
+
+func (r *StandaloneApicastReconciler) createResource(obj common.KubernetesObject) error {
+	obj.SetNamespace(r.apicast.Namespace)
+	if err := controllerutil.SetControllerReference(r.apicast, obj, r.Scheme()); err != nil {
+		return err
+	}
+
+	r.Logger().Info(fmt.Sprintf("Created object %s", ObjectInfo(obj)))
+	return r.Client().Create(context.TODO(), obj) // don't wrap error
+}
+
+func (r *StandaloneApicastReconciler) ensureOwnerReference(obj common.KubernetesObject) (bool, error) {
+	originalSize := len(obj.GetOwnerReferences())
+	err := controllerutil.SetControllerReference(r.apicast, obj, r.Scheme())
+	if err != nil {
+		return false, err
+	}
+
+	return originalSize != len(obj.GetOwnerReferences()), nil
+}
@@ -0,0 +1,153 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func testStandaloneApicast(t *testing.T) *appsv1alpha1.APIcast {
+	apicast := &appsv1alpha1.APIcast{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apicast",
+			Namespace: "operator-unittest",
+		},
+		Spec: appsv1alpha1.APIcastSpec{
+			AdminPortalCredentialsRef: v1.LocalObjectReference{Name: "apicast-admin-portal-credentials"},
+			ExposedHost:               &appsv1alpha1.APIcastExposedHostSpec{Host: "apicast.example.com"},
+		},
+	}
+	_, err := apicast.SetDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return apicast
+}
+
+func TestStandaloneApicastReconciler(t *testing.T) {
+	apicast := testStandaloneApicast(t)
+	baseReconciler, cl := testBaseReconciler(t, apicast)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+
+	_, err := NewStandaloneApicastReconciler(baseLogicReconciler, apicast).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "apicast-example-apicast", Namespace: apicast.Namespace}
+
+	deployment := &k8sappsv1.Deployment{}
+	err = cl.Get(context.TODO(), namespacedName, deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deployment.GetOwnerReferences()) != 1 || deployment.GetOwnerReferences()[0].Name != apicast.Name {
+		t.Errorf("unexpected owner references: %v", deployment.GetOwnerReferences())
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 1 {
+		t.Errorf("unexpected replicas: %v", deployment.Spec.Replicas)
+	}
+	env := deployment.Spec.Template.Spec.Containers[0].Env
+	idx := findEnvVar(env, "THREESCALE_PORTAL_ENDPOINT")
+	if idx < 0 {
+		t.Fatal("THREESCALE_PORTAL_ENDPOINT not found")
+	}
+	if secretKeyRef := env[idx].ValueFrom.SecretKeyRef; secretKeyRef == nil ||
+		secretKeyRef.Name != "apicast-admin-portal-credentials" ||
+		secretKeyRef.Key != appsv1alpha1.APIcastAdminPortalURLSecretKey {
+		t.Errorf("unexpected THREESCALE_PORTAL_ENDPOINT: %v", env[idx])
+	}
+
+	err = cl.Get(context.TODO(), namespacedName, &v1.Service{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	route := &routev1.Route{}
+	err = cl.Get(context.TODO(), namespacedName, route)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Spec.Host != "apicast.example.com" {
+		t.Errorf("unexpected route host: %s", route.Spec.Host)
+	}
+
+	err = cl.Get(context.TODO(), namespacedName, &extensions.Ingress{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("ingress should not exist. Got: %v", err)
+	}
+}
+
+func TestStandaloneApicastReconcilerUpdate(t *testing.T) {
+	apicast := testStandaloneApicast(t)
+	baseReconciler, cl := testBaseReconciler(t, apicast)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+
+	_, err := NewStandaloneApicastReconciler(baseLogicReconciler, apicast).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var replicas int64 = 3
+	image := "quay.io/3scale/apicast:custom"
+	apicast.Spec.Replicas = &replicas
+	apicast.Spec.Image = &image
+	_, err = NewStandaloneApicastReconciler(baseLogicReconciler, apicast).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deployment := &k8sappsv1.Deployment{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "apicast-example-apicast", Namespace: apicast.Namespace}, deployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 3 {
+		t.Errorf("unexpected replicas: %v", deployment.Spec.Replicas)
+	}
+	if deployment.Spec.Template.Spec.Containers[0].Image != image {
+		t.Errorf("unexpected image: %s", deployment.Spec.Template.Spec.Containers[0].Image)
+	}
+}
+
+func TestStandaloneApicastReconcilerSwitchToIngress(t *testing.T) {
+	apicast := testStandaloneApicast(t)
+	baseReconciler, cl := testBaseReconciler(t, apicast)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+
+	_, err := NewStandaloneApicastReconciler(baseLogicReconciler, apicast).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exposureType := appsv1alpha1.ExposureTypeIngress
+	apicast.Spec.ExposedHost.ExposureType = &exposureType
+	_, err = NewStandaloneApicastReconciler(baseLogicReconciler, apicast).Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "apicast-example-apicast", Namespace: apicast.Namespace}
+
+	ingress := &extensions.Ingress{}
+	err = cl.Get(context.TODO(), namespacedName, ingress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ingress.Spec.Rules) != 1 || ingress.Spec.Rules[0].Host != "apicast.example.com" {
+		t.Errorf("unexpected ingress rules: %v", ingress.Spec.Rules)
+	}
+
+	err = cl.Get(context.TODO(), namespacedName, &routev1.Route{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("route should have been deleted. Got: %v", err)
+	}
+}
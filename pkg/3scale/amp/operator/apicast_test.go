@@ -1,9 +1,18 @@
 package operator
 
 import (
+	"context"
+	"reflect"
 	"testing"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestGetApicastOptions(t *testing.T) {
@@ -58,3 +67,476 @@ func TestGetApicastOptions(t *testing.T) {
 	}
 
 }
+
+func TestGetApicastOptionsResourcesOverride(t *testing.T) {
+	wildcardDomain := "test.3scale.net"
+	appLabel := "someLabel"
+	tenantName := "someTenant"
+	apicastManagementAPI := "disabled"
+	trueValue := true
+	falseValue := false
+	var oneValue int64 = 1
+	productionResources := v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("2"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+
+	apimanager := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			WildcardDomain:               wildcardDomain,
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &trueValue,
+			TenantName:                   &tenantName,
+			ResourceRequirementsEnabled:  &falseValue,
+		},
+		Apicast: &appsv1alpha1.ApicastSpec{
+			ApicastManagementAPI: &apicastManagementAPI,
+			OpenSSLVerify:        &trueValue,
+			IncludeResponseCodes: &trueValue,
+			StagingSpec: &appsv1alpha1.ApicastStagingSpec{
+				Replicas: &oneValue,
+			},
+			ProductionSpec: &appsv1alpha1.ApicastProductionSpec{
+				Replicas:  &oneValue,
+				Resources: &productionResources,
+			},
+		},
+	}
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: apimanager}
+	opts, err := optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apicast := component.NewApicast(opts)
+	productionDC := apicast.ProductionDeploymentConfig()
+	if !helper.CmpResources(&productionDC.Spec.Template.Spec.Containers[0].Resources, &productionResources) {
+		t.Errorf("apicast-production resources not overridden: %v", productionDC.Spec.Template.Spec.Containers[0].Resources)
+	}
+
+	stagingDC := apicast.StagingDeploymentConfig()
+	emptyResources := v1.ResourceRequirements{}
+	if !helper.CmpResources(&stagingDC.Spec.Template.Spec.Containers[0].Resources, &emptyResources) {
+		t.Errorf("apicast-staging resources not empty: %v", stagingDC.Spec.Template.Spec.Containers[0].Resources)
+	}
+}
+
+func TestGetApicastOptionsPodPlacement(t *testing.T) {
+	wildcardDomain := "test.3scale.net"
+	appLabel := "someLabel"
+	tenantName := "someTenant"
+	apicastManagementAPI := "disabled"
+	trueValue := true
+	var oneValue int64 = 1
+	priorityClassName := "edge-priority"
+	scheduleAnyway := appsv1alpha1.TopologySpreadScheduleAnyway
+	productionPlacement := appsv1alpha1.PodPlacementSpec{
+		NodeSelector: map[string]string{"node-role.kubernetes.io/edge": "true"},
+		Tolerations: []v1.Toleration{
+			v1.Toleration{Key: "edge", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+		},
+		Affinity: &v1.Affinity{
+			PodAntiAffinity: &v1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+					{Weight: 10, PodAffinityTerm: v1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+				},
+			},
+		},
+		PriorityClassName: &priorityClassName,
+		TopologySpreadConstraints: []appsv1alpha1.TopologySpreadConstraint{
+			{TopologyKey: "topology.kubernetes.io/zone"},
+			{TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: &scheduleAnyway},
+		},
+	}
+
+	apimanager := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			WildcardDomain:               wildcardDomain,
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &trueValue,
+			TenantName:                   &tenantName,
+			ResourceRequirementsEnabled:  &trueValue,
+		},
+		Apicast: &appsv1alpha1.ApicastSpec{
+			ApicastManagementAPI: &apicastManagementAPI,
+			OpenSSLVerify:        &trueValue,
+			IncludeResponseCodes: &trueValue,
+			StagingSpec: &appsv1alpha1.ApicastStagingSpec{
+				Replicas: &oneValue,
+			},
+			ProductionSpec: &appsv1alpha1.ApicastProductionSpec{
+				Replicas:  &oneValue,
+				Placement: &productionPlacement,
+			},
+		},
+	}
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: apimanager}
+	opts, err := optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apicast := component.NewApicast(opts)
+	productionPodSpec := apicast.ProductionDeploymentConfig().Spec.Template.Spec
+	if !reflect.DeepEqual(productionPodSpec.NodeSelector, productionPlacement.NodeSelector) {
+		t.Errorf("apicast-production node selector not set: %v", productionPodSpec.NodeSelector)
+	}
+	if !reflect.DeepEqual(productionPodSpec.Tolerations, productionPlacement.Tolerations) {
+		t.Errorf("apicast-production tolerations not set: %v", productionPodSpec.Tolerations)
+	}
+	if productionPodSpec.PriorityClassName != priorityClassName {
+		t.Errorf("apicast-production priority class name not set: %s", productionPodSpec.PriorityClassName)
+	}
+
+	// The topology spread constraints are emulated with anti-affinity terms
+	// on the apicast-production pods, added to the anti-affinity of the spec
+	podSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"deploymentConfig": "apicast-production"}}
+	expectedAntiAffinity := &v1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+			{LabelSelector: podSelector, TopologyKey: "topology.kubernetes.io/zone"},
+		},
+		PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+			{Weight: 10, PodAffinityTerm: v1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+			{Weight: 100, PodAffinityTerm: v1.PodAffinityTerm{LabelSelector: podSelector, TopologyKey: "kubernetes.io/hostname"}},
+		},
+	}
+	if productionPodSpec.Affinity == nil || !reflect.DeepEqual(productionPodSpec.Affinity.PodAntiAffinity, expectedAntiAffinity) {
+		t.Errorf("apicast-production topology spread constraints not emulated: %v", productionPodSpec.Affinity)
+	}
+	if len(productionPlacement.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution) != 1 {
+		t.Error("apicast-production placement spec affinity modified")
+	}
+
+	stagingPodSpec := apicast.StagingDeploymentConfig().Spec.Template.Spec
+	if stagingPodSpec.NodeSelector != nil || stagingPodSpec.Tolerations != nil || stagingPodSpec.Affinity != nil || stagingPodSpec.PriorityClassName != "" {
+		t.Errorf("apicast-staging placement not empty: %v", stagingPodSpec)
+	}
+}
+
+func TestGetApicastOptionsCustomPolicies(t *testing.T) {
+	wildcardDomain := "test.3scale.net"
+	appLabel := "someLabel"
+	tenantName := "someTenant"
+	apicastManagementAPI := "disabled"
+	namespace := "operator-unittest"
+	trueValue := true
+	var oneValue int64 = 1
+	policyImage := "quay.io/example/my-policy:1.0"
+
+	apimanager := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			WildcardDomain:               wildcardDomain,
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &trueValue,
+			TenantName:                   &tenantName,
+			ResourceRequirementsEnabled:  &trueValue,
+		},
+		Apicast: &appsv1alpha1.ApicastSpec{
+			ApicastManagementAPI: &apicastManagementAPI,
+			OpenSSLVerify:        &trueValue,
+			IncludeResponseCodes: &trueValue,
+			StagingSpec: &appsv1alpha1.ApicastStagingSpec{
+				Replicas: &oneValue,
+			},
+			ProductionSpec: &appsv1alpha1.ApicastProductionSpec{
+				Replicas: &oneValue,
+			},
+			CustomPolicies: []appsv1alpha1.CustomPolicySpec{
+				{Name: "my-cm-policy", Version: "0.1", ConfigMapRef: &v1.LocalObjectReference{Name: "my-cm-policy"}},
+				{Name: "my-image-policy", Version: "1.0", Image: &policyImage},
+			},
+		},
+	}
+	policyConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cm-policy", Namespace: namespace},
+		Data: map[string]string{
+			"init.lua":            "return require('my-cm-policy')",
+			"apicast-policy.json": "{}",
+		},
+	}
+	cl := fake.NewFakeClient(policyConfigMap)
+
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: apimanager, Namespace: namespace, Client: cl}
+	opts, err := optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apicast := component.NewApicast(opts)
+	for _, dc := range []*appsv1.DeploymentConfig{apicast.StagingDeploymentConfig(), apicast.ProductionDeploymentConfig()} {
+		podSpec := dc.Spec.Template.Spec
+		if len(podSpec.Volumes) != 2 || podSpec.Volumes[0].ConfigMap == nil || podSpec.Volumes[1].EmptyDir == nil {
+			t.Errorf("%s unexpected custom policy volumes: %v", dc.Name, podSpec.Volumes)
+		}
+		volumeMounts := podSpec.Containers[0].VolumeMounts
+		if len(volumeMounts) != 2 ||
+			volumeMounts[0].MountPath != "/opt/app-root/src/policies/my-cm-policy/0.1" ||
+			volumeMounts[1].MountPath != "/opt/app-root/src/policies/my-image-policy/1.0" {
+			t.Errorf("%s unexpected custom policy volume mounts: %v", dc.Name, volumeMounts)
+		}
+		initContainer := podSpec.InitContainers[len(podSpec.InitContainers)-1]
+		if initContainer.Image != policyImage {
+			t.Errorf("%s custom policy init container not found: %v", dc.Name, podSpec.InitContainers)
+		}
+		if dc.Spec.Template.Annotations[component.ApicastCustomPoliciesHashAnnotation] == "" {
+			t.Errorf("%s custom policies hash annotation not set", dc.Name)
+		}
+	}
+
+	// Changes in the policy ConfigMap content change the hash
+	hash := apicast.ProductionDeploymentConfig().Spec.Template.Annotations[component.ApicastCustomPoliciesHashAnnotation]
+	policyConfigMap.Data["init.lua"] = "return require('my-cm-policy-v2')"
+	err = cl.Update(context.TODO(), policyConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err = optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newHash := component.NewApicast(opts).ProductionDeploymentConfig().Spec.Template.Annotations[component.ApicastCustomPoliciesHashAnnotation]
+	if newHash == hash {
+		t.Error("custom policies hash not changed after ConfigMap update")
+	}
+}
+
+func TestGetApicastOptionsCustomPolicyImageRegistryMirror(t *testing.T) {
+	wildcardDomain := "test.3scale.net"
+	appLabel := "someLabel"
+	tenantName := "someTenant"
+	apicastManagementAPI := "disabled"
+	namespace := "operator-unittest"
+	trueValue := true
+	var oneValue int64 = 1
+	policyImage := "quay.io/example/my-policy:1.0"
+	mirror := "mirror.example.com"
+
+	apimanager := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			WildcardDomain:               wildcardDomain,
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &trueValue,
+			TenantName:                   &tenantName,
+			ResourceRequirementsEnabled:  &trueValue,
+			ImageRegistryMirror:          &mirror,
+		},
+		Apicast: &appsv1alpha1.ApicastSpec{
+			ApicastManagementAPI: &apicastManagementAPI,
+			OpenSSLVerify:        &trueValue,
+			IncludeResponseCodes: &trueValue,
+			StagingSpec: &appsv1alpha1.ApicastStagingSpec{
+				Replicas: &oneValue,
+			},
+			ProductionSpec: &appsv1alpha1.ApicastProductionSpec{
+				Replicas: &oneValue,
+			},
+			CustomPolicies: []appsv1alpha1.CustomPolicySpec{
+				{Name: "my-image-policy", Version: "1.0", Image: &policyImage},
+			},
+		},
+	}
+
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: apimanager, Namespace: namespace, Client: fake.NewFakeClient()}
+	opts, err := optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedImage := "mirror.example.com/example/my-policy:1.0"
+	podSpec := component.NewApicast(opts).ProductionDeploymentConfig().Spec.Template.Spec
+	initContainer := podSpec.InitContainers[len(podSpec.InitContainers)-1]
+	if initContainer.Image != expectedImage {
+		t.Errorf("custom policy image (%s) not the expected (%s)", initContainer.Image, expectedImage)
+	}
+	if specImage := *apimanager.Apicast.CustomPolicies[0].Image; specImage != policyImage {
+		t.Errorf("custom policy image mirrored in the APIManager spec: %s", specImage)
+	}
+}
+
+func TestGetApicastOptionsRuntimeSettings(t *testing.T) {
+	wildcardDomain := "test.3scale.net"
+	appLabel := "someLabel"
+	tenantName := "someTenant"
+	apicastManagementAPI := "disabled"
+	namespace := "operator-unittest"
+	trueValue := true
+	var oneValue int64 = 1
+	logLevel := "debug"
+	var workers int32 = 4
+	lazyLoader := "lazy"
+
+	apimanager := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			WildcardDomain:               wildcardDomain,
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &trueValue,
+			TenantName:                   &tenantName,
+			ResourceRequirementsEnabled:  &trueValue,
+		},
+		Apicast: &appsv1alpha1.ApicastSpec{
+			ApicastManagementAPI: &apicastManagementAPI,
+			OpenSSLVerify:        &trueValue,
+			IncludeResponseCodes: &trueValue,
+			StagingSpec: &appsv1alpha1.ApicastStagingSpec{
+				Replicas: &oneValue,
+				ApicastRuntimeSpec: appsv1alpha1.ApicastRuntimeSpec{
+					ConfigurationLoader: &lazyLoader,
+				},
+			},
+			ProductionSpec: &appsv1alpha1.ApicastProductionSpec{
+				Replicas: &oneValue,
+				ApicastRuntimeSpec: appsv1alpha1.ApicastRuntimeSpec{
+					LogLevel:     &logLevel,
+					Workers:      &workers,
+					PathRouting:  &trueValue,
+					ServicesList: []string{"1", "2"},
+					CustomEnvironments: []appsv1alpha1.CustomEnvironmentSpec{
+						{ConfigMapRef: v1.LocalObjectReference{Name: "my-environment"}},
+					},
+				},
+			},
+		},
+	}
+	environmentConfigMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-environment", Namespace: namespace},
+		Data: map[string]string{
+			"custom.lua": "return { policy_chain = require('apicast.policy_chain').default() }",
+		},
+	}
+	cl := fake.NewFakeClient(environmentConfigMap)
+
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: apimanager, Namespace: namespace, Client: cl}
+	opts, err := optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apicast := component.NewApicast(opts)
+	envValue := func(dc *appsv1.DeploymentConfig, name string) string {
+		for _, envVar := range dc.Spec.Template.Spec.Containers[0].Env {
+			if envVar.Name == name {
+				return envVar.Value
+			}
+		}
+		return ""
+	}
+
+	production := apicast.ProductionDeploymentConfig()
+	expectedProductionEnv := map[string]string{
+		"APICAST_LOG_LEVEL":     "debug",
+		"APICAST_WORKERS":       "4",
+		"APICAST_PATH_ROUTING":  "true",
+		"APICAST_SERVICES_LIST": "1,2",
+		"APICAST_ENVIRONMENT":   "/opt/app-root/src/environments/my-environment/custom.lua",
+	}
+	for name, expected := range expectedProductionEnv {
+		if value := envValue(production, name); value != expected {
+			t.Errorf("unexpected production %s. Expected: '%s', got: '%s'", name, expected, value)
+		}
+	}
+	if production.Spec.Template.Annotations[component.ApicastCustomEnvironmentsHashAnnotation] == "" {
+		t.Error("production custom environments hash annotation not set")
+	}
+
+	staging := apicast.StagingDeploymentConfig()
+	if value := envValue(staging, "APICAST_CONFIGURATION_LOADER"); value != "lazy" {
+		t.Errorf("unexpected staging APICAST_CONFIGURATION_LOADER. Expected: 'lazy', got: '%s'", value)
+	}
+	if value := envValue(staging, "APICAST_LOG_LEVEL"); value != "" {
+		t.Errorf("unexpected staging APICAST_LOG_LEVEL: '%s'", value)
+	}
+	if _, ok := staging.Spec.Template.Annotations[component.ApicastCustomEnvironmentsHashAnnotation]; ok {
+		t.Error("staging custom environments hash annotation set")
+	}
+}
+
+func TestGetApicastOptionsHTTPS(t *testing.T) {
+	wildcardDomain := "test.3scale.net"
+	appLabel := "someLabel"
+	tenantName := "someTenant"
+	apicastManagementAPI := "disabled"
+	namespace := "operator-unittest"
+	trueValue := true
+	var oneValue int64 = 1
+	reencrypt := appsv1alpha1.ApicastHTTPSRouteTerminationReencrypt
+
+	apimanager := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			WildcardDomain:               wildcardDomain,
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &trueValue,
+			TenantName:                   &tenantName,
+			ResourceRequirementsEnabled:  &trueValue,
+		},
+		Apicast: &appsv1alpha1.ApicastSpec{
+			ApicastManagementAPI: &apicastManagementAPI,
+			OpenSSLVerify:        &trueValue,
+			IncludeResponseCodes: &trueValue,
+			StagingSpec: &appsv1alpha1.ApicastStagingSpec{
+				Replicas: &oneValue,
+			},
+			ProductionSpec: &appsv1alpha1.ApicastProductionSpec{
+				Replicas: &oneValue,
+			},
+			HTTPS: &appsv1alpha1.ApicastHTTPSSpec{
+				CertificateSecretRef: v1.LocalObjectReference{Name: "apicast-tls"},
+				RouteTermination:     &reencrypt,
+			},
+		},
+	}
+	certificateSecret := helper.GetTestSecret(namespace, "apicast-tls", map[string]string{
+		"tls.crt": "certificate",
+		"tls.key": "key",
+		"ca.crt":  "ca certificate",
+	})
+	cl := fake.NewFakeClient(certificateSecret)
+
+	optsProvider := OperatorApicastOptionsProvider{APIManagerSpec: apimanager, Namespace: namespace, Client: cl}
+	opts, err := optsProvider.GetApicastOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	apicast := component.NewApicast(opts)
+	for _, dc := range []*appsv1.DeploymentConfig{apicast.StagingDeploymentConfig(), apicast.ProductionDeploymentConfig()} {
+		container := dc.Spec.Template.Spec.Containers[0]
+		env := map[string]string{}
+		for _, envVar := range container.Env {
+			env[envVar.Name] = envVar.Value
+		}
+		if env["APICAST_HTTPS_PORT"] != "8443" ||
+			env["APICAST_HTTPS_CERTIFICATE"] != "/var/run/secrets/apicast-https/tls.crt" ||
+			env["APICAST_HTTPS_CERTIFICATE_KEY"] != "/var/run/secrets/apicast-https/tls.key" {
+			t.Errorf("%s unexpected HTTPS env: %v", dc.Name, container.Env)
+		}
+		lastPort := container.Ports[len(container.Ports)-1]
+		if lastPort.Name != "https" || lastPort.ContainerPort != 8443 {
+			t.Errorf("%s HTTPS container port not found: %v", dc.Name, container.Ports)
+		}
+		volumes := dc.Spec.Template.Spec.Volumes
+		if len(volumes) != 1 || volumes[0].Secret == nil || volumes[0].Secret.SecretName != "apicast-tls" {
+			t.Errorf("%s unexpected HTTPS volumes: %v", dc.Name, volumes)
+		}
+		if dc.Spec.Template.Annotations[component.ApicastHTTPSCertificateHashAnnotation] == "" {
+			t.Errorf("%s HTTPS certificate hash annotation not set", dc.Name)
+		}
+	}
+
+	servicePorts := apicast.ProductionService().Spec.Ports
+	if servicePorts[len(servicePorts)-1].Name != "https" {
+		t.Errorf("HTTPS service port not found: %v", servicePorts)
+	}
+
+	route := apicast.ProductionHTTPSRoute()
+	if route == nil {
+		t.Fatal("HTTPS route not returned")
+	}
+	if route.Spec.TLS.Termination != "reencrypt" || route.Spec.TLS.DestinationCACertificate != "ca certificate" {
+		t.Errorf("unexpected HTTPS route TLS config: %v", route.Spec.TLS)
+	}
+	if route.Spec.Host != "api-someTenant-apicast-production.test.3scale.net" {
+		t.Errorf("unexpected HTTPS route host: %s", route.Spec.Host)
+	}
+}
@@ -0,0 +1,184 @@
+package operator
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// backupSecretNames are the secrets of an APIManager backed up and
+// restored. The file storage S3 secret is added when configured
+var backupSecretNames = []string{
+	component.BackendSecretInternalApiSecretName,
+	component.BackendSecretBackendListenerSecretName,
+	component.BackendSecretBackendRedisSecretName,
+	component.SystemSecretSystemAppSecretName,
+	component.SystemSecretSystemDatabaseSecretName,
+	component.SystemSecretSystemEventsHookSecretName,
+	component.SystemSecretSystemMasterApicastSecretName,
+	component.SystemSecretSystemMemcachedSecretName,
+	component.SystemSecretSystemRecaptchaSecretName,
+	component.SystemSecretSystemRedisSecretName,
+	component.SystemSecretSystemSeedSecretName,
+	component.SystemSecretSystemSMTPSecretName,
+	component.ZyncSecretName,
+}
+
+func (o *OperatorBackupOptionsProvider) GetBackupOptions() (*component.BackupOptions, error) {
+	optProv := component.BackupOptionsBuilder{}
+	optProv.Name(o.Name)
+	optProv.PersistentVolumeClaim(o.PersistentVolumeClaim)
+	optProv.StorageClassName(o.StorageClassName)
+	if o.VolumeSize != nil {
+		volumeSize, err := resource.ParseQuantity(*o.VolumeSize)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create Backup Options - %s", err)
+		}
+		optProv.VolumeSize(volumeSize)
+	}
+
+	o.setImageOptions(&optProv)
+	if o.APIManager != nil {
+		err := o.setAPIManagerOptions(&optProv)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create Backup Options - %s", err)
+		}
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Backup Options - %s", err)
+	}
+	return res, nil
+}
+
+func (o *OperatorBackupOptionsProvider) setAPIManagerOptions(b *component.BackupOptionsBuilder) error {
+	spec := &o.APIManager.Spec
+	b.AppLabel(*spec.AppLabel)
+
+	databaseType, err := o.systemDatabaseType()
+	if err != nil {
+		return err
+	}
+	b.SystemDatabaseType(databaseType)
+
+	var databaseImage *string
+	if spec.System != nil && spec.System.DatabaseSpec != nil {
+		if mysqlSpec := spec.System.DatabaseSpec.MySQL; mysqlSpec != nil {
+			databaseImage = mysqlSpec.Image
+		}
+		if postgreSQLSpec := spec.System.DatabaseSpec.PostgreSQL; postgreSQLSpec != nil {
+			databaseImage = postgreSQLSpec.Image
+		}
+	}
+	if databaseType == component.BackupSystemDatabaseTypePostgreSQL {
+		b.SystemDatabaseImage(o.imageURL(databaseImage, SystemPostgreSQLImageURL()))
+	} else {
+		b.SystemDatabaseImage(o.imageURL(databaseImage, SystemMySQLImageURL()))
+	}
+
+	queuesInstance, err := o.backendRedisQueuesInstance()
+	if err != nil {
+		return err
+	}
+	b.BackendRedisQueuesInstance(queuesInstance)
+
+	secretNames := append([]string{}, backupSecretNames...)
+	if spec.System != nil && spec.System.FileStorageSpec != nil && spec.System.FileStorageSpec.S3 != nil {
+		secretNames = append(secretNames, spec.System.FileStorageSpec.S3.ConfigurationSecretRef.Name)
+	} else {
+		b.SystemStoragePVC(true)
+	}
+	b.SecretNames(secretNames)
+
+	return nil
+}
+
+// setImageOptions sets the images of the operator release, or the images
+// set in the APIManager spec. The system database image depends on the
+// database type, it is set with the APIManager options
+func (o *OperatorBackupOptionsProvider) setImageOptions(b *component.BackupOptionsBuilder) {
+	var systemImage, systemRedisImage, backendRedisImage, zyncDatabaseImage *string
+	if o.APIManager != nil {
+		spec := &o.APIManager.Spec
+		if spec.System != nil {
+			systemImage = spec.System.Image
+			systemRedisImage = spec.System.RedisImage
+		}
+		if spec.Backend != nil {
+			backendRedisImage = spec.Backend.RedisImage
+		}
+		if spec.Zync != nil {
+			zyncDatabaseImage = spec.Zync.PostgreSQLImage
+		}
+	}
+
+	b.SystemImage(o.imageURL(systemImage, SystemImageURL()))
+	b.SystemRedisImage(o.imageURL(systemRedisImage, SystemRedisImageURL()))
+	b.BackendRedisImage(o.imageURL(backendRedisImage, BackendRedisImageURL()))
+	b.ZyncDatabaseImage(o.imageURL(zyncDatabaseImage, ZyncPostgreSQLImageURL()))
+	b.CLIImage(o.imageURL(nil, CLIImageURL()))
+}
+
+// imageURL returns the image set in the spec, or the image of the
+// release, pulled from the registry mirror of the APIManager when set
+func (o *OperatorBackupOptionsProvider) imageURL(specImageURL *string, releaseImageURL string) string {
+	imageURL := releaseImageURL
+	if specImageURL != nil {
+		imageURL = *specImageURL
+	}
+	if o.APIManager == nil {
+		return imageURL
+	}
+	return mirroredImageURL(&o.APIManager.Spec, imageURL)
+}
+
+// systemDatabaseType returns the system database type from the scheme of
+// the system-database secret URL. It covers the external databases too
+func (o *OperatorBackupOptionsProvider) systemDatabaseType() (string, error) {
+	secret, err := helper.GetSecret(component.SystemSecretSystemDatabaseSecretName, o.Namespace, o.Client)
+	if err != nil {
+		return "", err
+	}
+
+	rawURL := helper.GetSecretDataValueOrDefault(secret.Data, component.SystemSecretSystemDatabaseURLFieldName, "")
+	databaseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("'%s' field of '%s' secret is not a valid URL", component.SystemSecretSystemDatabaseURLFieldName, component.SystemSecretSystemDatabaseSecretName)
+	}
+
+	switch databaseURL.Scheme {
+	case "mysql2":
+		return component.BackupSystemDatabaseTypeMySQL, nil
+	case "postgresql", "postgres":
+		return component.BackupSystemDatabaseTypePostgreSQL, nil
+	}
+	return "", fmt.Errorf("unsupported scheme '%s' in '%s' field of '%s' secret", databaseURL.Scheme, component.SystemSecretSystemDatabaseURLFieldName, component.SystemSecretSystemDatabaseSecretName)
+}
+
+// backendRedisQueuesInstance returns true when the backend queues are not
+// stored in the backend redis storage instance. The databases of one
+// instance are all in the same snapshot
+func (o *OperatorBackupOptionsProvider) backendRedisQueuesInstance() (bool, error) {
+	secret, err := helper.GetSecret(component.BackendSecretBackendRedisSecretName, o.Namespace, o.Client)
+	if err != nil {
+		return false, err
+	}
+
+	instances := []string{}
+	for _, fieldName := range []string{component.BackendSecretBackendRedisStorageURLFieldName, component.BackendSecretBackendRedisQueuesURLFieldName} {
+		rawURL := helper.GetSecretDataValueOrDefault(secret.Data, fieldName, "")
+		redisURL, err := url.Parse(rawURL)
+		if err != nil {
+			return false, fmt.Errorf("'%s' field of '%s' secret is not a valid URL", fieldName, component.BackendSecretBackendRedisSecretName)
+		}
+		instances = append(instances, redisURL.Host)
+	}
+	storageSentinelHosts := helper.GetSecretDataValueOrDefault(secret.Data, component.BackendSecretBackendRedisStorageSentinelHostsFieldName, "")
+	queuesSentinelHosts := helper.GetSecretDataValueOrDefault(secret.Data, component.BackendSecretBackendRedisQueuesSentinelHostsFieldName, "")
+
+	return instances[0] != instances[1] || storageSentinelHosts != queuesSentinelHosts, nil
+}
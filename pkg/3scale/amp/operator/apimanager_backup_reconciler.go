@@ -0,0 +1,189 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// jobStepState is the state of the Job running a backup or restore step
+type jobStepState int
+
+const (
+	jobStepRunning jobStepState = iota
+	jobStepSucceeded
+	jobStepFailed
+)
+
+// APIManagerBackupReconciler runs the backup steps one after the other. The
+// progress is recorded in the backup status, updated by the caller
+type APIManagerBackupReconciler struct {
+	BaseLogicReconciler
+	backup *appsv1alpha1.APIManagerBackup
+}
+
+// blank assignment to verify that APIManagerBackupReconciler implements LogicReconciler
+var _ LogicReconciler = &APIManagerBackupReconciler{}
+
+func NewAPIManagerBackupReconciler(baseLogicReconciler BaseLogicReconciler, backup *appsv1alpha1.APIManagerBackup) *APIManagerBackupReconciler {
+	return &APIManagerBackupReconciler{
+		BaseLogicReconciler: baseLogicReconciler,
+		backup:              backup,
+	}
+}
+
+func (r *APIManagerBackupReconciler) Reconcile() (reconcile.Result, error) {
+	status := &r.backup.Status
+	if r.backup.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	if status.Phase == "" {
+		status.Phase = appsv1alpha1.BackupPhasePending
+		status.StartTime = nowTime()
+	}
+
+	apimanager := &appsv1alpha1.APIManager{}
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: r.backup.Spec.APIManagerName, Namespace: r.backup.Namespace}, apimanager)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.failBackup(fmt.Sprintf("APIManager %s not found", r.backup.Spec.APIManagerName))
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	optsProvider := OperatorBackupOptionsProvider{
+		Name:                  r.backup.Name,
+		APIManager:            apimanager,
+		PersistentVolumeClaim: r.backup.BackupPersistentVolumeClaimName(),
+		StorageClassName:      r.backup.Spec.BackupDestination.PersistentVolumeClaim.StorageClassName,
+		VolumeSize:            r.backup.Spec.BackupDestination.PersistentVolumeClaim.VolumeSize,
+		Namespace:             r.backup.Namespace,
+		Client:                r.Client(),
+	}
+	opts, err := optsProvider.GetBackupOptions()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	backup := component.NewAPIManagerBackup(opts)
+
+	err = r.reconcilePersistentVolumeClaim(backup)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	status.PersistentVolumeClaimName = r.backup.BackupPersistentVolumeClaimName()
+
+	for _, step := range component.BackupSteps(opts) {
+		if findBackupArtifact(status.Artifacts, step) != nil {
+			continue
+		}
+
+		state, err := runJobStep(r.BaseLogicReconciler, r.backup, backup.Job(step))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		switch state {
+		case jobStepRunning:
+			status.Phase = appsv1alpha1.BackupPhaseRunning
+			status.CurrentStep = step
+			status.Message = fmt.Sprintf("Backing up %s", step)
+			return reconcile.Result{}, nil
+		case jobStepFailed:
+			r.failBackup(fmt.Sprintf("Job %s failed", component.BackupJobName(r.backup.Name, "backup", step)))
+			return reconcile.Result{}, nil
+		}
+
+		status.Artifacts = append(status.Artifacts, appsv1alpha1.BackupArtifact{
+			Step:           step,
+			Path:           component.BackupArtifactPath(step),
+			CompletionTime: nowTime(),
+		})
+	}
+
+	status.Phase = appsv1alpha1.BackupPhaseCompleted
+	status.CurrentStep = ""
+	status.Message = "Backup completed"
+	status.CompletionTime = nowTime()
+	return reconcile.Result{}, nil
+}
+
+// reconcilePersistentVolumeClaim creates the PersistentVolumeClaim of the
+// artifacts unless an existing one is used
+func (r *APIManagerBackupReconciler) reconcilePersistentVolumeClaim(backup *component.APIManagerBackup) error {
+	claimName := r.backup.BackupPersistentVolumeClaimName()
+	existing := &v1.PersistentVolumeClaim{}
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: r.backup.Namespace}, existing)
+	if err == nil || !errors.IsNotFound(err) {
+		return err
+	}
+
+	if r.backup.Spec.BackupDestination.PersistentVolumeClaim.ClaimName != nil {
+		return fmt.Errorf("PersistentVolumeClaim %s not found", claimName)
+	}
+
+	desired := backup.PersistentVolumeClaim()
+	desired.Namespace = r.backup.Namespace
+	r.Logger().Info(fmt.Sprintf("Created object %s", ObjectInfo(desired)))
+	return r.Client().Create(context.TODO(), desired)
+}
+
+func (r *APIManagerBackupReconciler) failBackup(message string) {
+	r.backup.Status.Phase = appsv1alpha1.BackupPhaseFailed
+	r.backup.Status.Message = message
+	r.backup.Status.CompletionTime = nowTime()
+}
+
+// runJobStep creates the Job of a step, owned by the given resource, when
+// it does not exist and returns its state
+func runJobStep(r BaseLogicReconciler, owner common.KubernetesObject, desired *batchv1.Job) (jobStepState, error) {
+	existing := &batchv1.Job{}
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: owner.GetNamespace()}, existing)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return jobStepRunning, err
+		}
+
+		desired.Namespace = owner.GetNamespace()
+		err = controllerutil.SetControllerReference(owner, desired, r.Scheme())
+		if err != nil {
+			return jobStepRunning, err
+		}
+		r.Logger().Info(fmt.Sprintf("Created object %s", ObjectInfo(desired)))
+		return jobStepRunning, r.Client().Create(context.TODO(), desired)
+	}
+
+	if existing.Status.Succeeded > 0 {
+		return jobStepSucceeded, nil
+	}
+	for _, condition := range existing.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == v1.ConditionTrue {
+			return jobStepFailed, nil
+		}
+	}
+	return jobStepRunning, nil
+}
+
+func findBackupArtifact(artifacts []appsv1alpha1.BackupArtifact, step string) *appsv1alpha1.BackupArtifact {
+	for idx := range artifacts {
+		if artifacts[idx].Step == step {
+			return &artifacts[idx]
+		}
+	}
+	return nil
+}
+
+func nowTime() *metav1.Time {
+	now := metav1.Now()
+	return &now
+}
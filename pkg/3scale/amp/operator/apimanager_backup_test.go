@@ -0,0 +1,58 @@
+package operator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+)
+
+func TestGetBackupOptionsImages(t *testing.T) {
+	namespace := "operator-unittest"
+	mirror := "mirror.example.com"
+	systemImage := "quay.io/myorg/porta:custom"
+	backendRedisImage := "quay.io/myorg/redis:custom"
+	os.Setenv("ZYNC_POSTGRESQL_IMAGE", "registry.example.com/myorg/postgresql:custom")
+	defer os.Unsetenv("ZYNC_POSTGRESQL_IMAGE")
+
+	apimanager, secrets := backupTestObjects(namespace)
+	apimanager.Spec.ImageRegistryMirror = &mirror
+	apimanager.Spec.System = &appsv1alpha1.SystemSpec{Image: &systemImage}
+	apimanager.Spec.Backend = &appsv1alpha1.BackendSpec{RedisImage: &backendRedisImage}
+
+	_, cl := testBaseReconciler(t, secrets...)
+	optsProvider := OperatorBackupOptionsProvider{
+		Name:                  "example-apimanagerbackup",
+		PersistentVolumeClaim: "apimanager-backup-example-apimanagerbackup",
+		APIManager:            apimanager,
+		Namespace:             namespace,
+		Client:                cl,
+	}
+	opts, err := optsProvider.GetBackupOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := component.NewAPIManagerBackup(opts)
+
+	cases := []struct {
+		step     string
+		expected string
+	}{
+		{appsv1alpha1.BackupStepSecrets, component.RegistryMirrorImageURL(CLIImageURL(), mirror)},
+		{appsv1alpha1.BackupStepSystemDatabase, component.RegistryMirrorImageURL(SystemMySQLImageURL(), mirror)},
+		{appsv1alpha1.BackupStepSystemRedis, component.RegistryMirrorImageURL(SystemRedisImageURL(), mirror)},
+		{appsv1alpha1.BackupStepBackendRedis, "mirror.example.com/myorg/redis:custom"},
+		{appsv1alpha1.BackupStepZyncDatabase, "mirror.example.com/myorg/postgresql:custom"},
+		{appsv1alpha1.BackupStepSystemStorage, "mirror.example.com/myorg/porta:custom"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.step, func(subT *testing.T) {
+			image := backup.Job(tc.step).Spec.Template.Spec.Containers[0].Image
+			if image != tc.expected {
+				subT.Errorf("image does not match. Expected: %s, got: %s", tc.expected, image)
+			}
+		})
+	}
+}
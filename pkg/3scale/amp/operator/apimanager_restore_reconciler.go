@@ -0,0 +1,425 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/common"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// restoreComponentsPollPeriod is how often the restore checks that the
+// scaled down components are stopped. Their pods are not watched
+const restoreComponentsPollPeriod = 10 * time.Second
+
+// restoredComponents are the APIManager components of the
+// RestoredDeploymentConfigs. Their reconciliation is paused while they are
+// scaled down, so the operator does not scale them back up
+var restoredComponents = []appsv1alpha1.APIManagerComponentName{
+	appsv1alpha1.APIManagerSystemComponent,
+	appsv1alpha1.APIManagerBackendComponent,
+	appsv1alpha1.APIManagerZyncComponent,
+}
+
+// APIManagerRestoreReconciler runs the restore steps one after the other.
+// The secrets are restored first, so an APIManager deployed afterwards
+// uses them. Once the APIManager is available, the components writing to
+// the data stores are paused and scaled down, the data stores are
+// restored and the components are scaled back up last. The progress is
+// recorded in the restore status, updated by the caller
+type APIManagerRestoreReconciler struct {
+	BaseLogicReconciler
+	restore *appsv1alpha1.APIManagerRestore
+}
+
+// blank assignment to verify that APIManagerRestoreReconciler implements LogicReconciler
+var _ LogicReconciler = &APIManagerRestoreReconciler{}
+
+func NewAPIManagerRestoreReconciler(baseLogicReconciler BaseLogicReconciler, restore *appsv1alpha1.APIManagerRestore) *APIManagerRestoreReconciler {
+	return &APIManagerRestoreReconciler{
+		BaseLogicReconciler: baseLogicReconciler,
+		restore:             restore,
+	}
+}
+
+func (r *APIManagerRestoreReconciler) Reconcile() (reconcile.Result, error) {
+	status := &r.restore.Status
+	if r.restore.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	if status.Phase == "" {
+		status.Phase = appsv1alpha1.BackupPhasePending
+		status.StartTime = nowTime()
+	}
+
+	claimName := r.restore.Spec.RestoreSource.PersistentVolumeClaim.ClaimName
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: claimName, Namespace: r.restore.Namespace}, &v1.PersistentVolumeClaim{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			r.failRestore(fmt.Sprintf("PersistentVolumeClaim %s not found", claimName))
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	apimanager := &appsv1alpha1.APIManager{}
+	err = r.Client().Get(context.TODO(), types.NamespacedName{Name: r.restore.Spec.APIManagerName, Namespace: r.restore.Namespace}, apimanager)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		apimanager = nil
+	}
+
+	optsProvider := OperatorBackupOptionsProvider{
+		Name:                  r.restore.Name,
+		PersistentVolumeClaim: claimName,
+		Namespace:             r.restore.Namespace,
+		Client:                r.Client(),
+	}
+	// The secrets restore does not depend on the APIManager. The data store
+	// options are read from it once it is available. It is not available
+	// anymore once its components are scaled down
+	apimanagerReady := apimanager != nil &&
+		(status.ComponentsScaledDown || appsv1alpha1.IsConditionTrue(apimanager.Status.Conditions, appsv1alpha1.APIManagerAvailable))
+	if apimanagerReady {
+		optsProvider.APIManager = apimanager
+	}
+	opts, err := optsProvider.GetBackupOptions()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	restore := component.NewAPIManagerRestore(opts)
+
+	err = r.reconcileServiceAccount(restore)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, step := range component.BackupSteps(opts) {
+		if findBackupArtifact(status.Artifacts, step) != nil {
+			continue
+		}
+
+		if step != appsv1alpha1.BackupStepSecrets {
+			if !apimanagerReady {
+				status.Phase = appsv1alpha1.BackupPhaseRunning
+				status.CurrentStep = ""
+				status.Message = fmt.Sprintf("Waiting for APIManager %s to be available", r.restore.Spec.APIManagerName)
+				return reconcile.Result{}, nil
+			}
+
+			stopped, err := r.scaleDownComponents(apimanager)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+			if !stopped {
+				status.Phase = appsv1alpha1.BackupPhaseRunning
+				status.CurrentStep = appsv1alpha1.RestoreStepScaleDownComponents
+				status.Message = "Waiting for the components to be scaled down"
+				return reconcile.Result{RequeueAfter: restoreComponentsPollPeriod}, nil
+			}
+		}
+
+		state, err := runJobStep(r.BaseLogicReconciler, r.restore, restore.Job(step))
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		switch state {
+		case jobStepRunning:
+			status.Phase = appsv1alpha1.BackupPhaseRunning
+			status.CurrentStep = step
+			status.Message = fmt.Sprintf("Restoring %s", step)
+			return reconcile.Result{}, nil
+		case jobStepFailed:
+			r.failRestore(fmt.Sprintf("Job %s failed", component.BackupJobName(r.restore.Name, "restore", step)))
+			return reconcile.Result{}, nil
+		}
+
+		status.Artifacts = append(status.Artifacts, appsv1alpha1.BackupArtifact{
+			Step:           step,
+			Path:           component.BackupArtifactPath(step),
+			CompletionTime: nowTime(),
+		})
+	}
+
+	status.CurrentStep = appsv1alpha1.RestoreStepRestartComponents
+	if apimanager != nil {
+		err = r.scaleUpComponents(apimanager)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	status.Phase = appsv1alpha1.BackupPhaseCompleted
+	status.CurrentStep = ""
+	status.Message = "Restore completed"
+	status.CompletionTime = nowTime()
+	return reconcile.Result{}, nil
+}
+
+// reconcileServiceAccount creates the service account of the secrets
+// restore Job and grants it access to the secrets
+func (r *APIManagerRestoreReconciler) reconcileServiceAccount(restore *component.APIManagerRestore) error {
+	objects := []struct {
+		desired  common.KubernetesObject
+		existing common.KubernetesObject
+	}{
+		{restore.ServiceAccount(), &v1.ServiceAccount{}},
+		{restore.Role(), &rbacv1.Role{}},
+		{restore.RoleBinding(), &rbacv1.RoleBinding{}},
+	}
+
+	for _, object := range objects {
+		err := r.Client().Get(context.TODO(), types.NamespacedName{Name: object.desired.GetName(), Namespace: r.restore.Namespace}, object.existing)
+		if err == nil {
+			continue
+		}
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		object.desired.SetNamespace(r.restore.Namespace)
+		err = controllerutil.SetControllerReference(r.restore, object.desired, r.Scheme())
+		if err != nil {
+			return err
+		}
+		r.Logger().Info(fmt.Sprintf("Created object %s", ObjectInfo(object.desired)))
+		err = r.Client().Create(context.TODO(), object.desired)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaleDownComponents pauses the restored components and scales their
+// deployments down, recording the replicas to scale them back up to. True
+// once their pods are stopped
+func (r *APIManagerRestoreReconciler) scaleDownComponents(apimanager *appsv1alpha1.APIManager) (bool, error) {
+	status := &r.restore.Status
+	if !status.ComponentsScaledDown {
+		status.ComponentsScaledDown = true
+		status.PausedComponents = nil
+		for _, name := range restoredComponents {
+			if !apimanager.IsComponentPaused(name) {
+				status.PausedComponents = append(status.PausedComponents, string(name))
+			}
+		}
+	}
+
+	err := r.updatePausedComponents(apimanager, status.PausedComponents, true)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range component.RestoredDeploymentConfigs {
+		replicas, found, err := r.deploymentReplicas(apimanager, name)
+		if err != nil {
+			return false, err
+		}
+		if !found || replicas == 0 {
+			continue
+		}
+
+		if findScaledDownDeployment(status.ScaledDownDeployments, name) == nil {
+			status.ScaledDownDeployments = append(status.ScaledDownDeployments, appsv1alpha1.ScaledDownDeployment{
+				Name:     name,
+				Replicas: replicas,
+			})
+		}
+		err = r.scaleDeployment(apimanager, name, 0)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return r.componentsStopped()
+}
+
+// scaleUpComponents scales the deployments back up to their replicas
+// before the restore and resumes the reconciliation of the components
+// paused by the restore
+func (r *APIManagerRestoreReconciler) scaleUpComponents(apimanager *appsv1alpha1.APIManager) error {
+	status := &r.restore.Status
+	if !status.ComponentsScaledDown {
+		return nil
+	}
+
+	for _, deployment := range status.ScaledDownDeployments {
+		err := r.scaleDeployment(apimanager, deployment.Name, deployment.Replicas)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := r.updatePausedComponents(apimanager, status.PausedComponents, false)
+	if err != nil {
+		return err
+	}
+
+	status.ComponentsScaledDown = false
+	status.PausedComponents = nil
+	status.ScaledDownDeployments = nil
+	return nil
+}
+
+// updatePausedComponents adds the components to, or removes them from,
+// the paused components annotation of the APIManager
+func (r *APIManagerRestoreReconciler) updatePausedComponents(apimanager *appsv1alpha1.APIManager, components []string, pause bool) error {
+	if len(components) == 0 {
+		return nil
+	}
+
+	paused := []string{}
+	for _, name := range strings.Split(apimanager.Annotations[appsv1alpha1.PausedComponentsAnnotation], ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || containsComponent(components, name) {
+			continue
+		}
+		paused = append(paused, name)
+	}
+	if pause {
+		paused = append(paused, components...)
+	}
+
+	value := strings.Join(paused, ",")
+	if value == apimanager.Annotations[appsv1alpha1.PausedComponentsAnnotation] {
+		return nil
+	}
+	if apimanager.Annotations == nil {
+		apimanager.Annotations = map[string]string{}
+	}
+	if value == "" {
+		delete(apimanager.Annotations, appsv1alpha1.PausedComponentsAnnotation)
+	} else {
+		apimanager.Annotations[appsv1alpha1.PausedComponentsAnnotation] = value
+	}
+	r.Logger().Info(fmt.Sprintf("Paused components of %s: '%s'", ObjectInfo(apimanager), value))
+	return r.Client().Update(context.TODO(), apimanager)
+}
+
+// deploymentReplicas returns the replicas of the DeploymentConfig, or of
+// the Deployment in Kubernetes mode. False when it is not deployed
+func (r *APIManagerRestoreReconciler) deploymentReplicas(apimanager *appsv1alpha1.APIManager, name string) (int32, bool, error) {
+	key := types.NamespacedName{Name: name, Namespace: apimanager.Namespace}
+	if apimanager.IsKubernetesDeploymentEnabled() {
+		deployment := &k8sappsv1.Deployment{}
+		err := r.Client().Get(context.TODO(), key, deployment)
+		if errors.IsNotFound(err) {
+			return 0, false, nil
+		}
+		if err != nil || deployment.Spec.Replicas == nil {
+			return 0, false, err
+		}
+		return *deployment.Spec.Replicas, true, nil
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err := r.Client().Get(context.TODO(), key, dc)
+	if errors.IsNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return dc.Spec.Replicas, true, nil
+}
+
+// scaleDeployment sets the replicas of the DeploymentConfig, or of the
+// Deployment in Kubernetes mode. Deployments not found are skipped
+func (r *APIManagerRestoreReconciler) scaleDeployment(apimanager *appsv1alpha1.APIManager, name string, replicas int32) error {
+	key := types.NamespacedName{Name: name, Namespace: apimanager.Namespace}
+	var object common.KubernetesObject
+	if apimanager.IsKubernetesDeploymentEnabled() {
+		deployment := &k8sappsv1.Deployment{}
+		err := r.Client().Get(context.TODO(), key, deployment)
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == replicas {
+			return nil
+		}
+		deployment.Spec.Replicas = &replicas
+		object = deployment
+	} else {
+		dc := &appsv1.DeploymentConfig{}
+		err := r.Client().Get(context.TODO(), key, dc)
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if dc.Spec.Replicas == replicas {
+			return nil
+		}
+		dc.Spec.Replicas = replicas
+		object = dc
+	}
+
+	r.Logger().Info(fmt.Sprintf("Scaling %s to %d replicas", ObjectInfo(object), replicas))
+	return r.Client().Update(context.TODO(), object)
+}
+
+// componentsStopped returns true when the pods of the restored components
+// are gone
+func (r *APIManagerRestoreReconciler) componentsStopped() (bool, error) {
+	for _, name := range component.RestoredDeploymentConfigs {
+		podList := &v1.PodList{}
+		listOps := &client.ListOptions{Namespace: r.restore.Namespace}
+		err := listOps.SetLabelSelector(fmt.Sprintf("deploymentConfig=%s", name))
+		if err != nil {
+			return false, err
+		}
+		err = r.Client().List(context.TODO(), listOps, podList)
+		if err != nil {
+			return false, err
+		}
+		if len(podList.Items) > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func findScaledDownDeployment(deployments []appsv1alpha1.ScaledDownDeployment, name string) *appsv1alpha1.ScaledDownDeployment {
+	for idx := range deployments {
+		if deployments[idx].Name == name {
+			return &deployments[idx]
+		}
+	}
+	return nil
+}
+
+func containsComponent(components []string, name string) bool {
+	for _, componentName := range components {
+		if componentName == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *APIManagerRestoreReconciler) failRestore(message string) {
+	r.restore.Status.Phase = appsv1alpha1.BackupPhaseFailed
+	r.restore.Status.Message = message
+	r.restore.Status.CompletionTime = nowTime()
+}
@@ -0,0 +1,227 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func restoreTestObject(namespace, apimanagerName, claimName string) *appsv1alpha1.APIManagerRestore {
+	return &appsv1alpha1.APIManagerRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apimanagerrestore",
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerRestoreSpec{
+			APIManagerName: apimanagerName,
+			RestoreSource: appsv1alpha1.APIManagerRestoreSourceSpec{
+				PersistentVolumeClaim: appsv1alpha1.PersistentVolumeClaimRestoreSourceSpec{
+					ClaimName: claimName,
+				},
+			},
+		},
+	}
+}
+
+func testDeploymentConfigReplicas(t *testing.T, cl client.Client, name, namespace string) int32 {
+	dc := &appsv1.DeploymentConfig{}
+	err := cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dc.Spec.Replicas
+}
+
+func TestAPIManagerRestoreReconcilerWaitsForAPIManager(t *testing.T) {
+	namespace := "operator-unittest"
+	apimanager, _ := backupTestObjects(namespace)
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "apimanager-backup-example-apimanagerbackup",
+			Namespace: namespace,
+		},
+	}
+	restore := restoreTestObject(namespace, apimanager.Name, pvc.Name)
+
+	baseReconciler, cl := testBaseReconciler(t, pvc, restore)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+
+	reconciler := NewAPIManagerRestoreReconciler(baseLogicReconciler, restore)
+	_, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restore.Status.CurrentStep != appsv1alpha1.BackupStepSecrets {
+		t.Fatalf("Unexpected current step. Expected: %s. Got: %s", appsv1alpha1.BackupStepSecrets, restore.Status.CurrentStep)
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "apimanager-restore-" + restore.Name, Namespace: namespace}, &v1.ServiceAccount{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completeJob(t, cl, component.BackupJobName(restore.Name, "restore", appsv1alpha1.BackupStepSecrets), namespace)
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restore.Status.Phase != appsv1alpha1.BackupPhaseRunning || restore.Status.CurrentStep != "" {
+		t.Errorf("Unexpected status. Phase: %s. CurrentStep: %s", restore.Status.Phase, restore.Status.CurrentStep)
+	}
+	if len(restore.Status.Artifacts) != 1 {
+		t.Errorf("Unexpected artifacts: %v", restore.Status.Artifacts)
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: component.BackupJobName(restore.Name, "restore", appsv1alpha1.BackupStepSystemDatabase), Namespace: namespace}, &batchv1.Job{})
+	if err == nil {
+		t.Error("System database restore Job created before the APIManager is available")
+	}
+}
+
+func TestAPIManagerRestoreReconcilerScalesDownComponents(t *testing.T) {
+	namespace := "operator-unittest"
+	apimanager, secrets := backupTestObjects(namespace)
+	apimanager.Annotations = map[string]string{appsv1alpha1.PausedComponentsAnnotation: "apicast"}
+	apimanager.Status.Conditions = []appsv1alpha1.APIManagerCondition{
+		{Type: appsv1alpha1.APIManagerAvailable, Status: v1.ConditionTrue},
+	}
+	// The backend queues have their own redis instance
+	for _, secret := range secrets {
+		if secret := secret.(*v1.Secret); secret.Name == component.BackendSecretBackendRedisSecretName {
+			secret.Data[component.BackendSecretBackendRedisQueuesURLFieldName] = []byte("redis://backend-redis-queues:6379/1")
+		}
+	}
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "apimanager-backup-example-apimanagerbackup",
+			Namespace: namespace,
+		},
+	}
+	restore := restoreTestObject(namespace, apimanager.Name, pvc.Name)
+	restore.Status.Phase = appsv1alpha1.BackupPhaseRunning
+	restore.Status.Artifacts = []appsv1alpha1.BackupArtifact{
+		{Step: appsv1alpha1.BackupStepSecrets, Path: component.BackupArtifactPath(appsv1alpha1.BackupStepSecrets)},
+	}
+	systemApp := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-app", Namespace: namespace},
+		Spec:       appsv1.DeploymentConfigSpec{Replicas: 2},
+	}
+	backendListener := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-listener", Namespace: namespace},
+		Spec:       appsv1.DeploymentConfigSpec{Replicas: 1},
+	}
+	systemAppPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "system-app-1-abcde",
+			Namespace: namespace,
+			Labels:    map[string]string{"deploymentConfig": "system-app"},
+		},
+	}
+
+	baseReconciler, cl := testBaseReconciler(t, append([]runtime.Object{apimanager, pvc, restore, systemApp, backendListener, systemAppPod}, secrets...)...)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+
+	reconciler := NewAPIManagerRestoreReconciler(baseLogicReconciler, restore)
+	result, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restore.Status.CurrentStep != appsv1alpha1.RestoreStepScaleDownComponents || result.RequeueAfter == 0 {
+		t.Fatalf("Restore does not wait for the components to stop. CurrentStep: %s. Result: %v", restore.Status.CurrentStep, result)
+	}
+	existing := &appsv1alpha1.APIManager{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: apimanager.Name, Namespace: namespace}, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := existing.Annotations[appsv1alpha1.PausedComponentsAnnotation]; value != "apicast,system,backend,zync" {
+		t.Errorf("Unexpected paused components: %s", value)
+	}
+	for _, name := range []string{"system-app", "backend-listener"} {
+		if replicas := testDeploymentConfigReplicas(t, cl, name, namespace); replicas != 0 {
+			t.Errorf("DeploymentConfig %s not scaled down. Replicas: %d", name, replicas)
+		}
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: component.BackupJobName(restore.Name, "restore", appsv1alpha1.BackupStepSystemDatabase), Namespace: namespace}, &batchv1.Job{})
+	if err == nil {
+		t.Error("System database restore Job created before the components are stopped")
+	}
+
+	// The APIManager is not available anymore once scaled down
+	existing.Status.Conditions = []appsv1alpha1.APIManagerCondition{
+		{Type: appsv1alpha1.APIManagerAvailable, Status: v1.ConditionFalse},
+	}
+	err = cl.Update(context.TODO(), existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cl.Delete(context.TODO(), systemAppPod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSteps := []string{
+		appsv1alpha1.BackupStepSystemDatabase,
+		appsv1alpha1.BackupStepSystemRedis,
+		appsv1alpha1.BackupStepBackendRedis,
+		appsv1alpha1.BackupStepBackendRedisQueues,
+		appsv1alpha1.BackupStepZyncDatabase,
+		appsv1alpha1.BackupStepSystemStorage,
+	}
+	for _, step := range expectedSteps {
+		_, err = reconciler.Reconcile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if restore.Status.CurrentStep != step {
+			t.Fatalf("Unexpected current step. Expected: %s. Got: %s", step, restore.Status.CurrentStep)
+		}
+		completeJob(t, cl, component.BackupJobName(restore.Name, "restore", step), namespace)
+	}
+
+	job := &batchv1.Job{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: component.BackupJobName(restore.Name, "restore", appsv1alpha1.BackupStepBackendRedisQueues), Namespace: namespace}, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range job.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == "REDIS_URL" && env.ValueFrom.SecretKeyRef.Key != component.BackendSecretBackendRedisQueuesURLFieldName {
+			t.Errorf("Unexpected REDIS_URL of the queues restore: %s", env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
+
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restore.Status.Phase != appsv1alpha1.BackupPhaseCompleted {
+		t.Fatalf("Restore not completed. Phase: %s. Message: %s", restore.Status.Phase, restore.Status.Message)
+	}
+	if restore.Status.ComponentsScaledDown || len(restore.Status.PausedComponents) > 0 || len(restore.Status.ScaledDownDeployments) > 0 {
+		t.Errorf("Scaled down components not cleared from the status: %v", restore.Status)
+	}
+	if replicas := testDeploymentConfigReplicas(t, cl, "system-app", namespace); replicas != 2 {
+		t.Errorf("DeploymentConfig system-app not scaled back up. Replicas: %d", replicas)
+	}
+	if replicas := testDeploymentConfigReplicas(t, cl, "backend-listener", namespace); replicas != 1 {
+		t.Errorf("DeploymentConfig backend-listener not scaled back up. Replicas: %d", replicas)
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: apimanager.Name, Namespace: namespace}, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := existing.Annotations[appsv1alpha1.PausedComponentsAnnotation]; value != "apicast" {
+		t.Errorf("Unexpected paused components once restored: %s", value)
+	}
+}
@@ -7,8 +7,6 @@ import (
 	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"k8s.io/apimachinery/pkg/api/errors"
-
-	v1 "k8s.io/api/core/v1"
 )
 
 func (o *OperatorBackendOptionsProvider) GetBackendOptions() (*component.BackendOptions, error) {
@@ -22,8 +20,18 @@ func (o *OperatorBackendOptionsProvider) GetBackendOptions() (*component.Backend
 		return nil, err
 	}
 
+	redisTLS, err := backendRedisTLSOptions(o.APIManagerSpec, o.Client, o.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Backend Redis TLS options - %s", err)
+	}
+	if redisTLS != nil {
+		optProv.RedisTLS(*redisTLS)
+	}
+
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 	o.setReplicas(&optProv)
+	optProv.MetricsEnabled(o.APIManagerSpec.Monitoring.IsEnabled())
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -50,10 +58,21 @@ func (o *OperatorBackendOptionsProvider) setSecretBasedOptions(b *component.Back
 }
 
 func (o *OperatorBackendOptionsProvider) setResourceRequirementsOptions(b *component.BackendOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.ListenerResourceRequirements(v1.ResourceRequirements{})
-		b.WorkerResourceRequirements(v1.ResourceRequirements{})
-		b.CronResourceRequirements(v1.ResourceRequirements{})
+	enabled := *o.APIManagerSpec.ResourceRequirementsEnabled
+	setResourceRequirements(b.ListenerResourceRequirements, enabled, o.APIManagerSpec.Backend.ListenerSpec.Resources)
+	setResourceRequirements(b.WorkerResourceRequirements, enabled, o.APIManagerSpec.Backend.WorkerSpec.Resources)
+	setResourceRequirements(b.CronResourceRequirements, enabled, o.APIManagerSpec.Backend.CronSpec.Resources)
+}
+
+func (o *OperatorBackendOptionsProvider) setPodPlacementOptions(b *component.BackendOptionsBuilder) {
+	if o.APIManagerSpec.Backend.ListenerSpec.Placement != nil {
+		b.ListenerPodPlacement(podPlacement(o.APIManagerSpec.Backend.ListenerSpec.Placement, "backend-listener"))
+	}
+	if o.APIManagerSpec.Backend.WorkerSpec.Placement != nil {
+		b.WorkerPodPlacement(podPlacement(o.APIManagerSpec.Backend.WorkerSpec.Placement, "backend-worker"))
+	}
+	if o.APIManagerSpec.Backend.CronSpec.Placement != nil {
+		b.CronPodPlacement(podPlacement(o.APIManagerSpec.Backend.CronSpec.Placement, "backend-cron"))
 	}
 }
 
@@ -101,6 +120,23 @@ func (o *OperatorBackendOptionsProvider) setBackendRedisOptions(b *component.Bac
 	b.RedisQueuesSentinelHosts(helper.GetSecretDataValue(secretData, component.BackendSecretBackendRedisQueuesSentinelHostsFieldName))
 	b.RedisQueuesSentinelRole(helper.GetSecretDataValue(secretData, component.BackendSecretBackendRedisQueuesSentinelRoleFieldName))
 
+	// The operator managed sentinels override the secret values
+	if o.APIManagerSpec.Redis.IsSentinelEnabled() {
+		sentinelHosts, err := redisSentinelHosts(o.APIManagerSpec)
+		if err != nil {
+			return err
+		}
+		storageURL := component.RedisSentinelBackendStorageURL
+		queuesURL := component.RedisSentinelBackendQueuesURL
+		sentinelRole := component.RedisSentinelRole
+		b.RedisStorageURL(&storageURL)
+		b.RedisQueuesURL(&queuesURL)
+		b.RedisStorageSentinelHosts(&sentinelHosts)
+		b.RedisStorageSentinelRole(&sentinelRole)
+		b.RedisQueuesSentinelHosts(&sentinelHosts)
+		b.RedisQueuesSentinelRole(&sentinelRole)
+	}
+
 	return nil
 }
 
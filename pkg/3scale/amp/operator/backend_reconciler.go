@@ -8,6 +8,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// Environment variables enabling the Prometheus metrics of backend, set
+// when monitoring is enabled
+var (
+	backendListenerMetricsEnvVars = []string{"CONFIG_LISTENER_PROMETHEUS_METRICS_ENABLED", "CONFIG_LISTENER_PROMETHEUS_METRICS_PORT"}
+	backendWorkerMetricsEnvVars   = []string{"CONFIG_WORKER_PROMETHEUS_METRICS_ENABLED", "CONFIG_WORKER_PROMETHEUS_METRICS_PORT"}
+)
+
 type BackendWorkerDCReconciler struct {
 	BaseAPIManagerLogicReconciler
 }
@@ -27,6 +34,15 @@ func (r *BackendWorkerDCReconciler) IsUpdateNeeded(desired, existing *appsv1.Dep
 	tmpUpdate = DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = DeploymentConfigReconcileDatastoreTLS(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerPorts(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerEnvVars(desired, existing, backendWorkerMetricsEnvVars, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -49,6 +65,15 @@ func (r *BackendListenerDCReconciler) IsUpdateNeeded(desired, existing *appsv1.D
 	tmpUpdate = DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = DeploymentConfigReconcileDatastoreTLS(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerPorts(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerEnvVars(desired, existing, backendListenerMetricsEnvVars, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -71,6 +96,9 @@ func (r *BackendCronDCReconciler) IsUpdateNeeded(desired, existing *appsv1.Deplo
 	tmpUpdate = DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = DeploymentConfigReconcileDatastoreTLS(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -113,7 +141,18 @@ func (r *BackendReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileWorkerDeploymentConfig(backend.WorkerDeploymentConfig())
+	err = r.reconcileIngress(backend.ListenerIngress())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	workerDeploymentConfig := backend.WorkerDeploymentConfig()
+	queueScalingResult, err := r.reconcileWorkerQueueScaling(workerDeploymentConfig)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileWorkerDeploymentConfig(workerDeploymentConfig)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -153,7 +192,18 @@ func (r *BackendReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	err = r.reconcileHorizontalPodAutoscaler(backend.ListenerDeploymentConfig(), r.apiManager.Spec.Backend.ListenerSpec.Autoscaling)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileHorizontalPodAutoscaler(backend.WorkerDeploymentConfig(), r.apiManager.Spec.Backend.WorkerSpec.Autoscaling)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Queue scaling needs the queue lengths to be read periodically
+	return reconcile.Result{RequeueAfter: queueScalingResult.RequeueAfter}, nil
 }
 
 func (r *BackendReconciler) backend() (*component.Backend, error) {
@@ -171,7 +221,8 @@ func (r *BackendReconciler) reconcileCronDeploymentConfig(desiredDeploymentConfi
 }
 
 func (r *BackendReconciler) reconcileListenerDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
-	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewBackendListenerDCReconciler(r.BaseAPIManagerLogicReconciler))
+	dcReconciler := NewAutoscalingDCReconciler(r.apiManager.Spec.Backend.ListenerSpec.Autoscaling, NewBackendListenerDCReconciler(r.BaseAPIManagerLogicReconciler))
+	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, dcReconciler)
 	return reconciler.Reconcile(desiredDeploymentConfig)
 }
 
@@ -185,8 +236,14 @@ func (r *BackendReconciler) reconcileListenerRoute(desiredRoute *routev1.Route)
 	return reconciler.Reconcile(desiredRoute)
 }
 
+func (r *BackendReconciler) reconcileWorkerQueueScaling(desiredDeploymentConfig *appsv1.DeploymentConfig) (reconcile.Result, error) {
+	scaler := NewBackendWorkerQueueScaler(r.BaseAPIManagerLogicReconciler)
+	return scaler.Reconcile(desiredDeploymentConfig)
+}
+
 func (r *BackendReconciler) reconcileWorkerDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
-	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewBackendWorkerDCReconciler(r.BaseAPIManagerLogicReconciler))
+	dcReconciler := NewAutoscalingDCReconciler(r.apiManager.Spec.Backend.WorkerSpec.Autoscaling, NewBackendWorkerDCReconciler(r.BaseAPIManagerLogicReconciler))
+	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, dcReconciler)
 	return reconciler.Reconcile(desiredDeploymentConfig)
 }
 
@@ -202,8 +259,20 @@ func (r *BackendReconciler) reconcileInternalAPISecret(desiredSecret *v1.Secret)
 }
 
 func (r *BackendReconciler) reconcileRedisSecret(desiredSecret *v1.Secret) error {
-	// Secret values are not affected by CR field values
-	reconciler := NewSecretBaseReconciler(r.BaseAPIManagerLogicReconciler, NewDefaultsOnlySecretReconciler())
+	// Secret values are not affected by CR field values unless the
+	// operator manages the redis sentinels
+	var secretReconciler SecretReconciler = NewDefaultsOnlySecretReconciler()
+	if r.apiManager.IsRedisSentinelEnabled() {
+		secretReconciler = NewFieldsSecretReconciler(
+			component.BackendSecretBackendRedisStorageURLFieldName,
+			component.BackendSecretBackendRedisQueuesURLFieldName,
+			component.BackendSecretBackendRedisStorageSentinelHostsFieldName,
+			component.BackendSecretBackendRedisStorageSentinelRoleFieldName,
+			component.BackendSecretBackendRedisQueuesSentinelHostsFieldName,
+			component.BackendSecretBackendRedisQueuesSentinelRoleFieldName,
+		)
+	}
+	reconciler := NewSecretBaseReconciler(r.BaseAPIManagerLogicReconciler, secretReconciler)
 	return reconciler.Reconcile(desiredSecret)
 }
 
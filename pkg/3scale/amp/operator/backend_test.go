@@ -1,14 +1,19 @@
 package operator
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/google/go-cmp/cmp"
+	appsv1 "github.com/openshift/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
 func getInternalSecret(namespace string) *v1.Secret {
@@ -132,3 +137,136 @@ func TestGetBackendOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestBackendRedisTLS(t *testing.T) {
+	appLabel := "someLabel"
+	namespace := "someNS"
+	log := logf.Log.WithName("operator_test")
+
+	newWorkerDC := func(tlsEnabled bool, secretData map[string]string) *appsv1.DeploymentConfig {
+		apimanager := &appsv1alpha1.APIManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+			Spec: appsv1alpha1.APIManagerSpec{
+				APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+					AppLabel:       &appLabel,
+					WildcardDomain: "test.3scale.net",
+				},
+				HighAvailability: &appsv1alpha1.HighAvailabilitySpec{
+					Enabled:                true,
+					BackendRedisTLSEnabled: tlsEnabled,
+				},
+			},
+		}
+		_, err := apimanager.SetDefaults()
+		if err != nil {
+			t.Fatal(err)
+		}
+		redisSecret := helper.GetTestSecret(namespace, component.BackendSecretBackendRedisSecretName, secretData)
+		optsProvider := OperatorBackendOptionsProvider{
+			APIManagerSpec: &apimanager.Spec,
+			Namespace:      namespace,
+			Client:         fake.NewFakeClient(redisSecret),
+		}
+		opts, err := optsProvider.GetBackendOptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return component.NewBackend(opts).WorkerDeploymentConfig()
+	}
+
+	secretData := backendRedisTestData()
+	secretData[component.BackendSecretBackendRedisSSLCAFieldName] = "ca"
+	desired := newWorkerDC(true, secretData)
+	existing := newWorkerDC(false, secretData)
+
+	if len(desired.Spec.Template.Spec.Volumes) != len(existing.Spec.Template.Spec.Volumes)+1 {
+		t.Fatalf("TLS volume not added: %v", desired.Spec.Template.Spec.Volumes)
+	}
+	env := desired.Spec.Template.Spec.Containers[0].Env
+	if idx := findEnvVar(env, "CONFIG_REDIS_CA_FILE"); idx < 0 || env[idx].Value != "/var/run/secrets/backend-redis-tls/ca.crt" {
+		t.Errorf("CONFIG_REDIS_CA_FILE not set: %v", env)
+	}
+	if idx := findEnvVar(env, "CONFIG_REDIS_CERT"); idx >= 0 {
+		t.Error("CONFIG_REDIS_CERT set without client certificate")
+	}
+
+	if !DeploymentConfigReconcileDatastoreTLS(desired, existing, log) {
+		t.Fatal("added TLS files not reconciled")
+	}
+	if !reflect.DeepEqual(desired.Spec.Template, existing.Spec.Template) {
+		t.Errorf("reconciled pod template differs: %s", cmp.Diff(desired.Spec.Template, existing.Spec.Template))
+	}
+	if DeploymentConfigReconcileDatastoreTLS(desired, existing, log) {
+		t.Error("unchanged TLS files reconciled")
+	}
+
+	secretData[component.BackendSecretBackendRedisSSLCAFieldName] = "renewed-ca"
+	desired = newWorkerDC(true, secretData)
+	if !DeploymentConfigReconcileDatastoreTLS(desired, existing, log) {
+		t.Error("renewed CA certificate not reconciled")
+	}
+
+	desired = newWorkerDC(false, secretData)
+	if !DeploymentConfigReconcileDatastoreTLS(desired, existing, log) {
+		t.Fatal("removed TLS files not reconciled")
+	}
+	if _, ok := existing.Spec.Template.Annotations[component.DatastoreTLSHashAnnotation]; ok {
+		t.Error("TLS hash annotation not removed")
+	}
+}
+
+func TestBackendMetrics(t *testing.T) {
+	appLabel := "someLabel"
+	namespace := "someNS"
+
+	newListenerDC := func(monitoringEnabled bool) *appsv1.DeploymentConfig {
+		apimanager := &appsv1alpha1.APIManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+			Spec: appsv1alpha1.APIManagerSpec{
+				APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+					AppLabel:       &appLabel,
+					WildcardDomain: "test.3scale.net",
+				},
+				Monitoring: &appsv1alpha1.MonitoringSpec{Enabled: monitoringEnabled},
+			},
+		}
+		_, err := apimanager.SetDefaults()
+		if err != nil {
+			t.Fatal(err)
+		}
+		optsProvider := OperatorBackendOptionsProvider{
+			APIManagerSpec: &apimanager.Spec,
+			Namespace:      namespace,
+			Client:         fake.NewFakeClient(),
+		}
+		opts, err := optsProvider.GetBackendOptions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return component.NewBackend(opts).ListenerDeploymentConfig()
+	}
+
+	desired := newListenerDC(true)
+	existing := newListenerDC(false)
+	reconciler := NewBackendListenerDCReconciler(BaseAPIManagerLogicReconciler{
+		BaseLogicReconciler: NewBaseLogicReconciler(NewBaseReconciler(nil, nil, nil, logf.Log.WithName("operator_test"))),
+	})
+
+	if !reconciler.IsUpdateNeeded(desired, existing) {
+		t.Fatal("metrics port not reconciled")
+	}
+	if !reflect.DeepEqual(desired.Spec.Template.Spec.Containers[0], existing.Spec.Template.Spec.Containers[0]) {
+		t.Errorf("reconciled container differs: %s", cmp.Diff(desired.Spec.Template.Spec.Containers[0], existing.Spec.Template.Spec.Containers[0]))
+	}
+	if reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("unchanged metrics port reconciled")
+	}
+
+	desired = newListenerDC(false)
+	if !reconciler.IsUpdateNeeded(desired, existing) {
+		t.Fatal("removed metrics port not reconciled")
+	}
+	if idx := findEnvVar(existing.Spec.Template.Spec.Containers[0].Env, "CONFIG_LISTENER_PROMETHEUS_METRICS_ENABLED"); idx >= 0 {
+		t.Error("metrics environment variable not removed")
+	}
+}
@@ -0,0 +1,248 @@
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	BackendWorkerQueueScalingDefaultMinReplicas       int32 = 1
+	BackendWorkerQueueScalingDefaultTargetQueueLength int64 = 100
+	BackendWorkerQueueScalingDefaultSyncPeriodSeconds int32 = 30
+
+	backendWorkerQueueScalingRedisTimeout = 5 * time.Second
+)
+
+// BackendWorkerQueues are the resque queues of backend-redis processed by
+// backend-worker
+var BackendWorkerQueues = []string{
+	"resque:queue:priority",
+	"resque:queue:main",
+	"resque:queue:stats",
+}
+
+// backendWorkerQueueReads holds the last time the queues of each APIManager
+// were read. The status is only updated when the reading changes, so it
+// does not hold the time of every read
+var backendWorkerQueueReads sync.Map
+
+// BackendWorkerQueueScaler sets the backend-worker replicas from the length
+// of the backend job queues. Queue lengths are read at most once every sync
+// period and the last changed observation is kept in the APIManager status
+type BackendWorkerQueueScaler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewBackendWorkerQueueScaler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *BackendWorkerQueueScaler {
+	return &BackendWorkerQueueScaler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+// Reconcile sets the replicas of the desired backend-worker
+// DeploymentConfig when queue scaling is enabled. The returned result
+// requeues the request when the next queue read is due
+func (s *BackendWorkerQueueScaler) Reconcile(desired *appsv1.DeploymentConfig) (reconcile.Result, error) {
+	queueScaling := s.apiManager.Spec.Backend.WorkerSpec.QueueScaling
+	if queueScaling == nil {
+		if s.apiManager.Status.BackendWorkerQueueScaling != nil {
+			s.apiManager.Status.BackendWorkerQueueScaling = nil
+			return reconcile.Result{}, s.updateStatus()
+		}
+		return reconcile.Result{}, nil
+	}
+
+	minReplicas := BackendWorkerQueueScalingDefaultMinReplicas
+	if queueScaling.MinReplicas != nil {
+		minReplicas = *queueScaling.MinReplicas
+	}
+	syncPeriod := time.Duration(BackendWorkerQueueScalingDefaultSyncPeriodSeconds) * time.Second
+	if queueScaling.SyncPeriodSeconds != nil {
+		syncPeriod = time.Duration(*queueScaling.SyncPeriodSeconds) * time.Second
+	}
+
+	status := s.apiManager.Status.BackendWorkerQueueScaling
+	if status != nil {
+		lastRead := status.LastObservedTime.Time
+		if t, ok := backendWorkerQueueReads.Load(s.queueReadsKey()); ok && t.(time.Time).After(lastRead) {
+			lastRead = t.(time.Time)
+		}
+		elapsed := time.Since(lastRead)
+		if elapsed >= 0 && elapsed < syncPeriod {
+			desired.Spec.Replicas = boundReplicas(status.DesiredReplicas, minReplicas, queueScaling.MaxReplicas)
+			return reconcile.Result{RequeueAfter: syncPeriod - elapsed}, nil
+		}
+	}
+
+	queueLength, err := s.queueLength()
+	if err != nil {
+		// The queues are not readable while backend-redis is being deployed.
+		// The replicas are kept until the next read
+		s.Logger().Error(err, "Error reading backend-worker queue lengths")
+		if status != nil {
+			desired.Spec.Replicas = boundReplicas(status.DesiredReplicas, minReplicas, queueScaling.MaxReplicas)
+			return reconcile.Result{RequeueAfter: syncPeriod}, nil
+		}
+		replicas, deployed, err := s.currentReplicas(desired.Name)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		desired.Spec.Replicas = minReplicas
+		if deployed {
+			desired.Spec.Replicas = boundReplicas(replicas, minReplicas, queueScaling.MaxReplicas)
+		}
+		return reconcile.Result{RequeueAfter: syncPeriod}, nil
+	}
+	backendWorkerQueueReads.Store(s.queueReadsKey(), time.Now())
+
+	targetQueueLength := BackendWorkerQueueScalingDefaultTargetQueueLength
+	if queueScaling.TargetQueueLength != nil {
+		targetQueueLength = *queueScaling.TargetQueueLength
+	}
+	desired.Spec.Replicas = BackendWorkerQueueReplicas(queueLength, targetQueueLength, minReplicas, queueScaling.MaxReplicas)
+
+	if status != nil && status.QueueLength == queueLength && status.DesiredReplicas == desired.Spec.Replicas {
+		return reconcile.Result{RequeueAfter: syncPeriod}, nil
+	}
+
+	s.Logger().Info(fmt.Sprintf("backend-worker queue length %d. Desired replicas %d", queueLength, desired.Spec.Replicas))
+	s.apiManager.Status.BackendWorkerQueueScaling = &appsv1alpha1.BackendWorkerQueueScalingStatus{
+		QueueLength:      queueLength,
+		DesiredReplicas:  desired.Spec.Replicas,
+		LastObservedTime: metav1.Now(),
+	}
+	err = s.updateStatus()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{RequeueAfter: syncPeriod}, nil
+}
+
+func (s *BackendWorkerQueueScaler) queueReadsKey() types.NamespacedName {
+	return types.NamespacedName{Name: s.apiManager.Name, Namespace: s.apiManager.Namespace}
+}
+
+// currentReplicas returns the replicas of the deployed backend-worker
+// workload. False when it is not deployed yet
+func (s *BackendWorkerQueueScaler) currentReplicas(name string) (int32, bool, error) {
+	key := types.NamespacedName{Name: name, Namespace: s.apiManager.Namespace}
+	if s.apiManager.IsKubernetesDeploymentEnabled() {
+		deployment := &k8sappsv1.Deployment{}
+		err := s.Client().Get(context.TODO(), key, deployment)
+		if errors.IsNotFound(err) {
+			return 0, false, nil
+		}
+		if err != nil || deployment.Spec.Replicas == nil {
+			return 0, false, err
+		}
+		return *deployment.Spec.Replicas, true, nil
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err := s.Client().Get(context.TODO(), key, dc)
+	if errors.IsNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return dc.Spec.Replicas, true, nil
+}
+
+// BackendWorkerQueueReplicas returns the replicas needed to process
+// queueLength jobs when each replica handles targetQueueLength jobs,
+// bounded by minReplicas and maxReplicas
+func BackendWorkerQueueReplicas(queueLength, targetQueueLength int64, minReplicas, maxReplicas int32) int32 {
+	replicas := (queueLength + targetQueueLength - 1) / targetQueueLength
+	if replicas > int64(maxReplicas) {
+		return maxReplicas
+	}
+	return boundReplicas(int32(replicas), minReplicas, maxReplicas)
+}
+
+func boundReplicas(replicas, minReplicas, maxReplicas int32) int32 {
+	if replicas < minReplicas {
+		return minReplicas
+	}
+	if replicas > maxReplicas {
+		return maxReplicas
+	}
+	return replicas
+}
+
+// queueLength returns the total number of jobs in the backend queues of the
+// redis instance configured in the backend-redis secret
+func (s *BackendWorkerQueueScaler) queueLength() (int64, error) {
+	secret, err := helper.GetSecret(component.BackendSecretBackendRedisSecretName, s.apiManager.Namespace, s.Client())
+	if err != nil {
+		return 0, err
+	}
+
+	redisURL := helper.GetSecretDataValueOrDefault(secret.Data, component.BackendSecretBackendRedisQueuesURLFieldName, "")
+	if redisURL == "" {
+		return 0, fmt.Errorf("Secret %s does not have a %s value", component.BackendSecretBackendRedisSecretName, component.BackendSecretBackendRedisQueuesURLFieldName)
+	}
+
+	sentinelHosts := helper.GetSecretDataValueOrDefault(secret.Data, component.BackendSecretBackendRedisQueuesSentinelHostsFieldName, "")
+	if sentinelHosts != "" {
+		redisURL, err = redisSentinelMasterURL(sentinelHosts, redisURL, backendWorkerQueueScalingRedisTimeout)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// rediss URLs trust the CA, and present the client certificate, of
+	// the backend redis TLS fields of the secret
+	tlsConfig, err := redisTLSConfig(
+		secret.Data[component.BackendSecretBackendRedisSSLCAFieldName],
+		secret.Data[component.BackendSecretBackendRedisSSLCertFieldName],
+		secret.Data[component.BackendSecretBackendRedisSSLKeyFieldName],
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return RedisQueueLength(redisURL, BackendWorkerQueues, tlsConfig)
+}
+
+// RedisQueueLength returns the sum of the lengths of the given queues in the
+// redis server of redisURL
+func RedisQueueLength(redisURL string, queues []string, tlsConfig *tls.Config) (int64, error) {
+	c, err := dialRedisURL(redisURL, backendWorkerQueueScalingRedisTimeout, tlsConfig)
+	if err != nil {
+		return 0, err
+	}
+	defer c.Close()
+
+	var total int64
+	for _, queue := range queues {
+		length, err := c.llen(queue)
+		if err != nil {
+			return 0, err
+		}
+		total += length
+	}
+	return total, nil
+}
+
+func (s *BackendWorkerQueueScaler) updateStatus() error {
+	err := s.Client().Status().Update(context.TODO(), s.apiManager)
+	if err != nil {
+		s.Logger().Error(err, "Error updating backend-worker queue scaling status")
+	}
+	return err
+}
@@ -0,0 +1,349 @@
+package operator
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// startTestRedisServer starts a local server speaking the subset of the
+// redis protocol used by the queue scaler. It replies to LLEN with the
+// lengths of the given lists
+func startTestRedisServer(t *testing.T, lists map[string]int64) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestRedisConn(conn, lists)
+		}
+	}()
+
+	return listener
+}
+
+func serveTestRedisConn(conn net.Conn, lists map[string]int64) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		argc, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, argc)
+		for i := range args {
+			_, err = reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			arg, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			args[i] = strings.TrimSpace(arg)
+		}
+
+		switch strings.ToUpper(args[0]) {
+		case "SELECT", "AUTH":
+			fmt.Fprint(conn, "+OK\r\n")
+		case "LLEN":
+			fmt.Fprintf(conn, ":%d\r\n", lists[args[1]])
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+	}
+}
+
+func TestBackendWorkerQueueReplicas(t *testing.T) {
+	cases := []struct {
+		name              string
+		queueLength       int64
+		targetQueueLength int64
+		minReplicas       int32
+		maxReplicas       int32
+		expected          int32
+	}{
+		{"EmptyQueues", 0, 100, 1, 5, 1},
+		{"PartialReplica", 150, 100, 1, 5, 2},
+		{"ExactReplicas", 300, 100, 1, 5, 3},
+		{"AboveMax", 100000, 100, 1, 5, 5},
+		{"BelowMin", 10, 100, 2, 5, 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			replicas := BackendWorkerQueueReplicas(tc.queueLength, tc.targetQueueLength, tc.minReplicas, tc.maxReplicas)
+			if replicas != tc.expected {
+				subT.Errorf("Unexpected replicas. Expected: %d, got: %d", tc.expected, replicas)
+			}
+		})
+	}
+}
+
+func TestBackendWorkerQueueScalerReconcile(t *testing.T) {
+	var (
+		name                    = "example-apimanager"
+		namespace               = "operator-unittest"
+		maxReplicas       int32 = 10
+		targetQueueLength int64 = 50
+	)
+
+	listener := startTestRedisServer(t, map[string]int64{
+		"resque:queue:priority": 20,
+		"resque:queue:main":     100,
+		"resque:queue:stats":    60,
+	})
+	defer listener.Close()
+
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			Backend: &appsv1alpha1.BackendSpec{
+				WorkerSpec: &appsv1alpha1.BackendWorkerSpec{
+					QueueScaling: &appsv1alpha1.BackendWorkerQueueScalingSpec{
+						MaxReplicas:       maxReplicas,
+						TargetQueueLength: &targetQueueLength,
+					},
+				},
+			},
+		},
+	}
+	redisSecret := helper.GetTestSecret(namespace, component.BackendSecretBackendRedisSecretName, map[string]string{
+		component.BackendSecretBackendRedisQueuesURLFieldName: fmt.Sprintf("redis://%s/1", listener.Addr().String()),
+	})
+
+	baseAPIManagerLogicReconciler, cl := testBaseAPIManagerLogicReconciler(t, apimanager, redisSecret)
+
+	scaler := NewBackendWorkerQueueScaler(baseAPIManagerLogicReconciler)
+	backendWorkerQueueReads.Delete(scaler.queueReadsKey())
+	desired := &appsv1.DeploymentConfig{Spec: appsv1.DeploymentConfigSpec{Replicas: 1}}
+	result, err := scaler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 180 queued jobs, 50 jobs per replica
+	if desired.Spec.Replicas != 4 {
+		t.Errorf("Unexpected backend-worker replicas. Expected: 4, got: %d", desired.Spec.Replicas)
+	}
+	expectedRequeueAfter := time.Duration(BackendWorkerQueueScalingDefaultSyncPeriodSeconds) * time.Second
+	if result.RequeueAfter != expectedRequeueAfter {
+		t.Errorf("Unexpected requeue. Expected: %s, got: %s", expectedRequeueAfter, result.RequeueAfter)
+	}
+
+	reconciled := &appsv1alpha1.APIManager{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := reconciled.Status.BackendWorkerQueueScaling
+	if status == nil {
+		t.Fatal("backend-worker queue scaling status not set")
+	}
+	if status.QueueLength != 180 || status.DesiredReplicas != 4 {
+		t.Errorf("Unexpected backend-worker queue scaling status: %v", status)
+	}
+
+	// The status is not updated when the reading does not change
+	lastObservedTime := status.LastObservedTime
+	backendWorkerQueueReads.Delete(scaler.queueReadsKey())
+	apimanager.Status.BackendWorkerQueueScaling.LastObservedTime = metav1.NewTime(time.Now().Add(-time.Hour))
+	desired = &appsv1.DeploymentConfig{Spec: appsv1.DeploymentConfigSpec{Replicas: 1}}
+	_, err = scaler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := backendWorkerQueueReads.Load(scaler.queueReadsKey()); !ok {
+		t.Error("Queues not read after the sync period")
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reconciled.Status.BackendWorkerQueueScaling.LastObservedTime.Equal(&lastObservedTime) {
+		t.Error("Unchanged backend-worker queue scaling status updated")
+	}
+
+	// Queues are not read again within the sync period
+	listener.Close()
+	desired = &appsv1.DeploymentConfig{Spec: appsv1.DeploymentConfigSpec{Replicas: 1}}
+	result, err = scaler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desired.Spec.Replicas != 4 {
+		t.Errorf("Unexpected backend-worker replicas within the sync period. Expected: 4, got: %d", desired.Spec.Replicas)
+	}
+	if result.RequeueAfter <= 0 || result.RequeueAfter > expectedRequeueAfter {
+		t.Errorf("Unexpected requeue within the sync period: %s", result.RequeueAfter)
+	}
+}
+
+func TestBackendWorkerQueueScalerRedisUnavailable(t *testing.T) {
+	var (
+		name              = "example-apimanager"
+		namespace         = "operator-unittest"
+		maxReplicas int32 = 10
+	)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	redisAddress := listener.Addr().String()
+	listener.Close()
+
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			Backend: &appsv1alpha1.BackendSpec{
+				WorkerSpec: &appsv1alpha1.BackendWorkerSpec{
+					QueueScaling: &appsv1alpha1.BackendWorkerQueueScalingSpec{
+						MaxReplicas: maxReplicas,
+					},
+				},
+			},
+		},
+		Status: appsv1alpha1.APIManagerStatus{
+			BackendWorkerQueueScaling: &appsv1alpha1.BackendWorkerQueueScalingStatus{
+				QueueLength:      600,
+				DesiredReplicas:  6,
+				LastObservedTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			},
+		},
+	}
+	redisSecret := helper.GetTestSecret(namespace, component.BackendSecretBackendRedisSecretName, map[string]string{
+		component.BackendSecretBackendRedisQueuesURLFieldName: fmt.Sprintf("redis://%s/1", redisAddress),
+	})
+
+	baseAPIManagerLogicReconciler, cl := testBaseAPIManagerLogicReconciler(t, apimanager, redisSecret)
+
+	scaler := NewBackendWorkerQueueScaler(baseAPIManagerLogicReconciler)
+	backendWorkerQueueReads.Delete(scaler.queueReadsKey())
+	desired := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-worker", Namespace: namespace},
+		Spec:       appsv1.DeploymentConfigSpec{Replicas: 1},
+	}
+	_, err = scaler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if desired.Spec.Replicas != 6 {
+		t.Errorf("backend-worker replicas not kept. Expected: 6, got: %d", desired.Spec.Replicas)
+	}
+
+	// Without a previous reading, the deployed replicas are kept
+	apimanager.Status.BackendWorkerQueueScaling = nil
+	deployed := desired.DeepCopy()
+	deployed.Spec.Replicas = 3
+	err = cl.Create(context.TODO(), deployed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desired.Spec.Replicas = 1
+	_, err = scaler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desired.Spec.Replicas != 3 {
+		t.Errorf("deployed backend-worker replicas not kept. Expected: 3, got: %d", desired.Spec.Replicas)
+	}
+}
+
+// testRedisTLSConfig returns the server TLS configuration of a self signed
+// certificate for 127.0.0.1 and the PEM encoded certificate
+func testRedisTLSConfig(t *testing.T) (*tls.Config, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "backend-redis"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestRedisQueueLengthTLS(t *testing.T) {
+	serverTLSConfig, caPEM := testRedisTLSConfig(t)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverTLSConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestRedisConn(conn, map[string]int64{"resque:queue:main": 42})
+		}
+	}()
+
+	redisURL := fmt.Sprintf("rediss://%s/1", listener.Addr().String())
+	tlsConfig, err := redisTLSConfig(caPEM, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	length, err := RedisQueueLength(redisURL, BackendWorkerQueues, tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length != 42 {
+		t.Errorf("Unexpected queue length. Expected: 42, got: %d", length)
+	}
+
+	// The server certificate is not trusted by the system CAs
+	_, err = RedisQueueLength(redisURL, BackendWorkerQueues, nil)
+	if err == nil {
+		t.Error("Untrusted redis server certificate accepted")
+	}
+}
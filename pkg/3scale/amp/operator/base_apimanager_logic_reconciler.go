@@ -3,10 +3,14 @@ package operator
 import (
 	"context"
 	"fmt"
+	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/api/policy/v1beta1"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/common"
+	appsv1 "github.com/openshift/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -70,6 +74,9 @@ func (r *BaseAPIManagerLogicReconciler) createResource(obj common.KubernetesObje
 	if err := r.setOwnerReference(obj); err != nil {
 		return err
 	}
+	if err := recordLastAppliedConfiguration(obj); err != nil {
+		return err
+	}
 
 	r.Logger().Info(fmt.Sprintf("Created object %s", ObjectInfo(obj)))
 	return r.Client().Create(context.TODO(), obj) // don't wrap error
@@ -89,7 +96,36 @@ func (r *BaseAPIManagerLogicReconciler) deleteResource(obj common.KubernetesObje
 	return r.Client().Delete(context.TODO(), obj)
 }
 
+// deleteOwnedResource deletes the object of the given name when it is
+// controlled by the APIManager. The object is read into obj. Missing objects
+// and kinds not served by the cluster are ignored. When given, shouldDelete
+// selects the owned objects to delete
+func (r *BaseAPIManagerLogicReconciler) deleteOwnedResource(name string, obj common.KubernetesObject, shouldDelete func() bool) error {
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: name, Namespace: r.apiManager.GetNamespace()}, obj)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(obj, r.apiManager) || (shouldDelete != nil && !shouldDelete()) {
+		return nil
+	}
+	return r.deleteResource(obj)
+}
+
 func (r *BaseAPIManagerLogicReconciler) reconcilePodDisruptionBudget(desiredPDB *v1beta1.PodDisruptionBudget) error {
 	reconciler := NewPodDisruptionBudgetReconciler(*r)
 	return reconciler.Reconcile(desiredPDB)
 }
+
+func (r *BaseAPIManagerLogicReconciler) reconcileIngress(desiredIngress *extensions.Ingress) error {
+	reconciler := NewIngressReconciler(*r)
+	return reconciler.Reconcile(desiredIngress)
+}
+
+func (r *BaseAPIManagerLogicReconciler) reconcileHorizontalPodAutoscaler(desiredDeploymentConfig *appsv1.DeploymentConfig, autoscaling *appsv1alpha1.AutoscalingSpec) error {
+	reconciler := NewHorizontalPodAutoscalerReconciler(*r)
+	return reconciler.Reconcile(desiredDeploymentConfig, autoscaling)
+}
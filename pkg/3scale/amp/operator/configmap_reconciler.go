@@ -70,6 +70,12 @@ func (r *ConfigMapBaseReconciler) isUpdateNeeded(desired, existing *v1.ConfigMap
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
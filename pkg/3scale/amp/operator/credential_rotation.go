@@ -0,0 +1,717 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// CredentialRotationAnnotationPrefix prefixes the annotation, named after
+// the credential, set to the rotation ID on the rotated secret and on the
+// pod templates of the workloads reading it
+const CredentialRotationAnnotationPrefix = "apps.3scale.net/credential-rotation-"
+
+const credentialRotationRolloutRequeueAfter = 10 * time.Second
+
+const (
+	// AccessTokenRotationSecretName is the secret keeping the access token
+	// being rotated until it is revoked
+	AccessTokenRotationSecretName = "system-access-token-rotation"
+	// AccessTokenRotationPreviousFieldName is the key of the access token
+	// being rotated
+	AccessTokenRotationPreviousFieldName = "PREVIOUS_ACCESS_TOKEN"
+)
+
+const (
+	accessTokenJobAdd    = "add"
+	accessTokenJobRevoke = "revoke"
+)
+
+// accessTokenScripts are the rails runner scripts run by the access token
+// Jobs. Both are idempotent: the rotated token is added once as a copy of
+// the previous one, with its owner and scopes, and the previous token is
+// revoked only once the rotated one exists
+var accessTokenScripts = map[string]string{
+	accessTokenJobAdd: `previous = AccessToken.find_by(value: ENV.fetch('PREVIOUS_ACCESS_TOKEN'))
+unless AccessToken.exists?(value: ENV.fetch('ROTATED_ACCESS_TOKEN'))
+  abort('Access token to rotate not found') unless previous
+  token = previous.dup
+  token.value = ENV.fetch('ROTATED_ACCESS_TOKEN')
+  token.save!(validate: false)
+  AccessToken.where(id: token.id).update_all(value: ENV.fetch('ROTATED_ACCESS_TOKEN'))
+end
+`,
+	accessTokenJobRevoke: `abort('Rotated access token not found') unless AccessToken.exists?(value: ENV.fetch('ROTATED_ACCESS_TOKEN'))
+AccessToken.where(value: ENV.fetch('PREVIOUS_ACCESS_TOKEN')).delete_all
+`,
+}
+
+// RotatableCredential describes where a credential is stored and the
+// workloads reading it
+type RotatableCredential struct {
+	SecretName string
+	SecretKey  string
+	// Length of the generated values
+	Length int
+	// Workloads reading the credential. They are rolled out together, the
+	// workloads sending the credential and the ones checking it are
+	// updated in the same step
+	Workloads []string
+	// AccessToken credentials are seeded into the system database. The
+	// rotated token is added to the database before the rollout and the
+	// previous one is revoked after it, so both are accepted while the
+	// workloads are rolled out
+	AccessToken bool
+}
+
+// rotatableCredentials indexes by name the credentials the operator can
+// rotate
+var rotatableCredentials = map[string]RotatableCredential{
+	appsv1alpha1.CredentialBackendInternalAPI: {
+		SecretName: component.BackendSecretInternalApiSecretName,
+		SecretKey:  component.BackendSecretInternalApiPasswordFieldName,
+		Length:     8,
+		Workloads:  []string{"backend-listener", "system-app", "system-sidekiq"},
+	},
+	appsv1alpha1.CredentialSystemAppSecretKeyBase: {
+		SecretName: component.SystemSecretSystemAppSecretName,
+		SecretKey:  component.SystemSecretSystemAppSecretKeyBaseFieldName,
+		Length:     128,
+		Workloads:  []string{"system-app", "system-sidekiq"},
+	},
+	appsv1alpha1.CredentialSystemEventsHook: {
+		SecretName: component.SystemSecretSystemEventsHookSecretName,
+		SecretKey:  component.SystemSecretSystemEventsHookPasswordFieldName,
+		Length:     8,
+		Workloads:  []string{"system-app", "system-sidekiq", "backend-worker"},
+	},
+	appsv1alpha1.CredentialZyncAuthenticationToken: {
+		SecretName: component.ZyncSecretName,
+		SecretKey:  component.ZyncSecretAuthenticationTokenFieldName,
+		Length:     16,
+		Workloads:  []string{"zync", "zync-que", "system-app", "system-sidekiq"},
+	},
+	appsv1alpha1.CredentialZyncSecretKeyBase: {
+		SecretName: component.ZyncSecretName,
+		SecretKey:  component.ZyncSecretKeyBaseFieldName,
+		Length:     16,
+		Workloads:  []string{"zync", "zync-que"},
+	},
+	appsv1alpha1.CredentialSystemSeedMasterAccessToken: {
+		SecretName:  component.SystemSecretSystemSeedSecretName,
+		SecretKey:   component.SystemSecretSystemSeedMasterAccessTokenFieldName,
+		Length:      8,
+		Workloads:   []string{"system-app", "system-sidekiq"},
+		AccessToken: true,
+	},
+	appsv1alpha1.CredentialSystemSeedAdminAccessToken: {
+		SecretName:  component.SystemSecretSystemSeedSecretName,
+		SecretKey:   component.SystemSecretSystemSeedAdminAccessTokenFieldName,
+		Length:      16,
+		Workloads:   []string{"system-app", "system-sidekiq"},
+		AccessToken: true,
+	},
+	appsv1alpha1.CredentialSystemMasterApicastAccessToken: {
+		SecretName:  component.SystemSecretSystemMasterApicastSecretName,
+		SecretKey:   component.SystemSecretSystemMasterApicastAccessToken,
+		Length:      8,
+		Workloads:   []string{"system-app", "system-sidekiq"},
+		AccessToken: true,
+	},
+}
+
+// CredentialRotator rotates the credentials requested with the
+// RotateCredentialsAnnotation or due by the CredentialRotationSpec
+// schedule. One credential is rotated at a time: the new value is stored
+// in its secret and the workloads reading it are rolled out. The progress is kept in the APIManager status, so a rotation is
+// resumed on the next reconciliation
+type CredentialRotator struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewCredentialRotator(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *CredentialRotator {
+	return &CredentialRotator{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+// Reconcile continues the rotation in progress or starts the next one.
+// The returned result requeues the request while workloads are rolled out
+// and when the next scheduled rotation is due
+func (r *CredentialRotator) Reconcile() (reconcile.Result, error) {
+	status := r.apiManager.Status.CredentialRotation
+	if status != nil && status.Current != "" {
+		return r.continueRotation()
+	}
+
+	requested, err := r.requestedCredential()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if requested != "" {
+		return r.startRotation(requested)
+	}
+
+	due, requeueAfter, err := r.scheduledCredential(time.Now())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if due != "" {
+		return r.startRotation(due)
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// requestedCredential returns the first credential of the
+// RotateCredentialsAnnotation. Unknown credentials are dropped from the
+// annotation and reported in the status
+func (r *CredentialRotator) requestedCredential() (string, error) {
+	value, ok := r.apiManager.Annotations[appsv1alpha1.RotateCredentialsAnnotation]
+	if !ok {
+		return "", nil
+	}
+
+	requested := []string{}
+	unknown := []string{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !appsv1alpha1.IsRotatableCredential(name) {
+			unknown = append(unknown, name)
+			continue
+		}
+		requested = append(requested, name)
+	}
+
+	message := ""
+	if len(unknown) > 0 {
+		message = fmt.Sprintf("Credentials %s are not rotatable. Rotatable credentials: %s",
+			strings.Join(unknown, ", "), strings.Join(appsv1alpha1.RotatableCredentials, ", "))
+		r.Logger().Info(message)
+	}
+	if message != r.rotationStatus().Message {
+		r.apiManager.Status.CredentialRotation.Message = message
+		err := r.updateStatus()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(requested) == 0 {
+		delete(r.apiManager.Annotations, appsv1alpha1.RotateCredentialsAnnotation)
+		return "", r.Client().Update(context.TODO(), r.apiManager)
+	}
+	return requested[0], nil
+}
+
+// scheduledCredential returns the first credential whose last rotation,
+// or the creation of its secret when never rotated, is older than the
+// rotation interval. Otherwise, the time until the next one is due
+func (r *CredentialRotator) scheduledCredential(now time.Time) (string, time.Duration, error) {
+	rotationSpec := r.apiManager.Spec.CredentialRotation
+	if rotationSpec == nil {
+		return "", 0, nil
+	}
+	interval := time.Duration(rotationSpec.IntervalDays) * 24 * time.Hour
+
+	var requeueAfter time.Duration
+	for _, name := range rotationSpec.ScheduledCredentials() {
+		var lastRotation time.Time
+		if rotated := r.apiManager.Status.CredentialRotation.FindRotatedCredential(name); rotated != nil {
+			lastRotation = rotated.LastRotationTime.Time
+		} else {
+			secret, err := helper.GetSecret(rotatableCredentials[name].SecretName, r.apiManager.Namespace, r.Client())
+			if err != nil {
+				if errors.IsNotFound(err) {
+					// Not deployed yet
+					continue
+				}
+				return "", 0, err
+			}
+			lastRotation = secret.CreationTimestamp.Time
+		}
+
+		remaining := lastRotation.Add(interval).Sub(now)
+		if remaining <= 0 {
+			return name, 0, nil
+		}
+		if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+	}
+	return "", requeueAfter, nil
+}
+
+// startRotation records the rotation in the status before changing any
+// object, and drops the credential from the RotateCredentialsAnnotation
+func (r *CredentialRotator) startRotation(name string) (reconcile.Result, error) {
+	r.Logger().Info(fmt.Sprintf("Rotating credential %s", name))
+
+	if value, ok := r.apiManager.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; ok {
+		pending := []string{}
+		for _, requested := range strings.Split(value, ",") {
+			requested = strings.TrimSpace(requested)
+			if requested != name && appsv1alpha1.IsRotatableCredential(requested) {
+				pending = append(pending, requested)
+			}
+		}
+		if len(pending) == 0 {
+			delete(r.apiManager.Annotations, appsv1alpha1.RotateCredentialsAnnotation)
+		} else {
+			r.apiManager.Annotations[appsv1alpha1.RotateCredentialsAnnotation] = strings.Join(pending, ",")
+		}
+		err := r.Client().Update(context.TODO(), r.apiManager)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	status := r.rotationStatus()
+	status.Current = name
+	status.RotationID = strconv.FormatInt(time.Now().Unix(), 10)
+	status.RolledOut = nil
+	err := r.updateStatus()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// continueRotation stores the new credential value, unless already stored
+// for the rotation in progress, and rolls out the workloads reading it.
+// Access tokens are added to the system database before the rollout and
+// the previous ones revoked after it. The rotation is completed once all
+// the steps are done
+func (r *CredentialRotator) continueRotation() (reconcile.Result, error) {
+	status := r.apiManager.Status.CredentialRotation
+	credential, ok := rotatableCredentials[status.Current]
+	if !ok {
+		return reconcile.Result{}, fmt.Errorf("Credential %s is not rotatable", status.Current)
+	}
+	annotation := CredentialRotationAnnotationPrefix + status.Current
+
+	if credential.AccessToken {
+		err := r.storePreviousAccessToken(credential, annotation, status.RotationID)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err := r.rotateSecret(credential, annotation, status.RotationID)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if credential.AccessToken {
+		done, err := r.runAccessTokenJob(credential, accessTokenJobAdd)
+		if err != nil || !done {
+			return reconcile.Result{Requeue: true, RequeueAfter: credentialRotationRolloutRequeueAfter}, err
+		}
+	}
+
+	rolledOut := map[string]bool{}
+	for _, workload := range status.RolledOut {
+		rolledOut[workload] = true
+	}
+
+	// All the workloads are updated before waiting for any of them
+	allRolledOut := true
+	for _, workload := range credential.Workloads {
+		if rolledOut[workload] {
+			continue
+		}
+		workloadRolledOut, err := r.rolloutWorkload(workload, annotation, status.RotationID)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !workloadRolledOut {
+			allRolledOut = false
+			continue
+		}
+		rolledOut[workload] = true
+		status.RolledOut = append(status.RolledOut, workload)
+		err = r.updateStatus()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if !allRolledOut {
+		return reconcile.Result{Requeue: true, RequeueAfter: credentialRotationRolloutRequeueAfter}, nil
+	}
+
+	if credential.AccessToken {
+		done, err := r.runAccessTokenJob(credential, accessTokenJobRevoke)
+		if err != nil || !done {
+			return reconcile.Result{Requeue: true, RequeueAfter: credentialRotationRolloutRequeueAfter}, err
+		}
+	}
+
+	r.Logger().Info(fmt.Sprintf("Credential %s rotated", status.Current))
+	now := metav1.Now()
+	rotated := status.FindRotatedCredential(status.Current)
+	if rotated == nil {
+		status.Credentials = append(status.Credentials, appsv1alpha1.RotatedCredentialStatus{Name: status.Current})
+		rotated = &status.Credentials[len(status.Credentials)-1]
+	}
+	rotated.LastRotationTime = now
+	sort.Slice(status.Credentials, func(i, j int) bool { return status.Credentials[i].Name < status.Credentials[j].Name })
+	current := status.Current
+	rotationID := status.RotationID
+	status.Current = ""
+	status.RotationID = ""
+	status.RolledOut = nil
+	status.FailedJob = ""
+	err = r.updateStatus()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if credential.AccessToken {
+		err = r.deleteAccessTokenRotationResources(current, rotationID)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// storePreviousAccessToken keeps the access token being rotated in the
+// AccessTokenRotationSecretName secret, read by the access token Jobs.
+// Once the credential secret holds the rotated token, the stored one is
+// not changed
+func (r *CredentialRotator) storePreviousAccessToken(credential RotatableCredential, annotation, rotationID string) error {
+	secret := &v1.Secret{}
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: credential.SecretName, Namespace: r.apiManager.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+	if secret.Annotations[annotation] == rotationID {
+		return nil
+	}
+
+	previousData := map[string][]byte{
+		AccessTokenRotationPreviousFieldName: secret.Data[credential.SecretKey],
+	}
+	existing := &v1.Secret{}
+	err = r.Client().Get(context.TODO(), types.NamespacedName{Name: AccessTokenRotationSecretName, Namespace: r.apiManager.Namespace}, existing)
+	if errors.IsNotFound(err) {
+		return r.createResource(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   AccessTokenRotationSecretName,
+				Labels: map[string]string{"app": *r.apiManager.Spec.AppLabel, "threescale_component": "system"},
+			},
+			Data: previousData,
+			Type: v1.SecretTypeOpaque,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	existing.Data = previousData
+	return r.updateResource(existing)
+}
+
+// runAccessTokenJob runs the Job updating the access tokens of the system
+// database and returns whether it succeeded. Failed Jobs are reported in
+// the status, runJobStep creates them again once deleted
+func (r *CredentialRotator) runAccessTokenJob(credential RotatableCredential, action string) (bool, error) {
+	status := r.apiManager.Status.CredentialRotation
+	job, err := r.accessTokenJob(credential, accessTokenJobName(status.Current, action, status.RotationID), accessTokenScripts[action])
+	if err != nil {
+		return false, err
+	}
+
+	state, err := runJobStep(r.BaseLogicReconciler, r.apiManager, job)
+	if err != nil {
+		return false, err
+	}
+
+	failedJob := ""
+	if state == jobStepFailed {
+		failedJob = job.Name
+		r.Logger().Info(fmt.Sprintf("Job %s failed rotating credential %s. Delete the Job to retry", job.Name, status.Current))
+	}
+	if failedJob != status.FailedJob {
+		status.FailedJob = failedJob
+		err = r.updateStatus()
+		if err != nil {
+			return false, err
+		}
+	}
+	return state == jobStepSucceeded, nil
+}
+
+// accessTokenJob returns the Job running the script with the system-app
+// environment, taken from its system-master container. The access token
+// being rotated and the rotated one are passed in the PREVIOUS_ACCESS_TOKEN
+// and ROTATED_ACCESS_TOKEN variables
+func (r *CredentialRotator) accessTokenJob(credential RotatableCredential, name, script string) (*batchv1.Job, error) {
+	template, err := r.systemAppPodTemplate()
+	if err != nil {
+		return nil, err
+	}
+	if len(template.Spec.Containers) == 0 {
+		return nil, fmt.Errorf("system-app has no containers")
+	}
+	systemContainer := template.Spec.Containers[0]
+	for _, container := range template.Spec.Containers {
+		if container.Name == "system-master" {
+			systemContainer = container
+		}
+	}
+
+	// The file storage is not needed. ReadWriteOnce volumes could not be
+	// mounted by the Job pod
+	volumes := []v1.Volume{}
+	volumeNames := map[string]bool{}
+	for _, volume := range template.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			continue
+		}
+		volumes = append(volumes, volume)
+		volumeNames[volume.Name] = true
+	}
+	volumeMounts := []v1.VolumeMount{}
+	for _, volumeMount := range systemContainer.VolumeMounts {
+		if volumeNames[volumeMount.Name] {
+			volumeMounts = append(volumeMounts, volumeMount)
+		}
+	}
+
+	env := append([]v1.EnvVar{}, systemContainer.Env...)
+	env = append(env,
+		envVarFromSecretKey("PREVIOUS_ACCESS_TOKEN", AccessTokenRotationSecretName, AccessTokenRotationPreviousFieldName),
+		envVarFromSecretKey("ROTATED_ACCESS_TOKEN", credential.SecretName, credential.SecretKey),
+	)
+
+	var backoffLimit int32 = 2
+	labels := map[string]string{
+		"app":                          *r.apiManager.Spec.AppLabel,
+		"threescale_component":         "system",
+		"threescale_component_element": "access-token-rotation",
+	}
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Job",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy:      v1.RestartPolicyNever,
+					ServiceAccountName: template.Spec.ServiceAccountName,
+					ImagePullSecrets:   template.Spec.ImagePullSecrets,
+					NodeSelector:       template.Spec.NodeSelector,
+					Tolerations:        template.Spec.Tolerations,
+					Volumes:            volumes,
+					Containers: []v1.Container{
+						{
+							Name:            "system-access-token-rotation",
+							Image:           systemContainer.Image,
+							ImagePullPolicy: systemContainer.ImagePullPolicy,
+							Command:         []string{"bash", "-c", `bundle exec rails runner "$ACCESS_TOKEN_SCRIPT"`},
+							Env:             append(env, v1.EnvVar{Name: "ACCESS_TOKEN_SCRIPT", Value: script}),
+							EnvFrom:         systemContainer.EnvFrom,
+							VolumeMounts:    volumeMounts,
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// systemAppPodTemplate returns the pod template of the system-app
+// DeploymentConfig, or Deployment in Deployment mode
+func (r *CredentialRotator) systemAppPodTemplate() (*v1.PodTemplateSpec, error) {
+	key := types.NamespacedName{Name: "system-app", Namespace: r.apiManager.Namespace}
+
+	if r.apiManager.IsKubernetesDeploymentEnabled() {
+		deployment := &k8sappsv1.Deployment{}
+		err := r.Client().Get(context.TODO(), key, deployment)
+		if err != nil {
+			return nil, err
+		}
+		return &deployment.Spec.Template, nil
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err := r.Client().Get(context.TODO(), key, dc)
+	if err != nil {
+		return nil, err
+	}
+	if dc.Spec.Template == nil {
+		return nil, fmt.Errorf("system-app has no pod template")
+	}
+	return dc.Spec.Template, nil
+}
+
+// deleteAccessTokenRotationResources deletes the access token Jobs, with
+// their pods, and the secret keeping the revoked token
+func (r *CredentialRotator) deleteAccessTokenRotationResources(credentialName, rotationID string) error {
+	for _, action := range []string{accessTokenJobAdd, accessTokenJobRevoke} {
+		job := &batchv1.Job{}
+		err := r.Client().Get(context.TODO(), types.NamespacedName{Name: accessTokenJobName(credentialName, action, rotationID), Namespace: r.apiManager.Namespace}, job)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		r.Logger().Info(fmt.Sprintf("Delete object %s", ObjectInfo(job)))
+		err = r.Client().Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return r.deleteOwnedResource(AccessTokenRotationSecretName, &v1.Secret{}, nil)
+}
+
+func accessTokenJobName(credentialName, action, rotationID string) string {
+	return fmt.Sprintf("%s-%s-%s", credentialName, action, rotationID)
+}
+
+func envVarFromSecretKey(name, secretName, secretKey string) v1.EnvVar {
+	return v1.EnvVar{
+		Name: name,
+		ValueFrom: &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: secretName},
+				Key:                  secretKey,
+			},
+		},
+	}
+}
+
+// rotateSecret stores a new credential value in the secret. The secret is
+// annotated with the rotation ID, so the value is generated once per
+// rotation
+func (r *CredentialRotator) rotateSecret(credential RotatableCredential, annotation, rotationID string) error {
+	secret := &v1.Secret{}
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: credential.SecretName, Namespace: r.apiManager.Namespace}, secret)
+	if err != nil {
+		return err
+	}
+	if secret.Annotations[annotation] == rotationID {
+		return nil
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[annotation] = rotationID
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[credential.SecretKey] = []byte(oprand.String(credential.Length))
+	return r.Client().Update(context.TODO(), secret)
+}
+
+// rolloutWorkload sets the rotation annotation of the workload pod
+// template, which rolls it out, and returns whether the rollout finished.
+// The DeploymentConfig, or the Deployment in Deployment mode, is used.
+// Workloads not deployed are considered rolled out
+func (r *CredentialRotator) rolloutWorkload(name, annotation, rotationID string) (bool, error) {
+	key := types.NamespacedName{Name: name, Namespace: r.apiManager.Namespace}
+
+	if r.apiManager.IsKubernetesDeploymentEnabled() {
+		deployment := &k8sappsv1.Deployment{}
+		err := r.Client().Get(context.TODO(), key, deployment)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if setPodTemplateAnnotation(&deployment.Spec.Template, annotation, rotationID) {
+			r.Logger().Info(fmt.Sprintf("Rolling out %s with the rotated credential", name))
+			return false, r.Client().Update(context.TODO(), deployment)
+		}
+		return deploymentRolledOut(deployment), nil
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err := r.Client().Get(context.TODO(), key, dc)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if dc.Spec.Template == nil {
+		return true, nil
+	}
+	if setPodTemplateAnnotation(dc.Spec.Template, annotation, rotationID) {
+		r.Logger().Info(fmt.Sprintf("Rolling out %s with the rotated credential", name))
+		return false, r.Client().Update(context.TODO(), dc)
+	}
+	return dc.Status.ObservedGeneration >= dc.Generation &&
+		dc.Status.Replicas == dc.Spec.Replicas &&
+		dc.Status.UpdatedReplicas == dc.Spec.Replicas &&
+		dc.Status.AvailableReplicas == dc.Spec.Replicas, nil
+}
+
+func setPodTemplateAnnotation(template *v1.PodTemplateSpec, annotation, value string) bool {
+	if template.Annotations[annotation] == value {
+		return false
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[annotation] = value
+	return true
+}
+
+func deploymentRolledOut(deployment *k8sappsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+func (r *CredentialRotator) rotationStatus() *appsv1alpha1.CredentialRotationStatus {
+	if r.apiManager.Status.CredentialRotation == nil {
+		r.apiManager.Status.CredentialRotation = &appsv1alpha1.CredentialRotationStatus{}
+	}
+	return r.apiManager.Status.CredentialRotation
+}
+
+func (r *CredentialRotator) updateStatus() error {
+	err := r.Client().Status().Update(context.TODO(), r.apiManager)
+	if err != nil {
+		r.Logger().Error(err, "Error updating credential rotation status")
+	}
+	return err
+}
@@ -0,0 +1,368 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func credentialRotationTestDC(name, namespace string) *appsv1.DeploymentConfig {
+	return &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentConfigSpec{
+			Replicas: 1,
+			Template: &v1.PodTemplateSpec{},
+		},
+	}
+}
+
+func TestRotatableCredentials(t *testing.T) {
+	for _, name := range appsv1alpha1.RotatableCredentials {
+		credential, ok := rotatableCredentials[name]
+		if !ok {
+			t.Errorf("credential %s has no secret and workloads", name)
+			continue
+		}
+		if credential.SecretName == "" || credential.SecretKey == "" || credential.Length == 0 || len(credential.Workloads) == 0 {
+			t.Errorf("credential %s is incomplete: %v", name, credential)
+		}
+	}
+	if len(rotatableCredentials) != len(appsv1alpha1.RotatableCredentials) {
+		t.Errorf("rotatable credentials (%d) not the expected (%d)", len(rotatableCredentials), len(appsv1alpha1.RotatableCredentials))
+	}
+}
+
+func TestCredentialRotationAnnotation(t *testing.T) {
+	namespace := "operator-unittest"
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apimanager",
+			Namespace: namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.RotateCredentialsAnnotation: "system-seed, backend-internal-api",
+			},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.BackendSecretInternalApiSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.BackendSecretInternalApiUsernameFieldName: []byte("3scale_api_user"),
+			component.BackendSecretInternalApiPasswordFieldName: []byte("old"),
+		},
+	}
+	listener := credentialRotationTestDC("backend-listener", namespace)
+	systemApp := credentialRotationTestDC("system-app", namespace)
+	baseReconciler, cl := testBaseAPIManagerLogicReconciler(t, apimanager, secret, listener, systemApp)
+	rotator := NewCredentialRotator(baseReconciler)
+
+	getObject := func(name string, obj runtime.Object) {
+		err := cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	reconcileRotation := func() {
+		res, err := rotator.Reconcile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Requeue {
+			t.Fatal("rotation in progress should requeue")
+		}
+	}
+	rotationAnnotation := CredentialRotationAnnotationPrefix + appsv1alpha1.CredentialBackendInternalAPI
+
+	// Rotation started
+	reconcileRotation()
+	status := apimanager.Status.CredentialRotation
+	if status == nil || status.Current != appsv1alpha1.CredentialBackendInternalAPI || status.RotationID == "" {
+		t.Fatalf("rotation not started: %v", status)
+	}
+	if !strings.Contains(status.Message, "Credentials system-seed are not rotatable") {
+		t.Errorf("not rotatable credentials not reported: %s", status.Message)
+	}
+	if _, ok := apimanager.Annotations[appsv1alpha1.RotateCredentialsAnnotation]; ok {
+		t.Error("rotate credentials annotation not removed")
+	}
+
+	// Secret rotated and the workloads checking and sending the credential
+	// rolled out in the same step
+	reconcileRotation()
+	getObject(secret.Name, secret)
+	password := string(secret.Data[component.BackendSecretInternalApiPasswordFieldName])
+	if password == "old" || secret.Annotations[rotationAnnotation] != status.RotationID {
+		t.Fatalf("secret not rotated: %v", secret)
+	}
+	if string(secret.Data[component.BackendSecretInternalApiUsernameFieldName]) != "3scale_api_user" {
+		t.Error("secret field not rotated changed")
+	}
+	getObject(listener.Name, listener)
+	if listener.Spec.Template.Annotations[rotationAnnotation] != status.RotationID {
+		t.Fatal("backend-listener not rolled out")
+	}
+	getObject(systemApp.Name, systemApp)
+	if systemApp.Spec.Template.Annotations[rotationAnnotation] != status.RotationID {
+		t.Fatal("system-app not rolled out with backend-listener")
+	}
+
+	// Waiting for the rollouts, the value is generated once
+	reconcileRotation()
+	getObject(secret.Name, secret)
+	if string(secret.Data[component.BackendSecretInternalApiPasswordFieldName]) != password {
+		t.Fatal("credential generated again for the same rotation")
+	}
+
+	listener.Status = appsv1.DeploymentConfigStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	err := cl.Update(context.TODO(), listener)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileRotation()
+	// system-sidekiq is not deployed
+	if status.Current == "" || len(status.RolledOut) != 2 || status.RolledOut[1] != listener.Name {
+		t.Errorf("unexpected rolled out workloads: %v", status.RolledOut)
+	}
+
+	systemApp.Status = appsv1.DeploymentConfigStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	err = cl.Update(context.TODO(), systemApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileRotation()
+	if status.Current != "" || status.RotationID != "" || len(status.RolledOut) != 0 {
+		t.Errorf("rotation not completed: %v", status)
+	}
+	rotated := status.FindRotatedCredential(appsv1alpha1.CredentialBackendInternalAPI)
+	if rotated == nil || rotated.LastRotationTime.IsZero() {
+		t.Errorf("rotation time not recorded: %v", status.Credentials)
+	}
+
+	res, err := rotator.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue || res.RequeueAfter != 0 {
+		t.Errorf("unexpected requeue without rotations: %v", res)
+	}
+}
+
+func TestCredentialRotationAccessToken(t *testing.T) {
+	namespace := "operator-unittest"
+	appLabel := "someLabel"
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apimanager",
+			Namespace: namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.RotateCredentialsAnnotation: appsv1alpha1.CredentialSystemSeedMasterAccessToken,
+			},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{AppLabel: &appLabel},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSeedSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSeedMasterAccessTokenFieldName: []byte("old"),
+			component.SystemSecretSystemSeedAdminAccessTokenFieldName:  []byte("admin"),
+		},
+	}
+	systemApp := credentialRotationTestDC("system-app", namespace)
+	systemApp.Spec.Template.Spec = v1.PodSpec{
+		ServiceAccountName: "amp",
+		Volumes: []v1.Volume{
+			{Name: "system-storage", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "system-storage"}}},
+			{Name: "system-config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "system"}}}},
+		},
+		Containers: []v1.Container{
+			{
+				Name:  "system-master",
+				Image: "quay.io/3scale/porta:nightly",
+				Env:   []v1.EnvVar{{Name: "RAILS_ENV", Value: "production"}},
+				VolumeMounts: []v1.VolumeMount{
+					{Name: "system-storage", MountPath: "/opt/system/public/system"},
+					{Name: "system-config", MountPath: "/opt/system-extra-configs"},
+				},
+			},
+		},
+	}
+	baseReconciler, cl := testBaseAPIManagerLogicReconciler(t, apimanager, secret, systemApp)
+	rotator := NewCredentialRotator(baseReconciler)
+
+	getObject := func(name string, obj runtime.Object) {
+		err := cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	reconcileRotation := func() {
+		res, err := rotator.Reconcile()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Requeue {
+			t.Fatal("rotation in progress should requeue")
+		}
+	}
+	rotationAnnotation := CredentialRotationAnnotationPrefix + appsv1alpha1.CredentialSystemSeedMasterAccessToken
+
+	reconcileRotation()
+	status := apimanager.Status.CredentialRotation
+	if status == nil || status.Current != appsv1alpha1.CredentialSystemSeedMasterAccessToken {
+		t.Fatalf("rotation not started: %v", status)
+	}
+	addJobName := accessTokenJobName(status.Current, accessTokenJobAdd, status.RotationID)
+	revokeJobName := accessTokenJobName(status.Current, accessTokenJobRevoke, status.RotationID)
+
+	// Previous token kept, secret rotated and the rotated token added to the
+	// system database before the rollout
+	reconcileRotation()
+	previous := &v1.Secret{}
+	getObject(AccessTokenRotationSecretName, previous)
+	if string(previous.Data[AccessTokenRotationPreviousFieldName]) != "old" {
+		t.Errorf("previous access token not kept: %v", previous.Data)
+	}
+	getObject(secret.Name, secret)
+	if token := string(secret.Data[component.SystemSecretSystemSeedMasterAccessTokenFieldName]); token == "old" || token == "" {
+		t.Fatalf("secret not rotated: %v", secret)
+	}
+	if string(secret.Data[component.SystemSecretSystemSeedAdminAccessTokenFieldName]) != "admin" {
+		t.Error("secret field not rotated changed")
+	}
+	addJob := &batchv1.Job{}
+	getObject(addJobName, addJob)
+	podSpec := addJob.Spec.Template.Spec
+	if podSpec.ServiceAccountName != "amp" || len(podSpec.Volumes) != 1 || podSpec.Volumes[0].Name != "system-config" {
+		t.Errorf("unexpected access token Job pod: %v", podSpec)
+	}
+	container := podSpec.Containers[0]
+	if container.Image != "quay.io/3scale/porta:nightly" || len(container.VolumeMounts) != 1 {
+		t.Errorf("unexpected access token Job container: %v", container)
+	}
+	envSecrets := map[string]string{}
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			envSecrets[env.Name] = env.ValueFrom.SecretKeyRef.Name + "/" + env.ValueFrom.SecretKeyRef.Key
+		}
+	}
+	if envSecrets["PREVIOUS_ACCESS_TOKEN"] != AccessTokenRotationSecretName+"/"+AccessTokenRotationPreviousFieldName ||
+		envSecrets["ROTATED_ACCESS_TOKEN"] != component.SystemSecretSystemSeedSecretName+"/"+component.SystemSecretSystemSeedMasterAccessTokenFieldName {
+		t.Errorf("unexpected access token Job secrets: %v", envSecrets)
+	}
+
+	// Failed Jobs are reported and run again once deleted
+	addJob.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}}
+	err := cl.Update(context.TODO(), addJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileRotation()
+	if status.FailedJob != addJobName {
+		t.Errorf("failed Job not reported: %v", status)
+	}
+	getObject(systemApp.Name, systemApp)
+	if _, ok := systemApp.Spec.Template.Annotations[rotationAnnotation]; ok {
+		t.Fatal("system-app rolled out before the rotated token was added")
+	}
+	err = cl.Delete(context.TODO(), addJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileRotation()
+	if status.FailedJob != "" {
+		t.Errorf("failed Job still reported: %v", status)
+	}
+
+	completeJob(t, cl, addJobName, namespace)
+	reconcileRotation()
+	getObject(systemApp.Name, systemApp)
+	if systemApp.Spec.Template.Annotations[rotationAnnotation] != status.RotationID {
+		t.Fatal("system-app not rolled out")
+	}
+
+	// The previous token is revoked once rolled out
+	systemApp.Status = appsv1.DeploymentConfigStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	err = cl.Update(context.TODO(), systemApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileRotation()
+	if status.Current == "" {
+		t.Fatal("rotation completed before revoking the previous token")
+	}
+	completeJob(t, cl, revokeJobName, namespace)
+	reconcileRotation()
+	if status.Current != "" || status.FindRotatedCredential(appsv1alpha1.CredentialSystemSeedMasterAccessToken) == nil {
+		t.Errorf("rotation not completed: %v", status)
+	}
+	for _, name := range []string{addJobName, revokeJobName} {
+		err = cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &batchv1.Job{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("Job %s not deleted once rotated: %v", name, err)
+		}
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: AccessTokenRotationSecretName, Namespace: namespace}, &v1.Secret{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("previous access token not deleted once rotated: %v", err)
+	}
+}
+
+func TestCredentialRotationSchedule(t *testing.T) {
+	var (
+		namespace = "operator-unittest"
+		now       = time.Now()
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: namespace},
+		Spec: appsv1alpha1.APIManagerSpec{
+			CredentialRotation: &appsv1alpha1.CredentialRotationSpec{
+				IntervalDays: 90,
+				Credentials:  []string{appsv1alpha1.CredentialZyncSecretKeyBase},
+			},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              component.ZyncSecretName,
+			Namespace:         namespace,
+			CreationTimestamp: metav1.NewTime(now.Add(-24 * time.Hour)),
+		},
+	}
+	baseReconciler, _ := testBaseAPIManagerLogicReconciler(t, apimanager, secret)
+	rotator := NewCredentialRotator(baseReconciler)
+
+	due, requeueAfter, err := rotator.scheduledCredential(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The creation timestamp is stored with seconds precision
+	if due != "" || requeueAfter > 89*24*time.Hour || requeueAfter < 89*24*time.Hour-time.Second {
+		t.Errorf("unexpected scheduled rotation %s in %s", due, requeueAfter)
+	}
+
+	lastRotationTime := metav1.NewTime(now.Add(-91 * 24 * time.Hour))
+	apimanager.Status.CredentialRotation = &appsv1alpha1.CredentialRotationStatus{
+		Credentials: []appsv1alpha1.RotatedCredentialStatus{
+			{Name: appsv1alpha1.CredentialZyncSecretKeyBase, LastRotationTime: lastRotationTime},
+		},
+	}
+	due, _, err = rotator.scheduledCredential(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if due != appsv1alpha1.CredentialZyncSecretKeyBase {
+		t.Errorf("credential rotated %s ago not due", now.Sub(lastRotationTime.Time))
+	}
+}
@@ -3,12 +3,16 @@ package operator
 import (
 	"context"
 	"fmt"
+	"reflect"
 
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"github.com/go-logr/logr"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,6 +35,10 @@ func NewDeploymentConfigBaseReconciler(baseAPIManagerLogicReconciler BaseAPIMana
 }
 
 func (r *DeploymentConfigBaseReconciler) Reconcile(desired *appsv1.DeploymentConfig) error {
+	if r.apiManager.IsKubernetesDeploymentEnabled() {
+		return r.reconcileKubernetesWorkload(desired)
+	}
+
 	objectInfo := ObjectInfo(desired)
 	existing := &appsv1.DeploymentConfig{}
 	err := r.Client().Get(
@@ -74,6 +82,17 @@ func (r *DeploymentConfigBaseReconciler) isUpdateNeeded(desired, existing *appsv
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	// Pod placement is always managed from the APIManager, whatever the
+	// reconciler of the DeploymentConfig is
+	updatedTmp = DeploymentConfigReconcilePodPlacement(desired, existing, r.Logger())
+	updated = updated || updatedTmp
+
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -104,6 +123,87 @@ func DeploymentConfigReconcileContainerResources(desired, existing *appsv1.Deplo
 	return update
 }
 
+// DeploymentConfigReconcileContainerEnvVars reconciles the given environment
+// variables of the DeploymentConfig container. Variables missing in the
+// desired DeploymentConfig are removed. Other variables are left untouched
+func DeploymentConfigReconcileContainerEnvVars(desired, existing *appsv1.DeploymentConfig, envVarNames []string, logger logr.Logger) bool {
+	desiredName := ObjectInfo(desired)
+	update := false
+
+	if len(desired.Spec.Template.Spec.Containers) != 1 || len(existing.Spec.Template.Spec.Containers) != 1 {
+		return false
+	}
+
+	desiredContainer := &desired.Spec.Template.Spec.Containers[0]
+	existingContainer := &existing.Spec.Template.Spec.Containers[0]
+	for _, envVarName := range envVarNames {
+		desiredIdx := findEnvVar(desiredContainer.Env, envVarName)
+		existingIdx := findEnvVar(existingContainer.Env, envVarName)
+
+		switch {
+		case desiredIdx < 0 && existingIdx < 0:
+			continue
+		case desiredIdx < 0:
+			existingContainer.Env = append(existingContainer.Env[:existingIdx], existingContainer.Env[existingIdx+1:]...)
+		case existingIdx < 0:
+			existingContainer.Env = append(existingContainer.Env, desiredContainer.Env[desiredIdx])
+		case !reflect.DeepEqual(desiredContainer.Env[desiredIdx], existingContainer.Env[existingIdx]):
+			existingContainer.Env[existingIdx] = desiredContainer.Env[desiredIdx]
+		default:
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("%s spec.template.spec.containers[0].env %s differs", desiredName, envVarName))
+		update = true
+	}
+
+	return update
+}
+
+// DeploymentConfigReconcileContainerPorts reconciles the ports of the
+// DeploymentConfig container
+func DeploymentConfigReconcileContainerPorts(desired, existing *appsv1.DeploymentConfig, logger logr.Logger) bool {
+	if len(desired.Spec.Template.Spec.Containers) != 1 || len(existing.Spec.Template.Spec.Containers) != 1 {
+		return false
+	}
+
+	desiredPorts := desired.Spec.Template.Spec.Containers[0].Ports
+	if reflect.DeepEqual(desiredPorts, existing.Spec.Template.Spec.Containers[0].Ports) {
+		return false
+	}
+
+	logger.Info(fmt.Sprintf("%s spec.template.spec.containers[0].ports differ", ObjectInfo(desired)))
+	existing.Spec.Template.Spec.Containers[0].Ports = desiredPorts
+	return true
+}
+
+// DeploymentConfigReconcileContainerImage reconciles the image of the
+// DeploymentConfig container. Only DeploymentConfigs with plain image
+// references are expected, ImageChange triggers manage the image otherwise
+func DeploymentConfigReconcileContainerImage(desired, existing *appsv1.DeploymentConfig, logger logr.Logger) bool {
+	if len(desired.Spec.Template.Spec.Containers) != 1 || len(existing.Spec.Template.Spec.Containers) != 1 {
+		return false
+	}
+
+	desiredImage := desired.Spec.Template.Spec.Containers[0].Image
+	if desiredImage == existing.Spec.Template.Spec.Containers[0].Image {
+		return false
+	}
+
+	logger.Info(fmt.Sprintf("%s spec.template.spec.containers[0].image differs", ObjectInfo(desired)))
+	existing.Spec.Template.Spec.Containers[0].Image = desiredImage
+	return true
+}
+
+func findEnvVar(envVars []v1.EnvVar, name string) int {
+	for idx := range envVars {
+		if envVars[idx].Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
 func DeploymentConfigReconcileReplicas(desired, existing *appsv1.DeploymentConfig, logger logr.Logger) bool {
 	desiredName := ObjectInfo(desired)
 	update := false
@@ -117,6 +217,118 @@ func DeploymentConfigReconcileReplicas(desired, existing *appsv1.DeploymentConfi
 	return update
 }
 
+// DeploymentConfigReconcilePodPlacement reconciles the scheduling
+// constraints of the pod template: node selector, tolerations, affinity
+// and priority class
+func DeploymentConfigReconcilePodPlacement(desired, existing *appsv1.DeploymentConfig, logger logr.Logger) bool {
+	desiredName := ObjectInfo(desired)
+	update := false
+
+	if desired.Spec.Template == nil || existing.Spec.Template == nil {
+		return false
+	}
+
+	desiredPodSpec := &desired.Spec.Template.Spec
+	existingPodSpec := &existing.Spec.Template.Spec
+
+	if !equality.Semantic.DeepEqual(existingPodSpec.NodeSelector, desiredPodSpec.NodeSelector) {
+		logger.Info(fmt.Sprintf("%s spec.template.spec.nodeSelector differs", desiredName))
+		existingPodSpec.NodeSelector = desiredPodSpec.NodeSelector
+		update = true
+	}
+
+	if !equality.Semantic.DeepEqual(existingPodSpec.Tolerations, desiredPodSpec.Tolerations) {
+		logger.Info(fmt.Sprintf("%s spec.template.spec.tolerations differ", desiredName))
+		existingPodSpec.Tolerations = desiredPodSpec.Tolerations
+		update = true
+	}
+
+	if !equality.Semantic.DeepEqual(existingPodSpec.Affinity, desiredPodSpec.Affinity) {
+		logger.Info(fmt.Sprintf("%s spec.template.spec.affinity differs", desiredName))
+		existingPodSpec.Affinity = desiredPodSpec.Affinity
+		update = true
+	}
+
+	if existingPodSpec.PriorityClassName != desiredPodSpec.PriorityClassName {
+		logger.Info(fmt.Sprintf("%s spec.template.spec.priorityClassName differs", desiredName))
+		existingPodSpec.PriorityClassName = desiredPodSpec.PriorityClassName
+		update = true
+	}
+
+	return update
+}
+
+// DeploymentConfigReconcileDatastoreTLS reconciles the TLS files of the
+// external datastores mounted into a DeploymentConfig. The hash annotation
+// of the pod template identifies them, so the volumes, mounts and
+// environment variables are only replaced when it differs. The pre
+// lifecycle hook is replaced as well
+func DeploymentConfigReconcileDatastoreTLS(desired, existing *appsv1.DeploymentConfig, logger logr.Logger) bool {
+	if desired.Spec.Template == nil || existing.Spec.Template == nil {
+		return false
+	}
+
+	desiredHash := desired.Spec.Template.Annotations[component.DatastoreTLSHashAnnotation]
+	existingHash := existing.Spec.Template.Annotations[component.DatastoreTLSHashAnnotation]
+	if desiredHash == existingHash {
+		return false
+	}
+
+	if desiredHash == "" {
+		delete(existing.Spec.Template.Annotations, component.DatastoreTLSHashAnnotation)
+	} else {
+		if existing.Spec.Template.Annotations == nil {
+			existing.Spec.Template.Annotations = map[string]string{}
+		}
+		existing.Spec.Template.Annotations[component.DatastoreTLSHashAnnotation] = desiredHash
+	}
+
+	existing.Spec.Template.Spec.Volumes = desired.Spec.Template.Spec.Volumes
+	reconcileContainersTLS(desired.Spec.Template.Spec.InitContainers, existing.Spec.Template.Spec.InitContainers)
+	reconcileContainersTLS(desired.Spec.Template.Spec.Containers, existing.Spec.Template.Spec.Containers)
+	existing.Spec.Strategy.RollingParams = desired.Spec.Strategy.RollingParams
+
+	logger.Info(fmt.Sprintf("%s mounted datastore TLS files differ", ObjectInfo(desired)))
+	return true
+}
+
+// DeploymentConfigReconcilePodTemplateAnnotation reconciles a pod template
+// annotation. Updating it rolls out the DeploymentConfig
+func DeploymentConfigReconcilePodTemplateAnnotation(desired, existing *appsv1.DeploymentConfig, annotation string, logger logr.Logger) bool {
+	if desired.Spec.Template == nil || existing.Spec.Template == nil {
+		return false
+	}
+
+	desiredValue, desiredOk := desired.Spec.Template.Annotations[annotation]
+	existingValue, existingOk := existing.Spec.Template.Annotations[annotation]
+	if desiredOk == existingOk && desiredValue == existingValue {
+		return false
+	}
+
+	if !desiredOk {
+		delete(existing.Spec.Template.Annotations, annotation)
+	} else {
+		if existing.Spec.Template.Annotations == nil {
+			existing.Spec.Template.Annotations = map[string]string{}
+		}
+		existing.Spec.Template.Annotations[annotation] = desiredValue
+	}
+
+	logger.Info(fmt.Sprintf("%s spec.template.metadata.annotations[%s] differs", ObjectInfo(desired), annotation))
+	return true
+}
+
+func reconcileContainersTLS(desired, existing []v1.Container) {
+	for _, desiredContainer := range desired {
+		for idx := range existing {
+			if existing[idx].Name == desiredContainer.Name {
+				existing[idx].VolumeMounts = desiredContainer.VolumeMounts
+				existing[idx].Env = desiredContainer.Env
+			}
+		}
+	}
+}
+
 type CreateOnlyDCReconciler struct {
 }
 
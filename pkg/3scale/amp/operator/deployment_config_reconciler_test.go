@@ -2,6 +2,7 @@ package operator
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
@@ -336,3 +337,127 @@ func TestDeploymentConfigReconcileContainerResources(t *testing.T) {
 		})
 	}
 }
+
+func TestDeploymentConfigReconcilePodPlacement(t *testing.T) {
+	logger := logf.Log.WithName("operator_test")
+	dcFactory := func(mutate func(*corev1.PodSpec)) *appsv1.DeploymentConfig {
+		dc := &appsv1.DeploymentConfig{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "DeploymentConfig",
+				APIVersion: "apps.openshift.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myDC",
+				Namespace: "myNS",
+			},
+			Spec: appsv1.DeploymentConfigSpec{
+				Template: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							corev1.Container{Name: "container1"},
+						},
+					},
+				},
+			},
+		}
+		mutate(&dc.Spec.Template.Spec)
+		return dc
+	}
+	noPlacement := func(podSpec *corev1.PodSpec) {}
+	edgePlacement := func(podSpec *corev1.PodSpec) {
+		podSpec.NodeSelector = map[string]string{"node-role.kubernetes.io/edge": "true"}
+		podSpec.Tolerations = []corev1.Toleration{
+			corev1.Toleration{Key: "edge", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		}
+		podSpec.Affinity = &corev1.Affinity{
+			PodAntiAffinity: &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+					corev1.WeightedPodAffinityTerm{
+						Weight: 100,
+						PodAffinityTerm: corev1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"deploymentConfig": "myDC"}},
+							TopologyKey:   "kubernetes.io/hostname",
+						},
+					},
+				},
+			},
+		}
+		podSpec.PriorityClassName = "high-priority"
+	}
+	emptyPlacement := func(podSpec *corev1.PodSpec) {
+		podSpec.NodeSelector = map[string]string{}
+		podSpec.Tolerations = []corev1.Toleration{}
+	}
+
+	cases := []struct {
+		testName          string
+		existingPlacement func(*corev1.PodSpec)
+		desiredPlacement  func(*corev1.PodSpec)
+		expectedResult    bool
+	}{
+		{"NothingToReconcile", noPlacement, noPlacement, false},
+		{"NothingToReconcileWithPlacement", edgePlacement, edgePlacement, false},
+		{"NothingToReconcileEmptyPlacement", noPlacement, emptyPlacement, false},
+		{"AddPlacement", noPlacement, edgePlacement, true},
+		{"RemovePlacement", edgePlacement, noPlacement, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			existing := dcFactory(tc.existingPlacement)
+			desired := dcFactory(tc.desiredPlacement)
+			update := DeploymentConfigReconcilePodPlacement(desired, existing, logger)
+			if update != tc.expectedResult {
+				subT.Fatalf("result failed, expected: %t, got: %t", tc.expectedResult, update)
+			}
+			if update && !cmp.Equal(existing.Spec.Template.Spec, desired.Spec.Template.Spec) {
+				subT.Fatal(cmp.Diff(existing.Spec.Template.Spec, desired.Spec.Template.Spec))
+			}
+		})
+	}
+}
+
+func TestDeploymentConfigReconcileContainerEnvVars(t *testing.T) {
+	log := logf.Log.WithName("operator_test")
+	newDC := func(env []corev1.EnvVar) *appsv1.DeploymentConfig {
+		return &appsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "apicast-production"},
+			Spec: appsv1.DeploymentConfigSpec{
+				Template: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							corev1.Container{Name: "apicast-production", Env: env},
+						},
+					},
+				},
+			},
+		}
+	}
+	managed := []string{"APICAST_LOG_LEVEL", "APICAST_WORKERS", "APICAST_SERVICES_LIST"}
+
+	desired := newDC([]corev1.EnvVar{
+		{Name: "APICAST_LOG_LEVEL", Value: "debug"},
+		{Name: "APICAST_WORKERS", Value: "4"},
+	})
+	existing := newDC([]corev1.EnvVar{
+		{Name: "THREESCALE_PORTAL_ENDPOINT", Value: "http://system-master"},
+		{Name: "APICAST_LOG_LEVEL", Value: "warn"},
+		{Name: "APICAST_SERVICES_LIST", Value: "1,2"},
+	})
+
+	if !DeploymentConfigReconcileContainerEnvVars(desired, existing, managed, log) {
+		t.Error("env vars not reconciled")
+	}
+	expected := []corev1.EnvVar{
+		{Name: "THREESCALE_PORTAL_ENDPOINT", Value: "http://system-master"},
+		{Name: "APICAST_LOG_LEVEL", Value: "debug"},
+		{Name: "APICAST_WORKERS", Value: "4"},
+	}
+	if !reflect.DeepEqual(existing.Spec.Template.Spec.Containers[0].Env, expected) {
+		t.Errorf("unexpected reconciled env vars: %v", existing.Spec.Template.Spec.Containers[0].Env)
+	}
+
+	if DeploymentConfigReconcileContainerEnvVars(desired, existing, managed, log) {
+		t.Error("unchanged env vars reconciled")
+	}
+}
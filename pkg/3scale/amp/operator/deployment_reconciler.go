@@ -0,0 +1,290 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/go-logr/logr"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type DeploymentReconciler interface {
+	IsUpdateNeeded(desired, existing *k8sappsv1.Deployment) bool
+}
+
+type DeploymentBaseReconciler struct {
+	BaseAPIManagerLogicReconciler
+	reconciler DeploymentReconciler
+}
+
+func NewDeploymentBaseReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler, reconciler DeploymentReconciler) *DeploymentBaseReconciler {
+	return &DeploymentBaseReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+		reconciler:                    reconciler,
+	}
+}
+
+func (r *DeploymentBaseReconciler) Reconcile(desired *k8sappsv1.Deployment) error {
+	objectInfo := ObjectInfo(desired)
+	existing := &k8sappsv1.Deployment{}
+	err := r.Client().Get(
+		context.TODO(),
+		types.NamespacedName{Name: desired.Name, Namespace: r.apiManager.GetNamespace()},
+		existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			createErr := r.createResource(desired)
+			if createErr != nil {
+				r.Logger().Error(createErr, fmt.Sprintf("Error creating object %s. Requeuing request...", objectInfo))
+				return createErr
+			}
+			return nil
+		}
+		return err
+	}
+
+	update, err := r.isUpdateNeeded(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		return r.updateResource(existing)
+	}
+
+	return nil
+}
+
+func (r *DeploymentBaseReconciler) isUpdateNeeded(desired, existing *k8sappsv1.Deployment) (bool, error) {
+	updated := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+
+	updatedTmp, err := r.ensureOwnerReference(existing)
+	if err != nil {
+		return false, err
+	}
+
+	updated = updated || updatedTmp
+
+	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
+	updated = updated || updatedTmp
+
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	return updated, nil
+}
+
+type CreateOnlyDeploymentReconciler struct {
+}
+
+func NewCreateOnlyDeploymentReconciler() *CreateOnlyDeploymentReconciler {
+	return &CreateOnlyDeploymentReconciler{}
+}
+
+func (r *CreateOnlyDeploymentReconciler) IsUpdateNeeded(desired, existing *k8sappsv1.Deployment) bool {
+	return false
+}
+
+type StatefulSetReconciler interface {
+	IsUpdateNeeded(desired, existing *k8sappsv1.StatefulSet) bool
+}
+
+type StatefulSetBaseReconciler struct {
+	BaseAPIManagerLogicReconciler
+	reconciler StatefulSetReconciler
+}
+
+func NewStatefulSetBaseReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler, reconciler StatefulSetReconciler) *StatefulSetBaseReconciler {
+	return &StatefulSetBaseReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+		reconciler:                    reconciler,
+	}
+}
+
+func (r *StatefulSetBaseReconciler) Reconcile(desired *k8sappsv1.StatefulSet) error {
+	objectInfo := ObjectInfo(desired)
+	existing := &k8sappsv1.StatefulSet{}
+	err := r.Client().Get(
+		context.TODO(),
+		types.NamespacedName{Name: desired.Name, Namespace: r.apiManager.GetNamespace()},
+		existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			createErr := r.createResource(desired)
+			if createErr != nil {
+				r.Logger().Error(createErr, fmt.Sprintf("Error creating object %s. Requeuing request...", objectInfo))
+				return createErr
+			}
+			return nil
+		}
+		return err
+	}
+
+	update, err := r.isUpdateNeeded(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		return r.updateResource(existing)
+	}
+
+	return nil
+}
+
+func (r *StatefulSetBaseReconciler) isUpdateNeeded(desired, existing *k8sappsv1.StatefulSet) (bool, error) {
+	updated := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+
+	updatedTmp, err := r.ensureOwnerReference(existing)
+	if err != nil {
+		return false, err
+	}
+
+	updated = updated || updatedTmp
+
+	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
+	updated = updated || updatedTmp
+
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	return updated, nil
+}
+
+type CreateOnlyStatefulSetReconciler struct {
+}
+
+func NewCreateOnlyStatefulSetReconciler() *CreateOnlyStatefulSetReconciler {
+	return &CreateOnlyStatefulSetReconciler{}
+}
+
+func (r *CreateOnlyStatefulSetReconciler) IsUpdateNeeded(desired, existing *k8sappsv1.StatefulSet) bool {
+	return false
+}
+
+// deploymentConfigAdapterReconciler reuses a DeploymentConfigReconciler to
+// reconcile the Deployment and StatefulSet equivalents of a DeploymentConfig.
+// DeploymentConfig reconcilers only check replicas and pod template fields,
+// both present in Deployments and StatefulSets
+type deploymentConfigAdapterReconciler struct {
+	reconciler DeploymentConfigReconciler
+	logger     logr.Logger
+}
+
+func (r *deploymentConfigAdapterReconciler) IsUpdateNeeded(desired, existing *k8sappsv1.Deployment) bool {
+	return r.isUpdateNeeded(desired.ObjectMeta, desired.Spec.Replicas, &desired.Spec.Template,
+		existing.ObjectMeta, &existing.Spec.Replicas, &existing.Spec.Template)
+}
+
+type statefulSetDeploymentConfigAdapterReconciler struct {
+	deploymentConfigAdapterReconciler
+}
+
+func (r *statefulSetDeploymentConfigAdapterReconciler) IsUpdateNeeded(desired, existing *k8sappsv1.StatefulSet) bool {
+	return r.isUpdateNeeded(desired.ObjectMeta, desired.Spec.Replicas, &desired.Spec.Template,
+		existing.ObjectMeta, &existing.Spec.Replicas, &existing.Spec.Template)
+}
+
+func (r *deploymentConfigAdapterReconciler) isUpdateNeeded(desiredMeta metav1.ObjectMeta, desiredReplicas *int32, desiredTemplate *v1.PodTemplateSpec,
+	existingMeta metav1.ObjectMeta, existingReplicas **int32, existingTemplate *v1.PodTemplateSpec) bool {
+	desiredDC := &appsv1.DeploymentConfig{
+		ObjectMeta: desiredMeta,
+		Spec:       appsv1.DeploymentConfigSpec{Template: desiredTemplate},
+	}
+	if desiredReplicas != nil {
+		desiredDC.Spec.Replicas = *desiredReplicas
+	}
+
+	// The existing pod template is shared so changes made by the
+	// DeploymentConfig reconciler are applied to the existing object
+	existingDC := &appsv1.DeploymentConfig{
+		ObjectMeta: existingMeta,
+		Spec:       appsv1.DeploymentConfigSpec{Template: existingTemplate},
+	}
+	if *existingReplicas != nil {
+		existingDC.Spec.Replicas = **existingReplicas
+	}
+
+	update := r.reconciler.IsUpdateNeeded(desiredDC, existingDC)
+	update = DeploymentConfigReconcilePodPlacement(desiredDC, existingDC, r.logger) || update
+	if *existingReplicas == nil || **existingReplicas != existingDC.Spec.Replicas {
+		replicas := existingDC.Spec.Replicas
+		*existingReplicas = &replicas
+	}
+
+	return update
+}
+
+// reconcileKubernetesWorkload reconciles the Deployment, or the StatefulSet
+// for datastores, equivalent to the desired DeploymentConfig
+func (r *DeploymentConfigBaseReconciler) reconcileKubernetesWorkload(desired *appsv1.DeploymentConfig) error {
+	images, err := WorkloadImages(r.apiManager)
+	if err != nil {
+		return err
+	}
+
+	adapter := deploymentConfigAdapterReconciler{reconciler: r.reconciler, logger: r.Logger()}
+	if component.IsStatefulDeploymentConfig(desired) {
+		reconciler := NewStatefulSetBaseReconciler(r.BaseAPIManagerLogicReconciler, &statefulSetDeploymentConfigAdapterReconciler{adapter})
+		return reconciler.Reconcile(component.StatefulSetFromDeploymentConfig(desired, images))
+	}
+
+	reconciler := NewDeploymentBaseReconciler(r.BaseAPIManagerLogicReconciler, &adapter)
+	return reconciler.Reconcile(component.DeploymentFromDeploymentConfig(desired, images))
+}
+
+// WorkloadImages returns the images referenced by the ImageStreamTags the
+// APIManager DeploymentConfigs are configured with, indexed by
+// ImageStreamTag name
+func WorkloadImages(cr *appsv1alpha1.APIManager) (map[string]string, error) {
+	ampImagesOptsProvider := OperatorAmpImagesOptionsProvider{APIManagerSpec: &cr.Spec}
+	ampImagesOpts, err := ampImagesOptsProvider.GetAmpImagesOptions()
+	if err != nil {
+		return nil, err
+	}
+	ampImages := component.NewAmpImages(ampImagesOpts)
+
+	redis, err := Redis(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	mysqlImageOptsProvider := OperatorSystemMySQLImageOptionsProvider{APIManagerSpec: &cr.Spec}
+	mysqlImageOpts, err := mysqlImageOptsProvider.GetSystemMySQLImageOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	postgreSQLImageOptsProvider := OperatorSystemPostgreSQLImageOptionsProvider{APIManagerSpec: &cr.Spec}
+	postgreSQLImageOpts, err := postgreSQLImageOptsProvider.GetSystemPostgreSQLImageOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return component.ImageStreamTagImages(
+		ampImages.BackendImageStream(),
+		ampImages.ZyncImageStream(),
+		ampImages.APICastImageStream(),
+		ampImages.SystemImageStream(),
+		ampImages.ZyncDatabasePostgreSQLImageStream(),
+		ampImages.SystemMemcachedImageStream(),
+		redis.BackendImageStream(),
+		redis.SystemImageStream(),
+		component.NewSystemMySQLImage(mysqlImageOpts).ImageStream(),
+		component.NewSystemPostgreSQLImage(postgreSQLImageOpts).ImageStream(),
+	), nil
+}
@@ -0,0 +1,261 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestDeploymentBaseReconcilerCreate(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	reconciler := NewDeploymentBaseReconciler(baseAPIManagerLogicReconciler, NewCreateOnlyDeploymentReconciler())
+
+	desired := &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myDeployment",
+			Namespace: namespace,
+		},
+	}
+
+	err := reconciler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "myDeployment", Namespace: namespace}
+	existing := &k8sappsv1.Deployment{}
+	err = cl.Get(context.TODO(), namespacedName, existing)
+	// object must exist, that is all required to be tested
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStatefulSetBaseReconcilerUpdateOwnerRef(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	existing := &k8sappsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myStatefulSet",
+			Namespace: namespace,
+		},
+	}
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{existing}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	reconciler := NewStatefulSetBaseReconciler(baseAPIManagerLogicReconciler, NewCreateOnlyStatefulSetReconciler())
+
+	desired := existing.DeepCopy()
+	err := reconciler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "myStatefulSet", Namespace: namespace}
+	reconciled := &k8sappsv1.StatefulSet{}
+	err = cl.Get(context.TODO(), namespacedName, reconciled)
+	// object must exist, that is all required to be tested
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reconciled.GetOwnerReferences()) != 1 {
+		t.Fatal("reconciled does not have owner reference")
+	}
+
+	if reconciled.GetOwnerReferences()[0].Name != name {
+		t.Fatalf("reconciled owner reference is not apimanager, expected: %s, got: %s", name, reconciled.GetOwnerReferences()[0].Name)
+	}
+}
+
+func TestDeploymentConfigBaseReconcilerKubernetesWorkloads(t *testing.T) {
+	var (
+		name           = "example-apimanager"
+		namespace      = "operator-unittest"
+		wildcardDomain = "test.3scale.net"
+		workloadType   = appsv1alpha1.WorkloadTypeDeployment
+		exposureType   = appsv1alpha1.ExposureTypeIngress
+		log            = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: wildcardDomain,
+				WorkloadType:   &workloadType,
+				ExposureType:   &exposureType,
+			},
+		},
+	}
+	_, err := apimanager.SetDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err = appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &k8sappsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Deployment",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myDC",
+			Namespace: namespace,
+		},
+	}
+	// existing does not need to be updated to set owner reference
+	err = controllerutil.SetControllerReference(apimanager, existing, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{existing}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	reconciler := NewDeploymentConfigBaseReconciler(baseAPIManagerLogicReconciler, newmyCustomDeploymentConfigReconciler())
+
+	podTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "system-mysql", Image: "system-mysql:latest"}},
+		},
+	}
+	desiredDeployment := &appsv1.DeploymentConfig{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DeploymentConfig",
+			APIVersion: "apps.openshift.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myDC",
+			Namespace: namespace,
+		},
+		Spec: appsv1.DeploymentConfigSpec{
+			Template: podTemplate,
+		},
+	}
+	desiredStatefulSet := &appsv1.DeploymentConfig{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DeploymentConfig",
+			APIVersion: "apps.openshift.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myStatefulDC",
+			Namespace: namespace,
+		},
+		Spec: appsv1.DeploymentConfigSpec{
+			Replicas: 1,
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.DeploymentStrategyTypeRecreate},
+			Template: podTemplate,
+		},
+	}
+
+	for _, desired := range []*appsv1.DeploymentConfig{desiredDeployment, desiredStatefulSet} {
+		err = reconciler.Reconcile(desired)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconciledDeployment := &k8sappsv1.Deployment{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "myDC", Namespace: namespace}, reconciledDeployment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reconciledDeployment.Spec.Replicas == nil || *reconciledDeployment.Spec.Replicas != 4 {
+		t.Fatalf("reconciled does not have reconciled data. Expected: 4, got: %v", reconciledDeployment.Spec.Replicas)
+	}
+
+	reconciledStatefulSet := &k8sappsv1.StatefulSet{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "myStatefulDC", Namespace: namespace}, reconciledStatefulSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	image := reconciledStatefulSet.Spec.Template.Spec.Containers[0].Image
+	if image != SystemMySQLImageURL() {
+		t.Fatalf("ImageStreamTag not replaced by image. Expected: %s, got: %s", SystemMySQLImageURL(), image)
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "myStatefulDC", Namespace: namespace}, dc)
+	if err == nil {
+		t.Fatal("DeploymentConfig created in Kubernetes workload mode")
+	}
+}
@@ -0,0 +1,287 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/common"
+	jsonpatch "github.com/evanphx/json-patch"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+const (
+	// LastAppliedConfigurationAnnotation stores the configuration last
+	// applied by the operator on a managed object. It is the original of the
+	// three-way comparison that corrects drift, so that fields removed from
+	// the desired configuration are removed from the object too
+	LastAppliedConfigurationAnnotation = "apps.3scale.net/last-applied-configuration"
+
+	// DriftCorrectionAnnotation set to DriftCorrectionDisabled on a managed
+	// object keeps manual changes made to it. The operator still reconciles
+	// the fields it always manages, like resources or pod placement
+	DriftCorrectionAnnotation = "apps.3scale.net/drift-correction"
+	DriftCorrectionDisabled   = "disabled"
+
+	// Annotations are limited to 256KiB in total. Larger configurations are
+	// not recorded and drift is corrected with a two-way comparison
+	maxLastAppliedConfigurationSize = 128 * 1024
+)
+
+// driftIgnoredPaths returns the fields of the object kind that are not
+// compared. They are either set by other controllers, like replicas scaled
+// by autoscalers or images resolved by triggers, immutable, or secret
+func driftIgnoredPaths(obj runtime.Object) [][]string {
+	switch obj.(type) {
+	case *appsv1.DeploymentConfig:
+		return [][]string{
+			{"spec", "replicas"},
+			{"spec", "triggers"},
+			{"spec", "template", "spec", "containers", "[]", "image"},
+			{"spec", "template", "spec", "initContainers", "[]", "image"},
+		}
+	case *k8sappsv1.Deployment:
+		return [][]string{
+			{"spec", "replicas"},
+			{"spec", "selector"},
+		}
+	case *k8sappsv1.StatefulSet:
+		return [][]string{
+			{"spec", "replicas"},
+			{"spec", "selector"},
+			{"spec", "serviceName"},
+			{"spec", "podManagementPolicy"},
+			{"spec", "volumeClaimTemplates"},
+		}
+	case *v1.Secret:
+		return [][]string{{"data"}, {"stringData"}, {"type"}}
+	case *v1.PersistentVolumeClaim, *imagev1.ImageStream:
+		return [][]string{{"spec"}}
+	case *rbacv1.RoleBinding:
+		return [][]string{{"roleRef"}}
+	}
+	return nil
+}
+
+// correctDrift reverts the changes made to the existing object on the fields
+// of the desired object, comparing the desired configuration, the existing
+// object and the configuration last applied. Fields added to the existing
+// object by others are kept. Returns whether the existing object changed
+func (r BaseAPIManagerLogicReconciler) correctDrift(desired, existing common.KubernetesObject) (bool, error) {
+	if existing.GetAnnotations()[DriftCorrectionAnnotation] == DriftCorrectionDisabled {
+		return false, nil
+	}
+
+	modified, err := lastAppliedConfiguration(desired)
+	if err != nil {
+		return false, err
+	}
+
+	current, err := json.Marshal(existing)
+	if err != nil {
+		return false, err
+	}
+
+	var original []byte
+	if lastApplied, ok := existing.GetAnnotations()[LastAppliedConfigurationAnnotation]; ok {
+		original = []byte(lastApplied)
+	}
+
+	patch, patched, err := threeWayMergePatch(original, modified, current, existing)
+	if err != nil {
+		return false, err
+	}
+
+	corrected := reflect.New(reflect.TypeOf(existing).Elem()).Interface()
+	err = json.Unmarshal(patched, corrected)
+	if err != nil {
+		return false, err
+	}
+
+	updated := false
+	if !equality.Semantic.DeepEqual(existing, corrected) {
+		r.Logger().Info(fmt.Sprintf("%s drifted from the desired configuration, correcting it: %s", ObjectInfo(existing), patch))
+		reflect.ValueOf(existing).Elem().Set(reflect.ValueOf(corrected).Elem())
+		updated = true
+	}
+
+	if setLastAppliedConfiguration(existing, modified) {
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// threeWayMergePatch returns the patch correcting the drift of the current
+// object and the patched object. Typed objects are patched with a strategic
+// merge patch, which merges lists by key. Unstructured objects, like the
+// Prometheus operator ones, have no patch strategy and are patched with a
+// JSON merge patch, which replaces lists
+func threeWayMergePatch(original, modified, current []byte, existing common.KubernetesObject) ([]byte, []byte, error) {
+	if _, ok := existing.(*unstructured.Unstructured); ok {
+		patch, err := jsonmergepatch.CreateThreeWayJSONMergePatch(original, modified, current)
+		if err != nil {
+			return nil, nil, err
+		}
+		patched, err := jsonpatch.MergePatch(current, patch)
+		return patch, patched, err
+	}
+
+	schema, err := strategicpatch.NewPatchMetaFromStruct(existing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patch, err := strategicpatch.CreateThreeWayMergePatch(original, modified, current, schema, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(current, patch, existing)
+	return patch, patched, err
+}
+
+// recordLastAppliedConfiguration stores the configuration of the desired
+// object about to be created
+func recordLastAppliedConfiguration(desired common.KubernetesObject) error {
+	configuration, err := lastAppliedConfiguration(desired)
+	if err != nil {
+		return err
+	}
+
+	setLastAppliedConfiguration(desired, configuration)
+	return nil
+}
+
+func setLastAppliedConfiguration(obj common.KubernetesObject, configuration []byte) bool {
+	annotations := obj.GetAnnotations()
+	if len(configuration) > maxLastAppliedConfigurationSize {
+		if _, ok := annotations[LastAppliedConfigurationAnnotation]; !ok {
+			return false
+		}
+		delete(annotations, LastAppliedConfigurationAnnotation)
+		obj.SetAnnotations(annotations)
+		return true
+	}
+
+	if annotations[LastAppliedConfigurationAnnotation] == string(configuration) {
+		return false
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[LastAppliedConfigurationAnnotation] = string(configuration)
+	obj.SetAnnotations(annotations)
+	return true
+}
+
+// lastAppliedConfiguration returns the configuration of the desired object
+// compared to correct drift: the labels, annotations and contents the
+// operator sets, without the ignored fields of the kind and without empty
+// values, which are left for the API server to default
+func lastAppliedConfiguration(desired common.KubernetesObject) ([]byte, error) {
+	data, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := map[string]interface{}{}
+	err = json.Unmarshal(data, &configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(configuration, "apiVersion")
+	delete(configuration, "kind")
+	delete(configuration, "status")
+	if metadata, ok := configuration["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, LastAppliedConfigurationAnnotation)
+		}
+		configuration["metadata"] = map[string]interface{}{
+			"labels":      metadata["labels"],
+			"annotations": metadata["annotations"],
+		}
+	}
+
+	for _, path := range driftIgnoredPaths(desired) {
+		removeJSONPath(configuration, path)
+	}
+
+	pruneEmptyValues(configuration)
+
+	return json.Marshal(configuration)
+}
+
+// removeJSONPath removes the field at the given path. A "[]" element of
+// the path matches all the items of a list
+func removeJSONPath(obj map[string]interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+
+	switch value := obj[path[0]].(type) {
+	case map[string]interface{}:
+		removeJSONPath(value, path[1:])
+	case []interface{}:
+		if path[1] != "[]" {
+			return
+		}
+		for _, item := range value {
+			if itemMap, ok := item.(map[string]interface{}); ok {
+				removeJSONPath(itemMap, path[2:])
+			}
+		}
+	}
+}
+
+// pruneEmptyValues removes null, empty strings, zero numbers, empty maps
+// and empty lists. Booleans are kept
+func pruneEmptyValues(obj map[string]interface{}) {
+	for key, value := range obj {
+		if isEmptyValue(pruneEmptyValue(value)) {
+			delete(obj, key)
+		}
+	}
+}
+
+func pruneEmptyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		pruneEmptyValues(v)
+	case []interface{}:
+		for _, item := range v {
+			pruneEmptyValue(item)
+		}
+	}
+	return value
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
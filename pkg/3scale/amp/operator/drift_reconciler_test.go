@@ -0,0 +1,294 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func testDriftReconciler(t *testing.T, objs ...runtime.Object) (BaseAPIManagerLogicReconciler, client.Client) {
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "example-apimanager", Namespace: "operator-unittest"},
+	}
+	return testBaseAPIManagerLogicReconciler(t, apimanager, objs...)
+}
+
+func driftTestDC() *appsv1.DeploymentConfig {
+	return &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "system-app",
+			Namespace: "operator-unittest",
+			Labels:    map[string]string{"app": "3scale-api-management"},
+		},
+		Spec: appsv1.DeploymentConfigSpec{
+			Replicas: 1,
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{
+							Name:  "system-master",
+							Image: "amp-system:latest",
+							Env: []v1.EnvVar{
+								{Name: "RAILS_ENV", Value: "production"},
+								{Name: "RAILS_LOG_LEVEL", Value: "info"},
+							},
+							Ports: []v1.ContainerPort{{Name: "master", ContainerPort: 3002, Protocol: v1.ProtocolTCP}},
+							ReadinessProbe: &v1.Probe{
+								Handler: v1.Handler{
+									HTTPGet: &v1.HTTPGetAction{Path: "/check.txt", Port: intstr.FromString("master")},
+								},
+								InitialDelaySeconds: 30,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDriftCorrectionDeploymentConfig(t *testing.T) {
+	r, cl := testDriftReconciler(t)
+	dcReconciler := NewDeploymentConfigBaseReconciler(r, NewCreateOnlyDCReconciler())
+
+	err := dcReconciler.Reconcile(driftTestDC())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &appsv1.DeploymentConfig{}
+	namespacedName := types.NamespacedName{Name: "system-app", Namespace: "operator-unittest"}
+	err = cl.Get(context.TODO(), namespacedName, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := existing.Annotations[LastAppliedConfigurationAnnotation]; !ok {
+		t.Fatal("last applied configuration not recorded on create")
+	}
+
+	// Manual changes
+	container := &existing.Spec.Template.Spec.Containers[0]
+	container.Env[1].Value = "debug"
+	container.Env = append(container.Env, v1.EnvVar{Name: "USER_DEFINED", Value: "kept"})
+	container.ReadinessProbe.InitialDelaySeconds = 5
+	container.Image = "docker-registry.default.svc:5000/3scale/amp-system@sha256:1234"
+	existing.Spec.Replicas = 3
+	existing.Labels["app"] = "edited"
+	err = cl.Update(context.TODO(), existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RAILS_LOG_LEVEL no longer desired
+	desired := driftTestDC()
+	desired.Spec.Template.Spec.Containers[0].Env = desired.Spec.Template.Spec.Containers[0].Env[:1]
+	err = dcReconciler.Reconcile(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cl.Get(context.TODO(), namespacedName, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	container = &existing.Spec.Template.Spec.Containers[0]
+	if findEnvVar(container.Env, "RAILS_LOG_LEVEL") >= 0 {
+		t.Error("env var removed from the desired configuration not removed")
+	}
+	if idx := findEnvVar(container.Env, "USER_DEFINED"); idx < 0 || container.Env[idx].Value != "kept" {
+		t.Error("env var added manually not kept")
+	}
+	if container.ReadinessProbe.InitialDelaySeconds != 30 {
+		t.Errorf("probe drift not corrected: %v", container.ReadinessProbe)
+	}
+	if existing.Labels["app"] != "3scale-api-management" {
+		t.Errorf("label drift not corrected: %v", existing.Labels)
+	}
+	if container.Image != "docker-registry.default.svc:5000/3scale/amp-system@sha256:1234" || existing.Spec.Replicas != 3 {
+		t.Error("ignored fields changed")
+	}
+}
+
+func TestDriftCorrectionDisabled(t *testing.T) {
+	existing := driftTestDC()
+	existing.Annotations = map[string]string{DriftCorrectionAnnotation: DriftCorrectionDisabled}
+	existing.Spec.Template.Spec.Containers[0].Env[1].Value = "debug"
+	r, _ := testDriftReconciler(t)
+
+	updated, err := r.correctDrift(driftTestDC(), existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated || existing.Spec.Template.Spec.Containers[0].Env[1].Value != "debug" {
+		t.Error("manual override with drift correction disabled not kept")
+	}
+}
+
+func TestDriftCorrectionServerDefaults(t *testing.T) {
+	desired := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-provider", Namespace: "operator-unittest"},
+		Spec: v1.ServiceSpec{
+			Ports:    []v1.ServicePort{{Name: "http", Port: 3000}},
+			Selector: map[string]string{"deploymentConfig": "system-app"},
+		},
+	}
+	// Defaults set by the API server on an object created before drift
+	// correction, without last applied configuration
+	existing := desired.DeepCopy()
+	existing.Spec.ClusterIP = "172.30.0.10"
+	existing.Spec.Type = v1.ServiceTypeClusterIP
+	existing.Spec.SessionAffinity = v1.ServiceAffinityNone
+	existing.Spec.Ports[0].Protocol = v1.ProtocolTCP
+	existing.Spec.Ports[0].TargetPort = intstr.FromInt(3000)
+	lastApplied, err := lastAppliedConfiguration(desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _ := testDriftReconciler(t)
+
+	updated, err := r.correctDrift(desired, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated || existing.Annotations[LastAppliedConfigurationAnnotation] != string(lastApplied) {
+		t.Fatal("last applied configuration not recorded")
+	}
+	if existing.Spec.ClusterIP != "172.30.0.10" || existing.Spec.Ports[0].TargetPort.IntValue() != 3000 {
+		t.Errorf("server defaults changed: %v", existing.Spec)
+	}
+
+	updated, err = r.correctDrift(desired, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated {
+		t.Error("update reported without drift")
+	}
+}
+
+func TestLastAppliedConfigurationSecret(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-internal-api", Namespace: "operator-unittest"},
+		StringData: map[string]string{"password": "secret"},
+	}
+	lastApplied, err := lastAppliedConfiguration(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(lastApplied) != `{}` {
+		t.Errorf("unexpected secret last applied configuration: %s", lastApplied)
+	}
+}
+
+func TestDriftCorrectionUnstructured(t *testing.T) {
+	newPodMonitor := func() *unstructured.Unstructured {
+		podMonitor := &unstructured.Unstructured{}
+		podMonitor.SetAPIVersion("monitoring.coreos.com/v1")
+		podMonitor.SetKind("PodMonitor")
+		podMonitor.SetName("apicast-production")
+		podMonitor.SetNamespace("operator-unittest")
+		podMonitor.SetLabels(map[string]string{"app": "3scale-api-management"})
+		podMonitor.Object["spec"] = map[string]interface{}{
+			"podMetricsEndpoints": []interface{}{
+				map[string]interface{}{"port": "metrics", "interval": "30s"},
+			},
+		}
+		return podMonitor
+	}
+	r, _ := testDriftReconciler(t)
+
+	existing := newPodMonitor()
+	err := recordLastAppliedConfiguration(existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Manual changes
+	existing.SetLabels(map[string]string{"app": "edited", "team": "kept"})
+	existing.Object["spec"].(map[string]interface{})["podMetricsEndpoints"] = []interface{}{
+		map[string]interface{}{"port": "metrics", "interval": "5s"},
+	}
+
+	updated, err := r.correctDrift(newPodMonitor(), existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated {
+		t.Fatal("drift not corrected")
+	}
+	if labels := existing.GetLabels(); labels["app"] != "3scale-api-management" || labels["team"] != "kept" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(existing.Object, "spec", "podMetricsEndpoints")
+	if len(endpoints) != 1 || endpoints[0].(map[string]interface{})["interval"] != "30s" {
+		t.Errorf("spec drift not corrected: %v", endpoints)
+	}
+
+	updated, err = r.correctDrift(newPodMonitor(), existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated {
+		t.Error("update reported without drift")
+	}
+}
+
+func TestDriftCorrectionPodDisruptionBudget(t *testing.T) {
+	minAvailable := intstr.FromInt(1)
+	newPDB := func() *policyv1beta1.PodDisruptionBudget {
+		return &policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "backend-listener",
+				Namespace: "operator-unittest",
+				Labels:    map[string]string{"app": "3scale-api-management"},
+			},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				MinAvailable: &minAvailable,
+				Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"deploymentConfig": "backend-listener"}},
+			},
+		}
+	}
+	r, cl := testDriftReconciler(t)
+	r.apiManager.Spec.PodDisruptionBudget = &appsv1alpha1.PodDisruptionBudgetSpec{Enabled: true}
+	pdbReconciler := NewPodDisruptionBudgetReconciler(r)
+
+	err := pdbReconciler.Reconcile(newPDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &policyv1beta1.PodDisruptionBudget{}
+	namespacedName := types.NamespacedName{Name: "backend-listener", Namespace: "operator-unittest"}
+	err = cl.Get(context.TODO(), namespacedName, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing.Labels["app"] = "edited"
+	err = cl.Update(context.TODO(), existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pdbReconciler.Reconcile(newPDB())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cl.Get(context.TODO(), namespacedName, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if existing.Labels["app"] != "3scale-api-management" {
+		t.Errorf("label drift not corrected: %v", existing.Labels)
+	}
+}
@@ -0,0 +1,44 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/apis"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// testBaseReconciler returns a BaseReconciler with a fake client tracking
+// the given objects, also used as API reader. The custom resources and the
+// OpenShift APIs are registered in the scheme
+func testBaseReconciler(t *testing.T, objs ...runtime.Object) (BaseReconciler, client.Client) {
+	s := scheme.Scheme
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		apis.AddToScheme,
+		appsv1.AddToScheme,
+		imagev1.AddToScheme,
+		routev1.AddToScheme,
+	} {
+		err := addToScheme(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cl := fake.NewFakeClient(objs...)
+	return NewBaseReconciler(cl, cl, s, logf.Log.WithName(t.Name())), cl
+}
+
+// testBaseAPIManagerLogicReconciler returns a BaseAPIManagerLogicReconciler
+// of the APIManager with a fake client tracking it and the given objects
+func testBaseAPIManagerLogicReconciler(t *testing.T, apimanager *appsv1alpha1.APIManager, objs ...runtime.Object) (BaseAPIManagerLogicReconciler, client.Client) {
+	baseReconciler, cl := testBaseReconciler(t, append([]runtime.Object{apimanager}, objs...)...)
+	return NewBaseAPIManagerLogicReconciler(NewBaseLogicReconciler(baseReconciler), apimanager), cl
+}
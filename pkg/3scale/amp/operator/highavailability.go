@@ -1,10 +1,13 @@
 package operator
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/helper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (o *OperatorHighAvailabilityOptionsProvider) GetHighAvailabilityOptions() (*component.HighAvailabilityOptions, error) {
@@ -22,6 +25,10 @@ func (o *OperatorHighAvailabilityOptionsProvider) GetHighAvailabilityOptions() (
 	if err != nil {
 		return nil, err
 	}
+	err = o.checkZyncDatabaseSecret()
+	if err != nil {
+		return nil, err
+	}
 
 	res, err := hob.Build()
 	if err != nil {
@@ -92,3 +99,93 @@ func (o *OperatorHighAvailabilityOptionsProvider) setSystemDatabaseOptions(build
 
 	return nil
 }
+
+// checkZyncDatabaseSecret checks the zync secret points to the external
+// zync database when it is enabled
+func (o *OperatorHighAvailabilityOptionsProvider) checkZyncDatabaseSecret() error {
+	if o.APIManagerSpec == nil || o.APIManagerSpec.HighAvailability == nil || !o.APIManagerSpec.HighAvailability.ExternalZyncDatabaseEnabled {
+		return nil
+	}
+
+	currSecret, err := helper.GetSecret(component.ZyncSecretName, o.Namespace, o.Client)
+	if err != nil {
+		return err
+	}
+
+	result := helper.GetSecretDataValue(currSecret.Data, component.ZyncSecretDatabaseURLFieldName)
+	if result == nil {
+		return fmt.Errorf("Secret field '%s' is required in secret '%s'", component.ZyncSecretDatabaseURLFieldName, component.ZyncSecretName)
+	}
+
+	return nil
+}
+
+// datastoreTLSOptions returns the TLS files of an external datastore, read
+// from the given fields of its secret. The CA certificate is required. The
+// client certificate and private key are optional but go together
+func datastoreTLSOptions(k8sclient client.Client, namespace, name, secretName, caKey, certKey, privateKeyKey string) (*component.DatastoreTLSOptions, error) {
+	secret, err := helper.GetSecret(secretName, namespace, k8sclient)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := secret.Data[caKey]; !ok {
+		return nil, fmt.Errorf("Secret field '%s' is required in secret '%s'", caKey, secretName)
+	}
+	_, hasCert := secret.Data[certKey]
+	_, hasPrivateKey := secret.Data[privateKeyKey]
+	if hasCert != hasPrivateKey {
+		return nil, fmt.Errorf("Secret fields '%s' and '%s' have to be set together in secret '%s'", certKey, privateKeyKey, secretName)
+	}
+
+	tls := &component.DatastoreTLSOptions{
+		Name:       name,
+		SecretName: secretName,
+		CAKey:      caKey,
+	}
+	tlsData := map[string][]byte{caKey: secret.Data[caKey]}
+	if hasCert {
+		tls.CertKey = certKey
+		tls.PrivateKeyKey = privateKeyKey
+		tlsData[certKey] = secret.Data[certKey]
+		tlsData[privateKeyKey] = secret.Data[privateKeyKey]
+	}
+
+	// The pods read the files on start, so the hash rolls them out when
+	// the certificates are renewed
+	tlsHash := sha256.New()
+	hashBinaryData(tlsHash, tlsData)
+	tls.Hash = fmt.Sprintf("%x", tlsHash.Sum(nil))
+
+	return tls, nil
+}
+
+// systemDatabaseTLSOptions returns the TLS files of the external system
+// database. Nil when TLS is not enabled
+func systemDatabaseTLSOptions(spec *appsv1alpha1.APIManagerSpec, k8sclient client.Client, namespace string) (*component.DatastoreTLSOptions, error) {
+	if spec.HighAvailability == nil || !spec.HighAvailability.Enabled || !spec.HighAvailability.SystemDatabaseTLSEnabled {
+		return nil, nil
+	}
+	return datastoreTLSOptions(k8sclient, namespace, "system-database-tls", component.SystemSecretSystemDatabaseSecretName,
+		component.SystemSecretSystemDatabaseSSLCAFieldName, component.SystemSecretSystemDatabaseSSLCertFieldName, component.SystemSecretSystemDatabaseSSLKeyFieldName)
+}
+
+// backendRedisTLSOptions returns the TLS files of the external backend
+// redis. Nil when TLS is not enabled
+func backendRedisTLSOptions(spec *appsv1alpha1.APIManagerSpec, k8sclient client.Client, namespace string) (*component.DatastoreTLSOptions, error) {
+	if spec.HighAvailability == nil || !spec.HighAvailability.Enabled || !spec.HighAvailability.BackendRedisTLSEnabled {
+		return nil, nil
+	}
+	return datastoreTLSOptions(k8sclient, namespace, "backend-redis-tls", component.BackendSecretBackendRedisSecretName,
+		component.BackendSecretBackendRedisSSLCAFieldName, component.BackendSecretBackendRedisSSLCertFieldName, component.BackendSecretBackendRedisSSLKeyFieldName)
+}
+
+// systemRedisTLSOptions returns the TLS files of the external system
+// redis. Nil when TLS is not enabled
+func systemRedisTLSOptions(spec *appsv1alpha1.APIManagerSpec, k8sclient client.Client, namespace string) (*component.DatastoreTLSOptions, error) {
+	if spec.HighAvailability == nil || !spec.HighAvailability.Enabled || !spec.HighAvailability.SystemRedisTLSEnabled {
+		return nil, nil
+	}
+	return datastoreTLSOptions(k8sclient, namespace, "system-redis-tls", component.SystemSecretSystemRedisSecretName,
+		component.SystemSecretSystemRedisSSLCAFieldName, component.SystemSecretSystemRedisSSLCertFieldName, component.SystemSecretSystemRedisSSLKeyFieldName)
+}
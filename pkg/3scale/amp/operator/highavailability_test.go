@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/helper"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -148,3 +149,92 @@ func TestGetHighAvailabilityOptionsInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestDatastoreTLSOptions(t *testing.T) {
+	namespace := "someNS"
+	secretName := component.BackendSecretBackendRedisSecretName
+
+	cases := []struct {
+		testName    string
+		secretData  map[string]string
+		expectedErr bool
+		certFile    string
+	}{
+		{"CAOnly", map[string]string{component.BackendSecretBackendRedisSSLCAFieldName: "ca"}, false, ""},
+		{"ClientCert", map[string]string{
+			component.BackendSecretBackendRedisSSLCAFieldName:   "ca",
+			component.BackendSecretBackendRedisSSLCertFieldName: "cert",
+			component.BackendSecretBackendRedisSSLKeyFieldName:  "key",
+		}, false, "/var/run/secrets/backend-redis-tls/tls.crt"},
+		{"MissingCA", map[string]string{
+			component.BackendSecretBackendRedisSSLCertFieldName: "cert",
+			component.BackendSecretBackendRedisSSLKeyFieldName:  "key",
+		}, true, ""},
+		{"CertWithoutKey", map[string]string{
+			component.BackendSecretBackendRedisSSLCAFieldName:   "ca",
+			component.BackendSecretBackendRedisSSLCertFieldName: "cert",
+		}, true, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			secret := helper.GetTestSecret(namespace, secretName, tc.secretData)
+			cl := fake.NewFakeClient(secret)
+			tls, err := datastoreTLSOptions(cl, namespace, "backend-redis-tls", secretName,
+				component.BackendSecretBackendRedisSSLCAFieldName, component.BackendSecretBackendRedisSSLCertFieldName, component.BackendSecretBackendRedisSSLKeyFieldName)
+			if tc.expectedErr {
+				if err == nil {
+					subT.Error("expected error not returned")
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if tls.CAFile() != "/var/run/secrets/backend-redis-tls/ca.crt" {
+				subT.Errorf("unexpected CA file: %s", tls.CAFile())
+			}
+			if tls.CertFile() != tc.certFile {
+				subT.Errorf("unexpected cert file. Expected: '%s'. Got: '%s'", tc.certFile, tls.CertFile())
+			}
+			if tls.Hash == "" {
+				subT.Error("empty TLS files hash")
+			}
+		})
+	}
+}
+
+func TestGetHighAvailabilityOptionsExternalZyncDatabase(t *testing.T) {
+	namespace := "someNS"
+	spec := &appsv1alpha1.APIManagerSpec{
+		HighAvailability: &appsv1alpha1.HighAvailabilitySpec{
+			Enabled:                     true,
+			ExternalZyncDatabaseEnabled: true,
+		},
+	}
+
+	objs := []runtime.Object{
+		helper.GetTestSecret(namespace, component.BackendSecretBackendRedisSecretName, backendRedisTestData()),
+		helper.GetTestSecret(namespace, component.SystemSecretSystemRedisSecretName, systemRedisTestData()),
+		helper.GetTestSecret(namespace, component.SystemSecretSystemDatabaseSecretName, systemDatabaseTestData()),
+	}
+	optsProvider := OperatorHighAvailabilityOptionsProvider{APIManagerSpec: spec, Namespace: namespace, Client: fake.NewFakeClient(objs...)}
+	_, err := optsProvider.GetHighAvailabilityOptions()
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("\"%s\" not found", component.ZyncSecretName)) {
+		t.Fatalf("missing zync secret not detected: %v", err)
+	}
+
+	zyncSecret := helper.GetTestSecret(namespace, component.ZyncSecretName, map[string]string{component.ZyncSecretKeyBaseFieldName: "key"})
+	optsProvider.Client = fake.NewFakeClient(append(objs, zyncSecret)...)
+	_, err = optsProvider.GetHighAvailabilityOptions()
+	if err == nil || !strings.Contains(err.Error(), component.ZyncSecretDatabaseURLFieldName) {
+		t.Fatalf("missing zync database URL not detected: %v", err)
+	}
+
+	zyncSecret = helper.GetTestSecret(namespace, component.ZyncSecretName, map[string]string{component.ZyncSecretDatabaseURLFieldName: "postgresql://zync.example.com/zync"})
+	optsProvider.Client = fake.NewFakeClient(append(objs, zyncSecret)...)
+	_, err = optsProvider.GetHighAvailabilityOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,132 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	appsv1 "github.com/openshift/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type HorizontalPodAutoscalerReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewHorizontalPodAutoscalerReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *HorizontalPodAutoscalerReconciler {
+	return &HorizontalPodAutoscalerReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+// Reconcile creates or updates the HorizontalPodAutoscaler scaling the given
+// DeploymentConfig when autoscaling is configured, and deletes it otherwise
+func (r HorizontalPodAutoscalerReconciler) Reconcile(dc *appsv1.DeploymentConfig, autoscaling *appsv1alpha1.AutoscalingSpec) error {
+	existing, err := r.getCurrentHorizontalPodAutoscaler(types.NamespacedName{Name: dc.Name, Namespace: r.apiManager.GetNamespace()})
+	if err != nil {
+		r.Logger().Error(err, fmt.Sprintf("Error reading HorizontalPodAutoscaler %s. Requeuing request...", dc.Name))
+		return err
+	}
+
+	if autoscaling == nil {
+		if existing != nil {
+			return r.deleteResource(existing)
+		}
+		return nil
+	}
+
+	desired := component.HorizontalPodAutoscaler(dc, component.HorizontalPodAutoscalerOptions{
+		MinReplicas:                       autoscaling.MinReplicas,
+		MaxReplicas:                       autoscaling.MaxReplicas,
+		TargetCPUUtilizationPercentage:    autoscaling.TargetCPUUtilizationPercentage,
+		TargetMemoryUtilizationPercentage: autoscaling.TargetMemoryUtilizationPercentage,
+	})
+	if r.apiManager.IsKubernetesDeploymentEnabled() {
+		desired.Spec.ScaleTargetRef = autoscalingv2beta1.CrossVersionObjectReference{
+			Kind:       "Deployment",
+			Name:       dc.Name,
+			APIVersion: "apps/v1",
+		}
+	}
+
+	if existing == nil {
+		return r.createResource(desired)
+	}
+
+	update, err := r.isUpdateNeeded(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		return r.updateResource(existing)
+	}
+
+	return nil
+}
+
+func (r HorizontalPodAutoscalerReconciler) isUpdateNeeded(desired, existing *autoscalingv2beta1.HorizontalPodAutoscaler) (bool, error) {
+	updated := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+
+	updatedTmp, err := r.ensureOwnerReference(existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	if !reflect.DeepEqual(desired.Spec, existing.Spec) {
+		r.Logger().Info(fmt.Sprintf("%s spec differs", ObjectInfo(desired)))
+		existing.Spec = desired.Spec
+		updated = true
+	}
+
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	return updated, nil
+}
+
+func (r HorizontalPodAutoscalerReconciler) getCurrentHorizontalPodAutoscaler(selector client.ObjectKey) (*autoscalingv2beta1.HorizontalPodAutoscaler, error) {
+	existing := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	err := r.Client().Get(context.TODO(), selector, existing)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		return existing.DeepCopy(), nil
+	}
+	return nil, nil
+}
+
+// autoscaledDCReconciler leaves the replicas of a DeploymentConfig to its
+// HorizontalPodAutoscaler. The existing replicas are always the desired ones,
+// so DeploymentConfigReconcileReplicas does not revert the autoscaler changes
+type autoscaledDCReconciler struct {
+	reconciler DeploymentConfigReconciler
+}
+
+// NewAutoscalingDCReconciler returns the given reconciler when autoscaling
+// is not configured. Otherwise, it returns a reconciler not reconciling the
+// DeploymentConfig replicas
+func NewAutoscalingDCReconciler(autoscaling *appsv1alpha1.AutoscalingSpec, reconciler DeploymentConfigReconciler) DeploymentConfigReconciler {
+	if autoscaling == nil {
+		return reconciler
+	}
+	return &autoscaledDCReconciler{reconciler: reconciler}
+}
+
+func (r *autoscaledDCReconciler) IsUpdateNeeded(desired, existing *appsv1.DeploymentConfig) bool {
+	autoscaledDesired := desired.DeepCopy()
+	autoscaledDesired.Spec.Replicas = existing.Spec.Replicas
+	return r.reconciler.IsUpdateNeeded(autoscaledDesired, existing)
+}
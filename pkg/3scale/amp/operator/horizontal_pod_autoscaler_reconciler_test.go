@@ -0,0 +1,185 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	appsv1 "github.com/openshift/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func TestHorizontalPodAutoscalerReconcilerCreate(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := autoscalingv2beta1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+
+	reconciler := NewHorizontalPodAutoscalerReconciler(baseAPIManagerLogicReconciler)
+
+	dc := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "apicast-production",
+			Namespace: namespace,
+			Labels:    map[string]string{"threescale_component": "apicast"},
+		},
+	}
+	var maxReplicas int32 = 5
+	autoscaling := &appsv1alpha1.AutoscalingSpec{MaxReplicas: maxReplicas}
+
+	err = reconciler.Reconcile(dc, autoscaling)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "apicast-production", Namespace: namespace}
+	reconciled := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	err = cl.Get(context.TODO(), namespacedName, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reconciled.Spec.ScaleTargetRef.Kind != "DeploymentConfig" || reconciled.Spec.ScaleTargetRef.Name != "apicast-production" {
+		t.Errorf("unexpected scale target: %v", reconciled.Spec.ScaleTargetRef)
+	}
+	if reconciled.Spec.MinReplicas == nil || *reconciled.Spec.MinReplicas != 1 {
+		t.Errorf("unexpected min replicas: %v", reconciled.Spec.MinReplicas)
+	}
+	if reconciled.Spec.MaxReplicas != maxReplicas {
+		t.Errorf("unexpected max replicas. Expected: %d, got: %d", maxReplicas, reconciled.Spec.MaxReplicas)
+	}
+	if len(reconciled.Spec.Metrics) != 1 ||
+		reconciled.Spec.Metrics[0].Resource == nil ||
+		*reconciled.Spec.Metrics[0].Resource.TargetAverageUtilization != component.HPA_DEFAULT_TARGET_CPU_UTILIZATION_PERCENTAGE {
+		t.Errorf("unexpected metrics: %v", reconciled.Spec.Metrics)
+	}
+
+	if len(reconciled.GetOwnerReferences()) != 1 {
+		t.Fatal("reconciled does not have owner reference")
+	}
+}
+
+func TestHorizontalPodAutoscalerReconcilerDelete(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := autoscalingv2beta1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &autoscalingv2beta1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "apicast-production",
+			Namespace: namespace,
+		},
+	}
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{existing}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+
+	reconciler := NewHorizontalPodAutoscalerReconciler(baseAPIManagerLogicReconciler)
+
+	dc := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "apicast-production",
+			Namespace: namespace,
+		},
+	}
+
+	err = reconciler.Reconcile(dc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "apicast-production", Namespace: namespace}
+	reconciled := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	err = cl.Get(context.TODO(), namespacedName, reconciled)
+	if !errors.IsNotFound(err) {
+		t.Fatalf("HorizontalPodAutoscaler not deleted: %v", err)
+	}
+}
+
+func TestAutoscalingDCReconcilerKeepsReplicas(t *testing.T) {
+	desired := &appsv1.DeploymentConfig{Spec: appsv1.DeploymentConfigSpec{Replicas: 1}}
+	existing := &appsv1.DeploymentConfig{Spec: appsv1.DeploymentConfigSpec{Replicas: 4}}
+
+	reconciler := NewAutoscalingDCReconciler(&appsv1alpha1.AutoscalingSpec{MaxReplicas: 5}, newmyCustomReplicasDCReconciler())
+	if reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("autoscaled DeploymentConfig replicas reconciled")
+	}
+	if existing.Spec.Replicas != 4 {
+		t.Errorf("autoscaled DeploymentConfig replicas changed. Expected: 4, got: %d", existing.Spec.Replicas)
+	}
+
+	reconciler = NewAutoscalingDCReconciler(nil, newmyCustomReplicasDCReconciler())
+	if !reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("DeploymentConfig replicas not reconciled")
+	}
+	if existing.Spec.Replicas != 1 {
+		t.Errorf("DeploymentConfig replicas not reconciled. Expected: 1, got: %d", existing.Spec.Replicas)
+	}
+}
+
+type myCustomReplicasDCReconciler struct {
+}
+
+func (r *myCustomReplicasDCReconciler) IsUpdateNeeded(desired, existing *appsv1.DeploymentConfig) bool {
+	return DeploymentConfigReconcileReplicas(desired, existing, logf.Log.WithName("operator_test"))
+}
+
+func newmyCustomReplicasDCReconciler() DeploymentConfigReconciler {
+	return &myCustomReplicasDCReconciler{}
+}
@@ -2,6 +2,7 @@ package operator
 
 import (
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/helper"
 )
 
@@ -44,3 +45,44 @@ func SystemPostgreSQLImageURL() string {
 func ZyncPostgreSQLImageURL() string {
 	return helper.GetEnvVar("ZYNC_POSTGRESQL_IMAGE", component.ZyncPostgreSQLImageURL())
 }
+
+func CLIImageURL() string {
+	return helper.GetEnvVar("CLI_IMAGE", component.CLIImageURL())
+}
+
+// ReleaseImageURLs returns the images deployed by the operator, the backup
+// and restore CLI image included, without duplicates. The images are
+// overridden through the environment of the operator, like the deployed
+// ones
+func ReleaseImageURLs() []string {
+	imageURLs := []string{}
+	seen := map[string]bool{}
+	for _, imageURL := range []string{
+		ApicastImageURL(),
+		BackendImageURL(),
+		SystemImageURL(),
+		ZyncImageURL(),
+		BackendRedisImageURL(),
+		SystemRedisImageURL(),
+		SystemMySQLImageURL(),
+		SystemPostgreSQLImageURL(),
+		SystemMemcachedImageURL(),
+		ZyncPostgreSQLImageURL(),
+		CLIImageURL(),
+	} {
+		if !seen[imageURL] {
+			seen[imageURL] = true
+			imageURLs = append(imageURLs, imageURL)
+		}
+	}
+	return imageURLs
+}
+
+// mirroredImageURL returns imageURL pulled from the registry mirror of the
+// APIManager, when set
+func mirroredImageURL(spec *appsv1alpha1.APIManagerSpec, imageURL string) string {
+	if spec.ImageRegistryMirror == nil {
+		return imageURL
+	}
+	return component.RegistryMirrorImageURL(imageURL, *spec.ImageRegistryMirror)
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
 )
 
 func TestImageURLFromEnv(t *testing.T) {
@@ -24,6 +26,7 @@ func TestImageURLFromEnv(t *testing.T) {
 		{"SystemMySQLImageURL", "SYSTEM_MYSQL_IMAGE", func() string { return SystemMySQLImageURL() }},
 		{"SystemPostgreSQLImageURL", "SYSTEM_POSTGRESQL_IMAGE", func() string { return SystemPostgreSQLImageURL() }},
 		{"ZyncPostgreSQLImageURL", "ZYNC_POSTGRESQL_IMAGE", func() string { return ZyncPostgreSQLImageURL() }},
+		{"CLIImageURL", "CLI_IMAGE", func() string { return CLIImageURL() }},
 	}
 
 	for _, tc := range cases {
@@ -55,6 +58,7 @@ func TestImageURLDefault(t *testing.T) {
 		{"SystemMySQLImageURL", func() string { return SystemMySQLImageURL() }, func() string { return component.SystemMySQLImageURL() }},
 		{"SystemPostgreSQLImageURL", func() string { return SystemPostgreSQLImageURL() }, func() string { return component.SystemPostgreSQLImageURL() }},
 		{"ZyncPostgreSQLImageURL", func() string { return ZyncPostgreSQLImageURL() }, func() string { return component.ZyncPostgreSQLImageURL() }},
+		{"CLIImageURL", func() string { return CLIImageURL() }, func() string { return component.CLIImageURL() }},
 	}
 
 	for _, tc := range cases {
@@ -66,3 +70,79 @@ func TestImageURLDefault(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseImageURLsFromEnv(t *testing.T) {
+	newImageURL := "quay.io/myorg/backend:custom"
+	os.Setenv("BACKEND_IMAGE", newImageURL)
+	defer os.Unsetenv("BACKEND_IMAGE")
+
+	imageURLs := ReleaseImageURLs()
+	found := false
+	for _, imageURL := range imageURLs {
+		if imageURL == component.BackendImageURL() {
+			t.Errorf("default backend image (%s) listed instead of the env override", imageURL)
+		}
+		if imageURL == newImageURL {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("env backend image (%s) not listed: %v", newImageURL, imageURLs)
+	}
+}
+
+func TestRegistryMirrorImageURL(t *testing.T) {
+	cases := []struct {
+		name     string
+		imageURL string
+		mirror   string
+		expected string
+	}{
+		{"Registry", "quay.io/3scale/porta:nightly", "mirror.example.com", "mirror.example.com/3scale/porta:nightly"},
+		{"RegistryPort", "registry.example.com:5000/3scale/porta:nightly", "mirror.example.com", "mirror.example.com/3scale/porta:nightly"},
+		{"DockerHub", "centos/redis-32-centos7", "mirror.example.com", "mirror.example.com/centos/redis-32-centos7"},
+		{"DockerHubLibrary", "memcached:1.5", "mirror.example.com", "mirror.example.com/memcached:1.5"},
+		{"MirrorPath", "quay.io/3scale/porta:nightly", "mirror.example.com/3scale/", "mirror.example.com/3scale/3scale/porta:nightly"},
+		{"AlreadyMirrored", "mirror.example.com/3scale/porta:nightly", "mirror.example.com", "mirror.example.com/3scale/porta:nightly"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(subT *testing.T) {
+			imageURL := component.RegistryMirrorImageURL(tc.imageURL, tc.mirror)
+			if imageURL != tc.expected {
+				subT.Errorf("image url does not match. Expected: %s, got: %s", tc.expected, imageURL)
+			}
+		})
+	}
+}
+
+func TestAmpImagesRegistryMirror(t *testing.T) {
+	appLabel := "someLabel"
+	insecure := false
+	mirror := "mirror.example.com"
+	spec := &appsv1alpha1.APIManagerSpec{
+		APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+			AppLabel:                     &appLabel,
+			ImageStreamTagImportInsecure: &insecure,
+			ImageRegistryMirror:          &mirror,
+			ImagePullSecrets:             []v1.LocalObjectReference{{Name: "mirror-auth"}},
+		},
+	}
+
+	optsProvider := OperatorAmpImagesOptionsProvider{APIManagerSpec: spec}
+	opts, err := optsProvider.GetAmpImagesOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ampImages := component.NewAmpImages(opts)
+
+	expectedImage := component.RegistryMirrorImageURL(SystemImageURL(), mirror)
+	if image := ampImages.SystemImageStream().Spec.Tags[1].From.Name; image != expectedImage {
+		t.Errorf("system image (%s) not the expected (%s)", image, expectedImage)
+	}
+
+	pullSecrets := ampImages.DeploymentsServiceAccount().ImagePullSecrets
+	if len(pullSecrets) != 2 || pullSecrets[1].Name != "mirror-auth" {
+		t.Errorf("unexpected amp service account image pull secrets: %v", pullSecrets)
+	}
+}
@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/3scale/3scale-operator/pkg/helper"
 	imagev1 "github.com/openshift/api/image/v1"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 )
 
+// ImageSourceAnnotation is set on the ImageStream tags pinned by digest to
+// the image the digest was resolved from
+const ImageSourceAnnotation = "apps.3scale.net/image-source"
+
 type ImageStreamReconciler interface {
 	IsUpdateNeeded(desired, existing *imagev1.ImageStream) bool
 }
@@ -28,6 +34,12 @@ func NewImageStreamBaseReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLo
 }
 
 func (r *ImageStreamBaseReconciler) Reconcile(desired *imagev1.ImageStream) error {
+	// ImageStreams are not available in plain Kubernetes. Workloads
+	// reference the images directly instead
+	if r.apiManager.IsKubernetesDeploymentEnabled() {
+		return nil
+	}
+
 	objectInfo := ObjectInfo(desired)
 	existing := &imagev1.ImageStream{}
 	err := r.Client().Get(
@@ -46,6 +58,10 @@ func (r *ImageStreamBaseReconciler) Reconcile(desired *imagev1.ImageStream) erro
 		return err
 	}
 
+	if r.apiManager.IsImageDigestPinningEnabled() {
+		PinImageStreamTags(desired, existing)
+	}
+
 	update, err := r.isUpdateNeeded(desired, existing)
 	if err != nil {
 		return err
@@ -70,6 +86,12 @@ func (r *ImageStreamBaseReconciler) isUpdateNeeded(desired, existing *imagev1.Im
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -125,5 +147,93 @@ func imageStreamReconcile(existingTags []imagev1.TagReference, existingIdx int,
 		updated = true
 	}
 
+	if source := desiredTags[desiredIdx].Annotations[ImageSourceAnnotation]; existingTags[existingIdx].Annotations[ImageSourceAnnotation] != source {
+		if source == "" {
+			delete(existingTags[existingIdx].Annotations, ImageSourceAnnotation)
+		} else {
+			if existingTags[existingIdx].Annotations == nil {
+				existingTags[existingIdx].Annotations = map[string]string{}
+			}
+			existingTags[existingIdx].Annotations[ImageSourceAnnotation] = source
+		}
+		updated = true
+	}
+
 	return updated
 }
+
+// PinImageStreamTags replaces the external image of the desired tags with
+// the image by digest the existing tags resolved it to. Tags not imported
+// yet, or imported from another image, are pinned once imported
+func PinImageStreamTags(desired, existing *imagev1.ImageStream) {
+	for idx := range desired.Spec.Tags {
+		tag := &desired.Spec.Tags[idx]
+		if tag.From == nil || tag.From.Kind != "DockerImage" {
+			continue
+		}
+
+		source := tag.From.Name
+		resolvedImage := resolvedTagImage(existing, tag.Name, source)
+		if resolvedImage == "" {
+			continue
+		}
+
+		tag.From = &v1.ObjectReference{Kind: "DockerImage", Name: resolvedImage}
+		annotations := map[string]string{ImageSourceAnnotation: source}
+		for key, value := range tag.Annotations {
+			annotations[key] = value
+		}
+		tag.Annotations = annotations
+	}
+}
+
+// resolvedTagImage returns the image by digest the existing tag resolved
+// source to. Empty when source is not imported yet
+func resolvedTagImage(existing *imagev1.ImageStream, tagName, source string) string {
+	var existingTag *imagev1.TagReference
+	for idx := range existing.Spec.Tags {
+		if existing.Spec.Tags[idx].Name == tagName {
+			existingTag = &existing.Spec.Tags[idx]
+		}
+	}
+	if existingTag == nil || existingTag.From == nil {
+		return ""
+	}
+
+	// Already pinned
+	if existingTag.Annotations[ImageSourceAnnotation] != "" {
+		if existingTag.Annotations[ImageSourceAnnotation] == source {
+			return existingTag.From.Name
+		}
+		return ""
+	}
+
+	if existingTag.From.Name != source {
+		return ""
+	}
+	image, imported := ImageStreamTagImportedImage(existing, tagName)
+	if !imported || !strings.Contains(image, "@") {
+		return ""
+	}
+	return image
+}
+
+// ImageStreamTagImportedImage returns the image the ImageStream tag resolved
+// to, and whether it was imported for the current tag generation
+func ImageStreamTagImportedImage(imageStream *imagev1.ImageStream, tagName string) (string, bool) {
+	var generation *int64
+	for _, tag := range imageStream.Spec.Tags {
+		if tag.Name == tagName {
+			generation = tag.Generation
+		}
+	}
+
+	for _, tagEvents := range imageStream.Status.Tags {
+		if tagEvents.Tag != tagName || len(tagEvents.Items) == 0 {
+			continue
+		}
+		item := tagEvents.Items[0]
+		return item.DockerImageReference, generation == nil || item.Generation >= *generation
+	}
+	return "", false
+}
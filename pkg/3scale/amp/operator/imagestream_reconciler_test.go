@@ -322,3 +322,70 @@ func TestImageStreamGenericReconciler(t *testing.T) {
 		t.Fatal("reconciled obj does not have tag2")
 	}
 }
+
+func TestPinImageStreamTags(t *testing.T) {
+	var (
+		source     = "quay.io/3scale/porta:nightly"
+		digestRef  = "quay.io/3scale/porta@sha256:abc"
+		generation = int64(2)
+	)
+
+	desiredImageStream := func() *imagev1.ImageStream {
+		return &imagev1.ImageStream{
+			Spec: imagev1.ImageStreamSpec{
+				Tags: []imagev1.TagReference{
+					{Name: "2.8", From: &v1.ObjectReference{Kind: "DockerImage", Name: source}},
+					{Name: "latest", From: &v1.ObjectReference{Kind: "ImageStreamTag", Name: "amp-system:2.8"}},
+				},
+			},
+		}
+	}
+	existing := &imagev1.ImageStream{
+		Spec: imagev1.ImageStreamSpec{
+			Tags: []imagev1.TagReference{
+				{Name: "2.8", From: &v1.ObjectReference{Kind: "DockerImage", Name: source}, Generation: &generation},
+			},
+		},
+	}
+
+	// Not imported yet
+	desired := desiredImageStream()
+	PinImageStreamTags(desired, existing)
+	if desired.Spec.Tags[0].From.Name != source {
+		t.Fatalf("tag pinned before the image is imported: %s", desired.Spec.Tags[0].From.Name)
+	}
+
+	existing.Status.Tags = []imagev1.NamedTagEventList{
+		{Tag: "2.8", Items: []imagev1.TagEvent{{DockerImageReference: digestRef, Generation: generation}}},
+	}
+	desired = desiredImageStream()
+	PinImageStreamTags(desired, existing)
+	if desired.Spec.Tags[0].From.Name != digestRef || desired.Spec.Tags[0].Annotations[ImageSourceAnnotation] != source {
+		t.Fatalf("tag not pinned to the imported image: %v", desired.Spec.Tags[0])
+	}
+	if desired.Spec.Tags[1].From.Kind != "ImageStreamTag" || desired.Spec.Tags[1].Annotations != nil {
+		t.Errorf("ImageStreamTag reference pinned: %v", desired.Spec.Tags[1])
+	}
+
+	// Pinned tags stay pinned while the source does not change
+	existing.Spec.Tags[0] = desired.Spec.Tags[0]
+	desired = desiredImageStream()
+	PinImageStreamTags(desired, existing)
+	if desired.Spec.Tags[0].From.Name != digestRef {
+		t.Errorf("pinned tag not kept: %v", desired.Spec.Tags[0])
+	}
+
+	// A new source is imported before being pinned
+	desired = desiredImageStream()
+	desired.Spec.Tags[0].From.Name = "quay.io/3scale/porta:2.9"
+	PinImageStreamTags(desired, existing)
+	if desired.Spec.Tags[0].From.Name != "quay.io/3scale/porta:2.9" || desired.Spec.Tags[0].Annotations != nil {
+		t.Errorf("tag pinned to the image of the previous source: %v", desired.Spec.Tags[0])
+	}
+	if !imageStreamReconcile(existing.Spec.Tags, 0, desired.Spec.Tags, 0) {
+		t.Fatal("tag update not detected")
+	}
+	if _, ok := existing.Spec.Tags[0].Annotations[ImageSourceAnnotation]; ok {
+		t.Error("image source annotation not removed from the unpinned tag")
+	}
+}
@@ -0,0 +1,158 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/go-logr/logr"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// IngressClassAnnotation selects the ingress controller serving an
+	// Ingress. The extensions/v1beta1 Ingress has no ingressClassName field
+	IngressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+type IngressReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewIngressReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *IngressReconciler {
+	return &IngressReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+// Reconcile creates, updates or deletes the desired Ingress depending on
+// whether the APIManager exposure type is Ingress. Only Ingresses owned by
+// the APIManager are deleted. The Ingress spec of the APIManager is applied
+// to the desired Ingress
+func (r IngressReconciler) Reconcile(desired *extensions.Ingress) error {
+	objectInfo := ObjectInfo(desired)
+	existing, err := r.getCurrentIngress(types.NamespacedName{Name: desired.Name, Namespace: r.apiManager.GetNamespace()})
+	if err != nil {
+		r.Logger().Error(err, fmt.Sprintf("Error reading object %s. Requeuing request...", objectInfo))
+		return err
+	}
+
+	if !r.apiManager.IsIngressEnabled() {
+		// Ingresses of the same name not created by the operator are kept
+		if existing != nil && metav1.IsControlledBy(existing, r.apiManager) {
+			return r.deleteResource(existing)
+		}
+		return nil
+	}
+
+	applyIngressSpec(desired, r.apiManager.Spec.Ingress)
+
+	if existing == nil {
+		return r.createResource(desired)
+	}
+
+	update, err := r.isUpdateNeeded(desired, existing)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		return r.updateResource(existing)
+	}
+
+	return nil
+}
+
+// applyIngressSpec sets the ingress class, annotations and TLS settings of
+// the given spec on the desired Ingress
+func applyIngressSpec(desired *extensions.Ingress, ingressSpec *appsv1alpha1.IngressSpec) {
+	if ingressSpec == nil {
+		return
+	}
+
+	if desired.Annotations == nil {
+		desired.Annotations = map[string]string{}
+	}
+	for k, v := range ingressSpec.Annotations {
+		desired.Annotations[k] = v
+	}
+	if ingressSpec.IngressClassName != nil {
+		desired.Annotations[IngressClassAnnotation] = *ingressSpec.IngressClassName
+	}
+
+	if ingressSpec.TLSSecretRef != nil {
+		hosts := []string{}
+		for _, rule := range desired.Spec.Rules {
+			hosts = append(hosts, rule.Host)
+		}
+		desired.Spec.TLS = []extensions.IngressTLS{
+			{Hosts: hosts, SecretName: ingressSpec.TLSSecretRef.Name},
+		}
+	}
+}
+
+func (r IngressReconciler) isUpdateNeeded(desired, existing *extensions.Ingress) (bool, error) {
+	updated := helper.EnsureObjectMeta(&existing.ObjectMeta, &desired.ObjectMeta)
+
+	updatedTmp, err := r.ensureOwnerReference(existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	updatedTmp = ingressReconcileSpec(desired, existing, r.Logger())
+	updated = updated || updatedTmp
+
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
+	return updated, nil
+}
+
+// ingressReconcileSpec reconciles the rules, TLS and default backend of
+// the Ingress
+func ingressReconcileSpec(desired, existing *extensions.Ingress, logger logr.Logger) bool {
+	updated := false
+
+	if !reflect.DeepEqual(desired.Spec.Rules, existing.Spec.Rules) {
+		logger.Info(fmt.Sprintf("%s spec.rules differ", ObjectInfo(desired)))
+		existing.Spec.Rules = desired.Spec.Rules
+		updated = true
+	}
+
+	if !reflect.DeepEqual(desired.Spec.TLS, existing.Spec.TLS) {
+		logger.Info(fmt.Sprintf("%s spec.tls differs", ObjectInfo(desired)))
+		existing.Spec.TLS = desired.Spec.TLS
+		updated = true
+	}
+
+	if !reflect.DeepEqual(desired.Spec.Backend, existing.Spec.Backend) {
+		logger.Info(fmt.Sprintf("%s spec.backend differs", ObjectInfo(desired)))
+		existing.Spec.Backend = desired.Spec.Backend
+		updated = true
+	}
+
+	return updated
+}
+
+func (r IngressReconciler) getCurrentIngress(selector client.ObjectKey) (*extensions.Ingress, error) {
+	existing := &extensions.Ingress{}
+	err := r.Client().Get(context.TODO(), selector, existing)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+	} else {
+		return existing.DeepCopy(), nil
+	}
+	return nil, nil
+}
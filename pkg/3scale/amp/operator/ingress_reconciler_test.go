@@ -0,0 +1,141 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func testIngress(host string) *extensions.Ingress {
+	return &extensions.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Ingress",
+			APIVersion: "extensions/v1beta1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myIngress",
+			Namespace: "operator-unittest",
+		},
+		Spec: extensions.IngressSpec{
+			Rules: []extensions.IngressRule{
+				{
+					Host: host,
+					IngressRuleValue: extensions.IngressRuleValue{
+						HTTP: &extensions.HTTPIngressRuleValue{
+							Paths: []extensions.HTTPIngressPath{
+								{Path: "/", Backend: extensions.IngressBackend{ServiceName: "mySvc", ServicePort: intstr.FromString("http")}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestIngressReconciler(t *testing.T) {
+	var (
+		name             = "example-apimanager"
+		namespace        = "operator-unittest"
+		exposureType     = appsv1alpha1.ExposureTypeIngress
+		ingressClassName = "nginx"
+		log              = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ExposureType: &exposureType,
+			},
+			Ingress: &appsv1alpha1.IngressSpec{
+				IngressClassName: &ingressClassName,
+				Annotations:      map[string]string{"myAnnotation": "myValue"},
+				TLSSecretRef:     &corev1.LocalObjectReference{Name: "mySecret"},
+			},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	existing := testIngress("old.example.com")
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{existing}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	reconciler := NewIngressReconciler(baseAPIManagerLogicReconciler)
+
+	err := reconciler.Reconcile(testIngress("new.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	namespacedName := types.NamespacedName{Name: "myIngress", Namespace: namespace}
+	reconciled := &extensions.Ingress{}
+	err = cl.Get(context.TODO(), namespacedName, reconciled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reconciled.Spec.Rules[0].Host != "new.example.com" {
+		t.Errorf("Ingress host not reconciled. Expected: new.example.com, got: %s", reconciled.Spec.Rules[0].Host)
+	}
+	if reconciled.Annotations[IngressClassAnnotation] != ingressClassName {
+		t.Errorf("Ingress class not reconciled. Expected: %s, got: %s", ingressClassName, reconciled.Annotations[IngressClassAnnotation])
+	}
+	if reconciled.Annotations["myAnnotation"] != "myValue" {
+		t.Errorf("Ingress annotations not reconciled")
+	}
+	if len(reconciled.Spec.TLS) != 1 || reconciled.Spec.TLS[0].SecretName != "mySecret" || reconciled.Spec.TLS[0].Hosts[0] != "new.example.com" {
+		t.Errorf("Ingress TLS not reconciled: %v", reconciled.Spec.TLS)
+	}
+	if len(reconciled.GetOwnerReferences()) != 1 {
+		t.Errorf("reconciled does not have owner reference")
+	}
+
+	// Ingresses are deleted when the exposure type is not Ingress
+	routeExposureType := appsv1alpha1.ExposureTypeRoute
+	apimanager.Spec.ExposureType = &routeExposureType
+	err = reconciler.Reconcile(testIngress("new.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cl.Get(context.TODO(), namespacedName, reconciled)
+	if err == nil {
+		t.Fatal("Ingress not deleted when exposure type is Route")
+	}
+
+	// Ingresses not owned by the APIManager are kept
+	err = cl.Create(context.TODO(), testIngress("user.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reconciler.Reconcile(testIngress("new.example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cl.Get(context.TODO(), namespacedName, reconciled)
+	if err != nil {
+		t.Fatalf("Ingress not owned by the APIManager deleted: %v", err)
+	}
+}
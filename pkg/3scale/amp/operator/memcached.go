@@ -12,6 +12,7 @@ func (o *OperatorMemcachedOptionsProvider) GetMemcachedOptions() (*component.Mem
 	optProv.AppLabel(*o.APIManagerSpec.AppLabel)
 
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -21,7 +22,15 @@ func (o *OperatorMemcachedOptionsProvider) GetMemcachedOptions() (*component.Mem
 }
 
 func (o *OperatorMemcachedOptionsProvider) setResourceRequirementsOptions(b *component.MemcachedOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.ResourceRequirements(v1.ResourceRequirements{})
+	var resources *v1.ResourceRequirements
+	if o.APIManagerSpec.System != nil {
+		resources = o.APIManagerSpec.System.MemcachedResources
+	}
+	setResourceRequirements(b.ResourceRequirements, *o.APIManagerSpec.ResourceRequirementsEnabled, resources)
+}
+
+func (o *OperatorMemcachedOptionsProvider) setPodPlacementOptions(b *component.MemcachedOptionsBuilder) {
+	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.MemcachedPlacement != nil {
+		b.PodPlacement(podPlacement(o.APIManagerSpec.System.MemcachedPlacement, "system-memcache"))
 	}
 }
@@ -0,0 +1,22 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+)
+
+func (o *OperatorMonitoringOptionsProvider) GetMonitoringOptions() (*component.MonitoringOptions, error) {
+	optProv := component.MonitoringOptionsBuilder{}
+	optProv.AppLabel(*o.APIManagerSpec.AppLabel)
+	optProv.Namespace(o.Namespace)
+	if o.APIManagerSpec.Monitoring != nil {
+		optProv.Labels(o.APIManagerSpec.Monitoring.Labels)
+	}
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Monitoring Options - %s", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,182 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/helper"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type GrafanaDashboardConfigMapReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewGrafanaDashboardConfigMapReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *GrafanaDashboardConfigMapReconciler {
+	return &GrafanaDashboardConfigMapReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+func (r *GrafanaDashboardConfigMapReconciler) IsUpdateNeeded(desired, existing *v1.ConfigMap) bool {
+	if reflect.DeepEqual(desired.Data, existing.Data) {
+		return false
+	}
+
+	r.Logger().Info(fmt.Sprintf("%s data differs", ObjectInfo(desired)))
+	existing.Data = desired.Data
+	return true
+}
+
+// MonitoringReconciler reconciles the PodMonitors, the PrometheusRule and
+// the Grafana dashboards of the APIManager components. The objects are
+// deleted when monitoring is disabled
+type MonitoringReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+// blank assignment to verify that BaseReconciler implements reconcile.Reconciler
+var _ LogicReconciler = &MonitoringReconciler{}
+
+func NewMonitoringReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) MonitoringReconciler {
+	return MonitoringReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+func (r *MonitoringReconciler) Reconcile() (reconcile.Result, error) {
+	monitoring, err := r.monitoring()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	enabled := r.apiManager.Spec.Monitoring.IsEnabled()
+
+	// The first missing CRD stops the reconciliation of the objects of the
+	// same kind, every lookup of a missing kind reloads the REST mapper
+	for _, podMonitor := range monitoring.PodMonitors() {
+		installed, err := r.reconcileMonitoringObject(podMonitor, enabled)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !installed {
+			break
+		}
+	}
+
+	_, err = r.reconcileMonitoringObject(monitoring.PrometheusRule(), enabled)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	for _, dashboard := range monitoring.GrafanaDashboards() {
+		err = r.reconcileGrafanaDashboard(dashboard, enabled)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileMonitoringObject creates, updates or deletes a Prometheus
+// operator object. Nothing is done, and false is returned, when the CRD of
+// the object is not installed in the cluster
+func (r *MonitoringReconciler) reconcileMonitoringObject(desired *unstructured.Unstructured, enabled bool) (bool, error) {
+	objectInfo := ObjectInfo(desired)
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(desired.GroupVersionKind())
+	err := r.APIClientReader().Get(context.TODO(), r.NamespacedNameWithAPIManagerNamespace(desired), existing)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			if enabled {
+				r.Logger().Info(fmt.Sprintf("%s not reconciled, the %s CRD is not installed", objectInfo, desired.GetKind()))
+			}
+			return false, nil
+		}
+		if !errors.IsNotFound(err) {
+			return true, err
+		}
+		if !enabled {
+			return true, nil
+		}
+		createErr := r.createResource(desired)
+		if createErr != nil {
+			r.Logger().Error(createErr, fmt.Sprintf("Error creating object %s. Requeuing request...", objectInfo))
+		}
+		return true, createErr
+	}
+
+	if !enabled {
+		if metav1.IsControlledBy(existing, r.apiManager) {
+			return true, r.deleteResource(existing)
+		}
+		return true, nil
+	}
+
+	update := false
+	labels := existing.GetLabels()
+	helper.MergeMapStringString(&update, &labels, desired.GetLabels())
+	existing.SetLabels(labels)
+
+	updateTmp, err := r.ensureOwnerReference(existing)
+	if err != nil {
+		return true, err
+	}
+	update = update || updateTmp
+
+	if !reflect.DeepEqual(desired.Object["spec"], existing.Object["spec"]) {
+		r.Logger().Info(fmt.Sprintf("%s spec differs", objectInfo))
+		existing.Object["spec"] = desired.Object["spec"]
+		update = true
+	}
+
+	updateTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return true, err
+	}
+	update = update || updateTmp
+
+	if update {
+		return true, r.updateResource(existing)
+	}
+
+	return true, nil
+}
+
+func (r *MonitoringReconciler) reconcileGrafanaDashboard(desired *v1.ConfigMap, enabled bool) error {
+	if enabled {
+		reconciler := NewConfigMapBaseReconciler(r.BaseAPIManagerLogicReconciler, NewGrafanaDashboardConfigMapReconciler(r.BaseAPIManagerLogicReconciler))
+		return reconciler.Reconcile(desired)
+	}
+
+	existing := &v1.ConfigMap{}
+	err := r.Client().Get(context.TODO(), r.NamespacedNameWithAPIManagerNamespace(desired), existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if metav1.IsControlledBy(existing, r.apiManager) {
+		return r.deleteResource(existing)
+	}
+	return nil
+}
+
+func (r *MonitoringReconciler) monitoring() (*component.Monitoring, error) {
+	optsProvider := OperatorMonitoringOptionsProvider{APIManagerSpec: &r.apiManager.Spec, Namespace: r.apiManager.Namespace}
+	opts, err := optsProvider.GetMonitoringOptions()
+	if err != nil {
+		return nil, err
+	}
+	return component.NewMonitoring(opts), nil
+}
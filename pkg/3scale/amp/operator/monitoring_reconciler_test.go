@@ -0,0 +1,173 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func monitoringTestAPIManager(namespace string) *appsv1alpha1.APIManager {
+	appLabel := "someLabel"
+	return &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apimanager",
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				AppLabel:       &appLabel,
+				WildcardDomain: "test.3scale.net",
+			},
+			Monitoring: &appsv1alpha1.MonitoringSpec{
+				Enabled: true,
+				Labels:  map[string]string{"prometheus": "k8s"},
+			},
+		},
+	}
+}
+
+func getMonitoringObject(cl client.Reader, namespace, kind, name string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(component.MonitoringAPIVersion)
+	obj.SetKind(kind)
+	err := cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, obj)
+	return obj, err
+}
+
+func TestMonitoringReconciler(t *testing.T) {
+	var (
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := monitoringTestAPIManager(namespace)
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	cl := fake.NewFakeClient(apimanager)
+	baseReconciler := NewBaseReconciler(cl, cl, s, log)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(NewBaseLogicReconciler(baseReconciler), apimanager)
+	reconciler := NewMonitoringReconciler(baseAPIManagerLogicReconciler)
+	_, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, monitored := range component.MonitoredDeploymentConfigs {
+		podMonitor, err := getMonitoringObject(cl, namespace, component.PodMonitorKind, monitored.DeploymentConfig)
+		if err != nil {
+			t.Fatalf("PodMonitor %s: %v", monitored.DeploymentConfig, err)
+		}
+		if podMonitor.GetLabels()["prometheus"] != "k8s" {
+			t.Errorf("PodMonitor %s labels not set: %v", monitored.DeploymentConfig, podMonitor.GetLabels())
+		}
+		if !metav1.IsControlledBy(podMonitor, apimanager) {
+			t.Errorf("PodMonitor %s not owned by the APIManager", monitored.DeploymentConfig)
+		}
+	}
+
+	rule, err := getMonitoringObject(cl, namespace, component.PrometheusRuleKind, component.PrometheusRuleName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if len(groups) != 1 {
+		t.Fatalf("unexpected PrometheusRule groups: %v", groups)
+	}
+
+	dashboards := []string{"apicast", "backend", "system", "zync"}
+	for _, dashboard := range dashboards {
+		configMap := &v1.ConfigMap{}
+		err = cl.Get(context.TODO(), types.NamespacedName{Name: dashboard + "-grafana-dashboard", Namespace: namespace}, configMap)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if configMap.Labels[component.GrafanaDashboardLabel] != "1" {
+			t.Errorf("dashboard %s not labelled: %v", dashboard, configMap.Labels)
+		}
+		if configMap.Data[dashboard+".json"] == "" {
+			t.Errorf("dashboard %s is empty", dashboard)
+		}
+	}
+
+	// Disabling monitoring deletes the objects
+	apimanager.Spec.Monitoring.Enabled = false
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = getMonitoringObject(cl, namespace, component.PrometheusRuleKind, component.PrometheusRuleName)
+	if !errors.IsNotFound(err) {
+		t.Errorf("PrometheusRule not deleted: %v", err)
+	}
+	for _, monitored := range component.MonitoredDeploymentConfigs {
+		_, err = getMonitoringObject(cl, namespace, component.PodMonitorKind, monitored.DeploymentConfig)
+		if !errors.IsNotFound(err) {
+			t.Errorf("PodMonitor %s not deleted: %v", monitored.DeploymentConfig, err)
+		}
+	}
+	for _, dashboard := range dashboards {
+		err = cl.Get(context.TODO(), types.NamespacedName{Name: dashboard + "-grafana-dashboard", Namespace: namespace}, &v1.ConfigMap{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("dashboard %s not deleted: %v", dashboard, err)
+		}
+	}
+}
+
+// noMonitoringCRDsReader behaves like a cluster without the Prometheus
+// operator CRDs
+type noMonitoringCRDsReader struct {
+	client.Reader
+}
+
+func (r noMonitoringCRDsReader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Group == "monitoring.coreos.com" {
+		return &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: gvk.Group, Kind: gvk.Kind}, SearchedVersions: []string{gvk.Version}}
+	}
+	return r.Reader.Get(ctx, key, obj)
+}
+
+func TestMonitoringReconcilerCRDsNotInstalled(t *testing.T) {
+	var (
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+	)
+	apimanager := monitoringTestAPIManager(namespace)
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	cl := fake.NewFakeClient(apimanager)
+	baseReconciler := NewBaseReconciler(cl, noMonitoringCRDsReader{cl}, s, log)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(NewBaseLogicReconciler(baseReconciler), apimanager)
+	reconciler := NewMonitoringReconciler(baseAPIManagerLogicReconciler)
+	_, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = getMonitoringObject(cl, namespace, component.PrometheusRuleKind, component.PrometheusRuleName)
+	if !errors.IsNotFound(err) {
+		t.Errorf("PrometheusRule created without CRD: %v", err)
+	}
+
+	// The dashboards do not depend on the Prometheus operator
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "apicast-grafana-dashboard", Namespace: namespace}, &v1.ConfigMap{})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
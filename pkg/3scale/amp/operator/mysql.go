@@ -22,6 +22,7 @@ func (o *OperatorMysqlOptionsProvider) GetMysqlOptions() (*component.SystemMysql
 	}
 
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -111,7 +112,20 @@ func (o *OperatorMysqlOptionsProvider) systemDatabaseURLIsValid(rawURL string) (
 }
 
 func (o *OperatorMysqlOptionsProvider) setResourceRequirementsOptions(b *component.SystemMysqlOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.ContainerResourceRequirements(v1.ResourceRequirements{})
+	var resources *v1.ResourceRequirements
+	if o.APIManagerSpec.System != nil &&
+		o.APIManagerSpec.System.DatabaseSpec != nil &&
+		o.APIManagerSpec.System.DatabaseSpec.MySQL != nil {
+		resources = o.APIManagerSpec.System.DatabaseSpec.MySQL.Resources
+	}
+	setResourceRequirements(b.ContainerResourceRequirements, *o.APIManagerSpec.ResourceRequirementsEnabled, resources)
+}
+
+func (o *OperatorMysqlOptionsProvider) setPodPlacementOptions(b *component.SystemMysqlOptionsBuilder) {
+	if o.APIManagerSpec.System != nil &&
+		o.APIManagerSpec.System.DatabaseSpec != nil &&
+		o.APIManagerSpec.System.DatabaseSpec.MySQL != nil &&
+		o.APIManagerSpec.System.DatabaseSpec.MySQL.Placement != nil {
+		b.PodPlacement(podPlacement(o.APIManagerSpec.System.DatabaseSpec.MySQL.Placement, "system-mysql"))
 	}
 }
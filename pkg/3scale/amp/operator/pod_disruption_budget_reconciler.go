@@ -32,8 +32,23 @@ func (r PodDisruptionBudgetReconciler) Reconcile(desired *v1beta1.PodDisruptionB
 		return r.createResource(desired)
 	}
 
-	if r.apiManager.IsPDBEnabled() && existingPDB != nil && !reflect.DeepEqual(desired.Spec, existingPDB.Spec) {
-		return r.updateResource(desired)
+	if r.apiManager.IsPDBEnabled() && existingPDB != nil {
+		update := false
+		if !reflect.DeepEqual(desired.Spec, existingPDB.Spec) {
+			existingPDB.Spec = desired.Spec
+			update = true
+		}
+
+		updateTmp, err := r.correctDrift(desired, existingPDB)
+		if err != nil {
+			return err
+		}
+		update = update || updateTmp
+
+		if update {
+			return r.updateResource(existingPDB)
+		}
+		return nil
 	}
 
 	if !r.apiManager.IsPDBEnabled() && existingPDB != nil {
@@ -0,0 +1,61 @@
+package operator
+
+import (
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// podPlacement returns the placement of the pods of a DeploymentConfig,
+// or of the Deployment or StatefulSet of the same name, selected by their
+// deploymentConfig label
+func podPlacement(spec *appsv1alpha1.PodPlacementSpec, deploymentName string) component.PodPlacement {
+	return podPlacementWithSelector(spec, map[string]string{"deploymentConfig": deploymentName})
+}
+
+// podPlacementWithSelector returns the placement of the pods matching the
+// selector. The topology spread constraints are emulated with pod
+// anti-affinity terms on those pods, added to the affinity of the spec
+func podPlacementWithSelector(spec *appsv1alpha1.PodPlacementSpec, podSelector map[string]string) component.PodPlacement {
+	placement := component.PodPlacement{
+		NodeSelector: spec.NodeSelector,
+		Tolerations:  spec.Tolerations,
+		Affinity:     spec.Affinity,
+	}
+	if spec.PriorityClassName != nil {
+		placement.PriorityClassName = *spec.PriorityClassName
+	}
+	if len(spec.TopologySpreadConstraints) > 0 {
+		placement.Affinity = topologySpreadAffinity(spec.Affinity, spec.TopologySpreadConstraints, podSelector)
+	}
+	return placement
+}
+
+func topologySpreadAffinity(affinity *v1.Affinity, constraints []appsv1alpha1.TopologySpreadConstraint, podSelector map[string]string) *v1.Affinity {
+	result := &v1.Affinity{}
+	if affinity != nil {
+		result = affinity.DeepCopy()
+	}
+	if result.PodAntiAffinity == nil {
+		result.PodAntiAffinity = &v1.PodAntiAffinity{}
+	}
+	antiAffinity := result.PodAntiAffinity
+
+	for _, constraint := range constraints {
+		term := v1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: podSelector},
+			TopologyKey:   constraint.TopologyKey,
+		}
+		if constraint.WhenUnsatisfiable != nil && *constraint.WhenUnsatisfiable == appsv1alpha1.TopologySpreadScheduleAnyway {
+			antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, v1.WeightedPodAffinityTerm{
+				Weight:          100,
+				PodAffinityTerm: term,
+			})
+			continue
+		}
+		antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	}
+
+	return result
+}
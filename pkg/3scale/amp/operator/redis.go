@@ -17,18 +17,19 @@ func (o *OperatorRedisOptionsProvider) GetRedisOptions() (*component.RedisOption
 	optProv.InsecureImportPolicy(*o.APIManagerSpec.ImageStreamTagImportInsecure)
 
 	if o.APIManagerSpec.Backend != nil && o.APIManagerSpec.Backend.RedisImage != nil {
-		optProv.BackendImage(*o.APIManagerSpec.Backend.RedisImage)
+		optProv.BackendImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.Backend.RedisImage))
 	} else {
-		optProv.BackendImage(BackendRedisImageURL())
+		optProv.BackendImage(mirroredImageURL(o.APIManagerSpec, BackendRedisImageURL()))
 	}
 
 	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.RedisImage != nil {
-		optProv.SystemImage(*o.APIManagerSpec.System.RedisImage)
+		optProv.SystemImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.System.RedisImage))
 	} else {
-		optProv.SystemImage(SystemRedisImageURL())
+		optProv.SystemImage(mirroredImageURL(o.APIManagerSpec, SystemRedisImageURL()))
 	}
 
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -38,9 +39,24 @@ func (o *OperatorRedisOptionsProvider) GetRedisOptions() (*component.RedisOption
 }
 
 func (o *OperatorRedisOptionsProvider) setResourceRequirementsOptions(b *component.RedisOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.SystemRedisContainerResourceRequirements(v1.ResourceRequirements{})
-		b.BackendRedisContainerResourceRequirements(v1.ResourceRequirements{})
+	enabled := *o.APIManagerSpec.ResourceRequirementsEnabled
+	var systemRedisResources, backendRedisResources *v1.ResourceRequirements
+	if o.APIManagerSpec.System != nil {
+		systemRedisResources = o.APIManagerSpec.System.RedisResources
+	}
+	if o.APIManagerSpec.Backend != nil {
+		backendRedisResources = o.APIManagerSpec.Backend.RedisResources
+	}
+	setResourceRequirements(b.SystemRedisContainerResourceRequirements, enabled, systemRedisResources)
+	setResourceRequirements(b.BackendRedisContainerResourceRequirements, enabled, backendRedisResources)
+}
+
+func (o *OperatorRedisOptionsProvider) setPodPlacementOptions(b *component.RedisOptionsBuilder) {
+	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.RedisPlacement != nil {
+		b.SystemRedisPodPlacement(podPlacement(o.APIManagerSpec.System.RedisPlacement, "system-redis"))
+	}
+	if o.APIManagerSpec.Backend != nil && o.APIManagerSpec.Backend.RedisPlacement != nil {
+		b.BackendRedisPodPlacement(podPlacement(o.APIManagerSpec.Backend.RedisPlacement, "backend-redis"))
 	}
 }
 
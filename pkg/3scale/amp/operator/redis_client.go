@@ -0,0 +1,286 @@
+package operator
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	redisDefaultPort         = "6379"
+	redisSentinelDefaultPort = "26379"
+)
+
+// redisClient is a minimal client of the Redis serialization protocol.
+// It only implements what the operator needs to inspect the redis
+// instances used by 3scale
+type redisClient struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+}
+
+// redisError is an error reply sent by the redis server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+func dialRedis(address string, timeout time.Duration) (*redisClient, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &redisClient{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+func dialRedisTLS(address string, timeout time.Duration, tlsConfig *tls.Config) (*redisClient, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &redisClient{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+// dialRedisURL connects to the redis server of the given
+// redis[s]://[:password@]host[:port][/db] URL, authenticating and selecting
+// the database when present in the URL. rediss URLs are dialed with the
+// given TLS configuration, the system CAs are used when nil
+func dialRedisURL(rawURL string, timeout time.Duration, tlsConfig *tls.Config) (*redisClient, error) {
+	redisURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *redisClient
+	address := redisAddress(redisURL.Host, redisDefaultPort)
+	switch redisURL.Scheme {
+	case "redis":
+		c, err = dialRedis(address, timeout)
+	case "rediss":
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		c, err = dialRedisTLS(address, timeout, tlsConfig)
+	default:
+		return nil, fmt.Errorf("Unsupported redis URL scheme '%s'", redisURL.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if redisURL.User != nil {
+		if password, ok := redisURL.User.Password(); ok && password != "" {
+			_, err = c.do("AUTH", password)
+			if err != nil {
+				c.Close()
+				return nil, err
+			}
+		}
+	}
+
+	db := strings.TrimPrefix(redisURL.Path, "/")
+	if db != "" && db != "0" {
+		_, err = c.do("SELECT", db)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// redisSentinelMasterURL returns the given redis URL with its host replaced
+// by the address of the master the sentinels report for it. The host of
+// the given URL is the name of the master and sentinelHosts is a comma
+// separated list of sentinel URLs
+func redisSentinelMasterURL(sentinelHosts, rawURL string, timeout time.Duration) (string, error) {
+	redisURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	masterName := redisURL.Hostname()
+
+	var lastErr error
+	for _, sentinelHost := range strings.Split(sentinelHosts, ",") {
+		sentinelHost = strings.TrimSpace(sentinelHost)
+		if sentinelHost == "" {
+			continue
+		}
+		if sentinelURL, err := url.Parse(sentinelHost); err == nil && sentinelURL.Host != "" {
+			sentinelHost = sentinelURL.Host
+		}
+
+		masterAddress, err := redisSentinelMasterAddress(redisAddress(sentinelHost, redisSentinelDefaultPort), masterName, timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		redisURL.Host = masterAddress
+		return redisURL.String(), nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("No redis sentinel hosts given")
+	}
+	return "", lastErr
+}
+
+func redisSentinelMasterAddress(sentinelAddress, masterName string, timeout time.Duration) (string, error) {
+	c, err := dialRedis(sentinelAddress, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+
+	reply, err := c.do("SENTINEL", "get-master-addr-by-name", masterName)
+	if err != nil {
+		return "", err
+	}
+	address, ok := reply.([]interface{})
+	if !ok || len(address) != 2 {
+		return "", fmt.Errorf("Redis sentinel %s does not know master '%s'", sentinelAddress, masterName)
+	}
+	host, hostOk := address[0].(string)
+	port, portOk := address[1].(string)
+	if !hostOk || !portOk {
+		return "", fmt.Errorf("Unexpected redis sentinel reply %v", reply)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// redisTLSConfig returns the TLS configuration trusting the given PEM
+// encoded CA certificates and presenting the client certificate, when
+// given
+func redisTLSConfig(caPEM, certPEM, keyPEM []byte) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if len(caPEM) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("No valid redis CA certificate found")
+		}
+	}
+	if len(certPEM) > 0 || len(keyPEM) > 0 {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+func redisAddress(host, defaultPort string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, defaultPort)
+}
+
+func (c *redisClient) Close() error {
+	return c.conn.Close()
+}
+
+// llen returns the length of the list stored at key. Missing keys have
+// length 0
+func (c *redisClient) llen(key string) (int64, error) {
+	reply, err := c.do("LLEN", key)
+	if err != nil {
+		return 0, err
+	}
+	length, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("Unexpected redis LLEN reply %v", reply)
+	}
+	return length, nil
+}
+
+// do sends the given command and returns its reply. Replies are returned as
+// string, int64, []interface{} or nil values. Error replies are returned as
+// redisError errors
+func (c *redisClient) do(args ...string) (interface{}, error) {
+	err := c.conn.SetDeadline(time.Now().Add(c.timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err = io.WriteString(c.conn, command.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return c.readReply()
+}
+
+func (c *redisClient) readReply() (interface{}, error) {
+	line, err := c.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, fmt.Errorf("Empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		_, err = io.ReadFull(c.reader, data)
+		if err != nil {
+			return nil, err
+		}
+		return string(data[:length]), nil
+	case '*':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, length)
+		for i := range items {
+			items[i], err = c.readReply()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("Unexpected redis reply '%s'", line)
+	}
+}
+
+func (c *redisClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
@@ -1,8 +1,10 @@
 package operator
 
 import (
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	appsv1 "github.com/openshift/api/apps/v1"
 	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -64,6 +66,11 @@ func (r *RedisReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	err = r.deleteSentinelWorkloads()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	err = r.reconcileBackendDeploymentConfig(redis.BackendDeploymentConfig())
 	if err != nil {
 		return reconcile.Result{}, err
@@ -112,6 +119,29 @@ func (r *RedisReconciler) Reconcile() (reconcile.Result, error) {
 	return reconcile.Result{}, nil
 }
 
+// deleteSentinelWorkloads deletes the redis master/replica sets and the
+// sentinels deployed in Sentinel mode. The sets have the name of the
+// standalone instances and would be selected by the same Services. Their
+// PersistentVolumeClaims are kept
+func (r *RedisReconciler) deleteSentinelWorkloads() error {
+	sentinel := &k8sappsv1.StatefulSet{}
+	err := r.deleteOwnedResource(component.RedisSentinelName, sentinel, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"backend-redis", "system-redis"} {
+		existing := &k8sappsv1.StatefulSet{}
+		err = r.deleteOwnedResource(name, existing, func() bool {
+			return existing.Spec.ServiceName == component.RedisSentinelHeadlessServiceName(name)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *RedisReconciler) reconcileBackendDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
 	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewRedisBackendDCReconciler(r.BaseAPIManagerLogicReconciler))
 	return reconciler.Reconcile(desiredDeploymentConfig)
@@ -156,3 +186,22 @@ func (r *RedisReconciler) reconcileSystemImageStream(desiredImageStream *imagev1
 	reconciler := NewImageStreamBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImageStreamGenericReconciler())
 	return reconciler.Reconcile(desiredImageStream)
 }
+
+type RedisSentinelDCReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewRedisSentinelDCReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *RedisSentinelDCReconciler {
+	return &RedisSentinelDCReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+func (r *RedisSentinelDCReconciler) IsUpdateNeeded(desired, existing *appsv1.DeploymentConfig) bool {
+	update := false
+
+	tmpUpdate := DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	return update
+}
@@ -2,12 +2,15 @@ package operator
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
 	appsv1 "github.com/openshift/api/apps/v1"
 	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -99,3 +102,201 @@ func TestRedisBackendDCReconcilerCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestRedisSentinelReconcilerCreate(t *testing.T) {
+	var (
+		appLabel         = "someLabel"
+		name             = "example-apimanager"
+		namespace        = "operator-unittest"
+		trueValue        = true
+		wildcardDomain   = "test.3scale.net"
+		tenantName       = "someTenant"
+		sentinelMode     = appsv1alpha1.RedisModeSentinel
+		sentinelReplicas = int32(5)
+		log              = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				AppLabel:                     &appLabel,
+				ImageStreamTagImportInsecure: &trueValue,
+				ResourceRequirementsEnabled:  &trueValue,
+				WildcardDomain:               wildcardDomain,
+				TenantName:                   &tenantName,
+			},
+			Redis: &appsv1alpha1.RedisSpec{
+				Mode: &sentinelMode,
+				Sentinel: &appsv1alpha1.RedisSentinelSpec{
+					SentinelReplicas: &sentinelReplicas,
+				},
+			},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	reconciler := NewRedisSentinelReconciler(baseAPIManagerLogicReconciler)
+	_, err := reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		testName string
+		objName  string
+		obj      runtime.Object
+	}{
+		{"redisConfigCM", "redis-sentinel-config", &v1.ConfigMap{}},
+		{"backendRedisStatefulSet", "backend-redis", &k8sappsv1.StatefulSet{}},
+		{"backendRedisHeadlessService", "backend-redis-headless", &v1.Service{}},
+		{"backendRedisService", "backend-redis", &v1.Service{}},
+		{"systemRedisStatefulSet", "system-redis", &k8sappsv1.StatefulSet{}},
+		{"systemRedisHeadlessService", "system-redis-headless", &v1.Service{}},
+		{"systemRedisService", "system-redis", &v1.Service{}},
+		{"sentinelStatefulSet", "redis-sentinel", &k8sappsv1.StatefulSet{}},
+		{"sentinelService", "redis-sentinel", &v1.Service{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			obj := tc.obj
+			namespacedName := types.NamespacedName{
+				Name:      tc.objName,
+				Namespace: namespace,
+			}
+			err = cl.Get(context.TODO(), namespacedName, obj)
+			// object must exist, that is all required to be tested
+			if err != nil {
+				subT.Errorf("error fetching object %s: %v", tc.objName, err)
+			}
+		})
+	}
+
+	sentinels := &k8sappsv1.StatefulSet{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "redis-sentinel", Namespace: namespace}, sentinels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *sentinels.Spec.Replicas != sentinelReplicas {
+		t.Errorf("Unexpected sentinel replicas. Expected: %d. Got: %d", sentinelReplicas, *sentinels.Spec.Replicas)
+	}
+	// The quorum defaults to a majority of the sentinels
+	script := sentinels.Spec.Template.Spec.Containers[0].Command[3]
+	if !strings.Contains(script, "sentinel monitor backend-redis $MASTER 6379 3") || !strings.Contains(script, "sentinel monitor system-redis $MASTER 6379 3") {
+		t.Errorf("Unexpected sentinel monitor configuration: %s", script)
+	}
+
+	hosts, err := redisSentinelHosts(&apimanager.Spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(strings.Split(hosts, ",")) != int(sentinelReplicas) || !strings.HasPrefix(hosts, "redis://redis-sentinel-0.redis-sentinel:26379,") {
+		t.Errorf("Unexpected sentinel hosts: %s", hosts)
+	}
+}
+
+func TestRedisReconcilerModeSwitch(t *testing.T) {
+	var (
+		appLabel       = "someLabel"
+		name           = "example-apimanager"
+		namespace      = "operator-unittest"
+		trueValue      = true
+		wildcardDomain = "test.3scale.net"
+		tenantName     = "someTenant"
+		standaloneMode = appsv1alpha1.RedisModeStandalone
+		sentinelMode   = appsv1alpha1.RedisModeSentinel
+		log            = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				AppLabel:                     &appLabel,
+				ImageStreamTagImportInsecure: &trueValue,
+				ResourceRequirementsEnabled:  &trueValue,
+				WildcardDomain:               wildcardDomain,
+				TenantName:                   &tenantName,
+			},
+			Redis: &appsv1alpha1.RedisSpec{
+				Mode: &standaloneMode,
+			},
+		},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := imagev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := fake.NewFakeClient()
+	baseLogicReconciler := NewBaseLogicReconciler(NewBaseReconciler(cl, cl, s, log))
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	standaloneReconciler := NewRedisReconciler(baseAPIManagerLogicReconciler)
+	sentinelReconciler := NewRedisSentinelReconciler(baseAPIManagerLogicReconciler)
+	exists := func(name string, obj runtime.Object) bool {
+		err := cl.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, obj)
+		if err != nil && !errors.IsNotFound(err) {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+
+	_, err = standaloneReconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The standalone DeploymentConfigs are replaced by the StatefulSets
+	apimanager.Spec.Redis.Mode = &sentinelMode
+	_, err = sentinelReconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, redisName := range []string{"backend-redis", "system-redis"} {
+		if exists(redisName, &appsv1.DeploymentConfig{}) {
+			t.Errorf("Standalone DeploymentConfig %s not deleted in Sentinel mode", redisName)
+		}
+		if !exists(redisName, &k8sappsv1.StatefulSet{}) {
+			t.Errorf("StatefulSet %s not created in Sentinel mode", redisName)
+		}
+	}
+
+	// And the other way around
+	apimanager.Spec.Redis.Mode = &standaloneMode
+	_, err = standaloneReconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, redisName := range []string{"backend-redis", "system-redis", "redis-sentinel"} {
+		if exists(redisName, &k8sappsv1.StatefulSet{}) {
+			t.Errorf("Sentinel mode StatefulSet %s not deleted in Standalone mode", redisName)
+		}
+	}
+	for _, redisName := range []string{"backend-redis", "system-redis"} {
+		if !exists(redisName, &appsv1.DeploymentConfig{}) {
+			t.Errorf("DeploymentConfig %s not created in Standalone mode", redisName)
+		}
+	}
+}
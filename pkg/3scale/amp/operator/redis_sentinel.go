@@ -0,0 +1,110 @@
+package operator
+
+import (
+	"fmt"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+)
+
+func (o *OperatorRedisSentinelOptionsProvider) GetRedisSentinelOptions() (*component.RedisSentinelOptions, error) {
+	optProv := component.RedisSentinelOptionsBuilder{}
+
+	optProv.AppLabel(*o.APIManagerSpec.AppLabel)
+
+	if o.APIManagerSpec.Backend != nil && o.APIManagerSpec.Backend.RedisImage != nil {
+		optProv.BackendImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.Backend.RedisImage))
+	} else {
+		optProv.BackendImage(mirroredImageURL(o.APIManagerSpec, BackendRedisImageURL()))
+	}
+
+	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.RedisImage != nil {
+		optProv.SystemImage(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.System.RedisImage))
+	} else {
+		optProv.SystemImage(mirroredImageURL(o.APIManagerSpec, SystemRedisImageURL()))
+	}
+
+	o.setReplicasOptions(&optProv)
+	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
+
+	res, err := optProv.Build()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Redis Sentinel Options - %s", err)
+	}
+	return res, nil
+}
+
+func (o *OperatorRedisSentinelOptionsProvider) sentinelSpec() *appsv1alpha1.RedisSentinelSpec {
+	if o.APIManagerSpec.Redis == nil || o.APIManagerSpec.Redis.Sentinel == nil {
+		return &appsv1alpha1.RedisSentinelSpec{}
+	}
+	return o.APIManagerSpec.Redis.Sentinel
+}
+
+func (o *OperatorRedisSentinelOptionsProvider) setReplicasOptions(b *component.RedisSentinelOptionsBuilder) {
+	sentinelSpec := o.sentinelSpec()
+	if sentinelSpec.Replicas != nil {
+		b.Replicas(*sentinelSpec.Replicas)
+	}
+
+	sentinelReplicas := int32(3)
+	if sentinelSpec.SentinelReplicas != nil {
+		sentinelReplicas = *sentinelSpec.SentinelReplicas
+	}
+	b.SentinelReplicas(sentinelReplicas)
+
+	// The quorum defaults to a majority of the sentinels
+	if sentinelSpec.Quorum != nil {
+		b.Quorum(*sentinelSpec.Quorum)
+	} else {
+		b.Quorum(sentinelReplicas/2 + 1)
+	}
+}
+
+func (o *OperatorRedisSentinelOptionsProvider) setResourceRequirementsOptions(b *component.RedisSentinelOptionsBuilder) {
+	enabled := *o.APIManagerSpec.ResourceRequirementsEnabled
+	var systemRedisResources, backendRedisResources *v1.ResourceRequirements
+	if o.APIManagerSpec.System != nil {
+		systemRedisResources = o.APIManagerSpec.System.RedisResources
+	}
+	if o.APIManagerSpec.Backend != nil {
+		backendRedisResources = o.APIManagerSpec.Backend.RedisResources
+	}
+	setResourceRequirements(b.SystemRedisContainerResourceRequirements, enabled, systemRedisResources)
+	setResourceRequirements(b.BackendRedisContainerResourceRequirements, enabled, backendRedisResources)
+	setResourceRequirements(b.SentinelContainerResourceRequirements, enabled, o.sentinelSpec().SentinelResources)
+}
+
+func (o *OperatorRedisSentinelOptionsProvider) setPodPlacementOptions(b *component.RedisSentinelOptionsBuilder) {
+	if o.APIManagerSpec.System != nil && o.APIManagerSpec.System.RedisPlacement != nil {
+		b.SystemRedisPodPlacement(podPlacement(o.APIManagerSpec.System.RedisPlacement, "system-redis"))
+	}
+	if o.APIManagerSpec.Backend != nil && o.APIManagerSpec.Backend.RedisPlacement != nil {
+		b.BackendRedisPodPlacement(podPlacement(o.APIManagerSpec.Backend.RedisPlacement, "backend-redis"))
+	}
+	if sentinelSpec := o.sentinelSpec(); sentinelSpec.SentinelPlacement != nil {
+		b.SentinelPodPlacement(podPlacement(sentinelSpec.SentinelPlacement, component.RedisSentinelName))
+	}
+}
+
+func RedisSentinel(cr *appsv1alpha1.APIManager) (*component.RedisSentinel, error) {
+	optsProvider := OperatorRedisSentinelOptionsProvider{APIManagerSpec: &cr.Spec}
+	opts, err := optsProvider.GetRedisSentinelOptions()
+	if err != nil {
+		return nil, err
+	}
+	return component.NewRedisSentinel(opts), nil
+}
+
+// redisSentinelHosts returns the sentinel hosts set in the backend-redis and
+// system-redis secrets
+func redisSentinelHosts(spec *appsv1alpha1.APIManagerSpec) (string, error) {
+	optsProvider := OperatorRedisSentinelOptionsProvider{APIManagerSpec: spec}
+	opts, err := optsProvider.GetRedisSentinelOptions()
+	if err != nil {
+		return "", err
+	}
+	return component.NewRedisSentinel(opts).SentinelHosts(), nil
+}
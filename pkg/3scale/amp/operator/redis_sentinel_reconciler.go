@@ -0,0 +1,113 @@
+package operator
+
+import (
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1 "github.com/openshift/api/apps/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type RedisSentinelReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+// blank assignment to verify that RedisSentinelReconciler implements LogicReconciler
+var _ LogicReconciler = &RedisSentinelReconciler{}
+
+func NewRedisSentinelReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) RedisSentinelReconciler {
+	return RedisSentinelReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+func (r *RedisSentinelReconciler) Reconcile() (reconcile.Result, error) {
+	redis, err := RedisSentinel(r.apiManager)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileConfigMap(redis.ConfigMap())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	services := []*v1.Service{
+		redis.BackendHeadlessService(),
+		redis.BackendService(),
+		redis.SystemHeadlessService(),
+		redis.SystemService(),
+		redis.SentinelService(),
+	}
+	for _, service := range services {
+		err = r.reconcileService(service)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	err = r.deleteStandaloneWorkloads(redis)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileStatefulSet(redis.BackendStatefulSet(), NewRedisBackendDCReconciler(r.BaseAPIManagerLogicReconciler))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileStatefulSet(redis.SystemStatefulSet(), NewRedisSystemDCReconciler(r.BaseAPIManagerLogicReconciler))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileStatefulSet(redis.SentinelStatefulSet(), NewRedisSentinelDCReconciler(r.BaseAPIManagerLogicReconciler))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// deleteStandaloneWorkloads deletes the backend-redis and system-redis
+// workloads deployed in Standalone mode. They have the name of the
+// master/replica sets and would be selected by the same Services. Their
+// PersistentVolumeClaims are kept
+func (r *RedisSentinelReconciler) deleteStandaloneWorkloads(redis *component.RedisSentinel) error {
+	for _, desired := range []*k8sappsv1.StatefulSet{redis.BackendStatefulSet(), redis.SystemStatefulSet()} {
+		err := r.deleteOwnedResource(desired.Name, &appsv1.DeploymentConfig{}, nil)
+		if err != nil {
+			return err
+		}
+
+		// With Kubernetes workloads the standalone instances are
+		// StatefulSets too, governed by a different Service
+		existing := &k8sappsv1.StatefulSet{}
+		err = r.deleteOwnedResource(desired.Name, existing, func() bool {
+			return existing.Spec.ServiceName != desired.Spec.ServiceName
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *RedisSentinelReconciler) reconcileConfigMap(desiredConfigMap *v1.ConfigMap) error {
+	reconciler := NewConfigMapBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlyConfigMapReconciler())
+	return reconciler.Reconcile(desiredConfigMap)
+}
+
+func (r *RedisSentinelReconciler) reconcileService(desiredService *v1.Service) error {
+	reconciler := NewServiceBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlySvcReconciler())
+	return reconciler.Reconcile(desiredService)
+}
+
+// reconcileStatefulSet reconciles the container resources and the pod
+// placement of the StatefulSet, the same way they are reconciled for the
+// standalone redis DeploymentConfigs
+func (r *RedisSentinelReconciler) reconcileStatefulSet(desiredStatefulSet *k8sappsv1.StatefulSet, dcReconciler DeploymentConfigReconciler) error {
+	adapter := deploymentConfigAdapterReconciler{reconciler: dcReconciler, logger: r.Logger()}
+	reconciler := NewStatefulSetBaseReconciler(r.BaseAPIManagerLogicReconciler, &statefulSetDeploymentConfigAdapterReconciler{adapter})
+	return reconciler.Reconcile(desiredStatefulSet)
+}
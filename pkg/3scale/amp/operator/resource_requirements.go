@@ -0,0 +1,17 @@
+package operator
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// setResourceRequirements sets the resource requirements of a container
+// with the given builder setter. Component specific resource requirements
+// take precedence over the global resource requirements setting. Otherwise
+// the builder defaults are kept, unless resource requirements are disabled
+func setResourceRequirements(set func(v1.ResourceRequirements), enabled bool, resources *v1.ResourceRequirements) {
+	if resources != nil {
+		set(*resources)
+	} else if !enabled {
+		set(v1.ResourceRequirements{})
+	}
+}
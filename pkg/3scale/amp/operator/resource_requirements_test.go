@@ -0,0 +1,41 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/helper"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSetResourceRequirements(t *testing.T) {
+	defaultResources := v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+	}
+	componentResources := v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+	}
+	emptyResources := v1.ResourceRequirements{}
+
+	cases := []struct {
+		testName  string
+		enabled   bool
+		resources *v1.ResourceRequirements
+		expected  v1.ResourceRequirements
+	}{
+		{"Enabled", true, nil, defaultResources},
+		{"Disabled", false, nil, emptyResources},
+		{"EnabledOverride", true, &componentResources, componentResources},
+		{"DisabledOverride", false, &componentResources, componentResources},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			result := defaultResources
+			setResourceRequirements(func(resources v1.ResourceRequirements) { result = resources }, tc.enabled, tc.resources)
+			if !helper.CmpResources(&result, &tc.expected) {
+				subT.Errorf("Unexpected resource requirements. Expected: %v, got: %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -69,6 +69,12 @@ func (r *RoleBindingBaseReconciler) isUpdateNeeded(desired, existing *rbacv1.Rol
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
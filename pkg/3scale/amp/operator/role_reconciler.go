@@ -69,6 +69,12 @@ func (r *RoleBaseReconciler) isUpdateNeeded(desired, existing *rbacv1.Role) (boo
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -3,10 +3,14 @@ package operator
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -26,6 +30,9 @@ func NewRouteBaseReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicRec
 	}
 }
 
+// Reconcile creates or updates the desired Route. When the components are
+// exposed with Ingresses, the Route is deleted instead if it is owned by
+// the APIManager
 func (r *RouteBaseReconciler) Reconcile(desired *routev1.Route) error {
 	objectInfo := ObjectInfo(desired)
 	existing := &routev1.Route{}
@@ -33,6 +40,22 @@ func (r *RouteBaseReconciler) Reconcile(desired *routev1.Route) error {
 		context.TODO(),
 		types.NamespacedName{Name: desired.Name, Namespace: r.apiManager.GetNamespace()},
 		existing)
+
+	if r.apiManager.IsIngressEnabled() {
+		// The Route API is not available outside of OpenShift
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Routes of the same name not created by the operator are kept
+		if metav1.IsControlledBy(existing, r.apiManager) {
+			return r.deleteResource(existing)
+		}
+		return nil
+	}
+
 	if err != nil {
 		if errors.IsNotFound(err) {
 			createErr := r.createResource(desired)
@@ -69,6 +92,12 @@ func (r *RouteBaseReconciler) isUpdateNeeded(desired, existing *routev1.Route) (
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -82,3 +111,31 @@ func NewCreateOnlyRouteReconciler() *CreateOnlyRouteReconciler {
 func (r *CreateOnlyRouteReconciler) IsUpdateNeeded(desired, existing *routev1.Route) bool {
 	return false
 }
+
+// TLSRouteReconciler reconciles the target port and the TLS configuration
+// of Routes
+type TLSRouteReconciler struct {
+	logger logr.Logger
+}
+
+func NewTLSRouteReconciler(logger logr.Logger) *TLSRouteReconciler {
+	return &TLSRouteReconciler{logger: logger}
+}
+
+func (r *TLSRouteReconciler) IsUpdateNeeded(desired, existing *routev1.Route) bool {
+	update := false
+
+	if !reflect.DeepEqual(desired.Spec.Port, existing.Spec.Port) {
+		r.logger.Info(fmt.Sprintf("%s spec.port differs", ObjectInfo(desired)))
+		existing.Spec.Port = desired.Spec.Port
+		update = true
+	}
+
+	if !reflect.DeepEqual(desired.Spec.TLS, existing.Spec.TLS) {
+		r.logger.Info(fmt.Sprintf("%s spec.tls differs", ObjectInfo(desired)))
+		existing.Spec.TLS = desired.Spec.TLS
+		update = true
+	}
+
+	return update
+}
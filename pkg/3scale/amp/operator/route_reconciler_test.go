@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -231,3 +232,111 @@ func TestRouteBaseReconcilerUpdateNeeded(t *testing.T) {
 		t.Fatalf("reconciled have reconciled data. Expected: '/newPath', got: %s", reconciled.Spec.Path)
 	}
 }
+
+func TestTLSRouteReconciler(t *testing.T) {
+	log := logf.Log.WithName("operator_test")
+	desired := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicast-production-https"},
+		Spec: routev1.RouteSpec{
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString("https")},
+			TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationReencrypt},
+		},
+	}
+	existing := &routev1.Route{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicast-production-https"},
+		Spec: routev1.RouteSpec{
+			Port: &routev1.RoutePort{TargetPort: intstr.FromString("https")},
+			TLS:  &routev1.TLSConfig{Termination: routev1.TLSTerminationPassthrough},
+		},
+	}
+
+	reconciler := NewTLSRouteReconciler(log)
+	if !reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("route TLS termination not reconciled")
+	}
+	if existing.Spec.TLS.Termination != routev1.TLSTerminationReencrypt {
+		t.Errorf("unexpected reconciled route TLS termination: %s", existing.Spec.TLS.Termination)
+	}
+
+	if reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("unchanged route reconciled")
+	}
+}
+
+func TestRouteBaseReconcilerIngressExposure(t *testing.T) {
+	var (
+		name         = "example-apimanager"
+		namespace    = "operator-unittest"
+		exposureType = appsv1alpha1.ExposureTypeIngress
+		log          = logf.Log.WithName("operator_test")
+	)
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				ExposureType: &exposureType,
+			},
+		},
+	}
+	newRoute := func(routeName string) *routev1.Route {
+		return &routev1.Route{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Route",
+				APIVersion: "route.openshift.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      routeName,
+				Namespace: namespace,
+			},
+		}
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Route created before the exposure type was changed to Ingress
+	owned := newRoute("ownedRoute")
+	err = controllerutil.SetControllerReference(apimanager, owned, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notOwned := newRoute("userRoute")
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{owned, notOwned}
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+
+	baseReconciler := NewBaseReconciler(cl, clientAPIReader, s, log)
+	baseLogicReconciler := NewBaseLogicReconciler(baseReconciler)
+	baseAPIManagerLogicReconciler := NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager)
+	reconciler := NewRouteBaseReconciler(baseAPIManagerLogicReconciler, NewCreateOnlyRouteReconciler())
+
+	for _, routeName := range []string{"ownedRoute", "userRoute", "missingRoute"} {
+		err = reconciler.Reconcile(newRoute(routeName))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reconciled := &routev1.Route{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "ownedRoute", Namespace: namespace}, reconciled)
+	if err == nil {
+		t.Error("Route owned by the APIManager not deleted when exposure type is Ingress")
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "userRoute", Namespace: namespace}, reconciled)
+	if err != nil {
+		t.Errorf("Route not owned by the APIManager deleted: %v", err)
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "missingRoute", Namespace: namespace}, reconciled)
+	if err == nil {
+		t.Error("Route created when exposure type is Ingress")
+	}
+}
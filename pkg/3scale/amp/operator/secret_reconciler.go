@@ -70,6 +70,12 @@ func (r *SecretBaseReconciler) isUpdateNeeded(desired, existing *v1.Secret) (boo
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -111,6 +117,29 @@ func (r *DefaultsOnlySecretReconciler) IsUpdateNeeded(desired, existing *v1.Secr
 	return updated
 }
 
+// FieldsSecretReconciler adds the missing fields with default values, like
+// DefaultsOnlySecretReconciler, and sets the given fields to their desired
+// values. Useful for secrets partially managed from CR fields
+type FieldsSecretReconciler struct {
+	DefaultsOnlySecretReconciler
+	fields []string
+}
+
+func NewFieldsSecretReconciler(fields ...string) *FieldsSecretReconciler {
+	return &FieldsSecretReconciler{fields: fields}
+}
+
+func (r *FieldsSecretReconciler) IsUpdateNeeded(desired, existing *v1.Secret) bool {
+	updated := r.DefaultsOnlySecretReconciler.IsUpdateNeeded(desired, existing)
+
+	for _, field := range r.fields {
+		updatedTmp := SecretReconcileField(desired, existing, field)
+		updated = updated || updatedTmp
+	}
+
+	return updated
+}
+
 func SecretReconcileField(desired, existing *v1.Secret, fieldName string) bool {
 	updated := false
 
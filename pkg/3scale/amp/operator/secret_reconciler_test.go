@@ -86,6 +86,38 @@ func TestDefaultsOnlySecretReconcilerNoUpdateNeeded(t *testing.T) {
 	}
 }
 
+func TestFieldsSecretReconciler(t *testing.T) {
+	fieldsSecretReconciler := NewFieldsSecretReconciler("a2")
+	desiredSecret := &v1.Secret{
+		StringData: map[string]string{
+			"a1": "a1Value",
+			"a2": "a2Value",
+		},
+	}
+	existingSecret := &v1.Secret{
+		StringData: map[string]string{
+			"a2": "other_a2_value",
+			"a3": "a3Value",
+		},
+	}
+	existingSecret.Data = helper.GetSecretDataFromStringData(existingSecret.StringData)
+	if !fieldsSecretReconciler.IsUpdateNeeded(desiredSecret, existingSecret) {
+		t.Fatal("when fields differ, reconciler reported no update needed")
+	}
+
+	if existingSecret.StringData["a1"] != "a1Value" {
+		t.Fatalf("existingSecret a1 data not expected. Expected: 'a1Value', got: %s", existingSecret.StringData["a1"])
+	}
+
+	if existingSecret.StringData["a2"] != "a2Value" {
+		t.Fatalf("existingSecret a2 data not expected. Expected: 'a2Value', got: %s", existingSecret.StringData["a2"])
+	}
+
+	if existingSecret.StringData["a3"] != "a3Value" {
+		t.Fatalf("existingSecret a3 data not expected. Expected: 'a3Value', got: %s", existingSecret.StringData["a3"])
+	}
+}
+
 func TestSecretBaseReconcilerCreate(t *testing.T) {
 	var (
 		name      = "example-apimanager"
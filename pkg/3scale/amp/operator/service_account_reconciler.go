@@ -70,6 +70,12 @@ func (r *ServiceAccountBaseReconciler) isUpdateNeeded(desired, existing *v1.Serv
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -83,3 +89,31 @@ func NewCreateOnlyServiceAccountReconciler() *CreateOnlyServiceAccountReconciler
 func (r *CreateOnlyServiceAccountReconciler) IsUpdateNeeded(desired, existing *v1.ServiceAccount) bool {
 	return false
 }
+
+// ImagePullSecretsServiceAccountReconciler adds the desired image pull
+// secrets missing from the existing service account. Secrets linked by the
+// cluster, or by the user, are kept
+type ImagePullSecretsServiceAccountReconciler struct {
+}
+
+func NewImagePullSecretsServiceAccountReconciler() *ImagePullSecretsServiceAccountReconciler {
+	return &ImagePullSecretsServiceAccountReconciler{}
+}
+
+func (r *ImagePullSecretsServiceAccountReconciler) IsUpdateNeeded(desired, existing *v1.ServiceAccount) bool {
+	updated := false
+	for _, desiredSecret := range desired.ImagePullSecrets {
+		found := false
+		for _, existingSecret := range existing.ImagePullSecrets {
+			if existingSecret.Name == desiredSecret.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing.ImagePullSecrets = append(existing.ImagePullSecrets, desiredSecret)
+			updated = true
+		}
+	}
+	return updated
+}
@@ -240,3 +240,30 @@ func TestServiceAccountBaseReconcilerUpdateNeeded(t *testing.T) {
 		t.Fatalf("reconciled have reconciled data. Expected: 'mySecretAuth', got: %s", reconciled.ImagePullSecrets[0].Name)
 	}
 }
+
+func TestImagePullSecretsServiceAccountReconciler(t *testing.T) {
+	desired := &v1.ServiceAccount{
+		ImagePullSecrets: []v1.LocalObjectReference{{Name: "threescale-registry-auth"}, {Name: "mirror-auth"}},
+	}
+	existing := &v1.ServiceAccount{
+		ImagePullSecrets: []v1.LocalObjectReference{{Name: "threescale-registry-auth"}, {Name: "amp-dockercfg-x5r2k"}},
+	}
+
+	reconciler := NewImagePullSecretsServiceAccountReconciler()
+	if !reconciler.IsUpdateNeeded(desired, existing) {
+		t.Fatal("missing image pull secret not added")
+	}
+	expected := []string{"threescale-registry-auth", "amp-dockercfg-x5r2k", "mirror-auth"}
+	if len(existing.ImagePullSecrets) != len(expected) {
+		t.Fatalf("unexpected image pull secrets: %v", existing.ImagePullSecrets)
+	}
+	for idx, name := range expected {
+		if existing.ImagePullSecrets[idx].Name != name {
+			t.Errorf("image pull secret %d (%s) not the expected (%s)", idx, existing.ImagePullSecrets[idx].Name, name)
+		}
+	}
+
+	if reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("unexpected update with all the image pull secrets linked")
+	}
+}
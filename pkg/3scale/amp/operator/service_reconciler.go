@@ -3,8 +3,10 @@ package operator
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/go-logr/logr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -70,6 +72,12 @@ func (r *ServiceBaseReconciler) isUpdateNeeded(desired, existing *v1.Service) (b
 	updatedTmp = r.reconciler.IsUpdateNeeded(desired, existing)
 	updated = updated || updatedTmp
 
+	updatedTmp, err = r.correctDrift(desired, existing)
+	if err != nil {
+		return false, err
+	}
+	updated = updated || updatedTmp
+
 	return updated, nil
 }
 
@@ -83,3 +91,33 @@ func NewCreateOnlySvcReconciler() *CreateOnlySvcReconciler {
 func (r *CreateOnlySvcReconciler) IsUpdateNeeded(desired, existing *v1.Service) bool {
 	return false
 }
+
+// PortsSvcReconciler reconciles the ports of Services. The node ports
+// assigned to the existing ports are kept
+type PortsSvcReconciler struct {
+	logger logr.Logger
+}
+
+func NewPortsSvcReconciler(logger logr.Logger) *PortsSvcReconciler {
+	return &PortsSvcReconciler{logger: logger}
+}
+
+func (r *PortsSvcReconciler) IsUpdateNeeded(desired, existing *v1.Service) bool {
+	desiredPorts := make([]v1.ServicePort, len(desired.Spec.Ports))
+	for idx, desiredPort := range desired.Spec.Ports {
+		desiredPorts[idx] = desiredPort
+		for _, existingPort := range existing.Spec.Ports {
+			if existingPort.Name == desiredPort.Name && desiredPort.NodePort == 0 {
+				desiredPorts[idx].NodePort = existingPort.NodePort
+			}
+		}
+	}
+
+	if reflect.DeepEqual(desiredPorts, existing.Spec.Ports) {
+		return false
+	}
+
+	r.logger.Info(fmt.Sprintf("%s spec.ports differ", ObjectInfo(desired)))
+	existing.Spec.Ports = desiredPorts
+	return true
+}
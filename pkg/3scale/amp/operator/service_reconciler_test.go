@@ -235,3 +235,39 @@ func TestServiceBaseReconcilerUpdateNeeded(t *testing.T) {
 		t.Fatalf("reconciled have reconciled data. Expected: 4000, got: %d", reconciled.Spec.Ports[0].Port)
 	}
 }
+
+func TestPortsSvcReconciler(t *testing.T) {
+	log := logf.Log.WithName("operator_test")
+	desired := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicast-production"},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "gateway", Protocol: v1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt(8080)},
+				{Name: "https", Protocol: v1.ProtocolTCP, Port: 8443, TargetPort: intstr.FromInt(8443)},
+			},
+		},
+	}
+	existing := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicast-production"},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "gateway", Protocol: v1.ProtocolTCP, Port: 8080, TargetPort: intstr.FromInt(8080), NodePort: 30080},
+			},
+		},
+	}
+
+	reconciler := NewPortsSvcReconciler(log)
+	if !reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("added service port not reconciled")
+	}
+	if len(existing.Spec.Ports) != 2 || existing.Spec.Ports[1].Name != "https" {
+		t.Errorf("unexpected reconciled service ports: %v", existing.Spec.Ports)
+	}
+	if existing.Spec.Ports[0].NodePort != 30080 {
+		t.Errorf("existing node port not kept: %v", existing.Spec.Ports[0])
+	}
+
+	if reconciler.IsUpdateNeeded(desired, existing) {
+		t.Error("unchanged service ports reconciled")
+	}
+}
@@ -1,7 +1,9 @@
 package operator
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"strconv"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
@@ -27,9 +29,16 @@ func (o *OperatorSystemOptionsProvider) GetSystemOptions() (*component.SystemOpt
 		return nil, err
 	}
 
+	err = o.setDatastoreTLSOptions(&optProv)
+	if err != nil {
+		return nil, err
+	}
+
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 	o.setFileStorageOptions(&optProv)
 	o.setReplicas(&optProv)
+	optProv.MetricsEnabled(o.APIManagerSpec.Monitoring.IsEnabled())
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -82,6 +91,34 @@ func (o *OperatorSystemOptionsProvider) setSecretBasedOptions(builder *component
 	return nil
 }
 
+func (o *OperatorSystemOptionsProvider) setDatastoreTLSOptions(builder *component.SystemOptionsBuilder) error {
+	databaseTLS, err := systemDatabaseTLSOptions(o.APIManagerSpec, o.Client, o.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to create System Database TLS options - %s", err)
+	}
+	if databaseTLS != nil {
+		builder.DatabaseTLS(*databaseTLS)
+	}
+
+	redisTLS, err := systemRedisTLSOptions(o.APIManagerSpec, o.Client, o.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to create System Redis TLS options - %s", err)
+	}
+	if redisTLS != nil {
+		builder.RedisTLS(*redisTLS)
+	}
+
+	backendRedisTLS, err := backendRedisTLSOptions(o.APIManagerSpec, o.Client, o.Namespace)
+	if err != nil {
+		return fmt.Errorf("unable to create Backend Redis TLS options - %s", err)
+	}
+	if backendRedisTLS != nil {
+		builder.BackendRedisTLS(*backendRedisTLS)
+	}
+
+	return nil
+}
+
 func (o *OperatorSystemOptionsProvider) setSystemMemcachedOptions(builder *component.SystemOptionsBuilder) error {
 	currSecret, err := helper.GetSecret(component.SystemSecretSystemMemcachedSecretName, o.Namespace, o.Client)
 
@@ -141,6 +178,23 @@ func (o *OperatorSystemOptionsProvider) setSystemRedisOptions(builder *component
 	builder.RedisNamespace(helper.GetSecretDataValue(secretData, component.SystemSecretSystemRedisNamespace))
 	builder.MessageBusRedisNamespace(helper.GetSecretDataValue(secretData, component.SystemSecretSystemRedisMessageBusRedisNamespace))
 
+	// The operator managed sentinels override the secret values
+	if o.APIManagerSpec.Redis.IsSentinelEnabled() {
+		sentinelHosts, err := redisSentinelHosts(o.APIManagerSpec)
+		if err != nil {
+			return err
+		}
+		redisURL := component.RedisSentinelSystemURL
+		messageBusURL := component.RedisSentinelMessageBusURL
+		sentinelRole := component.RedisSentinelRole
+		builder.RedisURL(&redisURL)
+		builder.RedisSentinelHosts(&sentinelHosts)
+		builder.RedisSentinelRole(&sentinelRole)
+		builder.MessageBusRedisURL(&messageBusURL)
+		builder.MessageBusRedisSentinelHosts(&sentinelHosts)
+		builder.MessageBusRedisSentinelRole(&sentinelRole)
+	}
+
 	return nil
 }
 
@@ -207,6 +261,15 @@ func (o *OperatorSystemOptionsProvider) setSystemMasterApicastOptions(builder *c
 }
 
 func (o *OperatorSystemOptionsProvider) setSystemSMTPOptions(builder *component.SystemOptionsBuilder) error {
+	if o.APIManagerSpec.System.SMTPSpec != nil {
+		smtpSecretOptions, err := o.systemSMTPSpecOptions(o.APIManagerSpec.System.SMTPSpec)
+		if err != nil {
+			return err
+		}
+		builder.SystemSMTPSecretOptions(*smtpSecretOptions)
+		return nil
+	}
+
 	currSecret, err := helper.GetSecret(component.SystemSecretSystemSMTPSecretName, o.Namespace, o.Client)
 	if err != nil && !errors.IsNotFound(err) {
 		return err
@@ -229,13 +292,85 @@ func (o *OperatorSystemOptionsProvider) setSystemSMTPOptions(builder *component.
 	return nil
 }
 
+// systemSMTPSpecOptions returns the SMTP settings set in the APIManager.
+// The credentials are read from the referenced secret
+func (o *OperatorSystemOptionsProvider) systemSMTPSpecOptions(smtpSpec *appsv1alpha1.SystemSMTPSpec) (*component.SystemSMTPSecretOptions, error) {
+	smtpSecretOptions := &component.SystemSMTPSecretOptions{
+		Address: smtpSpec.Address,
+	}
+	credentialsVersion := ""
+	if smtpSpec.Port != nil {
+		smtpSecretOptions.Port = strconv.Itoa(int(*smtpSpec.Port))
+	}
+	if smtpSpec.Domain != nil {
+		smtpSecretOptions.Domain = *smtpSpec.Domain
+	}
+	if smtpSpec.Authentication != nil {
+		smtpSecretOptions.Authentication = *smtpSpec.Authentication
+	}
+	if smtpSpec.OpenSSLVerifyMode != nil {
+		smtpSecretOptions.OpenSSLVerifyMode = *smtpSpec.OpenSSLVerifyMode
+	}
+
+	if smtpSpec.CredentialsSecretRef != nil {
+		secretName := smtpSpec.CredentialsSecretRef.Name
+		credentialsSecret, err := helper.GetSecret(secretName, o.Namespace, o.Client)
+		if err != nil {
+			return nil, err
+		}
+		username := helper.GetSecretDataValue(credentialsSecret.Data, component.SystemSecretSystemSMTPUserNameFieldName)
+		if username == nil {
+			return nil, fmt.Errorf("Secret field '%s' is required in secret '%s'", component.SystemSecretSystemSMTPUserNameFieldName, secretName)
+		}
+		password := helper.GetSecretDataValue(credentialsSecret.Data, component.SystemSecretSystemSMTPPasswordFieldName)
+		if password == nil {
+			return nil, fmt.Errorf("Secret field '%s' is required in secret '%s'", component.SystemSecretSystemSMTPPasswordFieldName, secretName)
+		}
+		smtpSecretOptions.Username = *username
+		smtpSecretOptions.Password = *password
+		credentialsVersion = fmt.Sprintf("%s/%s", secretName, credentialsSecret.ResourceVersion)
+	}
+
+	// The credentials are not hashed, the annotation is readable by
+	// anyone allowed to get the DeploymentConfig. Their changes are
+	// tracked by the resourceVersion of the referenced secret instead
+	smtpHash := sha256.New()
+	hashBinaryData(smtpHash, map[string][]byte{
+		component.SystemSecretSystemSMTPAddressFieldName:           []byte(smtpSecretOptions.Address),
+		component.SystemSecretSystemSMTPAuthenticationFieldName:    []byte(smtpSecretOptions.Authentication),
+		component.SystemSecretSystemSMTPDomainFieldName:            []byte(smtpSecretOptions.Domain),
+		component.SystemSecretSystemSMTPOpenSSLVerifyModeFieldName: []byte(smtpSecretOptions.OpenSSLVerifyMode),
+		component.SystemSecretSystemSMTPPortFieldName:              []byte(smtpSecretOptions.Port),
+		"credentialsSecretRef":                                     []byte(credentialsVersion),
+	})
+	smtpSecretOptions.Hash = fmt.Sprintf("%x", smtpHash.Sum(nil))
+
+	return smtpSecretOptions, nil
+}
+
 func (o *OperatorSystemOptionsProvider) setResourceRequirementsOptions(b *component.SystemOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.AppMasterContainerResourceRequirements(v1.ResourceRequirements{})
-		b.AppProviderContainerResourceRequirements(v1.ResourceRequirements{})
-		b.AppDeveloperContainerResourceRequirements(v1.ResourceRequirements{})
-		b.SidekiqContainerResourceRequirements(v1.ResourceRequirements{})
-		b.SphinxContainerResourceRequirements(v1.ResourceRequirements{})
+	enabled := *o.APIManagerSpec.ResourceRequirementsEnabled
+	appSpec := o.APIManagerSpec.System.AppSpec
+	setResourceRequirements(b.AppMasterContainerResourceRequirements, enabled, appSpec.MasterContainerResources)
+	setResourceRequirements(b.AppProviderContainerResourceRequirements, enabled, appSpec.ProviderContainerResources)
+	setResourceRequirements(b.AppDeveloperContainerResourceRequirements, enabled, appSpec.DeveloperContainerResources)
+	setResourceRequirements(b.SidekiqContainerResourceRequirements, enabled, o.APIManagerSpec.System.SidekiqSpec.Resources)
+	var sphinxResources *v1.ResourceRequirements
+	if o.APIManagerSpec.System.SphinxSpec != nil {
+		sphinxResources = o.APIManagerSpec.System.SphinxSpec.Resources
+	}
+	setResourceRequirements(b.SphinxContainerResourceRequirements, enabled, sphinxResources)
+}
+
+func (o *OperatorSystemOptionsProvider) setPodPlacementOptions(b *component.SystemOptionsBuilder) {
+	if o.APIManagerSpec.System.AppSpec.Placement != nil {
+		b.AppPodPlacement(podPlacement(o.APIManagerSpec.System.AppSpec.Placement, "system-app"))
+	}
+	if o.APIManagerSpec.System.SidekiqSpec.Placement != nil {
+		b.SidekiqPodPlacement(podPlacement(o.APIManagerSpec.System.SidekiqSpec.Placement, "system-sidekiq"))
+	}
+	if o.APIManagerSpec.System.SphinxSpec != nil && o.APIManagerSpec.System.SphinxSpec.Placement != nil {
+		b.SphinxPodPlacement(podPlacement(o.APIManagerSpec.System.SphinxSpec.Placement, "system-sphinx"))
 	}
 }
 
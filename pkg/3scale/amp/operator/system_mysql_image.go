@@ -15,9 +15,9 @@ func (o *OperatorSystemMySQLImageOptionsProvider) GetSystemMySQLImageOptions() (
 	if o.APIManagerSpec.System.DatabaseSpec != nil &&
 		o.APIManagerSpec.System.DatabaseSpec.MySQL != nil &&
 		o.APIManagerSpec.System.DatabaseSpec.MySQL.Image != nil {
-		optProv.Image(*o.APIManagerSpec.System.DatabaseSpec.MySQL.Image)
+		optProv.Image(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.System.DatabaseSpec.MySQL.Image))
 	} else {
-		optProv.Image(SystemMySQLImageURL())
+		optProv.Image(mirroredImageURL(o.APIManagerSpec, SystemMySQLImageURL()))
 	}
 	optProv.InsecureImportPolicy(*o.APIManagerSpec.ImageStreamTagImportInsecure)
 
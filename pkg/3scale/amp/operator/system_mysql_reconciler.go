@@ -7,6 +7,25 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+type SystemMySQLDCReconciler struct {
+	BaseAPIManagerLogicReconciler
+}
+
+func NewSystemMySQLDCReconciler(baseAPIManagerLogicReconciler BaseAPIManagerLogicReconciler) *SystemMySQLDCReconciler {
+	return &SystemMySQLDCReconciler{
+		BaseAPIManagerLogicReconciler: baseAPIManagerLogicReconciler,
+	}
+}
+
+func (r *SystemMySQLDCReconciler) IsUpdateNeeded(desired, existing *appsv1.DeploymentConfig) bool {
+	update := false
+
+	tmpUpdate := DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	return update
+}
+
 type SystemMySQLReconciler struct {
 	BaseAPIManagerLogicReconciler
 }
@@ -69,7 +88,7 @@ func (r *SystemMySQLReconciler) systemMySQL() (*component.SystemMysql, error) {
 }
 
 func (r *SystemMySQLReconciler) reconcileSystemMySQLDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
-	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlyDCReconciler())
+	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewSystemMySQLDCReconciler(r.BaseAPIManagerLogicReconciler))
 	return reconciler.Reconcile(desiredDeploymentConfig)
 }
 
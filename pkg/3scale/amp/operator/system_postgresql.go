@@ -22,6 +22,7 @@ func (o *OperatorSystemPostgreSQLOptionsProvider) GetSystemPostgreSQLOptions() (
 	}
 
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -107,7 +108,20 @@ func (o *OperatorSystemPostgreSQLOptionsProvider) systemDatabaseURLIsValid(rawUR
 }
 
 func (o *OperatorSystemPostgreSQLOptionsProvider) setResourceRequirementsOptions(b *component.SystemPostgreSQLOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.ContainerResourceRequirements(v1.ResourceRequirements{})
+	var resources *v1.ResourceRequirements
+	if o.APIManagerSpec.System != nil &&
+		o.APIManagerSpec.System.DatabaseSpec != nil &&
+		o.APIManagerSpec.System.DatabaseSpec.PostgreSQL != nil {
+		resources = o.APIManagerSpec.System.DatabaseSpec.PostgreSQL.Resources
+	}
+	setResourceRequirements(b.ContainerResourceRequirements, *o.APIManagerSpec.ResourceRequirementsEnabled, resources)
+}
+
+func (o *OperatorSystemPostgreSQLOptionsProvider) setPodPlacementOptions(b *component.SystemPostgreSQLOptionsBuilder) {
+	if o.APIManagerSpec.System != nil &&
+		o.APIManagerSpec.System.DatabaseSpec != nil &&
+		o.APIManagerSpec.System.DatabaseSpec.PostgreSQL != nil &&
+		o.APIManagerSpec.System.DatabaseSpec.PostgreSQL.Placement != nil {
+		b.PodPlacement(podPlacement(o.APIManagerSpec.System.DatabaseSpec.PostgreSQL.Placement, "system-postgresql"))
 	}
 }
@@ -14,9 +14,9 @@ func (o *OperatorSystemPostgreSQLImageOptionsProvider) GetSystemPostgreSQLImageO
 	if o.APIManagerSpec.System.DatabaseSpec != nil &&
 		o.APIManagerSpec.System.DatabaseSpec.PostgreSQL != nil &&
 		o.APIManagerSpec.System.DatabaseSpec.PostgreSQL.Image != nil {
-		optProv.Image(*o.APIManagerSpec.System.DatabaseSpec.PostgreSQL.Image)
+		optProv.Image(mirroredImageURL(o.APIManagerSpec, *o.APIManagerSpec.System.DatabaseSpec.PostgreSQL.Image))
 	} else {
-		optProv.Image(SystemPostgreSQLImageURL())
+		optProv.Image(mirroredImageURL(o.APIManagerSpec, SystemPostgreSQLImageURL()))
 	}
 	optProv.InsecureImportPolicy(*o.APIManagerSpec.ImageStreamTagImportInsecure)
 
@@ -29,6 +29,9 @@ func (r *SystemSphinxDCReconciler) IsUpdateNeeded(desired, existing *appsv1.Depl
 	tmpUpdate := DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = DeploymentConfigReconcileDatastoreTLS(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -51,6 +54,15 @@ func (r *SystemSidekiqDCReconciler) IsUpdateNeeded(desired, existing *appsv1.Dep
 	tmpUpdate = DeploymentConfigReconcileContainerResources(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = DeploymentConfigReconcileDatastoreTLS(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcilePodTemplateAnnotation(desired, existing, component.SystemSMTPHashAnnotation, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcileContainerPorts(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -97,6 +109,12 @@ func (r *SystemAppDCReconciler) IsUpdateNeeded(desired, existing *appsv1.Deploym
 		}
 	}
 
+	tmpUpdate = DeploymentConfigReconcileDatastoreTLS(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
+	tmpUpdate = DeploymentConfigReconcilePodTemplateAnnotation(desired, existing, component.SystemSMTPHashAnnotation, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -236,6 +254,26 @@ func (r *SystemReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	err = r.reconcileIngress(system.MasterIngress())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileIngress(system.ProviderIngress())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileIngress(system.DeveloperIngress())
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = r.reconcileHorizontalPodAutoscaler(system.AppDeploymentConfig(), r.apiManager.Spec.System.AppSpec.Autoscaling)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -308,7 +346,8 @@ func (r *SystemReconciler) reconcileMemcachedService(desiredService *v1.Service)
 }
 
 func (r *SystemReconciler) reconcileAppDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
-	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewSystemAppDCReconciler(r.BaseAPIManagerLogicReconciler))
+	dcReconciler := NewAutoscalingDCReconciler(r.apiManager.Spec.System.AppSpec.Autoscaling, NewSystemAppDCReconciler(r.BaseAPIManagerLogicReconciler))
+	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, dcReconciler)
 	return reconciler.Reconcile(desiredDeploymentConfig)
 }
 
@@ -333,7 +372,20 @@ func (r *SystemReconciler) reconcileEnvironmentConfigMap(desiredConfigMap *v1.Co
 }
 
 func (r *SystemReconciler) reconcileSMTPSecret(desiredSecret *v1.Secret) error {
-	reconciler := NewSecretBaseReconciler(r.BaseAPIManagerLogicReconciler, NewDefaultsOnlySecretReconciler())
+	var secretReconciler SecretReconciler = NewDefaultsOnlySecretReconciler()
+	if r.apiManager.Spec.System.SMTPSpec != nil {
+		// The APIManager SMTP settings take precedence over the secret ones
+		secretReconciler = NewFieldsSecretReconciler(
+			component.SystemSecretSystemSMTPAddressFieldName,
+			component.SystemSecretSystemSMTPUserNameFieldName,
+			component.SystemSecretSystemSMTPPasswordFieldName,
+			component.SystemSecretSystemSMTPDomainFieldName,
+			component.SystemSecretSystemSMTPPortFieldName,
+			component.SystemSecretSystemSMTPAuthenticationFieldName,
+			component.SystemSecretSystemSMTPOpenSSLVerifyModeFieldName,
+		)
+	}
+	reconciler := NewSecretBaseReconciler(r.BaseAPIManagerLogicReconciler, secretReconciler)
 	return reconciler.Reconcile(desiredSecret)
 }
 
@@ -343,7 +395,18 @@ func (r *SystemReconciler) reconcileEventsHookSecret(desiredSecret *v1.Secret) e
 }
 
 func (r *SystemReconciler) reconcileRedisSecret(desiredSecret *v1.Secret) error {
-	reconciler := NewSecretBaseReconciler(r.BaseAPIManagerLogicReconciler, NewDefaultsOnlySecretReconciler())
+	var secretReconciler SecretReconciler = NewDefaultsOnlySecretReconciler()
+	if r.apiManager.IsRedisSentinelEnabled() {
+		secretReconciler = NewFieldsSecretReconciler(
+			component.SystemSecretSystemRedisURLFieldName,
+			component.SystemSecretSystemRedisSentinelHosts,
+			component.SystemSecretSystemRedisSentinelRole,
+			component.SystemSecretSystemRedisMessageBusRedisURLFieldName,
+			component.SystemSecretSystemRedisMessageBusSentinelHosts,
+			component.SystemSecretSystemRedisMessageBusSentinelRole,
+		)
+	}
+	reconciler := NewSecretBaseReconciler(r.BaseAPIManagerLogicReconciler, secretReconciler)
 	return reconciler.Reconcile(desiredSecret)
 }
 
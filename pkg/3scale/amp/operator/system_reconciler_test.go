@@ -11,6 +11,7 @@ import (
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -100,3 +101,113 @@ func TestSystemReconcilerCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestSystemReconcilerSMTP(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+		log       = logf.Log.WithName("operator_test")
+		port      = int32(587)
+		plain     = "plain"
+	)
+	apimanager := basicApimanagerSpecTestSystemOptions(name, namespace)
+	apimanager.Spec.System.SMTPSpec = &appsv1alpha1.SystemSMTPSpec{
+		Address:              "smtp.example.com",
+		Port:                 &port,
+		Authentication:       &plain,
+		CredentialsSecretRef: &v1.LocalObjectReference{Name: "smtp-credentials"},
+	}
+	credentialsSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "smtp-credentials", Namespace: namespace, ResourceVersion: "1"},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSMTPUserNameFieldName: []byte("user"),
+			component.SystemSecretSystemSMTPPasswordFieldName: []byte("password"),
+		},
+	}
+	// system-smtp secret created with empty defaults
+	smtpSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemSMTPSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemSMTPAddressFieldName: []byte(""),
+			component.SystemSecretSystemSMTPPortFieldName:    []byte(""),
+		},
+	}
+	objs := []runtime.Object{apimanager, credentialsSecret, smtpSecret}
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cl := fake.NewFakeClient(objs...)
+	clientAPIReader := fake.NewFakeClient(objs...)
+	baseLogicReconciler := NewBaseLogicReconciler(NewBaseReconciler(cl, clientAPIReader, s, log))
+	reconciler := NewSystemReconciler(NewBaseAPIManagerLogicReconciler(baseLogicReconciler, apimanager))
+
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &v1.Secret{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: component.SystemSecretSystemSMTPSecretName, Namespace: namespace}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := map[string]string{
+		component.SystemSecretSystemSMTPAddressFieldName:        "smtp.example.com",
+		component.SystemSecretSystemSMTPPortFieldName:           "587",
+		component.SystemSecretSystemSMTPAuthenticationFieldName: "plain",
+		component.SystemSecretSystemSMTPUserNameFieldName:       "user",
+		component.SystemSecretSystemSMTPPasswordFieldName:       "password",
+	}
+	for field, expected := range expectedFields {
+		// The fake client does not merge StringData into Data
+		if secret.StringData[field] != expected {
+			t.Errorf("system-smtp field %s. Expected: '%s'. Got: '%s'", field, expected, secret.StringData[field])
+		}
+	}
+
+	smtpHash := func(dcName string) string {
+		dc := &appsv1.DeploymentConfig{}
+		err := cl.Get(context.TODO(), types.NamespacedName{Name: dcName, Namespace: namespace}, dc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dc.Spec.Template.Annotations[component.SystemSMTPHashAnnotation]
+	}
+	appHash := smtpHash("system-app")
+	if appHash == "" || smtpHash("system-sidekiq") != appHash {
+		t.Fatalf("SMTP hash annotation not set. system-app: '%s'. system-sidekiq: '%s'", appHash, smtpHash("system-sidekiq"))
+	}
+
+	port = 2525
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smtpHash("system-app") == appHash || smtpHash("system-sidekiq") == appHash {
+		t.Error("SMTP settings change did not roll out system-app and system-sidekiq")
+	}
+
+	// The fake client does not bump the resourceVersion
+	appHash = smtpHash("system-app")
+	credentialsSecret.Data[component.SystemSecretSystemSMTPPasswordFieldName] = []byte("newpassword")
+	credentialsSecret.ResourceVersion = "2"
+	err = cl.Update(context.TODO(), credentialsSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = reconciler.Reconcile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if smtpHash("system-app") == appHash || smtpHash("system-sidekiq") == appHash {
+		t.Error("SMTP credentials change did not roll out system-app and system-sidekiq")
+	}
+}
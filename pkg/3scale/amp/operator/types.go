@@ -17,6 +17,10 @@ type OperatorRedisOptionsProvider struct {
 	APIManagerSpec *appsv1alpha1.APIManagerSpec
 }
 
+type OperatorRedisSentinelOptionsProvider struct {
+	APIManagerSpec *appsv1alpha1.APIManagerSpec
+}
+
 type OperatorBackendOptionsProvider struct {
 	APIManagerSpec *appsv1alpha1.APIManagerSpec
 	Namespace      string
@@ -72,3 +76,26 @@ type OperatorHighAvailabilityOptionsProvider struct {
 	Namespace      string
 	Client         k8sclient.Client
 }
+
+type OperatorMonitoringOptionsProvider struct {
+	APIManagerSpec *appsv1alpha1.APIManagerSpec
+	Namespace      string
+}
+
+type OperatorStandaloneApicastOptionsProvider struct {
+	APIcast *appsv1alpha1.APIcast
+	Client  k8sclient.Client
+}
+
+// OperatorBackupOptionsProvider provides the options of the backup and
+// restore Jobs. APIManager is nil when restoring the secrets of an
+// APIManager not deployed yet
+type OperatorBackupOptionsProvider struct {
+	Name                  string
+	APIManager            *appsv1alpha1.APIManager
+	PersistentVolumeClaim string
+	StorageClassName      *string
+	VolumeSize            *string
+	Namespace             string
+	Client                k8sclient.Client
+}
@@ -1,13 +1,67 @@
 package operator
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/version"
 	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// UpgradeStep is a change performed on the objects of an APIManager when
+// upgrading it between operator versions
+type UpgradeStep struct {
+	// Name identifies the step in the upgrade status
+	Name string
+	// Preflight checks the step can be run. An error stops the upgrade
+	// before the step changes any object. Optional
+	Preflight func(u *UpgradeApiManager) error
+	// Run performs the step. Steps are run again until they neither fail
+	// nor requeue, so they must be idempotent
+	Run func(u *UpgradeApiManager) (reconcile.Result, error)
+}
+
+// UpgradePath is the ordered list of steps upgrading an APIManager from an
+// operator minor version to the next one
+type UpgradePath struct {
+	// Minor version upgraded from, like "0.5"
+	From string
+	// Minor version upgraded to, like "0.6"
+	To    string
+	Steps []UpgradeStep
+}
+
+// UpgradePaths is the registry of the supported upgrades between minor
+// versions. Versions can not be skipped: an APIManager is only upgraded
+// from the previous minor version
+var UpgradePaths = []UpgradePath{
+	{
+		From: "0.5",
+		To:   "0.6",
+		Steps: []UpgradeStep{
+			imageStreamsUpgradeStep,
+			redisSecretsSentinelKeysUpgradeStep,
+			backendRedisSentinelEnvVarsUpgradeStep,
+			systemDatabaseMigrationUpgradeStep,
+		},
+	},
+}
+
+// PatchUpgradeSteps upgrade an APIManager between patch versions of the
+// same minor version
+var PatchUpgradeSteps = []UpgradeStep{
+	imageStreamsUpgradeStep,
+	systemDatabaseMigrationUpgradeStep,
+}
+
 type UpgradeApiManager struct {
 	Cr              *appsv1alpha1.APIManager
 	Client          client.Client
@@ -16,15 +70,159 @@ type UpgradeApiManager struct {
 	Scheme          *runtime.Scheme
 }
 
+// Upgrade runs the steps upgrading the APIManager from the operator version
+// of its annotation to the running operator version. The steps completed
+// are recorded in the status, so an upgrade requeued or failed is resumed
+// from the first step not completed
 func (u *UpgradeApiManager) Upgrade() (reconcile.Result, error) {
-	res, err := u.upgradeImages()
-	if res.Requeue || err != nil {
-		return res, err
+	fromVersion := u.Cr.Annotations[appsv1alpha1.OperatorVersionAnnotation]
+	u.initUpgradeStatus(fromVersion)
+
+	steps, err := UpgradeSteps(fromVersion, version.Version)
+	if err != nil {
+		return reconcile.Result{}, u.stopUpgrade(err)
+	}
+
+	if u.Cr.IsUpgradeSafetyGateEnabled() {
+		res, err := u.safetyGate()
+		if err != nil {
+			return reconcile.Result{}, u.stopUpgrade(err)
+		}
+		if res.Requeue {
+			return res, nil
+		}
+	}
+
+	for _, step := range steps {
+		if u.isStepCompleted(step.Name) {
+			continue
+		}
+
+		if step.Preflight != nil {
+			err = step.Preflight(u)
+			if err != nil {
+				return reconcile.Result{}, u.stopUpgrade(fmt.Errorf("upgrade step %s preflight check failed: %v", step.Name, err))
+			}
+		}
+
+		u.Cr.Status.Upgrade.CurrentStep = step.Name
+		res, err := step.Run(u)
+		if err != nil {
+			return reconcile.Result{}, u.stopUpgrade(fmt.Errorf("upgrade step %s failed: %v", step.Name, err))
+		}
+		if res.Requeue {
+			u.Logger.Info(fmt.Sprintf("Upgrade step %s not finished", step.Name))
+			u.Cr.Status.Upgrade.Message = ""
+			return res, u.updateUpgradeStatus()
+		}
+
+		u.Logger.Info(fmt.Sprintf("Upgrade step %s completed", step.Name))
+		u.Cr.Status.Upgrade.CompletedSteps = append(u.Cr.Status.Upgrade.CompletedSteps, step.Name)
+		u.Cr.Status.Upgrade.CurrentStep = ""
+		u.Cr.Status.Upgrade.Message = ""
+		err = u.updateUpgradeStatus()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if u.Cr.Status.Upgrade.CompletionTime == nil {
+		now := metav1.Now()
+		u.Cr.Status.Upgrade.CompletionTime = &now
+		err = u.updateUpgradeStatus()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	return reconcile.Result{}, nil
 }
 
+// UpgradeSteps returns the ordered steps upgrading an APIManager between
+// the given operator versions. Downgrades and upgrades skipping minor
+// versions are not supported
+func UpgradeSteps(fromVersion, toVersion string) ([]UpgradeStep, error) {
+	fromMajor, fromMinor, err := parseOperatorVersion(fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	toMajor, toMinor, err := parseOperatorVersion(toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromMajor == toMajor && fromMinor == toMinor {
+		return PatchUpgradeSteps, nil
+	}
+
+	from := fmt.Sprintf("%d.%d", fromMajor, fromMinor)
+	to := fmt.Sprintf("%d.%d", toMajor, toMinor)
+	for _, path := range UpgradePaths {
+		if path.From == from && path.To == to {
+			return path.Steps, nil
+		}
+	}
+
+	return nil, fmt.Errorf("upgrade from operator version %s to %s is not supported. Upgrade through each minor version", fromVersion, toVersion)
+}
+
+// parseOperatorVersion returns the major and minor numbers of a version
+// like "0.6.0"
+func parseOperatorVersion(v string) (int, int, error) {
+	parts := strings.Split(strings.TrimPrefix(v, "v"), ".")
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid operator version '%s'", v)
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid operator version '%s'", v)
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid operator version '%s'", v)
+	}
+	return major, minor, nil
+}
+
+// initUpgradeStatus starts recording the progress of the upgrade from
+// fromVersion unless the status already records it
+func (u *UpgradeApiManager) initUpgradeStatus(fromVersion string) {
+	upgradeStatus := u.Cr.Status.Upgrade
+	if upgradeStatus != nil && upgradeStatus.FromVersion == fromVersion && upgradeStatus.ToVersion == version.Version {
+		return
+	}
+
+	u.Cr.Status.Upgrade = &appsv1alpha1.APIManagerUpgradeStatus{
+		FromVersion: fromVersion,
+		ToVersion:   version.Version,
+		FromRelease: u.Cr.Annotations[appsv1alpha1.ThreescaleVersionAnnotation],
+		ToRelease:   product.ThreescaleRelease,
+	}
+}
+
+func (u *UpgradeApiManager) isStepCompleted(name string) bool {
+	for _, completed := range u.Cr.Status.Upgrade.CompletedSteps {
+		if completed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// stopUpgrade records the reason the upgrade can not go on in the status
+func (u *UpgradeApiManager) stopUpgrade(upgradeErr error) error {
+	u.Cr.Status.Upgrade.Message = upgradeErr.Error()
+	err := u.updateUpgradeStatus()
+	if err != nil {
+		u.Logger.Error(err, "Failed to update the upgrade status")
+	}
+	return upgradeErr
+}
+
+func (u *UpgradeApiManager) updateUpgradeStatus() error {
+	return u.Client.Status().Update(context.TODO(), u.Cr)
+}
+
 func (u *UpgradeApiManager) upgradeImages() (reconcile.Result, error) {
 	res, err := u.upgradeAMPImageStreams()
 	if res.Requeue || err != nil {
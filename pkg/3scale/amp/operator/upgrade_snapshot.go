@@ -0,0 +1,406 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/version"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// UpgradeSnapshotLabel is set on the snapshot ConfigMap and Secret to
+	// the operator version they were taken with
+	UpgradeSnapshotLabel = "apps.3scale.net/upgrade-snapshot"
+
+	// Delay between the checks of the upgrade approval annotation
+	upgradeApprovalRequeueDelay = time.Minute
+)
+
+// UpgradeSnapshotName returns the name of the ConfigMap and the Secret with
+// the snapshot of the objects of the APIManager taken before upgrading from
+// fromVersion
+func UpgradeSnapshotName(cr *appsv1alpha1.APIManager, fromVersion string) string {
+	return fmt.Sprintf("%s-upgrade-snapshot-%s", cr.Name, fromVersion)
+}
+
+// safetyGate snapshots the managed objects and waits for the upgrade
+// approval when required. Steps are not run until it neither fails nor
+// requeues
+func (u *UpgradeApiManager) safetyGate() (reconcile.Result, error) {
+	upgradeStatus := u.Cr.Status.Upgrade
+	if upgradeStatus.SnapshotName == "" {
+		name, err := u.snapshot()
+		if err != nil {
+			return reconcile.Result{}, fmt.Errorf("pre-upgrade snapshot failed: %v", err)
+		}
+		u.Logger.Info(fmt.Sprintf("Managed objects snapshotted in %s", name))
+		upgradeStatus.SnapshotName = name
+		err = u.updateUpgradeStatus()
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	if u.Cr.Spec.UpgradeSafetyGate.RequireApproval && u.Cr.Annotations[appsv1alpha1.UpgradeApprovedAnnotation] != version.Version {
+		message := fmt.Sprintf("Waiting for approval. Set the %s annotation to %s to upgrade", appsv1alpha1.UpgradeApprovedAnnotation, version.Version)
+		if upgradeStatus.Message != message {
+			upgradeStatus.Message = message
+			err := u.updateUpgradeStatus()
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		u.Logger.Info(message)
+		return reconcile.Result{Requeue: true, RequeueAfter: upgradeApprovalRequeueDelay}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// snapshot stores the objects managed by the operator in a ConfigMap and,
+// for the secrets, a Secret. Both named after the version upgraded from
+func (u *UpgradeApiManager) snapshot() (string, error) {
+	objects, err := u.managedObjects()
+	if err != nil {
+		return "", err
+	}
+
+	name := UpgradeSnapshotName(u.Cr, u.Cr.Status.Upgrade.FromVersion)
+	objectMeta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: u.Cr.Namespace,
+		Labels:    map[string]string{UpgradeSnapshotLabel: u.Cr.Status.Upgrade.FromVersion},
+	}
+	configMap := &v1.ConfigMap{ObjectMeta: *objectMeta.DeepCopy(), Data: map[string]string{}}
+	secret := &v1.Secret{ObjectMeta: *objectMeta.DeepCopy(), Data: map[string][]byte{}, Type: v1.SecretTypeOpaque}
+
+	for _, object := range objects {
+		key, content, err := u.snapshotEntry(object)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := object.(*v1.Secret); ok {
+			secret.Data[key] = content
+		} else {
+			configMap.Data[key] = string(content)
+		}
+	}
+
+	for _, snapshotObject := range []runtime.Object{configMap, secret} {
+		err = u.createSnapshotObject(snapshotObject)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+func (u *UpgradeApiManager) createSnapshotObject(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	err = controllerutil.SetControllerReference(u.Cr, accessor, u.Scheme)
+	if err != nil {
+		return err
+	}
+
+	err = u.Client.Create(context.TODO(), obj)
+	// A snapshot left by a previous attempt of the same upgrade is kept,
+	// objects were not mutated yet
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// managedObjects returns the DeploymentConfigs, ConfigMaps, Secrets and
+// ImageStreams owned by the APIManager. Deployments and StatefulSets too
+// when deployed with them. Snapshots are excluded
+func (u *UpgradeApiManager) managedObjects() ([]runtime.Object, error) {
+	// SecretList is registered in the image.openshift.io group too, its
+	// kind cannot be guessed from the type. Listed as unstructured
+	secretList := &unstructured.UnstructuredList{}
+	secretList.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("SecretList"))
+
+	lists := []runtime.Object{&v1.ConfigMapList{}, secretList, &k8sappsv1.StatefulSetList{}}
+	if u.Cr.IsKubernetesDeploymentEnabled() {
+		lists = append(lists, &k8sappsv1.DeploymentList{})
+	} else {
+		lists = append(lists, &appsv1.DeploymentConfigList{}, &imagev1.ImageStreamList{})
+	}
+
+	objects := []runtime.Object{}
+	for _, list := range lists {
+		err := u.Client.List(context.TODO(), &client.ListOptions{Namespace: u.Cr.Namespace}, list)
+		if err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		if list == secretList {
+			items, err = u.typedObjects(items)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := accessor.GetLabels()[UpgradeSnapshotLabel]; ok {
+				continue
+			}
+			if isOwnedBy(accessor, u.Cr) {
+				objects = append(objects, item)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// typedObjects converts unstructured objects to the types registered in the
+// scheme
+func (u *UpgradeApiManager) typedObjects(items []runtime.Object) ([]runtime.Object, error) {
+	objects := []runtime.Object{}
+	for _, item := range items {
+		obj, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			objects = append(objects, item)
+			continue
+		}
+		object, err := u.Scheme.New(obj.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, object)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func isOwnedBy(object metav1.Object, cr *appsv1alpha1.APIManager) bool {
+	for _, ownerRef := range object.GetOwnerReferences() {
+		if ownerRef.UID == cr.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotEntry returns the key and the JSON content of the object in the
+// snapshot. Status and server populated metadata are dropped
+func (u *UpgradeApiManager) snapshotEntry(object runtime.Object) (string, []byte, error) {
+	gvk, err := apiutil.GVKForObject(object, u.Scheme)
+	if err != nil {
+		return "", nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(object)
+	if err != nil {
+		return "", nil, err
+	}
+
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetSelfLink("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s.%s.json", strings.ToLower(gvk.Kind), obj.GetName()), data, nil
+}
+
+// ReconcileRollback rolls the managed objects back to the snapshot when the
+// components are not available once the rollback timeout has passed since
+// the upgrade. Otherwise the upgrade is verified and not rolled back
+// anymore. Components are checked once the timeout has passed so the
+// rollouts triggered by the upgrade have started
+func (u *UpgradeApiManager) ReconcileRollback() (reconcile.Result, error) {
+	gateSpec := u.Cr.Spec.UpgradeSafetyGate
+	upgradeStatus := u.Cr.Status.Upgrade
+	if !u.Cr.IsUpgradeSafetyGateEnabled() || gateSpec.RollbackTimeoutSeconds == nil ||
+		upgradeStatus == nil || upgradeStatus.ToVersion != version.Version ||
+		upgradeStatus.SnapshotName == "" || upgradeStatus.CompletionTime == nil || upgradeStatus.Verified {
+		return reconcile.Result{}, nil
+	}
+
+	deadline := upgradeStatus.CompletionTime.Add(time.Duration(*gateSpec.RollbackTimeoutSeconds) * time.Second)
+	if remaining := time.Until(deadline); remaining > 0 {
+		return reconcile.Result{RequeueAfter: remaining}, nil
+	}
+
+	if upgradedComponentsAvailable(u.Cr.Status.Conditions) {
+		u.Logger.Info("Components available after the upgrade")
+		upgradeStatus.Verified = true
+		return reconcile.Result{}, u.updateUpgradeStatus()
+	}
+
+	err := u.Rollback()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{Requeue: true}, nil
+}
+
+// upgradedComponentsAvailable returns true when all the components are
+// available and no rollout is in progress
+func upgradedComponentsAvailable(conditions []appsv1alpha1.APIManagerCondition) bool {
+	return appsv1alpha1.IsConditionTrue(conditions, appsv1alpha1.APIManagerAvailable) &&
+		!appsv1alpha1.IsConditionTrue(conditions, appsv1alpha1.APIManagerProgressing)
+}
+
+// Rollback restores the managed objects from the snapshot and the version
+// annotations to the version upgraded from. The UpgradeRolledBackAnnotation
+// stops the upgrade from being retried until the annotation is removed
+func (u *UpgradeApiManager) Rollback() error {
+	upgradeStatus := u.Cr.Status.Upgrade
+	u.Logger.Info(fmt.Sprintf("Rolling back upgrade %s -> %s from snapshot %s", upgradeStatus.FromVersion, upgradeStatus.ToVersion, upgradeStatus.SnapshotName))
+
+	objects, err := u.snapshotObjects(upgradeStatus.SnapshotName)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		err = u.restoreObject(object)
+		if err != nil {
+			return err
+		}
+	}
+
+	u.Cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] = upgradeStatus.FromVersion
+	u.Cr.Annotations[appsv1alpha1.ThreescaleVersionAnnotation] = upgradeStatus.FromRelease
+	u.Cr.Annotations[appsv1alpha1.UpgradeRolledBackAnnotation] = upgradeStatus.ToVersion
+	err = u.Client.Update(context.TODO(), u.Cr)
+	if err != nil {
+		return err
+	}
+
+	// Steps are run again when the upgrade is retried. The snapshot is
+	// still valid, objects have been restored from it
+	u.Cr.Status.Upgrade = &appsv1alpha1.APIManagerUpgradeStatus{
+		FromVersion:  upgradeStatus.FromVersion,
+		ToVersion:    upgradeStatus.ToVersion,
+		FromRelease:  upgradeStatus.FromRelease,
+		ToRelease:    upgradeStatus.ToRelease,
+		SnapshotName: upgradeStatus.SnapshotName,
+		Message: fmt.Sprintf("Components unavailable %ds after the upgrade. Rolled back to snapshot %s. Remove the %s annotation to retry",
+			*u.Cr.Spec.UpgradeSafetyGate.RollbackTimeoutSeconds, upgradeStatus.SnapshotName, appsv1alpha1.UpgradeRolledBackAnnotation),
+	}
+	return u.updateUpgradeStatus()
+}
+
+// snapshotObjects decodes the objects of the snapshot ConfigMap and Secret
+func (u *UpgradeApiManager) snapshotObjects(name string) ([]runtime.Object, error) {
+	key := types.NamespacedName{Name: name, Namespace: u.Cr.Namespace}
+	configMap := &v1.ConfigMap{}
+	err := u.Client.Get(context.TODO(), key, configMap)
+	if err != nil {
+		return nil, err
+	}
+	secret := &v1.Secret{}
+	err = u.Client.Get(context.TODO(), key, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := map[string][]byte{}
+	for entryKey, content := range configMap.Data {
+		entries[entryKey] = []byte(content)
+	}
+	for entryKey, content := range secret.Data {
+		entries[entryKey] = content
+	}
+
+	// Restored in a stable order
+	keys := []string{}
+	for entryKey := range entries {
+		keys = append(keys, entryKey)
+	}
+	sort.Strings(keys)
+
+	objects := []runtime.Object{}
+	for _, entryKey := range keys {
+		obj := &unstructured.Unstructured{}
+		err = obj.UnmarshalJSON(entries[entryKey])
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s entry %s: %v", name, entryKey, err)
+		}
+		object, err := u.Scheme.New(obj.GroupVersionKind())
+		if err != nil {
+			return nil, err
+		}
+		err = runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, object)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s entry %s: %v", name, entryKey, err)
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+// restoreObject replaces the existing object with its snapshot, creating
+// it when deleted
+func (u *UpgradeApiManager) restoreObject(object runtime.Object) error {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+	gvk, err := apiutil.GVKForObject(object, u.Scheme)
+	if err != nil {
+		return err
+	}
+	existing, err := u.Scheme.New(gvk)
+	if err != nil {
+		return err
+	}
+
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: accessor.GetName(), Namespace: u.Cr.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return u.Client.Create(context.TODO(), object)
+		}
+		return err
+	}
+
+	existingAccessor, err := meta.Accessor(existing)
+	if err != nil {
+		return err
+	}
+	accessor.SetResourceVersion(existingAccessor.GetResourceVersion())
+	return u.Client.Update(context.TODO(), object)
+}
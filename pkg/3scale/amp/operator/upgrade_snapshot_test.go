@@ -0,0 +1,281 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/version"
+	appsv1 "github.com/openshift/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func snapshotTestObjects(namespace string) (*appsv1.DeploymentConfig, *v1.ConfigMap, *v1.Secret, *v1.ConfigMap) {
+	ownerReferences := []metav1.OwnerReference{{APIVersion: "apps.3scale.net/v1alpha1", Kind: "APIManager", Name: "example-apimanager", UID: "apimanager-uid"}}
+	dc := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-app", Namespace: namespace, OwnerReferences: ownerReferences},
+		Spec: appsv1.DeploymentConfigSpec{
+			Replicas: 1,
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "system-master", Image: "porta:old"}}},
+			},
+		},
+	}
+	configMap := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-environment", Namespace: namespace, OwnerReferences: ownerReferences},
+		Data:       map[string]string{"RAILS_ENV": "production"},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-seed", Namespace: namespace, OwnerReferences: ownerReferences},
+		Data:       map[string][]byte{"MASTER_PASSWORD": []byte("secret")},
+	}
+	notOwned := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-owned", Namespace: namespace},
+	}
+	return dc, configMap, secret, notOwned
+}
+
+// unstructuredListClient lists unstructured objects through the typed lists
+// of the fake client, which cannot decode unstructured lists
+type unstructuredListClient struct {
+	client.Client
+	scheme *runtime.Scheme
+}
+
+func (c *unstructuredListClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	unstructuredList, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return c.Client.List(ctx, opts, list)
+	}
+
+	gvk := unstructuredList.GroupVersionKind()
+	typedList, err := c.scheme.New(gvk)
+	if err != nil {
+		return err
+	}
+	itemGVK := gvk.GroupVersion().WithKind(strings.TrimSuffix(gvk.Kind, "List"))
+	typedOpts := *opts
+	typedOpts.Raw = &metav1.ListOptions{TypeMeta: metav1.TypeMeta{APIVersion: itemGVK.GroupVersion().String(), Kind: itemGVK.Kind}}
+	err = c.Client.List(ctx, &typedOpts, typedList)
+	if err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(typedList)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return err
+		}
+		obj := unstructured.Unstructured{Object: content}
+		obj.SetGroupVersionKind(itemGVK)
+		unstructuredList.Items = append(unstructuredList.Items, obj)
+	}
+	return nil
+}
+
+func testSnapshotUpgradeApiManager(t *testing.T, fromVersion string, objs ...runtime.Object) *UpgradeApiManager {
+	u := testUpgradeApiManager(t, fromVersion, objs...)
+	u.Client = &unstructuredListClient{Client: u.Client, scheme: u.Scheme}
+	return u
+}
+
+func gatedUpgradePaths() []UpgradePath {
+	major, minor, _ := parseOperatorVersion(version.Version)
+	return []UpgradePath{
+		{
+			From: fmt.Sprintf("%d.%d", major, minor-1),
+			To:   fmt.Sprintf("%d.%d", major, minor),
+			Steps: []UpgradeStep{
+				{Name: "noop", Run: func(u *UpgradeApiManager) (reconcile.Result, error) { return reconcile.Result{}, nil }},
+			},
+		},
+	}
+}
+
+func TestUpgradeSafetyGate(t *testing.T) {
+	defer func(paths []UpgradePath) { UpgradePaths = paths }(UpgradePaths)
+	UpgradePaths = gatedUpgradePaths()
+
+	namespace := "operator-unittest"
+	dc, configMap, secret, notOwned := snapshotTestObjects(namespace)
+	u := testSnapshotUpgradeApiManager(t, previousMinorVersion(t), dc, configMap, secret, notOwned)
+	u.Cr.Spec.UpgradeSafetyGate = &appsv1alpha1.UpgradeSafetyGateSpec{Enabled: true, RequireApproval: true}
+
+	res, err := u.Upgrade()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Requeue {
+		t.Fatal("upgrade should wait for approval")
+	}
+	if len(u.Cr.Status.Upgrade.CompletedSteps) != 0 {
+		t.Errorf("steps run before approval: %v", u.Cr.Status.Upgrade.CompletedSteps)
+	}
+
+	snapshotName := UpgradeSnapshotName(u.Cr, previousMinorVersion(t))
+	if u.Cr.Status.Upgrade.SnapshotName != snapshotName {
+		t.Errorf("snapshot name (%s) not the expected (%s)", u.Cr.Status.Upgrade.SnapshotName, snapshotName)
+	}
+
+	snapshotConfigMap := &v1.ConfigMap{}
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: snapshotName, Namespace: namespace}, snapshotConfigMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"deploymentconfig.system-app.json", "configmap.system-environment.json"} {
+		if _, ok := snapshotConfigMap.Data[key]; !ok {
+			t.Errorf("%s not in the snapshot ConfigMap", key)
+		}
+	}
+	if _, ok := snapshotConfigMap.Data["configmap.not-owned.json"]; ok {
+		t.Error("object not owned by the APIManager in the snapshot")
+	}
+	if _, ok := snapshotConfigMap.Data["secret.system-seed.json"]; ok {
+		t.Error("secret stored in the snapshot ConfigMap")
+	}
+
+	snapshotSecret := &v1.Secret{}
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: snapshotName, Namespace: namespace}, snapshotSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snapshotSecret.Data["secret.system-seed.json"]; !ok {
+		t.Error("secret not in the snapshot Secret")
+	}
+
+	u.Cr.Annotations[appsv1alpha1.UpgradeApprovedAnnotation] = version.Version
+	res, err = u.Upgrade()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue {
+		t.Error("approved upgrade should finish")
+	}
+	if u.Cr.Status.Upgrade.CompletionTime == nil {
+		t.Error("upgrade completion time not set")
+	}
+}
+
+func TestUpgradeRollback(t *testing.T) {
+	defer func(paths []UpgradePath) { UpgradePaths = paths }(UpgradePaths)
+	UpgradePaths = gatedUpgradePaths()
+
+	namespace := "operator-unittest"
+	fromVersion := previousMinorVersion(t)
+	dc, configMap, secret, notOwned := snapshotTestObjects(namespace)
+	u := testSnapshotUpgradeApiManager(t, fromVersion, dc, configMap, secret, notOwned)
+	timeout := int64(60)
+	u.Cr.Spec.UpgradeSafetyGate = &appsv1alpha1.UpgradeSafetyGateSpec{Enabled: true, RollbackTimeoutSeconds: &timeout}
+
+	_, err := u.Upgrade()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] = version.Version
+
+	// Objects mutated by the upgrade
+	existingDC := &appsv1.DeploymentConfig{}
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: "system-app", Namespace: namespace}, existingDC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existingDC.Spec.Template.Spec.Containers[0].Image = "porta:new"
+	err = u.Client.Update(context.TODO(), existingDC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = u.Client.Delete(context.TODO(), configMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Before the timeout
+	appsv1alpha1.SetCondition(&u.Cr.Status.Conditions, appsv1alpha1.APIManagerCondition{Type: appsv1alpha1.APIManagerAvailable, Status: v1.ConditionFalse})
+	res, err := u.ReconcileRollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue || res.RequeueAfter <= 0 {
+		t.Fatalf("rollback should be checked once the timeout passes: %v", res)
+	}
+
+	// Timeout passed with the components unavailable
+	completionTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	u.Cr.Status.Upgrade.CompletionTime = &completionTime
+	res, err = u.ReconcileRollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Requeue {
+		t.Fatal("rollback should requeue")
+	}
+
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: "system-app", Namespace: namespace}, existingDC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image := existingDC.Spec.Template.Spec.Containers[0].Image; image != "porta:old" {
+		t.Errorf("DeploymentConfig not rolled back: %s", image)
+	}
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: "system-environment", Namespace: namespace}, &v1.ConfigMap{})
+	if err != nil {
+		t.Errorf("deleted ConfigMap not restored: %v", err)
+	}
+
+	if u.Cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] != fromVersion {
+		t.Errorf("OperatorVersionAnnotation not rolled back: %s", u.Cr.Annotations[appsv1alpha1.OperatorVersionAnnotation])
+	}
+	if u.Cr.Annotations[appsv1alpha1.UpgradeRolledBackAnnotation] != version.Version {
+		t.Errorf("UpgradeRolledBackAnnotation not set: %v", u.Cr.Annotations)
+	}
+	if upgradeStatus := u.Cr.Status.Upgrade; upgradeStatus.Message == "" || len(upgradeStatus.CompletedSteps) != 0 || upgradeStatus.CompletionTime != nil {
+		t.Errorf("unexpected upgrade status after rollback: %v", upgradeStatus)
+	}
+}
+
+func TestUpgradeRollbackVerified(t *testing.T) {
+	namespace := "operator-unittest"
+	dc, _, _, _ := snapshotTestObjects(namespace)
+	u := testUpgradeApiManager(t, version.Version, dc)
+	timeout := int64(60)
+	u.Cr.Spec.UpgradeSafetyGate = &appsv1alpha1.UpgradeSafetyGateSpec{Enabled: true, RollbackTimeoutSeconds: &timeout}
+	completionTime := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	u.Cr.Status.Upgrade = &appsv1alpha1.APIManagerUpgradeStatus{
+		FromVersion:    previousMinorVersion(t),
+		ToVersion:      version.Version,
+		SnapshotName:   "missing-snapshot",
+		CompletionTime: &completionTime,
+	}
+	appsv1alpha1.SetCondition(&u.Cr.Status.Conditions, appsv1alpha1.APIManagerCondition{Type: appsv1alpha1.APIManagerAvailable, Status: v1.ConditionTrue})
+
+	res, err := u.ReconcileRollback()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue {
+		t.Error("available components should not be rolled back")
+	}
+	if !u.Cr.Status.Upgrade.Verified {
+		t.Error("upgrade not verified")
+	}
+
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: "missing-snapshot", Namespace: namespace}, &v1.ConfigMap{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("unexpected snapshot: %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Delay between the checks of the upgrade steps waiting for rollouts
+const upgradeStepRequeueDelay = 10 * time.Second
+
+var backendRedisSentinelSecretKeys = []string{
+	component.BackendSecretBackendRedisStorageSentinelHostsFieldName,
+	component.BackendSecretBackendRedisStorageSentinelRoleFieldName,
+	component.BackendSecretBackendRedisQueuesSentinelHostsFieldName,
+	component.BackendSecretBackendRedisQueuesSentinelRoleFieldName,
+}
+
+var systemRedisSentinelSecretKeys = []string{
+	component.SystemSecretSystemRedisSentinelHosts,
+	component.SystemSecretSystemRedisSentinelRole,
+	component.SystemSecretSystemRedisMessageBusSentinelHosts,
+	component.SystemSecretSystemRedisMessageBusSentinelRole,
+}
+
+var backendRedisSentinelEnvVars = []string{
+	"CONFIG_REDIS_SENTINEL_HOSTS",
+	"CONFIG_REDIS_SENTINEL_ROLE",
+	"CONFIG_QUEUES_SENTINEL_HOSTS",
+	"CONFIG_QUEUES_SENTINEL_ROLE",
+}
+
+// imageStreamsUpgradeStep points the ImageStreams to the images of the new
+// release. DeploymentConfigs are rolled out by their image change triggers
+var imageStreamsUpgradeStep = UpgradeStep{
+	Name: "imagestreams",
+	Run:  (*UpgradeApiManager).upgradeImages,
+}
+
+// redisSecretsSentinelKeysUpgradeStep adds the sentinel fields to the redis
+// secrets. Secrets created by previous versions, or by the user when the
+// databases are external, may lack them and they are referenced from the
+// backend and system DeploymentConfigs
+var redisSecretsSentinelKeysUpgradeStep = UpgradeStep{
+	Name: "redis-secrets-sentinel-keys",
+	Run:  (*UpgradeApiManager).upgradeRedisSecretsSentinelKeys,
+}
+
+// backendRedisSentinelEnvVarsUpgradeStep adds the sentinel environment
+// variables to the backend DeploymentConfigs
+var backendRedisSentinelEnvVarsUpgradeStep = UpgradeStep{
+	Name:      "backend-redis-sentinel-env-vars",
+	Preflight: (*UpgradeApiManager).checkBackendRedisSentinelSecretKeys,
+	Run:       (*UpgradeApiManager).upgradeBackendRedisSentinelEnvVars,
+}
+
+// systemDatabaseMigrationUpgradeStep waits for the rollout of system-app
+// with the new system image. The pre hook of the rollout migrates the
+// system database
+var systemDatabaseMigrationUpgradeStep = UpgradeStep{
+	Name:      "system-database-migration",
+	Preflight: (*UpgradeApiManager).checkSystemDatabaseAvailable,
+	Run:       (*UpgradeApiManager).waitSystemDatabaseMigration,
+}
+
+func (u *UpgradeApiManager) baseAPIManagerLogicReconciler() BaseAPIManagerLogicReconciler {
+	baseReconciler := NewBaseReconciler(u.Client, u.ApiClientReader, u.Scheme, u.Logger)
+	return NewBaseAPIManagerLogicReconciler(NewBaseLogicReconciler(baseReconciler), u.Cr)
+}
+
+func (u *UpgradeApiManager) upgradeRedisSecretsSentinelKeys() (reconcile.Result, error) {
+	backendReconciler := NewBackendReconciler(u.baseAPIManagerLogicReconciler())
+	backend, err := backendReconciler.backend()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	err = u.addMissingSecretKeys(backend.RedisSecret(), backendRedisSentinelSecretKeys)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	system, err := System(u.Cr, u.Client)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, u.addMissingSecretKeys(system.RedisSecret(), systemRedisSentinelSecretKeys)
+}
+
+// addMissingSecretKeys adds the given keys, with their desired values, to
+// the existing secret when missing. Existing values are kept. Secrets not
+// found are created by the regular reconciliation
+func (u *UpgradeApiManager) addMissingSecretKeys(desired *v1.Secret, keys []string) error {
+	existing := &v1.Secret{}
+	err := u.Client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: u.Cr.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if existing.StringData == nil {
+		existing.StringData = map[string]string{}
+	}
+
+	update := false
+	for _, key := range keys {
+		if _, ok := existing.Data[key]; !ok {
+			existing.StringData[key] = desired.StringData[key]
+			update = true
+		}
+	}
+
+	if !update {
+		return nil
+	}
+
+	u.Logger.Info(fmt.Sprintf("Adding missing keys to secret %s", desired.Name))
+	return u.Client.Update(context.TODO(), existing)
+}
+
+// checkBackendRedisSentinelSecretKeys checks the backend-redis secret has
+// the keys referenced from the sentinel environment variables. Pods would
+// not start otherwise
+func (u *UpgradeApiManager) checkBackendRedisSentinelSecretKeys() error {
+	secret := &v1.Secret{}
+	err := u.Client.Get(context.TODO(), types.NamespacedName{Name: component.BackendSecretBackendRedisSecretName, Namespace: u.Cr.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	missing := []string{}
+	for _, key := range backendRedisSentinelSecretKeys {
+		_, inData := secret.Data[key]
+		_, inStringData := secret.StringData[key]
+		if !inData && !inStringData {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("secret %s lacks the keys %s", component.BackendSecretBackendRedisSecretName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (u *UpgradeApiManager) upgradeBackendRedisSentinelEnvVars() (reconcile.Result, error) {
+	// Kubernetes Deployments get the whole pod template from the regular
+	// reconciliation
+	if u.Cr.IsKubernetesDeploymentEnabled() {
+		return reconcile.Result{}, nil
+	}
+
+	backendReconciler := NewBackendReconciler(u.baseAPIManagerLogicReconciler())
+	backend, err := backendReconciler.backend()
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	desiredDCs := []*appsv1.DeploymentConfig{
+		backend.ListenerDeploymentConfig(),
+		backend.WorkerDeploymentConfig(),
+		backend.CronDeploymentConfig(),
+	}
+	for _, desired := range desiredDCs {
+		err = u.upgradeDeploymentConfigEnvVars(desired, backendRedisSentinelEnvVars)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// upgradeDeploymentConfigEnvVars sets the given environment variables of
+// the existing DeploymentConfig to their desired values. DeploymentConfigs
+// not found are created by the regular reconciliation
+func (u *UpgradeApiManager) upgradeDeploymentConfigEnvVars(desired *appsv1.DeploymentConfig, envVarNames []string) error {
+	existing := &appsv1.DeploymentConfig{}
+	err := u.Client.Get(context.TODO(), types.NamespacedName{Name: desired.Name, Namespace: u.Cr.Namespace}, existing)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !DeploymentConfigReconcileContainerEnvVars(desired, existing, envVarNames, u.Logger) {
+		return nil
+	}
+
+	return u.Client.Update(context.TODO(), existing)
+}
+
+// checkSystemDatabaseAvailable checks the system database managed by the
+// operator is available to run the migrations
+func (u *UpgradeApiManager) checkSystemDatabaseAvailable() error {
+	if u.Cr.IsExternalDatabaseEnabled() || u.Cr.IsKubernetesDeploymentEnabled() {
+		return nil
+	}
+
+	databaseDCName := "system-mysql"
+	if u.Cr.Spec.System.DatabaseSpec != nil && u.Cr.Spec.System.DatabaseSpec.PostgreSQL != nil {
+		databaseDCName = "system-postgresql"
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err := u.Client.Get(context.TODO(), types.NamespacedName{Name: databaseDCName, Namespace: u.Cr.Namespace}, dc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if dc.Status.AvailableReplicas < 1 {
+		return fmt.Errorf("system database %s is not available", databaseDCName)
+	}
+	return nil
+}
+
+func (u *UpgradeApiManager) waitSystemDatabaseMigration() (reconcile.Result, error) {
+	// The system image of Kubernetes Deployments is updated by the regular
+	// reconciliation
+	if u.Cr.IsKubernetesDeploymentEnabled() {
+		return reconcile.Result{}, nil
+	}
+
+	dc := &appsv1.DeploymentConfig{}
+	err := u.Client.Get(context.TODO(), types.NamespacedName{Name: "system-app", Namespace: u.Cr.Namespace}, dc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	image, err := u.imageStreamTagImage(dc)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if image == "" || !deploymentConfigRolledOut(dc, image) {
+		u.Logger.Info("Waiting for the system-app rollout migrating the system database")
+		return reconcile.Result{Requeue: true, RequeueAfter: upgradeStepRequeueDelay}, nil
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// imageStreamTagImage returns the image the ImageStreamTag of the image
+// change trigger of the DeploymentConfig points to. Empty until the
+// ImageStream imports the image of the current tag spec
+func (u *UpgradeApiManager) imageStreamTagImage(dc *appsv1.DeploymentConfig) (string, error) {
+	params := deploymentConfigImageChangeParams(dc)
+	if params == nil {
+		return "", fmt.Errorf("%s has no image change trigger", dc.Name)
+	}
+
+	nameAndTag := strings.SplitN(params.From.Name, ":", 2)
+	if len(nameAndTag) != 2 {
+		return "", fmt.Errorf("%s image change trigger from invalid ImageStreamTag '%s'", dc.Name, params.From.Name)
+	}
+
+	imageStream := &imagev1.ImageStream{}
+	err := u.Client.Get(context.TODO(), types.NamespacedName{Name: nameAndTag[0], Namespace: u.Cr.Namespace}, imageStream)
+	if err != nil {
+		return "", err
+	}
+
+	image, imported := ImageStreamTagImportedImage(imageStream, nameAndTag[1])
+	if !imported {
+		return "", nil
+	}
+	return image, nil
+}
+
+func deploymentConfigImageChangeParams(dc *appsv1.DeploymentConfig) *appsv1.DeploymentTriggerImageChangeParams {
+	for _, trigger := range dc.Spec.Triggers {
+		if trigger.Type == appsv1.DeploymentTriggerOnImageChange && trigger.ImageChangeParams != nil {
+			return trigger.ImageChangeParams
+		}
+	}
+	return nil
+}
+
+// deploymentConfigRolledOut returns true when the DeploymentConfig has been
+// triggered with the given image and all its replicas are updated and
+// available
+func deploymentConfigRolledOut(dc *appsv1.DeploymentConfig, image string) bool {
+	params := deploymentConfigImageChangeParams(dc)
+	if params == nil || params.LastTriggeredImage != image {
+		return false
+	}
+
+	return dc.Status.ObservedGeneration >= dc.Generation &&
+		dc.Status.UpdatedReplicas == dc.Spec.Replicas &&
+		dc.Status.AvailableReplicas == dc.Spec.Replicas
+}
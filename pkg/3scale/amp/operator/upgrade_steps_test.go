@@ -0,0 +1,240 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestImageStreamsUpgradeStep(t *testing.T) {
+	u := testUpgradeApiManager(t, previousMinorVersion(t))
+
+	res, err := imageStreamsUpgradeStep.Run(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue {
+		t.Error("unexpected requeue")
+	}
+
+	for _, name := range []string{"amp-backend", "amp-system", "amp-zync", "amp-apicast", "backend-redis", "system-redis", "system-mysql"} {
+		err = u.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: u.Cr.Namespace}, &imagev1.ImageStream{})
+		if err != nil {
+			t.Errorf("ImageStream %s: %v", name, err)
+		}
+	}
+}
+
+func TestRedisSecretsSentinelKeysUpgradeStep(t *testing.T) {
+	namespace := "operator-unittest"
+	backendRedisSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.BackendSecretBackendRedisSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.BackendSecretBackendRedisStorageURLFieldName:           []byte("redis://backend-redis:6379/0"),
+			component.BackendSecretBackendRedisStorageSentinelHostsFieldName: []byte("sentinel:26379"),
+		},
+	}
+	systemRedisSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.SystemSecretSystemRedisSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.SystemSecretSystemRedisURLFieldName: []byte("redis://system-redis:6379/1"),
+		},
+	}
+	u := testUpgradeApiManager(t, previousMinorVersion(t), backendRedisSecret, systemRedisSecret)
+
+	_, err := redisSecretsSentinelKeysUpgradeStep.Run(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &v1.Secret{}
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: component.BackendSecretBackendRedisSecretName, Namespace: namespace}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := secret.StringData[component.BackendSecretBackendRedisStorageSentinelHostsFieldName]; ok {
+		t.Error("existing secret key overwritten")
+	}
+	for _, key := range backendRedisSentinelSecretKeys[1:] {
+		if _, ok := secret.StringData[key]; !ok {
+			t.Errorf("key %s not added to secret %s", key, secret.Name)
+		}
+	}
+
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: component.SystemSecretSystemRedisSecretName, Namespace: namespace}, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range systemRedisSentinelSecretKeys {
+		if _, ok := secret.StringData[key]; !ok {
+			t.Errorf("key %s not added to secret %s", key, secret.Name)
+		}
+	}
+}
+
+func TestBackendRedisSentinelEnvVarsUpgradeStep(t *testing.T) {
+	namespace := "operator-unittest"
+	backendRedisSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: component.BackendSecretBackendRedisSecretName, Namespace: namespace},
+		Data: map[string][]byte{
+			component.BackendSecretBackendRedisStorageURLFieldName: []byte("redis://backend-redis:6379/0"),
+		},
+	}
+	listener := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend-listener", Namespace: namespace},
+		Spec: appsv1.DeploymentConfigSpec{
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "backend-listener", Env: []v1.EnvVar{{Name: "CONFIG_REDIS_PROXY", Value: "redis"}}},
+					},
+				},
+			},
+		},
+	}
+	u := testUpgradeApiManager(t, previousMinorVersion(t), backendRedisSecret, listener)
+
+	err := backendRedisSentinelEnvVarsUpgradeStep.Preflight(u)
+	if err == nil {
+		t.Fatal("preflight check should fail with the sentinel keys missing from the secret")
+	}
+
+	backendRedisSecret.Data[component.BackendSecretBackendRedisStorageSentinelHostsFieldName] = []byte("")
+	backendRedisSecret.Data[component.BackendSecretBackendRedisStorageSentinelRoleFieldName] = []byte("")
+	backendRedisSecret.Data[component.BackendSecretBackendRedisQueuesSentinelHostsFieldName] = []byte("")
+	backendRedisSecret.Data[component.BackendSecretBackendRedisQueuesSentinelRoleFieldName] = []byte("")
+	err = u.Client.Update(context.TODO(), backendRedisSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = backendRedisSentinelEnvVarsUpgradeStep.Preflight(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = backendRedisSentinelEnvVarsUpgradeStep.Run(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &appsv1.DeploymentConfig{}
+	err = u.Client.Get(context.TODO(), types.NamespacedName{Name: "backend-listener", Namespace: namespace}, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := existing.Spec.Template.Spec.Containers[0].Env
+	if findEnvVar(env, "CONFIG_REDIS_PROXY") < 0 {
+		t.Error("existing env var removed")
+	}
+	for _, name := range backendRedisSentinelEnvVars {
+		idx := findEnvVar(env, name)
+		if idx < 0 {
+			t.Errorf("env var %s not added", name)
+			continue
+		}
+		if env[idx].ValueFrom == nil || env[idx].ValueFrom.SecretKeyRef == nil || env[idx].ValueFrom.SecretKeyRef.Name != component.BackendSecretBackendRedisSecretName {
+			t.Errorf("env var %s not read from the %s secret", name, component.BackendSecretBackendRedisSecretName)
+		}
+	}
+}
+
+func TestSystemDatabaseMigrationUpgradeStep(t *testing.T) {
+	var (
+		namespace = "operator-unittest"
+		oldImage  = "quay.io/3scale/porta@sha256:old"
+		newImage  = "quay.io/3scale/porta@sha256:new"
+		replicas  = int32(2)
+	)
+
+	systemMySQL := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-mysql", Namespace: namespace},
+	}
+	systemApp := &appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-app", Namespace: namespace, Generation: 3},
+		Spec: appsv1.DeploymentConfigSpec{
+			Replicas: replicas,
+			Triggers: appsv1.DeploymentTriggerPolicies{
+				{Type: appsv1.DeploymentTriggerOnConfigChange},
+				{
+					Type: appsv1.DeploymentTriggerOnImageChange,
+					ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+						Automatic:          true,
+						From:               v1.ObjectReference{Kind: "ImageStreamTag", Name: "amp-system:latest"},
+						LastTriggeredImage: oldImage,
+					},
+				},
+			},
+		},
+		Status: appsv1.DeploymentConfigStatus{ObservedGeneration: 3, UpdatedReplicas: replicas, AvailableReplicas: replicas},
+	}
+	generation := int64(2)
+	imageStream := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{Name: "amp-system", Namespace: namespace},
+		Spec: imagev1.ImageStreamSpec{
+			Tags: []imagev1.TagReference{{Name: "latest", Generation: &generation}},
+		},
+		Status: imagev1.ImageStreamStatus{
+			Tags: []imagev1.NamedTagEventList{
+				{Tag: "latest", Items: []imagev1.TagEvent{{DockerImageReference: oldImage, Generation: 1}}},
+			},
+		},
+	}
+	u := testUpgradeApiManager(t, previousMinorVersion(t), systemMySQL, systemApp, imageStream)
+
+	err := systemDatabaseMigrationUpgradeStep.Preflight(u)
+	if err == nil {
+		t.Fatal("preflight check should fail with the system database unavailable")
+	}
+	systemMySQL.Status.AvailableReplicas = 1
+	err = u.Client.Update(context.TODO(), systemMySQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = systemDatabaseMigrationUpgradeStep.Preflight(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The new image is not imported yet
+	res, err := systemDatabaseMigrationUpgradeStep.Run(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Requeue {
+		t.Fatal("step should wait for the image import")
+	}
+
+	// The new image is imported, system-app not rolled out yet
+	imageStream.Status.Tags[0].Items = []imagev1.TagEvent{{DockerImageReference: newImage, Generation: 2}}
+	err = u.Client.Update(context.TODO(), imageStream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = systemDatabaseMigrationUpgradeStep.Run(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Requeue {
+		t.Fatal("step should wait for the system-app rollout")
+	}
+
+	systemApp.Spec.Triggers[1].ImageChangeParams.LastTriggeredImage = newImage
+	err = u.Client.Update(context.TODO(), systemApp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = systemDatabaseMigrationUpgradeStep.Run(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue {
+		t.Error("step should finish once system-app is rolled out")
+	}
+}
@@ -0,0 +1,203 @@
+package operator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/version"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// previousMinorVersion returns the first version of the minor version
+// previous to the running operator version
+func previousMinorVersion(t *testing.T) string {
+	major, minor, err := parseOperatorVersion(version.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fmt.Sprintf("%d.%d.0", major, minor-1)
+}
+
+func testUpgradeApiManager(t *testing.T, fromVersion string, objs ...runtime.Object) *UpgradeApiManager {
+	appLabel := "someLabel"
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apimanager",
+			Namespace: "operator-unittest",
+			UID:       "apimanager-uid",
+			Annotations: map[string]string{
+				appsv1alpha1.OperatorVersionAnnotation:   fromVersion,
+				appsv1alpha1.ThreescaleVersionAnnotation: "2.8",
+			},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				AppLabel:       &appLabel,
+				WildcardDomain: "test.3scale.net",
+			},
+		},
+	}
+	_, err := apimanager.SetDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	baseReconciler, cl := testBaseReconciler(t, append([]runtime.Object{apimanager}, objs...)...)
+	return &UpgradeApiManager{
+		Cr:              apimanager,
+		Client:          cl,
+		ApiClientReader: cl,
+		Scheme:          baseReconciler.Scheme(),
+		Logger:          baseReconciler.Logger(),
+	}
+}
+
+func TestUpgradeSteps(t *testing.T) {
+	major, minor, err := parseOperatorVersion(version.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		testName    string
+		fromVersion string
+		expectedErr bool
+	}{
+		{"Patch", fmt.Sprintf("%d.%d.9", major, minor), false},
+		{"PreviousMinor", fmt.Sprintf("%d.%d.0", major, minor-1), false},
+		{"SkippedMinor", fmt.Sprintf("%d.%d.0", major, minor-2), true},
+		{"Downgrade", fmt.Sprintf("%d.%d.0", major, minor+1), true},
+		{"Invalid", "not_a_version", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			steps, err := UpgradeSteps(tc.fromVersion, version.Version)
+			if tc.expectedErr {
+				if err == nil {
+					subT.Errorf("upgrade from %s should be refused", tc.fromVersion)
+				}
+				return
+			}
+			if err != nil {
+				subT.Fatal(err)
+			}
+			if len(steps) == 0 {
+				subT.Errorf("no upgrade steps from %s", tc.fromVersion)
+			}
+		})
+	}
+}
+
+func TestUpgradePathsStepNames(t *testing.T) {
+	for _, path := range UpgradePaths {
+		names := map[string]bool{}
+		for _, step := range path.Steps {
+			if step.Name == "" || step.Run == nil {
+				t.Errorf("upgrade %s -> %s has an incomplete step: %v", path.From, path.To, step.Name)
+			}
+			if names[step.Name] {
+				t.Errorf("upgrade %s -> %s has duplicated step %s", path.From, path.To, step.Name)
+			}
+			names[step.Name] = true
+		}
+	}
+}
+
+func TestUpgradeApiManagerResume(t *testing.T) {
+	var (
+		runs         = map[string]int{}
+		requeueB     = true
+		preflightErr = errors.New("not ready")
+	)
+
+	countingRun := func(name string) func(u *UpgradeApiManager) (reconcile.Result, error) {
+		return func(u *UpgradeApiManager) (reconcile.Result, error) {
+			runs[name]++
+			if name == "b" && requeueB {
+				requeueB = false
+				return reconcile.Result{Requeue: true}, nil
+			}
+			return reconcile.Result{}, nil
+		}
+	}
+
+	fromVersion := previousMinorVersion(t)
+	major, minor, _ := parseOperatorVersion(version.Version)
+	defer func(paths []UpgradePath) { UpgradePaths = paths }(UpgradePaths)
+	UpgradePaths = []UpgradePath{
+		{
+			From: fmt.Sprintf("%d.%d", major, minor-1),
+			To:   fmt.Sprintf("%d.%d", major, minor),
+			Steps: []UpgradeStep{
+				{Name: "a", Run: countingRun("a")},
+				{Name: "b", Run: countingRun("b")},
+				{Name: "c", Run: countingRun("c"), Preflight: func(u *UpgradeApiManager) error { return preflightErr }},
+			},
+		},
+	}
+
+	u := testUpgradeApiManager(t, fromVersion)
+
+	// b requeues
+	res, err := u.Upgrade()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Requeue {
+		t.Fatal("upgrade should requeue while a step is not finished")
+	}
+	upgradeStatus := u.Cr.Status.Upgrade
+	if upgradeStatus.FromVersion != fromVersion || upgradeStatus.ToVersion != version.Version || upgradeStatus.FromRelease != "2.8" {
+		t.Errorf("unexpected upgrade versions: %v", upgradeStatus)
+	}
+	if len(upgradeStatus.CompletedSteps) != 1 || upgradeStatus.CurrentStep != "b" {
+		t.Errorf("unexpected upgrade progress: %v", upgradeStatus)
+	}
+
+	// c preflight fails, a is not run again
+	_, err = u.Upgrade()
+	if err == nil {
+		t.Fatal("failed preflight check should stop the upgrade")
+	}
+	if runs["a"] != 1 || runs["b"] != 2 || runs["c"] != 0 {
+		t.Errorf("unexpected step runs: %v", runs)
+	}
+	if u.Cr.Status.Upgrade.Message == "" {
+		t.Error("failed preflight check not reported in the upgrade status")
+	}
+
+	preflightErr = nil
+	res, err = u.Upgrade()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue {
+		t.Error("finished upgrade should not requeue")
+	}
+	upgradeStatus = u.Cr.Status.Upgrade
+	if len(upgradeStatus.CompletedSteps) != 3 || upgradeStatus.Message != "" || upgradeStatus.CurrentStep != "" {
+		t.Errorf("unexpected upgrade progress: %v", upgradeStatus)
+	}
+	if runs["a"] != 1 || runs["b"] != 2 || runs["c"] != 1 {
+		t.Errorf("unexpected step runs: %v", runs)
+	}
+}
+
+func TestUpgradeApiManagerUnsupported(t *testing.T) {
+	u := testUpgradeApiManager(t, "0.1.0")
+
+	_, err := u.Upgrade()
+	if err == nil {
+		t.Fatal("upgrade skipping minor versions should be refused")
+	}
+
+	upgradeStatus := u.Cr.Status.Upgrade
+	if upgradeStatus == nil || upgradeStatus.Message != err.Error() {
+		t.Errorf("refused upgrade not reported in the upgrade status: %v", upgradeStatus)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
 	oprand "github.com/3scale/3scale-operator/pkg/crypto/rand"
 	"github.com/3scale/3scale-operator/pkg/helper"
-	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
@@ -20,7 +19,10 @@ func (o *OperatorZyncOptionsProvider) GetZyncOptions() (*component.ZyncOptions,
 	}
 
 	o.setResourceRequirementsOptions(&optProv)
+	o.setPodPlacementOptions(&optProv)
 	o.setReplicas(&optProv)
+	optProv.MetricsEnabled(o.APIManagerSpec.Monitoring.IsEnabled())
+	optProv.ImagePullSecrets(o.APIManagerSpec.ImagePullSecrets)
 
 	res, err := optProv.Build()
 	if err != nil {
@@ -56,14 +58,34 @@ func (o *OperatorZyncOptionsProvider) setZyncSecretOptions(zob *component.ZyncOp
 	zob.DatabasePassword(helper.GetSecretDataValueOrDefault(secretData, component.ZyncSecretDatabasePasswordFieldName, defaultZyncDatabasePassword))
 	zob.AuthenticationToken(helper.GetSecretDataValueOrDefault(secretData, component.ZyncSecretAuthenticationTokenFieldName, defaultZyncAuthenticationToken))
 
+	// The external database is only set in the secret
+	if o.APIManagerSpec.HighAvailability != nil && o.APIManagerSpec.HighAvailability.Enabled && o.APIManagerSpec.HighAvailability.ExternalZyncDatabaseEnabled {
+		databaseURL := helper.GetSecretDataValue(secretData, component.ZyncSecretDatabaseURLFieldName)
+		if databaseURL == nil {
+			return fmt.Errorf("Secret field '%s' is required in secret '%s'", component.ZyncSecretDatabaseURLFieldName, component.ZyncSecretName)
+		}
+		zob.DatabaseURL(*databaseURL)
+	}
+
 	return nil
 }
 
 func (o *OperatorZyncOptionsProvider) setResourceRequirementsOptions(b *component.ZyncOptionsBuilder) {
-	if !*o.APIManagerSpec.ResourceRequirementsEnabled {
-		b.ContainerResourceRequirements(v1.ResourceRequirements{})
-		b.QueContainerResourceRequirements(v1.ResourceRequirements{})
-		b.DatabaseContainerResourceRequirements(v1.ResourceRequirements{})
+	enabled := *o.APIManagerSpec.ResourceRequirementsEnabled
+	setResourceRequirements(b.ContainerResourceRequirements, enabled, o.APIManagerSpec.Zync.AppSpec.Resources)
+	setResourceRequirements(b.QueContainerResourceRequirements, enabled, o.APIManagerSpec.Zync.QueSpec.Resources)
+	setResourceRequirements(b.DatabaseContainerResourceRequirements, enabled, o.APIManagerSpec.Zync.DatabaseResources)
+}
+
+func (o *OperatorZyncOptionsProvider) setPodPlacementOptions(b *component.ZyncOptionsBuilder) {
+	if o.APIManagerSpec.Zync.AppSpec.Placement != nil {
+		b.PodPlacement(podPlacement(o.APIManagerSpec.Zync.AppSpec.Placement, "zync"))
+	}
+	if o.APIManagerSpec.Zync.QueSpec.Placement != nil {
+		b.QuePodPlacement(podPlacement(o.APIManagerSpec.Zync.QueSpec.Placement, "zync-que"))
+	}
+	if o.APIManagerSpec.Zync.DatabasePlacement != nil {
+		b.DatabasePodPlacement(podPlacement(o.APIManagerSpec.Zync.DatabasePlacement, "zync-database"))
 	}
 }
 
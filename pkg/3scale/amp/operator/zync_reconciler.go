@@ -46,6 +46,9 @@ func (r *ZyncDCReconciler) IsUpdateNeeded(desired, existing *appsv1.DeploymentCo
 	tmpUpdate = DeploymentConfigReconcileReplicas(desired, existing, r.Logger())
 	update = update || tmpUpdate
 
+	tmpUpdate = DeploymentConfigReconcileContainerPorts(desired, existing, r.Logger())
+	update = update || tmpUpdate
+
 	return update
 }
 
@@ -93,9 +96,12 @@ func (r *ZyncReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileZyncDatabaseDeploymentConfig(zync.DatabaseDeploymentConfig())
-	if err != nil {
-		return reconcile.Result{}, err
+	// An external zync database is managed by the user
+	if !r.apiManager.IsExternalZyncDatabaseEnabled() {
+		err = r.reconcileZyncDatabaseDeploymentConfig(zync.DatabaseDeploymentConfig())
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	err = r.reconcileZyncService(zync.Service())
@@ -103,9 +109,11 @@ func (r *ZyncReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
-	err = r.reconcileZyncDatabaseService(zync.DatabaseService())
-	if err != nil {
-		return reconcile.Result{}, err
+	if !r.apiManager.IsExternalZyncDatabaseEnabled() {
+		err = r.reconcileZyncDatabaseService(zync.DatabaseService())
+		if err != nil {
+			return reconcile.Result{}, err
+		}
 	}
 
 	err = r.reconcileZyncSecret(zync.Secret())
@@ -123,6 +131,11 @@ func (r *ZyncReconciler) Reconcile() (reconcile.Result, error) {
 		return reconcile.Result{}, err
 	}
 
+	err = r.reconcileHorizontalPodAutoscaler(zync.DeploymentConfig(), r.apiManager.Spec.Zync.AppSpec.Autoscaling)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -141,7 +154,7 @@ func (r *ZyncReconciler) reconcileQueRole(desiredRole *rbacv1.Role) error {
 }
 
 func (r *ZyncReconciler) reconcileQueServiceAccount(desiredServiceAccount *v1.ServiceAccount) error {
-	reconciler := NewServiceAccountBaseReconciler(r.BaseAPIManagerLogicReconciler, NewCreateOnlyServiceAccountReconciler())
+	reconciler := NewServiceAccountBaseReconciler(r.BaseAPIManagerLogicReconciler, NewImagePullSecretsServiceAccountReconciler())
 	return reconciler.Reconcile(desiredServiceAccount)
 }
 
@@ -151,7 +164,8 @@ func (r *ZyncReconciler) reconcileQueRoleBinding(desiredRoleBinding *rbacv1.Role
 }
 
 func (r *ZyncReconciler) reconcileZyncDeploymentConfig(desiredDeploymentConfig *appsv1.DeploymentConfig) error {
-	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, NewZyncDCReconciler(r.BaseAPIManagerLogicReconciler))
+	dcReconciler := NewAutoscalingDCReconciler(r.apiManager.Spec.Zync.AppSpec.Autoscaling, NewZyncDCReconciler(r.BaseAPIManagerLogicReconciler))
+	reconciler := NewDeploymentConfigBaseReconciler(r.BaseAPIManagerLogicReconciler, dcReconciler)
 	return reconciler.Reconcile(desiredDeploymentConfig)
 }
 
@@ -0,0 +1,183 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// APIcastAdminPortalURLSecretKey is the key of the admin portal
+	// credentials secret holding the admin portal endpoint, in the
+	// https://<access-token>@<admin-portal-domain> form
+	APIcastAdminPortalURLSecretKey = "AdminPortalURL"
+)
+
+const (
+	APIcastDeploymentEnvironmentStaging    = "staging"
+	APIcastDeploymentEnvironmentProduction = "production"
+)
+
+const (
+	defaultAPIcastReplicas              int64 = 1
+	defaultAPIcastDeploymentEnvironment       = APIcastDeploymentEnvironmentProduction
+	defaultAPIcastOpenSSLVerify               = false
+)
+
+// APIcastSpec defines the desired state of a standalone APIcast gateway
+type APIcastSpec struct {
+	// AdminPortalCredentialsRef references the secret with the admin portal
+	// endpoint the gateway loads its configuration from
+	AdminPortalCredentialsRef v1.LocalObjectReference `json:"adminPortalCredentialsRef"`
+	// +optional
+	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// DeploymentEnvironment is the 3scale environment the configuration is
+	// loaded from. One of staging or production. Defaults to production
+	// +optional
+	DeploymentEnvironment *string `json:"deploymentEnvironment,omitempty"`
+	// +optional
+	OpenSSLVerify *bool `json:"openSSLVerify,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+	// ExposedHost exposes the gateway out of the cluster. The gateway is
+	// only reachable through its Service when it is not set
+	// +optional
+	ExposedHost *APIcastExposedHostSpec `json:"exposedHost,omitempty"`
+	// +optional
+	CustomPolicies []CustomPolicySpec `json:"customPolicies,omitempty"`
+	// HTTPS enables the HTTPS listener of the gateway
+	// +optional
+	HTTPS *ApicastHTTPSSpec `json:"https,omitempty"`
+
+	ApicastRuntimeSpec `json:",inline"`
+}
+
+// APIcastExposedHostSpec defines the host a standalone APIcast gateway is
+// exposed on
+type APIcastExposedHostSpec struct {
+	Host string `json:"host"`
+	// ExposureType is either Route or Ingress. Defaults to Route
+	// +optional
+	ExposureType *string `json:"exposureType,omitempty"`
+	// Ingress configures the Ingress created when the exposure type is
+	// Ingress
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+}
+
+// APIcastStatus defines the observed state of a standalone APIcast gateway
+type APIcastStatus struct {
+	// Conditions describe the state of the gateway
+	// +optional
+	Conditions []APIManagerCondition `json:"conditions,omitempty"`
+	// Replicas is the number of gateway pods
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// ReadyReplicas is the number of gateway pods ready to serve requests
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// APIcast is the Schema for the apicasts API. It deploys an APIcast gateway
+// managed independently from any APIManager
+// +kubebuilder:subresource:status
+type APIcast struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIcastSpec   `json:"spec,omitempty"`
+	Status APIcastStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// APIcastList contains a list of APIcast
+type APIcastList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIcast `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIcast{}, &APIcastList{})
+}
+
+// SetDefaults sets the default values for the APIcast spec and returns true
+// if the spec was changed
+func (apicast *APIcast) SetDefaults() (bool, error) {
+	changed := false
+	spec := &apicast.Spec
+
+	if spec.Replicas == nil {
+		tmpDefaultReplicas := defaultAPIcastReplicas
+		spec.Replicas = &tmpDefaultReplicas
+		changed = true
+	}
+	if spec.DeploymentEnvironment == nil {
+		tmpDefaultDeploymentEnvironment := defaultAPIcastDeploymentEnvironment
+		spec.DeploymentEnvironment = &tmpDefaultDeploymentEnvironment
+		changed = true
+	}
+	if spec.OpenSSLVerify == nil {
+		tmpDefaultOpenSSLVerify := defaultAPIcastOpenSSLVerify
+		spec.OpenSSLVerify = &tmpDefaultOpenSSLVerify
+		changed = true
+	}
+	if spec.ExposedHost != nil && spec.ExposedHost.ExposureType == nil {
+		tmpDefaultExposureType := defaultExposureType
+		spec.ExposedHost.ExposureType = &tmpDefaultExposureType
+		changed = true
+	}
+
+	return changed, apicast.validate()
+}
+
+func (apicast *APIcast) validate() error {
+	spec := &apicast.Spec
+
+	if spec.AdminPortalCredentialsRef.Name == "" {
+		return fmt.Errorf("Invalid APIcast. adminPortalCredentialsRef name is required")
+	}
+	if *spec.DeploymentEnvironment != APIcastDeploymentEnvironmentStaging && *spec.DeploymentEnvironment != APIcastDeploymentEnvironmentProduction {
+		return fmt.Errorf("Invalid APIcast deploymentEnvironment '%s'. It must be %s or %s", *spec.DeploymentEnvironment, APIcastDeploymentEnvironmentStaging, APIcastDeploymentEnvironmentProduction)
+	}
+	if exposedHost := spec.ExposedHost; exposedHost != nil {
+		if exposedHost.Host == "" {
+			return fmt.Errorf("Invalid APIcast exposedHost. host is required")
+		}
+		if *exposedHost.ExposureType != ExposureTypeRoute && *exposedHost.ExposureType != ExposureTypeIngress {
+			return fmt.Errorf("Invalid APIcast exposedHost exposureType '%s'. It must be %s or %s", *exposedHost.ExposureType, ExposureTypeRoute, ExposureTypeIngress)
+		}
+	}
+
+	err := validateCustomPolicySpecs(spec.CustomPolicies)
+	if err != nil {
+		return err
+	}
+
+	err = validateApicastRuntimeSpec("APIcast", spec.ApicastRuntimeSpec)
+	if err != nil {
+		return err
+	}
+
+	err = validatePodPlacementSpec("APIcast", spec.Placement)
+	if err != nil {
+		return err
+	}
+
+	return validateApicastHTTPSSpec(spec.HTTPS)
+}
+
+// IsIngressEnabled returns true when the gateway is exposed with a
+// Kubernetes Ingress instead of an OpenShift Route
+func (apicast *APIcast) IsIngressEnabled() bool {
+	exposedHost := apicast.Spec.ExposedHost
+	return exposedHost != nil && exposedHost.ExposureType != nil && *exposedHost.ExposureType == ExposureTypeIngress
+}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	"github.com/3scale/3scale-operator/version"
@@ -18,13 +19,113 @@ import (
 const (
 	ThreescaleVersionAnnotation = "apps.3scale.net/apimanager-threescale-version"
 	OperatorVersionAnnotation   = "apps.3scale.net/threescale-operator-version"
+	// UpgradeApprovedAnnotation approves the upgrade to the operator version
+	// of its value when the upgrade safety gate requires approval
+	UpgradeApprovedAnnotation = "apps.3scale.net/upgrade-approved"
+	// UpgradeRolledBackAnnotation is set to the operator version of an
+	// upgrade rolled back. The upgrade is retried when it is removed
+	UpgradeRolledBackAnnotation = "apps.3scale.net/upgrade-rolled-back"
+	// RotateCredentialsAnnotation requests the rotation of the comma
+	// separated list of credentials of its value. It is removed once the
+	// rotations are started
+	RotateCredentialsAnnotation = "apps.3scale.net/rotate-credentials"
+	// PausedAnnotation set to "true" pauses the reconciliation of the whole
+	// APIManager, upgrades and credential rotations included
+	PausedAnnotation = "apps.3scale.net/paused"
+	// PausedComponentsAnnotation pauses the reconciliation of the comma
+	// separated list of components of its value. Upgrades and credential
+	// rotations wait until the components are resumed
+	PausedComponentsAnnotation = "apps.3scale.net/paused-components"
 )
 
+const (
+	// CredentialBackendInternalAPI is the password of the backend internal
+	// API, stored in the backend-internal-api secret
+	CredentialBackendInternalAPI = "backend-internal-api"
+	// CredentialSystemAppSecretKeyBase is the SECRET_KEY_BASE of the
+	// system-app secret
+	CredentialSystemAppSecretKeyBase = "system-app-secret-key-base"
+	// CredentialSystemEventsHook is the shared secret of the backend events
+	// hook, stored in the system-events-hook secret
+	CredentialSystemEventsHook = "system-events-hook"
+	// CredentialZyncAuthenticationToken is the ZYNC_AUTHENTICATION_TOKEN of
+	// the zync secret
+	CredentialZyncAuthenticationToken = "zync-authentication-token"
+	// CredentialZyncSecretKeyBase is the SECRET_KEY_BASE of the zync secret
+	CredentialZyncSecretKeyBase = "zync-secret-key-base"
+	// CredentialSystemSeedMasterAccessToken is the MASTER_ACCESS_TOKEN of
+	// the system-seed secret
+	CredentialSystemSeedMasterAccessToken = "system-seed-master-access-token"
+	// CredentialSystemSeedAdminAccessToken is the ADMIN_ACCESS_TOKEN of the
+	// system-seed secret
+	CredentialSystemSeedAdminAccessToken = "system-seed-admin-access-token"
+	// CredentialSystemMasterApicastAccessToken is the ACCESS_TOKEN of the
+	// system-master-apicast secret
+	CredentialSystemMasterApicastAccessToken = "system-master-apicast-access-token"
+)
+
+// RotatableCredentials are the credentials the operator can rotate
+var RotatableCredentials = []string{
+	CredentialBackendInternalAPI,
+	CredentialSystemAppSecretKeyBase,
+	CredentialSystemEventsHook,
+	CredentialZyncAuthenticationToken,
+	CredentialZyncSecretKeyBase,
+	CredentialSystemSeedMasterAccessToken,
+	CredentialSystemSeedAdminAccessToken,
+	CredentialSystemMasterApicastAccessToken,
+}
+
 const (
 	defaultAppLabel                    = "3scale-api-management"
 	defaultTenantName                  = "3scale"
 	defaultImageStreamImportInsecure   = false
 	defaultResourceRequirementsEnabled = true
+	defaultWorkloadType                = WorkloadTypeDeploymentConfig
+	defaultExposureType                = ExposureTypeRoute
+)
+
+const (
+	// WorkloadTypeDeploymentConfig deploys the components as OpenShift
+	// DeploymentConfigs with ImageStream triggers
+	WorkloadTypeDeploymentConfig = "DeploymentConfig"
+	// WorkloadTypeDeployment deploys the components as Kubernetes
+	// Deployments and StatefulSets with plain image references
+	WorkloadTypeDeployment = "Deployment"
+)
+
+const (
+	// TopologySpreadDoNotSchedule requires the pods of a deployment to be
+	// spread across the topology domains
+	TopologySpreadDoNotSchedule = "DoNotSchedule"
+	// TopologySpreadScheduleAnyway prefers the topology domains without
+	// pods of the deployment
+	TopologySpreadScheduleAnyway = "ScheduleAnyway"
+)
+
+const (
+	// ExposureTypeRoute exposes the components with OpenShift Routes
+	ExposureTypeRoute = "Route"
+	// ExposureTypeIngress exposes the components with Kubernetes Ingresses
+	ExposureTypeIngress = "Ingress"
+)
+
+const (
+	// ApicastHTTPSRouteTerminationPassthrough routes the HTTPS traffic to
+	// APIcast without terminating TLS in the router
+	ApicastHTTPSRouteTerminationPassthrough = "passthrough"
+	// ApicastHTTPSRouteTerminationReencrypt terminates TLS in the router and
+	// opens a new TLS connection to APIcast
+	ApicastHTTPSRouteTerminationReencrypt = "reencrypt"
+)
+
+const (
+	// RedisModeStandalone deploys backend-redis and system-redis as single
+	// redis instances
+	RedisModeStandalone = "Standalone"
+	// RedisModeSentinel deploys backend-redis and system-redis as redis
+	// master/replica sets monitored by redis sentinels
+	RedisModeSentinel = "Sentinel"
 )
 
 const (
@@ -34,6 +135,40 @@ const (
 	defaultApicastRegistryURL   = "http://apicast-staging:8090/policies"
 )
 
+const (
+	// defaultRedisSentinelReplicas is the number of sentinels when
+	// sentinelReplicas is not set
+	defaultRedisSentinelReplicas = 3
+)
+
+// systemSMTPAuthentications are the valid system SMTP authentication types
+var systemSMTPAuthentications = map[string]bool{
+	"plain":    true,
+	"login":    true,
+	"cram_md5": true,
+}
+
+// systemSMTPOpenSSLVerifyModes are the valid system SMTP OpenSSL verify
+// modes
+var systemSMTPOpenSSLVerifyModes = map[string]bool{
+	"none":                 true,
+	"peer":                 true,
+	"client_once":          true,
+	"fail_if_no_peer_cert": true,
+}
+
+// apicastLogLevels are the valid APIcast log levels
+var apicastLogLevels = map[string]bool{
+	"debug":  true,
+	"info":   true,
+	"notice": true,
+	"warn":   true,
+	"error":  true,
+	"crit":   true,
+	"alert":  true,
+	"emerg":  true,
+}
+
 // APIManagerSpec defines the desired state of APIManager
 // +k8s:openapi-gen=true
 type APIManagerSpec struct {
@@ -50,13 +185,180 @@ type APIManagerSpec struct {
 	HighAvailability    *HighAvailabilitySpec    `json:"highAvailability,omitempty"`
 	// +optional
 	PodDisruptionBudget *PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+	// +optional
+	Ingress *IngressSpec `json:"ingress,omitempty"`
+	// +optional
+	Redis *RedisSpec `json:"redis,omitempty"`
+	// +optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// +optional
+	UpgradeSafetyGate *UpgradeSafetyGateSpec `json:"upgradeSafetyGate,omitempty"`
+	// +optional
+	CredentialRotation *CredentialRotationSpec `json:"credentialRotation,omitempty"`
 }
 
 // APIManagerStatus defines the observed state of APIManager
 // +k8s:openapi-gen=true
 type APIManagerStatus struct {
-	Conditions  []APIManagerCondition `json:"conditions,omitempty" protobuf:"bytes,4,rep,name=conditions"`
-	Deployments olm.DeploymentStatus  `json:"deployments"`
+	// Conditions describe the state of the APIManager as a whole
+	// +optional
+	Conditions []APIManagerCondition `json:"conditions,omitempty" protobuf:"bytes,4,rep,name=conditions"`
+	// Components describe the state of each one of the APIManager components
+	// +optional
+	Components  []APIManagerComponentStatus `json:"components,omitempty"`
+	Deployments olm.DeploymentStatus        `json:"deployments"`
+	// BackendWorkerQueueScaling describes the last backend-worker queue
+	// scaling decision
+	// +optional
+	BackendWorkerQueueScaling *BackendWorkerQueueScalingStatus `json:"backendWorkerQueueScaling,omitempty"`
+	// Upgrade describes the progress of the last upgrade between operator
+	// versions
+	// +optional
+	Upgrade *APIManagerUpgradeStatus `json:"upgrade,omitempty"`
+	// Images describes the images the ImageStream tags resolved to and the
+	// images the DeploymentConfig containers run
+	// +optional
+	Images *APIManagerImagesStatus `json:"images,omitempty"`
+	// CredentialRotation describes the rotation in progress and the last
+	// rotation of each credential
+	// +optional
+	CredentialRotation *CredentialRotationStatus `json:"credentialRotation,omitempty"`
+}
+
+// CredentialRotationStatus defines the progress of the credential rotations
+type CredentialRotationStatus struct {
+	// Credential being rotated
+	// +optional
+	Current string `json:"current,omitempty"`
+	// Identifies the rotation in progress in the annotations of the rotated
+	// secret and of the pod templates rolled out
+	// +optional
+	RotationID string `json:"rotationID,omitempty"`
+	// Workloads already rolled out with the rotated credential
+	// +optional
+	RolledOut []string `json:"rolledOut,omitempty"`
+	// Access token Job failed in the rotation in progress. The Job is run
+	// again once deleted
+	// +optional
+	FailedJob string `json:"failedJob,omitempty"`
+	// Reason a requested rotation was not started
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Last rotation of each credential
+	// +optional
+	Credentials []RotatedCredentialStatus `json:"credentials,omitempty"`
+}
+
+// RotatedCredentialStatus defines the last rotation of a credential
+type RotatedCredentialStatus struct {
+	Name string `json:"name"`
+	// Time the rotated credential was rolled out to all its consumers
+	LastRotationTime metav1.Time `json:"lastRotationTime"`
+}
+
+// FindRotatedCredential returns the last rotation of the given credential.
+// Nil when never rotated
+func (s *CredentialRotationStatus) FindRotatedCredential(name string) *RotatedCredentialStatus {
+	if s == nil {
+		return nil
+	}
+	for idx := range s.Credentials {
+		if s.Credentials[idx].Name == name {
+			return &s.Credentials[idx]
+		}
+	}
+	return nil
+}
+
+// APIManagerImagesStatus defines the images deployed by the APIManager
+type APIManagerImagesStatus struct {
+	// Images the ImageStream tags imported from an external image
+	// resolved to
+	// +optional
+	ImageStreamTags []ImageStreamTagImageStatus `json:"imageStreamTags,omitempty"`
+	// Images run by the DeploymentConfig containers with an image trigger
+	// +optional
+	Containers []ContainerImageStatus `json:"containers,omitempty"`
+}
+
+// ImageStreamTagImageStatus defines the image an ImageStream tag resolved to
+type ImageStreamTagImageStatus struct {
+	// ImageStreamTag name. Eg. amp-system:2.8
+	Name string `json:"name"`
+	// Image the tag is imported from
+	Image string `json:"image"`
+	// Image by digest the tag resolved to. Empty until imported
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+	// The tag is pinned to ResolvedImage
+	// +optional
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// ContainerImageStatus defines the image a DeploymentConfig container runs
+type ContainerImageStatus struct {
+	DeploymentConfig string `json:"deploymentConfig"`
+	Container        string `json:"container"`
+	// ImageStreamTag the container image is triggered from
+	ImageStreamTag string `json:"imageStreamTag"`
+	// Image the container runs
+	Image string `json:"image"`
+}
+
+// APIManagerUpgradeStatus defines the progress of an upgrade of the
+// APIManager between operator versions
+type APIManagerUpgradeStatus struct {
+	// Operator version the APIManager is upgraded from
+	FromVersion string `json:"fromVersion"`
+	// Operator version the APIManager is upgraded to
+	ToVersion string `json:"toVersion"`
+	// 3scale release the APIManager is upgraded from
+	// +optional
+	FromRelease string `json:"fromRelease,omitempty"`
+	// 3scale release the APIManager is upgraded to
+	// +optional
+	ToRelease string `json:"toRelease,omitempty"`
+	// Upgrade steps already completed. Completed steps are not run again
+	// when the upgrade is resumed
+	// +optional
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+	// Upgrade step being run
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty"`
+	// Reason the upgrade is stopped: unsupported versions, a failed
+	// preflight check or a failed step
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Name of the ConfigMap and Secret with the snapshot of the managed
+	// objects taken before upgrading
+	// +optional
+	SnapshotName string `json:"snapshotName,omitempty"`
+	// Time all the upgrade steps were completed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Components were available after the upgrade. Verified upgrades are
+	// not rolled back
+	// +optional
+	Verified bool `json:"verified,omitempty"`
+}
+
+// BackendWorkerQueueScalingStatus defines the observed length of the
+// backend-redis job queues and the backend-worker replicas computed from it
+type BackendWorkerQueueScalingStatus struct {
+	// Total number of queued jobs
+	QueueLength int64 `json:"queueLength"`
+	// Replicas of backend-worker for QueueLength
+	DesiredReplicas int32 `json:"desiredReplicas"`
+	// Last time the queue lengths were read with a different result
+	LastObservedTime metav1.Time `json:"lastObservedTime"`
+}
+
+// APIManagerComponentStatus defines the observed state of an APIManager
+// component
+type APIManagerComponentStatus struct {
+	Name APIManagerComponentName `json:"name"`
+	// +optional
+	Conditions []APIManagerCondition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -75,31 +377,53 @@ type APIManager struct {
 type APIManagerConditionType string
 
 const (
-	// Ready means the APIManager is available. This is, when all of its
-	// elements are up and running
-	APIManagerReady APIManagerConditionType = "Ready"
-	// Progressing means the APIManager is being deployed
+	// APIManagerAvailable means the APIManager is available. This is, when all
+	// of its elements are up and running
+	APIManagerAvailable APIManagerConditionType = "Available"
+	// APIManagerProgressing means the APIManager is being deployed
 	APIManagerProgressing APIManagerConditionType = "Progressing"
+	// APIManagerDegraded means some of the APIManager elements failed to be
+	// deployed or reconciled
+	APIManagerDegraded APIManagerConditionType = "Degraded"
+	// APIManagerUpgradeInProgress means the APIManager is being upgraded to
+	// the version of the running operator
+	APIManagerUpgradeInProgress APIManagerConditionType = "UpgradeInProgress"
+	// APIManagerExternalDatabaseInvalid means the external databases
+	// configuration required in high availability mode is missing or invalid
+	APIManagerExternalDatabaseInvalid APIManagerConditionType = "ExternalDatabaseInvalid"
+	// APIManagerImagesDiverged means some DeploymentConfig containers do
+	// not run the image their ImageStream tag resolved to
+	APIManagerImagesDiverged APIManagerConditionType = "ImagesDiverged"
+	// APIManagerPaused means the reconciliation of the APIManager or of
+	// some of its components is paused
+	APIManagerPaused APIManagerConditionType = "Paused"
+	// APIManagerApicastCustomPoliciesInvalid means some ConfigMap
+	// referenced by the APIcast custom policies is missing. The APIcast
+	// DeploymentConfigs are not reconciled until it is created
+	APIManagerApicastCustomPoliciesInvalid APIManagerConditionType = "ApicastCustomPoliciesInvalid"
+)
+
+type APIManagerComponentName string
+
+const (
+	APIManagerSystemComponent    APIManagerComponentName = "system"
+	APIManagerBackendComponent   APIManagerComponentName = "backend"
+	APIManagerApicastComponent   APIManagerComponentName = "apicast"
+	APIManagerZyncComponent      APIManagerComponentName = "zync"
+	APIManagerRedisComponent     APIManagerComponentName = "redis"
+	APIManagerDatabasesComponent APIManagerComponentName = "databases"
 )
 
 type APIManagerCondition struct {
 	Type   APIManagerConditionType `json:"type" description:"type of APIManager condition"`
 	Status v1.ConditionStatus      `json:"status" description:"status of the condition, one of True, False, Unknown"` //TODO should be a custom ConditionStatus or the core v1 one?
 
-	// The Reason, Message, LastHeartbeatTime and LastTransitionTime fields are
-	// optional. Unless we really use them they should directly not be used even
-	// if they are optional
-
 	// +optional
-	//Reason *string `json:"reason,omitempty" description:"one-word CamelCase reason for the condition's last transition"`
+	Reason string `json:"reason,omitempty" description:"one-word CamelCase reason for the condition's last transition"`
 	// +optional
-	//Message *string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
-
+	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
 	// +optional
-	//LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime,omitempty" description:"last time we got an update on a given condition"` // TODO the Kubernetes API convention guide says *unversioned.Time should be used but that seems to be a client-side package. I've seen that objects like PersistentVolumeClaim use metav1.Time
-	// +optional
-	//metav1.Time        `json:"lastProbeTime,omitempty" protobuf:"bytes,3,opt,name=lastProbeTime"`
-	//LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" description:"last time the condition transit from one status to another"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty" description:"last time the condition transit from one status to another"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -119,8 +443,26 @@ type APIManagerCommonSpec struct {
 	TenantName *string `json:"tenantName,omitempty"`
 	// +optional
 	ImageStreamTagImportInsecure *bool `json:"imageStreamTagImportInsecure,omitempty"`
+	// Registry, and optional repository path, replacing the registry of
+	// every image. Eg. mirror.example.com/3scale pulls
+	// quay.io/3scale/porta:nightly from
+	// mirror.example.com/3scale/3scale/porta:nightly
+	// +optional
+	ImageRegistryMirror *string `json:"imageRegistryMirror,omitempty"`
+	// Secrets added to the image pull secrets of the amp and zync-que-sa
+	// service accounts
+	// +optional
+	ImagePullSecrets []v1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Pin the ImageStream tags to the digest of the images they resolved
+	// to. Only supported with the DeploymentConfig workload type
+	// +optional
+	ImageDigestPinningEnabled *bool `json:"imageDigestPinningEnabled,omitempty"`
 	// +optional
 	ResourceRequirementsEnabled *bool `json:"resourceRequirementsEnabled,omitempty"`
+	// +optional
+	WorkloadType *string `json:"workloadType,omitempty"`
+	// +optional
+	ExposureType *string `json:"exposureType,omitempty"`
 }
 
 type ApicastSpec struct {
@@ -138,16 +480,104 @@ type ApicastSpec struct {
 	ProductionSpec *ApicastProductionSpec `json:"productionSpec,omitempty"`
 	// +optional
 	StagingSpec *ApicastStagingSpec `json:"stagingSpec,omitempty"`
+	// +optional
+	CustomPolicies []CustomPolicySpec `json:"customPolicies,omitempty"`
+	// HTTPS enables the HTTPS listener of apicast-staging and
+	// apicast-production
+	// +optional
+	HTTPS *ApicastHTTPSSpec `json:"https,omitempty"`
+}
+
+// ApicastHTTPSSpec defines the HTTPS listener of the APIcast gateways
+type ApicastHTTPSSpec struct {
+	// Port of the HTTPS listener. Defaults to 8443
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// CertificateSecretRef references a kubernetes.io/tls secret with the
+	// certificate and key served by the HTTPS listener
+	CertificateSecretRef v1.LocalObjectReference `json:"certificateSecretRef"`
+	// RouteTermination is the TLS termination of the HTTPS Routes. One of
+	// passthrough or reencrypt. Defaults to passthrough
+	// +optional
+	RouteTermination *string `json:"routeTermination,omitempty"`
+}
+
+// CustomPolicySpec defines a custom APIcast policy loaded by apicast-staging
+// and apicast-production. The policy files are taken either from a
+// ConfigMap or from a container image
+type CustomPolicySpec struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// ConfigMap holding the policy files, one file per key
+	// +optional
+	ConfigMapRef *v1.LocalObjectReference `json:"configMapRef,omitempty"`
+	// Image holding the policy files
+	// +optional
+	Image *string `json:"image,omitempty"`
+	// Directory of Image holding the policy files
+	// +optional
+	ImagePath *string `json:"imagePath,omitempty"`
 }
 
 type ApicastProductionSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	ApicastRuntimeSpec `json:",inline"`
 }
 
 type ApicastStagingSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+
+	ApicastRuntimeSpec `json:",inline"`
+}
+
+// ApicastRuntimeSpec holds the APIcast runtime settings of an APIcast
+// deployment. APIcast defaults apply to the settings not set
+type ApicastRuntimeSpec struct {
+	// APIcast log level. One of debug, info, notice, warn, error, crit,
+	// alert or emerg
+	// +optional
+	LogLevel *string `json:"logLevel,omitempty"`
+	// Number of nginx worker processes
+	// +optional
+	Workers *int32 `json:"workers,omitempty"`
+	// How the configuration is loaded. One of boot or lazy
+	// +optional
+	ConfigurationLoader *string `json:"configurationLoader,omitempty"`
+	// Seconds the configuration is cached for
+	// +optional
+	ConfigurationCacheTTL *int32 `json:"configurationCacheTTL,omitempty"`
+	// Enables path based routing of the services
+	// +optional
+	PathRouting *bool `json:"pathRouting,omitempty"`
+	// Only services with a public base URL matching this regular expression
+	// are loaded
+	// +optional
+	ServicesFilterByURL *string `json:"servicesFilterByURL,omitempty"`
+	// Only the services with these IDs are loaded
+	// +optional
+	ServicesList []string `json:"servicesList,omitempty"`
+	// Custom environment files loaded by APIcast
+	// +optional
+	CustomEnvironments []CustomEnvironmentSpec `json:"customEnvironments,omitempty"`
+}
+
+// CustomEnvironmentSpec defines a ConfigMap holding APIcast environment
+// files, one file per key
+type CustomEnvironmentSpec struct {
+	ConfigMapRef v1.LocalObjectReference `json:"configMapRef"`
 }
 
 type BackendSpec struct {
@@ -156,6 +586,10 @@ type BackendSpec struct {
 	// +optional
 	RedisImage *string `json:"redisImage,omitempty"`
 	// +optional
+	RedisResources *v1.ResourceRequirements `json:"redisResources,omitempty"`
+	// +optional
+	RedisPlacement *PodPlacementSpec `json:"redisPlacement,omitempty"`
+	// +optional
 	ListenerSpec *BackendListenerSpec `json:"listenerSpec,omitempty"`
 	// +optional
 	WorkerSpec *BackendWorkerSpec `json:"workerSpec,omitempty"`
@@ -166,16 +600,34 @@ type BackendSpec struct {
 type BackendListenerSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
 }
 
 type BackendWorkerSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// +optional
+	QueueScaling *BackendWorkerQueueScalingSpec `json:"queueScaling,omitempty"`
 }
 
 type BackendCronSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
 }
 
 type SystemSpec struct {
@@ -185,9 +637,21 @@ type SystemSpec struct {
 	// +optional
 	MemcachedImage *string `json:"memcachedImage,omitempty"`
 
+	// +optional
+	MemcachedResources *v1.ResourceRequirements `json:"memcachedResources,omitempty"`
+
+	// +optional
+	MemcachedPlacement *PodPlacementSpec `json:"memcachedPlacement,omitempty"`
+
 	// +optional
 	RedisImage *string `json:"redisImage,omitempty"`
 
+	// +optional
+	RedisResources *v1.ResourceRequirements `json:"redisResources,omitempty"`
+
+	// +optional
+	RedisPlacement *PodPlacementSpec `json:"redisPlacement,omitempty"`
+
 	// TODO should this field be optional? We have different approaches in Kubernetes.
 	// For example, in v1.Volume it is optional and there's an implied behaviour
 	// on which one is the default VolumeSource of the ones available. However,
@@ -206,16 +670,67 @@ type SystemSpec struct {
 
 	AppSpec     *SystemAppSpec     `json:"appSpec,omitempty"`
 	SidekiqSpec *SystemSidekiqSpec `json:"sidekiqSpec,omitempty"`
+
+	// +optional
+	SphinxSpec *SystemSphinxSpec `json:"sphinxSpec,omitempty"`
+
+	// SMTPSpec configures the outbound mail of system. When set, the
+	// operator manages the system-smtp secret
+	// +optional
+	SMTPSpec *SystemSMTPSpec `json:"smtp,omitempty"`
+}
+
+type SystemSMTPSpec struct {
+	// Address of the SMTP server
+	Address string `json:"address"`
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+	// Domain sent in the HELO command
+	// +optional
+	Domain *string `json:"domain,omitempty"`
+	// Authentication mode: plain, login or cram_md5. No authentication
+	// when not set
+	// +optional
+	Authentication *string `json:"authentication,omitempty"`
+	// OpenSSLVerifyMode when using TLS: none, peer, client_once or
+	// fail_if_no_peer_cert
+	// +optional
+	OpenSSLVerifyMode *string `json:"opensslVerifyMode,omitempty"`
+	// CredentialsSecretRef references a secret with the username and
+	// password fields. Required when authentication is set
+	// +optional
+	CredentialsSecretRef *v1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
 
 type SystemAppSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	MasterContainerResources *v1.ResourceRequirements `json:"masterContainerResources,omitempty"`
+	// +optional
+	ProviderContainerResources *v1.ResourceRequirements `json:"providerContainerResources,omitempty"`
+	// +optional
+	DeveloperContainerResources *v1.ResourceRequirements `json:"developerContainerResources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
 }
 
 type SystemSidekiqSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+}
+
+type SystemSphinxSpec struct {
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
 }
 
 type SystemFileStorageSpec struct {
@@ -258,11 +773,19 @@ type SystemDatabaseSpec struct {
 type SystemMySQLSpec struct {
 	// +optional
 	Image *string `json:"image,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
 }
 
 type SystemPostgreSQLSpec struct {
 	// +optional
 	Image *string `json:"image,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
 }
 
 type ZyncSpec struct {
@@ -271,6 +794,12 @@ type ZyncSpec struct {
 	// +optional
 	PostgreSQLImage *string `json:"postgreSQLImage,omitempty"`
 
+	// +optional
+	DatabaseResources *v1.ResourceRequirements `json:"databaseResources,omitempty"`
+
+	// +optional
+	DatabasePlacement *PodPlacementSpec `json:"databasePlacement,omitempty"`
+
 	// +optional
 	AppSpec *ZyncAppSpec `json:"appSpec,omitempty"`
 
@@ -281,21 +810,240 @@ type ZyncSpec struct {
 type ZyncAppSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
 }
 
 type ZyncQueSpec struct {
 	// +optional
 	Replicas *int64 `json:"replicas,omitempty"`
+	// +optional
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Placement *PodPlacementSpec `json:"placement,omitempty"`
 }
 
 type HighAvailabilitySpec struct {
 	Enabled bool `json:"enabled,omitempty"`
+	// SystemDatabaseTLSEnabled mounts the CA certificate, and the client
+	// certificate when set, of the system-database secret into the system
+	// pods
+	// +optional
+	SystemDatabaseTLSEnabled bool `json:"systemDatabaseTLSEnabled,omitempty"`
+	// BackendRedisTLSEnabled mounts the CA certificate, and the client
+	// certificate when set, of the backend-redis secret into the backend
+	// and system pods
+	// +optional
+	BackendRedisTLSEnabled bool `json:"backendRedisTLSEnabled,omitempty"`
+	// SystemRedisTLSEnabled mounts the CA certificate, and the client
+	// certificate when set, of the system-redis secret into the system pods
+	// +optional
+	SystemRedisTLSEnabled bool `json:"systemRedisTLSEnabled,omitempty"`
+	// ExternalZyncDatabaseEnabled uses the external PostgreSQL database set
+	// in the DATABASE_URL field of the zync secret instead of deploying
+	// zync-database
+	// +optional
+	ExternalZyncDatabaseEnabled bool `json:"externalZyncDatabaseEnabled,omitempty"`
 }
 
 type PodDisruptionBudgetSpec struct {
 	Enabled bool `json:"enabled,omitempty"`
 }
 
+// RedisSpec defines how the backend-redis and system-redis instances are
+// deployed by the operator. It does not apply when high availability is
+// enabled, redis is external then
+type RedisSpec struct {
+	// Mode is one of Standalone or Sentinel. Defaults to Standalone
+	// +optional
+	Mode *string `json:"mode,omitempty"`
+	// +optional
+	Sentinel *RedisSentinelSpec `json:"sentinel,omitempty"`
+}
+
+// RedisSentinelSpec configures the redis master/replica sets and sentinels
+// deployed in Sentinel mode
+type RedisSentinelSpec struct {
+	// Replicas of each redis instance, the master included. Defaults to 3
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+	// SentinelReplicas is the number of sentinels. Defaults to 3
+	// +optional
+	SentinelReplicas *int32 `json:"sentinelReplicas,omitempty"`
+	// Quorum is the number of sentinels that need to agree a master is down
+	// to start a failover. Defaults to a majority of the sentinels
+	// +optional
+	Quorum *int32 `json:"quorum,omitempty"`
+	// +optional
+	SentinelResources *v1.ResourceRequirements `json:"sentinelResources,omitempty"`
+	// +optional
+	SentinelPlacement *PodPlacementSpec `json:"sentinelPlacement,omitempty"`
+}
+
+// IsSentinelEnabled returns true when the redis instances are deployed in
+// Sentinel mode
+func (r *RedisSpec) IsSentinelEnabled() bool {
+	return r != nil && r.Mode != nil && *r.Mode == RedisModeSentinel
+}
+
+// AutoscalingSpec configures the HorizontalPodAutoscaler managing the
+// replicas of a component. The replicas set in the component spec are
+// ignored while autoscaling is enabled
+type AutoscalingSpec struct {
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32  `json:"maxReplicas"`
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+}
+
+// BackendWorkerQueueScalingSpec configures the operator to scale
+// backend-worker based on the number of jobs waiting in the backend-redis
+// queues. The replicas set in the component spec are ignored while queue
+// scaling is enabled
+type BackendWorkerQueueScalingSpec struct {
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	MaxReplicas int32  `json:"maxReplicas"`
+	// Number of queued jobs handled by each backend-worker replica
+	// +optional
+	TargetQueueLength *int64 `json:"targetQueueLength,omitempty"`
+	// Seconds between two reads of the queue lengths
+	// +optional
+	SyncPeriodSeconds *int32 `json:"syncPeriodSeconds,omitempty"`
+}
+
+// PodPlacementSpec holds the scheduling constraints applied to the pods of
+// a component
+type PodPlacementSpec struct {
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+	// TopologySpreadConstraints spread the pods of the deployment across
+	// topology domains. The topologySpreadConstraints pod field is not
+	// available in the Kubernetes API version the operator is built
+	// against, so they are emulated with pod anti-affinity terms
+	// +optional
+	TopologySpreadConstraints []TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+// TopologySpreadConstraint spreads the pods of a deployment across the
+// domains of a node label. The pod anti-affinity emulating it places at
+// most one pod of the deployment per domain
+type TopologySpreadConstraint struct {
+	// MaxSkew must be 1, the only skew the emulation can enforce. Defaults
+	// to 1
+	// +optional
+	MaxSkew *int32 `json:"maxSkew,omitempty"`
+	// TopologyKey is the node label of the domains, like
+	// topology.kubernetes.io/zone
+	TopologyKey string `json:"topologyKey"`
+	// WhenUnsatisfiable is one of DoNotSchedule or ScheduleAnyway. Defaults
+	// to DoNotSchedule, which leaves pending the pods exceeding the number
+	// of domains. ScheduleAnyway only prefers the domains without pods of
+	// the deployment
+	// +optional
+	WhenUnsatisfiable *string `json:"whenUnsatisfiable,omitempty"`
+}
+
+// IngressSpec configures the Ingresses created when the exposure type is
+// Ingress
+type IngressSpec struct {
+	// IngressClassName selects the ingress controller serving the Ingresses
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// Annotations added to all the Ingresses
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// TLSSecretRef references the secret with the TLS certificate for the
+	// exposed hosts. TLS is not configured when it is not set
+	// +optional
+	TLSSecretRef *v1.LocalObjectReference `json:"tlsSecretRef,omitempty"`
+}
+
+// MonitoringSpec configures the Prometheus and Grafana objects created for
+// the APIManager components. The PodMonitors and the PrometheusRule are
+// skipped when the Prometheus operator CRDs are not installed
+type MonitoringSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Labels added to the monitoring objects, usually to match the
+	// selectors of the Prometheus instance
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// IsEnabled returns true when the monitoring objects are created
+func (m *MonitoringSpec) IsEnabled() bool {
+	return m != nil && m.Enabled
+}
+
+// UpgradeSafetyGateSpec configures the safety gate of the upgrades between
+// operator versions. The objects managed by the operator are snapshotted
+// before upgrading
+type UpgradeSafetyGateSpec struct {
+	Enabled bool `json:"enabled,omitempty"`
+	// Wait for the UpgradeApprovedAnnotation set to the operator version
+	// before upgrading
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+	// Seconds the components are allowed to be unavailable after the
+	// upgrade. Once passed, the managed objects are rolled back to the
+	// snapshot. No rollback when not set
+	// +optional
+	RollbackTimeoutSeconds *int64 `json:"rollbackTimeoutSeconds,omitempty"`
+}
+
+// CredentialRotationSpec schedules the rotation of the credentials
+// generated by the operator. Credentials are also rotated on demand with
+// the RotateCredentialsAnnotation
+type CredentialRotationSpec struct {
+	// Days between the rotations of each credential
+	IntervalDays int32 `json:"intervalDays"`
+	// Credentials rotated on schedule. All the RotatableCredentials when
+	// empty
+	// +optional
+	Credentials []string `json:"credentials,omitempty"`
+}
+
+// ScheduledCredentials returns the credentials rotated on schedule
+func (c *CredentialRotationSpec) ScheduledCredentials() []string {
+	if c == nil {
+		return nil
+	}
+	if len(c.Credentials) == 0 {
+		return RotatableCredentials
+	}
+	return c.Credentials
+}
+
+// IsRotatableCredential returns true when the operator can rotate the
+// credential of the given name
+func IsRotatableCredential(name string) bool {
+	for _, credential := range RotatableCredentials {
+		if credential == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsUpgradeSafetyGateEnabled returns true when the managed objects are
+// snapshotted before upgrading
+func (apimanager *APIManager) IsUpgradeSafetyGateEnabled() bool {
+	return apimanager.Spec.UpgradeSafetyGate != nil && apimanager.Spec.UpgradeSafetyGate.Enabled
+}
+
 func init() {
 	SchemeBuilder.Register(&APIManager{}, &APIManagerList{})
 }
@@ -308,8 +1056,11 @@ func (apimanager *APIManager) SetDefaults() (bool, error) {
 	tmpChanged := apimanager.setAPIManagerAnnotationsDefaults()
 	changed = changed || tmpChanged
 
-	tmpChanged = apimanager.setAPIManagerCommonSpecDefaults()
+	tmpChanged, err = apimanager.setAPIManagerCommonSpecDefaults()
 	changed = changed || tmpChanged
+	if err != nil {
+		return changed, err
+	}
 
 	tmpChanged = apimanager.setBackendSpecDefaults()
 	changed = changed || tmpChanged
@@ -326,6 +1077,63 @@ func (apimanager *APIManager) SetDefaults() (bool, error) {
 	tmpChanged = apimanager.setZyncDefaults()
 	changed = changed || tmpChanged
 
+	err = apimanager.validateAutoscalingSpecs()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateApicastCustomPolicies()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateApicastRuntimeSpecs()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateApicastHTTPSSpec()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateRedisSpec()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateSystemSMTPSpec()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateUpgradeSafetyGateSpec()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateImageRegistryMirror()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateImageDigestPinning()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateWorkloadExposure()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validatePodPlacementSpecs()
+	if err != nil {
+		return changed, err
+	}
+
+	err = apimanager.validateCredentialRotationSpec()
+
 	return changed, err
 }
 
@@ -450,7 +1258,7 @@ func (apimanager *APIManager) setBackendSpecDefaults() bool {
 	return changed
 }
 
-func (apimanager *APIManager) setAPIManagerCommonSpecDefaults() bool {
+func (apimanager *APIManager) setAPIManagerCommonSpecDefaults() (bool, error) {
 	changed := false
 	spec := &apimanager.Spec
 
@@ -458,6 +1266,7 @@ func (apimanager *APIManager) setAPIManagerCommonSpecDefaults() bool {
 	tmpDefaultTenantName := defaultTenantName
 	tmpDefaultImageStreamTagImportInsecure := defaultImageStreamImportInsecure
 	tmpDefaultResourceRequirementsEnabled := defaultResourceRequirementsEnabled
+	tmpDefaultWorkloadType := defaultWorkloadType
 
 	if spec.AppLabel == nil {
 		spec.AppLabel = &tmpDefaultAppLabel
@@ -479,10 +1288,29 @@ func (apimanager *APIManager) setAPIManagerCommonSpecDefaults() bool {
 		changed = true
 	}
 
+	if spec.WorkloadType == nil {
+		spec.WorkloadType = &tmpDefaultWorkloadType
+		changed = true
+	}
+
+	if *spec.WorkloadType != WorkloadTypeDeploymentConfig && *spec.WorkloadType != WorkloadTypeDeployment {
+		return changed, fmt.Errorf("Unsupported workload type '%s'. Only %s and %s are supported", *spec.WorkloadType, WorkloadTypeDeploymentConfig, WorkloadTypeDeployment)
+	}
+
+	tmpDefaultExposureType := defaultExposureType
+	if spec.ExposureType == nil {
+		spec.ExposureType = &tmpDefaultExposureType
+		changed = true
+	}
+
+	if *spec.ExposureType != ExposureTypeRoute && *spec.ExposureType != ExposureTypeIngress {
+		return changed, fmt.Errorf("Unsupported exposure type '%s'. Only %s and %s are supported", *spec.ExposureType, ExposureTypeRoute, ExposureTypeIngress)
+	}
+
 	// TODO do something with mandatory parameters?
 	// TODO check that only compatible ProductRelease versions are compatible?
 
-	return changed
+	return changed, nil
 }
 
 func (apimanager *APIManager) setSystemSpecDefaults() (bool, error) {
@@ -594,10 +1422,471 @@ func (apimanager *APIManager) setZyncDefaults() bool {
 	return changed
 }
 
+func (apimanager *APIManager) validateAutoscalingSpecs() error {
+	spec := &apimanager.Spec
+	autoscalingSpecs := []struct {
+		componentName string
+		autoscaling   *AutoscalingSpec
+	}{
+		{"apicast-production", spec.Apicast.ProductionSpec.Autoscaling},
+		{"backend-listener", spec.Backend.ListenerSpec.Autoscaling},
+		{"backend-worker", spec.Backend.WorkerSpec.Autoscaling},
+		{"system-app", spec.System.AppSpec.Autoscaling},
+		{"zync", spec.Zync.AppSpec.Autoscaling},
+	}
+
+	for _, item := range autoscalingSpecs {
+		autoscaling := item.autoscaling
+		if autoscaling == nil {
+			continue
+		}
+		if autoscaling.MaxReplicas < 1 {
+			return fmt.Errorf("Invalid %s autoscaling. maxReplicas must be greater than 0", item.componentName)
+		}
+		if autoscaling.MinReplicas != nil && (*autoscaling.MinReplicas < 1 || *autoscaling.MinReplicas > autoscaling.MaxReplicas) {
+			return fmt.Errorf("Invalid %s autoscaling. minReplicas must be between 1 and maxReplicas", item.componentName)
+		}
+	}
+
+	queueScaling := spec.Backend.WorkerSpec.QueueScaling
+	if queueScaling != nil {
+		if spec.Backend.WorkerSpec.Autoscaling != nil {
+			return fmt.Errorf("Invalid backend-worker queue scaling. autoscaling and queueScaling cannot be set at the same time")
+		}
+		if queueScaling.MaxReplicas < 1 {
+			return fmt.Errorf("Invalid backend-worker queue scaling. maxReplicas must be greater than 0")
+		}
+		if queueScaling.MinReplicas != nil && (*queueScaling.MinReplicas < 1 || *queueScaling.MinReplicas > queueScaling.MaxReplicas) {
+			return fmt.Errorf("Invalid backend-worker queue scaling. minReplicas must be between 1 and maxReplicas")
+		}
+		if queueScaling.TargetQueueLength != nil && *queueScaling.TargetQueueLength < 1 {
+			return fmt.Errorf("Invalid backend-worker queue scaling. targetQueueLength must be greater than 0")
+		}
+		if queueScaling.SyncPeriodSeconds != nil && *queueScaling.SyncPeriodSeconds < 1 {
+			return fmt.Errorf("Invalid backend-worker queue scaling. syncPeriodSeconds must be greater than 0")
+		}
+	}
+
+	return nil
+}
+
+// deploymentPlacement is the placement of the pods of a deployment
+type deploymentPlacement struct {
+	deploymentName string
+	placement      *PodPlacementSpec
+}
+
+func (apimanager *APIManager) validatePodPlacementSpecs() error {
+	spec := &apimanager.Spec
+	placements := []deploymentPlacement{
+		{"apicast-production", spec.Apicast.ProductionSpec.Placement},
+		{"apicast-staging", spec.Apicast.StagingSpec.Placement},
+		{"backend-listener", spec.Backend.ListenerSpec.Placement},
+		{"backend-worker", spec.Backend.WorkerSpec.Placement},
+		{"backend-cron", spec.Backend.CronSpec.Placement},
+		{"backend-redis", spec.Backend.RedisPlacement},
+		{"system-app", spec.System.AppSpec.Placement},
+		{"system-sidekiq", spec.System.SidekiqSpec.Placement},
+		{"system-memcache", spec.System.MemcachedPlacement},
+		{"system-redis", spec.System.RedisPlacement},
+		{"zync", spec.Zync.AppSpec.Placement},
+		{"zync-que", spec.Zync.QueSpec.Placement},
+		{"zync-database", spec.Zync.DatabasePlacement},
+	}
+	if spec.System.SphinxSpec != nil {
+		placements = append(placements, deploymentPlacement{"system-sphinx", spec.System.SphinxSpec.Placement})
+	}
+	if databaseSpec := spec.System.DatabaseSpec; databaseSpec != nil {
+		if databaseSpec.MySQL != nil {
+			placements = append(placements, deploymentPlacement{"system-mysql", databaseSpec.MySQL.Placement})
+		}
+		if databaseSpec.PostgreSQL != nil {
+			placements = append(placements, deploymentPlacement{"system-postgresql", databaseSpec.PostgreSQL.Placement})
+		}
+	}
+	if spec.Redis != nil && spec.Redis.Sentinel != nil {
+		placements = append(placements, deploymentPlacement{"redis-sentinel", spec.Redis.Sentinel.SentinelPlacement})
+	}
+
+	for _, item := range placements {
+		err := validatePodPlacementSpec(item.deploymentName, item.placement)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePodPlacementSpec(deploymentName string, placement *PodPlacementSpec) error {
+	if placement == nil {
+		return nil
+	}
+
+	for _, constraint := range placement.TopologySpreadConstraints {
+		if constraint.TopologyKey == "" {
+			return fmt.Errorf("Invalid %s topologySpreadConstraints. topologyKey is required", deploymentName)
+		}
+		if constraint.MaxSkew != nil && *constraint.MaxSkew != 1 {
+			return fmt.Errorf("Invalid %s topologySpreadConstraints maxSkew %d. Only 1 is supported, the constraints are emulated with pod anti-affinity", deploymentName, *constraint.MaxSkew)
+		}
+		if constraint.WhenUnsatisfiable != nil &&
+			*constraint.WhenUnsatisfiable != TopologySpreadDoNotSchedule &&
+			*constraint.WhenUnsatisfiable != TopologySpreadScheduleAnyway {
+			return fmt.Errorf("Invalid %s topologySpreadConstraints whenUnsatisfiable '%s'. It must be %s or %s", deploymentName, *constraint.WhenUnsatisfiable, TopologySpreadDoNotSchedule, TopologySpreadScheduleAnyway)
+		}
+	}
+
+	return nil
+}
+
+func (apimanager *APIManager) validateApicastCustomPolicies() error {
+	return validateCustomPolicySpecs(apimanager.Spec.Apicast.CustomPolicies)
+}
+
+func (apimanager *APIManager) validateApicastRuntimeSpecs() error {
+	err := validateApicastRuntimeSpec("apicast-production", apimanager.Spec.Apicast.ProductionSpec.ApicastRuntimeSpec)
+	if err != nil {
+		return err
+	}
+
+	return validateApicastRuntimeSpec("apicast-staging", apimanager.Spec.Apicast.StagingSpec.ApicastRuntimeSpec)
+}
+
+func (apimanager *APIManager) validateApicastHTTPSSpec() error {
+	return validateApicastHTTPSSpec(apimanager.Spec.Apicast.HTTPS)
+}
+
+func validateCustomPolicySpecs(customPolicies []CustomPolicySpec) error {
+	policies := map[string]bool{}
+	for _, policy := range customPolicies {
+		if policy.Name == "" || policy.Version == "" {
+			return fmt.Errorf("Invalid APIcast custom policy. name and version are required")
+		}
+		if (policy.ConfigMapRef == nil) == (policy.Image == nil) {
+			return fmt.Errorf("Invalid APIcast custom policy %s %s. One of configMapRef or image must be set", policy.Name, policy.Version)
+		}
+		if policy.ImagePath != nil && policy.Image == nil {
+			return fmt.Errorf("Invalid APIcast custom policy %s %s. imagePath requires image", policy.Name, policy.Version)
+		}
+		key := policy.Name + "/" + policy.Version
+		if policies[key] {
+			return fmt.Errorf("Invalid APIcast custom policy %s %s. Duplicated policy", policy.Name, policy.Version)
+		}
+		policies[key] = true
+	}
+
+	return nil
+}
+
+func validateApicastRuntimeSpec(componentName string, runtime ApicastRuntimeSpec) error {
+	if runtime.LogLevel != nil && !apicastLogLevels[*runtime.LogLevel] {
+		return fmt.Errorf("Invalid %s logLevel '%s'", componentName, *runtime.LogLevel)
+	}
+	if runtime.Workers != nil && *runtime.Workers < 1 {
+		return fmt.Errorf("Invalid %s workers. It must be greater than 0", componentName)
+	}
+	if runtime.ConfigurationLoader != nil && *runtime.ConfigurationLoader != "boot" && *runtime.ConfigurationLoader != "lazy" {
+		return fmt.Errorf("Invalid %s configurationLoader '%s'. It must be boot or lazy", componentName, *runtime.ConfigurationLoader)
+	}
+	for _, customEnvironment := range runtime.CustomEnvironments {
+		if customEnvironment.ConfigMapRef.Name == "" {
+			return fmt.Errorf("Invalid %s custom environment. configMapRef name is required", componentName)
+		}
+	}
+
+	return nil
+}
+
+func validateApicastHTTPSSpec(https *ApicastHTTPSSpec) error {
+	if https == nil {
+		return nil
+	}
+
+	if https.CertificateSecretRef.Name == "" {
+		return fmt.Errorf("Invalid APIcast https. certificateSecretRef name is required")
+	}
+	if https.Port != nil {
+		switch port := *https.Port; {
+		case port < 1 || port > 65535:
+			return fmt.Errorf("Invalid APIcast https port %d", port)
+		case port == 8080 || port == 8090 || port == 9421:
+			return fmt.Errorf("Invalid APIcast https port %d. It is already used by APIcast", port)
+		}
+	}
+	if https.RouteTermination != nil &&
+		*https.RouteTermination != ApicastHTTPSRouteTerminationPassthrough &&
+		*https.RouteTermination != ApicastHTTPSRouteTerminationReencrypt {
+		return fmt.Errorf("Invalid APIcast https routeTermination '%s'. It must be %s or %s", *https.RouteTermination, ApicastHTTPSRouteTerminationPassthrough, ApicastHTTPSRouteTerminationReencrypt)
+	}
+
+	return nil
+}
+
+func (apimanager *APIManager) validateUpgradeSafetyGateSpec() error {
+	gateSpec := apimanager.Spec.UpgradeSafetyGate
+	if gateSpec == nil || gateSpec.RollbackTimeoutSeconds == nil {
+		return nil
+	}
+
+	if *gateSpec.RollbackTimeoutSeconds < 1 {
+		return fmt.Errorf("Invalid upgradeSafetyGate rollbackTimeoutSeconds. It must be greater than 0")
+	}
+	return nil
+}
+
+func (apimanager *APIManager) validateImageRegistryMirror() error {
+	mirror := apimanager.Spec.ImageRegistryMirror
+	if mirror == nil {
+		return nil
+	}
+
+	if strings.Trim(*mirror, "/") == "" || strings.Contains(*mirror, "://") {
+		return fmt.Errorf("Invalid imageRegistryMirror '%s'. It must be a registry host, optionally followed by a repository path, without scheme", *mirror)
+	}
+	return nil
+}
+
+func (apimanager *APIManager) validateImageDigestPinning() error {
+	if apimanager.IsImageDigestPinningEnabled() && apimanager.IsKubernetesDeploymentEnabled() {
+		return fmt.Errorf("Invalid imageDigestPinningEnabled. Images are only pinned with the %s workload type", WorkloadTypeDeploymentConfig)
+	}
+	return nil
+}
+
+// validateWorkloadExposure rejects Kubernetes Deployments exposed with
+// OpenShift Routes: the Route API is not available on the clusters the
+// Deployment workload type targets
+func (apimanager *APIManager) validateWorkloadExposure() error {
+	if apimanager.IsKubernetesDeploymentEnabled() && !apimanager.IsIngressEnabled() {
+		return fmt.Errorf("Invalid exposureType. It must be %s when workloadType is %s", ExposureTypeIngress, WorkloadTypeDeployment)
+	}
+	return nil
+}
+
+func (apimanager *APIManager) validateCredentialRotationSpec() error {
+	rotationSpec := apimanager.Spec.CredentialRotation
+	if rotationSpec == nil {
+		return nil
+	}
+	if rotationSpec.IntervalDays <= 0 {
+		return fmt.Errorf("Invalid credentialRotation intervalDays %d. It must be greater than 0", rotationSpec.IntervalDays)
+	}
+	for _, credential := range rotationSpec.Credentials {
+		if !IsRotatableCredential(credential) {
+			return fmt.Errorf("Invalid credentialRotation credential '%s'. It must be one of %s", credential, strings.Join(RotatableCredentials, ", "))
+		}
+	}
+	return nil
+}
+
+func (apimanager *APIManager) validateRedisSpec() error {
+	redisSpec := apimanager.Spec.Redis
+	if redisSpec == nil {
+		return nil
+	}
+
+	if redisSpec.Mode != nil && *redisSpec.Mode != RedisModeStandalone && *redisSpec.Mode != RedisModeSentinel {
+		return fmt.Errorf("Unsupported redis mode '%s'. Only %s and %s are supported", *redisSpec.Mode, RedisModeStandalone, RedisModeSentinel)
+	}
+	if !redisSpec.IsSentinelEnabled() {
+		return nil
+	}
+	if apimanager.IsExternalDatabaseEnabled() {
+		return fmt.Errorf("Invalid redis mode %s. Redis is external when high availability is enabled", RedisModeSentinel)
+	}
+
+	sentinelSpec := redisSpec.Sentinel
+	if sentinelSpec == nil {
+		return nil
+	}
+	if sentinelSpec.Replicas != nil && *sentinelSpec.Replicas < 2 {
+		return fmt.Errorf("Invalid redis sentinel replicas. It must be greater than 1")
+	}
+	if sentinelSpec.SentinelReplicas != nil && *sentinelSpec.SentinelReplicas < 1 {
+		return fmt.Errorf("Invalid redis sentinel sentinelReplicas. It must be greater than 0")
+	}
+	if sentinelSpec.Quorum != nil {
+		sentinelReplicas := int32(defaultRedisSentinelReplicas)
+		if sentinelSpec.SentinelReplicas != nil {
+			sentinelReplicas = *sentinelSpec.SentinelReplicas
+		}
+		if *sentinelSpec.Quorum < 1 || *sentinelSpec.Quorum > sentinelReplicas {
+			return fmt.Errorf("Invalid redis sentinel quorum. It must be between 1 and sentinelReplicas")
+		}
+	}
+
+	return nil
+}
+
+func (apimanager *APIManager) validateSystemSMTPSpec() error {
+	if apimanager.Spec.System == nil || apimanager.Spec.System.SMTPSpec == nil {
+		return nil
+	}
+	smtpSpec := apimanager.Spec.System.SMTPSpec
+
+	if smtpSpec.Address == "" {
+		return fmt.Errorf("Invalid system smtp. address is required")
+	}
+	if smtpSpec.Port != nil && (*smtpSpec.Port < 1 || *smtpSpec.Port > 65535) {
+		return fmt.Errorf("Invalid system smtp port '%d'. It must be between 1 and 65535", *smtpSpec.Port)
+	}
+	if smtpSpec.Authentication != nil {
+		if !systemSMTPAuthentications[*smtpSpec.Authentication] {
+			return fmt.Errorf("Invalid system smtp authentication '%s'. Only plain, login and cram_md5 are supported", *smtpSpec.Authentication)
+		}
+		if smtpSpec.CredentialsSecretRef == nil || smtpSpec.CredentialsSecretRef.Name == "" {
+			return fmt.Errorf("Invalid system smtp. authentication requires credentialsSecretRef")
+		}
+	}
+	if smtpSpec.OpenSSLVerifyMode != nil && !systemSMTPOpenSSLVerifyModes[*smtpSpec.OpenSSLVerifyMode] {
+		return fmt.Errorf("Invalid system smtp opensslVerifyMode '%s'", *smtpSpec.OpenSSLVerifyMode)
+	}
+
+	return nil
+}
+
 func (apimanager *APIManager) IsExternalDatabaseEnabled() bool {
 	return apimanager.Spec.HighAvailability != nil && apimanager.Spec.HighAvailability.Enabled
 }
 
+// IsExternalZyncDatabaseEnabled returns true when zync uses an external
+// database instead of the zync-database deployed by the operator
+func (apimanager *APIManager) IsExternalZyncDatabaseEnabled() bool {
+	return apimanager.IsExternalDatabaseEnabled() && apimanager.Spec.HighAvailability.ExternalZyncDatabaseEnabled
+}
+
+// IsImageDigestPinningEnabled returns true when the ImageStream tags are
+// pinned to the digest of the images they resolved to
+func (apimanager *APIManager) IsImageDigestPinningEnabled() bool {
+	return apimanager.Spec.ImageDigestPinningEnabled != nil && *apimanager.Spec.ImageDigestPinningEnabled
+}
+
+// IsKubernetesDeploymentEnabled returns true when the components are
+// deployed as Kubernetes Deployments and StatefulSets instead of OpenShift
+// DeploymentConfigs
+func (apimanager *APIManager) IsKubernetesDeploymentEnabled() bool {
+	return apimanager.Spec.WorkloadType != nil && *apimanager.Spec.WorkloadType == WorkloadTypeDeployment
+}
+
+// IsIngressEnabled returns true when the components are exposed with
+// Kubernetes Ingresses instead of OpenShift Routes
+func (apimanager *APIManager) IsIngressEnabled() bool {
+	return apimanager.Spec.ExposureType != nil && *apimanager.Spec.ExposureType == ExposureTypeIngress
+}
+
+// IsRedisSentinelEnabled returns true when backend-redis and system-redis
+// are deployed as master/replica sets monitored by redis sentinels
+func (apimanager *APIManager) IsRedisSentinelEnabled() bool {
+	return !apimanager.IsExternalDatabaseEnabled() && apimanager.Spec.Redis.IsSentinelEnabled()
+}
+
 func (apimanager *APIManager) IsPDBEnabled() bool {
 	return apimanager.Spec.PodDisruptionBudget != nil && apimanager.Spec.PodDisruptionBudget.Enabled
 }
+
+// IsPaused returns true when the reconciliation of the whole APIManager is
+// paused with the PausedAnnotation
+func (apimanager *APIManager) IsPaused() bool {
+	return apimanager.Annotations[PausedAnnotation] == "true"
+}
+
+// PausedComponents returns the components listed in the
+// PausedComponentsAnnotation and the listed names that are not components
+func (apimanager *APIManager) PausedComponents() ([]APIManagerComponentName, []string) {
+	paused := []APIManagerComponentName{}
+	unknown := []string{}
+	for _, name := range strings.Split(apimanager.Annotations[PausedComponentsAnnotation], ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !isAPIManagerComponentName(name) {
+			unknown = append(unknown, name)
+			continue
+		}
+		paused = append(paused, APIManagerComponentName(name))
+	}
+	return paused, unknown
+}
+
+// IsComponentPaused returns true when the reconciliation of the component
+// is paused, either alone or with the whole APIManager
+func (apimanager *APIManager) IsComponentPaused(name APIManagerComponentName) bool {
+	if apimanager.IsPaused() {
+		return true
+	}
+	paused, _ := apimanager.PausedComponents()
+	for _, pausedName := range paused {
+		if pausedName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReconciliationPaused returns true when the reconciliation of the whole
+// APIManager or of any of its components is paused
+func (apimanager *APIManager) IsReconciliationPaused() bool {
+	paused, _ := apimanager.PausedComponents()
+	return apimanager.IsPaused() || len(paused) > 0
+}
+
+func isAPIManagerComponentName(name string) bool {
+	switch APIManagerComponentName(name) {
+	case APIManagerSystemComponent, APIManagerBackendComponent, APIManagerApicastComponent,
+		APIManagerZyncComponent, APIManagerRedisComponent, APIManagerDatabasesComponent:
+		return true
+	}
+	return false
+}
+
+// FindCondition returns the condition with the given type from the
+// conditions list, or nil if it is not present
+func FindCondition(conditions []APIManagerCondition, conditionType APIManagerConditionType) *APIManagerCondition {
+	for idx := range conditions {
+		if conditions[idx].Type == conditionType {
+			return &conditions[idx]
+		}
+	}
+	return nil
+}
+
+// SetCondition adds or updates the given condition in the conditions list.
+// LastTransitionTime is only updated when the status of the condition changes
+func SetCondition(conditions *[]APIManagerCondition, newCondition APIManagerCondition) {
+	if newCondition.LastTransitionTime.IsZero() {
+		newCondition.LastTransitionTime = metav1.Now()
+	}
+
+	existingCondition := FindCondition(*conditions, newCondition.Type)
+	if existingCondition == nil {
+		*conditions = append(*conditions, newCondition)
+		return
+	}
+
+	if existingCondition.Status != newCondition.Status {
+		existingCondition.Status = newCondition.Status
+		existingCondition.LastTransitionTime = newCondition.LastTransitionTime
+	}
+
+	existingCondition.Reason = newCondition.Reason
+	existingCondition.Message = newCondition.Message
+}
+
+// IsConditionTrue returns true when the condition with the given type is
+// present in the conditions list and its status is True
+func IsConditionTrue(conditions []APIManagerCondition, conditionType APIManagerConditionType) bool {
+	condition := FindCondition(conditions, conditionType)
+	return condition != nil && condition.Status == v1.ConditionTrue
+}
+
+// FindComponentStatus returns the status of the given component, or nil if
+// it is not present
+func (s *APIManagerStatus) FindComponentStatus(name APIManagerComponentName) *APIManagerComponentStatus {
+	for idx := range s.Components {
+		if s.Components[idx].Name == name {
+			return &s.Components[idx]
+		}
+	}
+	return nil
+}
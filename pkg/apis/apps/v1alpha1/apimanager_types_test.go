@@ -8,6 +8,7 @@ import (
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 	"github.com/3scale/3scale-operator/version"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -16,6 +17,8 @@ func TestSetDefaults(t *testing.T) {
 	tmpDefaultTenantName := defaultTenantName
 	tmpDefaultImageStreamTagImportInsecure := defaultImageStreamImportInsecure
 	tmpDefaultResourceRequirementsEnabled := defaultResourceRequirementsEnabled
+	tmpDefaultWorkloadType := defaultWorkloadType
+	tmpDefaultExposureType := defaultExposureType
 	tmpDefaultApicastManagementAPI := defaultApicastManagementAPI
 	tmpDefaultApicastOpenSSLVerify := defaultApicastOpenSSLVerify
 	tmpDefaultApicastResponseCodes := defaultApicastResponseCodes
@@ -44,6 +47,8 @@ func TestSetDefaults(t *testing.T) {
 				TenantName:                   &tmpDefaultTenantName,
 				ImageStreamTagImportInsecure: &tmpDefaultImageStreamTagImportInsecure,
 				ResourceRequirementsEnabled:  &tmpDefaultResourceRequirementsEnabled,
+				WorkloadType:                 &tmpDefaultWorkloadType,
+				ExposureType:                 &tmpDefaultExposureType,
 			},
 			Apicast: &ApicastSpec{
 				IncludeResponseCodes: &tmpDefaultApicastResponseCodes,
@@ -106,3 +111,319 @@ func testBasicAPIManagerDefaults(input, expected *APIManager) func(t *testing.T)
 		}
 	}
 }
+
+func TestSetCondition(t *testing.T) {
+	transitionTime := metav1.Unix(1, 0)
+	conditions := []APIManagerCondition{
+		{
+			Type:               APIManagerAvailable,
+			Status:             v1.ConditionFalse,
+			Reason:             "DeploymentsStarting",
+			LastTransitionTime: transitionTime,
+		},
+	}
+
+	SetCondition(&conditions, APIManagerCondition{Type: APIManagerAvailable, Status: v1.ConditionFalse, Reason: "DeploymentsMissing"})
+	if len(conditions) != 1 {
+		t.Fatalf("Expected 1 condition, got %d", len(conditions))
+	}
+	if conditions[0].Reason != "DeploymentsMissing" {
+		t.Errorf("Condition reason (%s) not the expected (%s)", conditions[0].Reason, "DeploymentsMissing")
+	}
+	if !conditions[0].LastTransitionTime.Equal(&transitionTime) {
+		t.Errorf("Condition last transition time changed without a status change")
+	}
+
+	SetCondition(&conditions, APIManagerCondition{Type: APIManagerAvailable, Status: v1.ConditionTrue, Reason: "DeploymentsReady"})
+	if !IsConditionTrue(conditions, APIManagerAvailable) {
+		t.Errorf("Expected condition %s to be true", APIManagerAvailable)
+	}
+	if conditions[0].LastTransitionTime.Equal(&transitionTime) {
+		t.Errorf("Condition last transition time not updated after a status change")
+	}
+
+	SetCondition(&conditions, APIManagerCondition{Type: APIManagerDegraded, Status: v1.ConditionFalse})
+	if len(conditions) != 2 {
+		t.Fatalf("Expected 2 conditions, got %d", len(conditions))
+	}
+	if FindCondition(conditions, APIManagerDegraded).LastTransitionTime.IsZero() {
+		t.Errorf("Expected last transition time to be set on new conditions")
+	}
+}
+
+func TestValidateRedisSpec(t *testing.T) {
+	sentinelMode := RedisModeSentinel
+	unknownMode := "Cluster"
+	one := int32(1)
+	four := int32(4)
+
+	cases := []struct {
+		testName    string
+		redis       *RedisSpec
+		ha          *HighAvailabilitySpec
+		expectError bool
+	}{
+		{"NoRedisSpec", nil, nil, false},
+		{"Sentinel", &RedisSpec{Mode: &sentinelMode}, nil, false},
+		{"UnknownMode", &RedisSpec{Mode: &unknownMode}, nil, true},
+		{"SentinelWithHA", &RedisSpec{Mode: &sentinelMode}, &HighAvailabilitySpec{Enabled: true}, true},
+		{"SingleReplica", &RedisSpec{Mode: &sentinelMode, Sentinel: &RedisSentinelSpec{Replicas: &one}}, nil, true},
+		{"QuorumGreaterThanSentinels", &RedisSpec{Mode: &sentinelMode, Sentinel: &RedisSentinelSpec{Quorum: &four}}, nil, true},
+		{"QuorumWithSentinels", &RedisSpec{Mode: &sentinelMode, Sentinel: &RedisSentinelSpec{Quorum: &four, SentinelReplicas: &four}}, nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			apimanager := &APIManager{
+				Spec: APIManagerSpec{
+					Redis:            tc.redis,
+					HighAvailability: tc.ha,
+				},
+			}
+			err := apimanager.validateRedisSpec()
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateSystemSMTPSpec(t *testing.T) {
+	var zero int32 = 0
+	var port int32 = 587
+	plain := "plain"
+	unknown := "unknown"
+	peer := "peer"
+	credentials := &v1.LocalObjectReference{Name: "smtp-credentials"}
+
+	cases := []struct {
+		testName    string
+		smtp        *SystemSMTPSpec
+		expectError bool
+	}{
+		{"NoSMTPSpec", nil, false},
+		{"AddressOnly", &SystemSMTPSpec{Address: "smtp.example.com"}, false},
+		{"NoAddress", &SystemSMTPSpec{Port: &port}, true},
+		{"InvalidPort", &SystemSMTPSpec{Address: "smtp.example.com", Port: &zero}, true},
+		{"Authentication", &SystemSMTPSpec{Address: "smtp.example.com", Port: &port, Authentication: &plain, CredentialsSecretRef: credentials, OpenSSLVerifyMode: &peer}, false},
+		{"AuthenticationWithoutCredentials", &SystemSMTPSpec{Address: "smtp.example.com", Authentication: &plain}, true},
+		{"UnknownAuthentication", &SystemSMTPSpec{Address: "smtp.example.com", Authentication: &unknown, CredentialsSecretRef: credentials}, true},
+		{"UnknownOpenSSLVerifyMode", &SystemSMTPSpec{Address: "smtp.example.com", OpenSSLVerifyMode: &unknown}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			apimanager := &APIManager{
+				Spec: APIManagerSpec{
+					System: &SystemSpec{SMTPSpec: tc.smtp},
+				},
+			}
+			err := apimanager.validateSystemSMTPSpec()
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePodPlacementSpec(t *testing.T) {
+	var one int32 = 1
+	var two int32 = 2
+	scheduleAnyway := TopologySpreadScheduleAnyway
+	unknown := "unknown"
+
+	cases := []struct {
+		testName    string
+		constraint  TopologySpreadConstraint
+		expectError bool
+	}{
+		{"TopologyKeyOnly", TopologySpreadConstraint{TopologyKey: "topology.kubernetes.io/zone"}, false},
+		{"ScheduleAnyway", TopologySpreadConstraint{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: &one, WhenUnsatisfiable: &scheduleAnyway}, false},
+		{"NoTopologyKey", TopologySpreadConstraint{}, true},
+		{"MaxSkew", TopologySpreadConstraint{TopologyKey: "topology.kubernetes.io/zone", MaxSkew: &two}, true},
+		{"UnknownWhenUnsatisfiable", TopologySpreadConstraint{TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: &unknown}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			placement := &PodPlacementSpec{
+				TopologySpreadConstraints: []TopologySpreadConstraint{tc.constraint},
+			}
+			err := validatePodPlacementSpec("system-app", placement)
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateImageRegistryMirror(t *testing.T) {
+	cases := []struct {
+		testName    string
+		mirror      *string
+		expectError bool
+	}{
+		{"NoMirror", nil, false},
+		{"Registry", &[]string{"mirror.example.com"}[0], false},
+		{"RegistryPath", &[]string{"mirror.example.com:5000/3scale"}[0], false},
+		{"Empty", &[]string{"/"}[0], true},
+		{"Scheme", &[]string{"https://mirror.example.com"}[0], true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			apimanager := &APIManager{
+				Spec: APIManagerSpec{
+					APIManagerCommonSpec: APIManagerCommonSpec{ImageRegistryMirror: tc.mirror},
+				},
+			}
+			err := apimanager.validateImageRegistryMirror()
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateImageDigestPinning(t *testing.T) {
+	trueValue := true
+	cases := []struct {
+		testName     string
+		workloadType string
+		expectError  bool
+	}{
+		{"DeploymentConfig", WorkloadTypeDeploymentConfig, false},
+		{"Deployment", WorkloadTypeDeployment, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			workloadType := tc.workloadType
+			apimanager := &APIManager{
+				Spec: APIManagerSpec{
+					APIManagerCommonSpec: APIManagerCommonSpec{
+						ImageDigestPinningEnabled: &trueValue,
+						WorkloadType:              &workloadType,
+					},
+				},
+			}
+			err := apimanager.validateImageDigestPinning()
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateWorkloadExposure(t *testing.T) {
+	cases := []struct {
+		testName     string
+		workloadType string
+		exposureType string
+		expectError  bool
+	}{
+		{"DeploymentConfigRoute", WorkloadTypeDeploymentConfig, ExposureTypeRoute, false},
+		{"DeploymentConfigIngress", WorkloadTypeDeploymentConfig, ExposureTypeIngress, false},
+		{"DeploymentRoute", WorkloadTypeDeployment, ExposureTypeRoute, true},
+		{"DeploymentIngress", WorkloadTypeDeployment, ExposureTypeIngress, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			workloadType := tc.workloadType
+			exposureType := tc.exposureType
+			apimanager := &APIManager{
+				Spec: APIManagerSpec{
+					APIManagerCommonSpec: APIManagerCommonSpec{
+						WorkloadType: &workloadType,
+						ExposureType: &exposureType,
+					},
+				},
+			}
+			err := apimanager.validateWorkloadExposure()
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCredentialRotationSpec(t *testing.T) {
+	cases := []struct {
+		testName     string
+		rotationSpec *CredentialRotationSpec
+		expectError  bool
+	}{
+		{"NoRotation", nil, false},
+		{"AllCredentials", &CredentialRotationSpec{IntervalDays: 90}, false},
+		{"Credentials", &CredentialRotationSpec{IntervalDays: 90, Credentials: []string{CredentialZyncAuthenticationToken}}, false},
+		{"InvalidInterval", &CredentialRotationSpec{}, true},
+		{"Unknown", &CredentialRotationSpec{IntervalDays: 90, Credentials: []string{"system-seed"}}, true},
+		{"SystemSeedMasterAccessToken", &CredentialRotationSpec{IntervalDays: 90, Credentials: []string{CredentialSystemSeedMasterAccessToken}}, false},
+		{"SystemSeedAdminAccessToken", &CredentialRotationSpec{IntervalDays: 90, Credentials: []string{CredentialSystemSeedAdminAccessToken}}, false},
+		{"SystemMasterApicastAccessToken", &CredentialRotationSpec{IntervalDays: 90, Credentials: []string{CredentialSystemMasterApicastAccessToken}}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			apimanager := &APIManager{
+				Spec: APIManagerSpec{CredentialRotation: tc.rotationSpec},
+			}
+			err := apimanager.validateCredentialRotationSpec()
+			if tc.expectError && err == nil {
+				subT.Error("Expected a validation error")
+			}
+			if !tc.expectError && err != nil {
+				subT.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPausedComponents(t *testing.T) {
+	cases := []struct {
+		testName        string
+		annotations     map[string]string
+		expectedPaused  []APIManagerComponentName
+		expectedUnknown []string
+		backendPaused   bool
+	}{
+		{"NotPaused", nil, []APIManagerComponentName{}, []string{}, false},
+		{"Components", map[string]string{PausedComponentsAnnotation: "backend, databases"}, []APIManagerComponentName{APIManagerBackendComponent, APIManagerDatabasesComponent}, []string{}, true},
+		{"UnknownComponents", map[string]string{PausedComponentsAnnotation: "system,memcached,"}, []APIManagerComponentName{APIManagerSystemComponent}, []string{"memcached"}, false},
+		{"APIManager", map[string]string{PausedAnnotation: "true"}, []APIManagerComponentName{}, []string{}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			apimanager := &APIManager{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+			}
+			paused, unknown := apimanager.PausedComponents()
+			if !reflect.DeepEqual(paused, tc.expectedPaused) || !reflect.DeepEqual(unknown, tc.expectedUnknown) {
+				subT.Errorf("Unexpected paused components %v and unknown components %v", paused, unknown)
+			}
+			if apimanager.IsComponentPaused(APIManagerBackendComponent) != tc.backendPaused {
+				subT.Errorf("Unexpected backend paused: %t", !tc.backendPaused)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Backup and restore steps. Each step dumps or restores one data store
+// into or from its own directory of the backup PersistentVolumeClaim
+const (
+	BackupStepSecrets        = "secrets"
+	BackupStepSystemDatabase = "system-database"
+	BackupStepSystemRedis    = "system-redis"
+	BackupStepBackendRedis   = "backend-redis"
+	// BackupStepBackendRedisQueues is only run when the backend queues are
+	// stored in their own redis instance
+	BackupStepBackendRedisQueues = "backend-redis-queues"
+	BackupStepZyncDatabase       = "zync-database"
+	BackupStepSystemStorage      = "system-storage"
+)
+
+// Backup and restore phases
+const (
+	BackupPhasePending   = "Pending"
+	BackupPhaseRunning   = "Running"
+	BackupPhaseCompleted = "Completed"
+	BackupPhaseFailed    = "Failed"
+)
+
+const (
+	defaultAPIManagerBackupVolumeSize = "10Gi"
+)
+
+// APIManagerBackupSpec defines the desired state of APIManagerBackup
+type APIManagerBackupSpec struct {
+	// APIManagerName is the name of the APIManager backed up
+	APIManagerName string `json:"apiManagerName"`
+	// BackupDestination is where the backup artifacts are stored
+	BackupDestination APIManagerBackupDestinationSpec `json:"backupDestination"`
+}
+
+// APIManagerBackupDestinationSpec defines where the backup artifacts are
+// stored
+type APIManagerBackupDestinationSpec struct {
+	// PersistentVolumeClaim stores the artifacts in a PersistentVolumeClaim
+	PersistentVolumeClaim PersistentVolumeClaimBackupDestinationSpec `json:"persistentVolumeClaim"`
+}
+
+// PersistentVolumeClaimBackupDestinationSpec defines the
+// PersistentVolumeClaim the backup artifacts are stored in
+type PersistentVolumeClaimBackupDestinationSpec struct {
+	// ClaimName is the name of an existing PersistentVolumeClaim. A new
+	// PersistentVolumeClaim is created when it is not set
+	// +optional
+	ClaimName *string `json:"claimName,omitempty"`
+	// StorageClassName of the created PersistentVolumeClaim
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+	// VolumeSize of the created PersistentVolumeClaim, in Kubernetes
+	// quantity format. Defaults to 10Gi
+	// +optional
+	VolumeSize *string `json:"volumeSize,omitempty"`
+}
+
+// BackupArtifact is a data store dump stored in the backup
+// PersistentVolumeClaim
+type BackupArtifact struct {
+	// Step that produced or restored the artifact
+	Step string `json:"step"`
+	// Path of the artifact in the PersistentVolumeClaim
+	Path string `json:"path"`
+	// CompletionTime of the step
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// APIManagerBackupStatus defines the observed state of APIManagerBackup
+type APIManagerBackupStatus struct {
+	// Phase is one of Pending, Running, Completed or Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// CurrentStep is the step being run
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty"`
+	// Message gives details about the phase, like the failure cause
+	// +optional
+	Message string `json:"message,omitempty"`
+	// PersistentVolumeClaimName is the PersistentVolumeClaim holding the
+	// artifacts
+	// +optional
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName,omitempty"`
+	// Artifacts are the data store dumps completed
+	// +optional
+	Artifacts []BackupArtifact `json:"artifacts,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// APIManagerBackup is the Schema for the apimanagerbackups API. It dumps
+// the data stores and secrets of an APIManager into a PersistentVolumeClaim
+// +kubebuilder:subresource:status
+type APIManagerBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIManagerBackupSpec   `json:"spec,omitempty"`
+	Status APIManagerBackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// APIManagerBackupList contains a list of APIManagerBackup
+type APIManagerBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIManagerBackup `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIManagerBackup{}, &APIManagerBackupList{})
+}
+
+// SetDefaults sets the default values for the APIManagerBackup spec and
+// returns true if the spec was changed
+func (backup *APIManagerBackup) SetDefaults() (bool, error) {
+	changed := false
+	pvcSpec := &backup.Spec.BackupDestination.PersistentVolumeClaim
+
+	if pvcSpec.ClaimName == nil && pvcSpec.VolumeSize == nil {
+		tmpDefaultVolumeSize := defaultAPIManagerBackupVolumeSize
+		pvcSpec.VolumeSize = &tmpDefaultVolumeSize
+		changed = true
+	}
+
+	return changed, backup.validate()
+}
+
+func (backup *APIManagerBackup) validate() error {
+	if backup.Spec.APIManagerName == "" {
+		return fmt.Errorf("Invalid APIManagerBackup. apiManagerName is required")
+	}
+	pvcSpec := &backup.Spec.BackupDestination.PersistentVolumeClaim
+	if pvcSpec.ClaimName != nil && (pvcSpec.StorageClassName != nil || pvcSpec.VolumeSize != nil) {
+		return fmt.Errorf("Invalid APIManagerBackup. storageClassName and volumeSize cannot be set with an existing claimName")
+	}
+	if pvcSpec.VolumeSize != nil {
+		if _, err := resource.ParseQuantity(*pvcSpec.VolumeSize); err != nil {
+			return fmt.Errorf("Invalid APIManagerBackup volumeSize '%s': %s", *pvcSpec.VolumeSize, err)
+		}
+	}
+	return nil
+}
+
+// BackupPersistentVolumeClaimName returns the name of the
+// PersistentVolumeClaim the artifacts are stored in
+func (backup *APIManagerBackup) BackupPersistentVolumeClaimName() string {
+	if claimName := backup.Spec.BackupDestination.PersistentVolumeClaim.ClaimName; claimName != nil {
+		return *claimName
+	}
+	return "apimanager-backup-" + backup.Name
+}
+
+// IsFinished returns true when the backup completed or failed
+func (backup *APIManagerBackup) IsFinished() bool {
+	return backup.Status.Phase == BackupPhaseCompleted || backup.Status.Phase == BackupPhaseFailed
+}
@@ -0,0 +1,126 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RestoreStepScaleDownComponents pauses the reconciliation of the
+	// components writing to the data stores and scales them down. It is run
+	// before the data stores are restored
+	RestoreStepScaleDownComponents = "scale-down-components"
+	// RestoreStepRestartComponents scales the components back up and
+	// resumes their reconciliation. It is the last restore step
+	RestoreStepRestartComponents = "restart-components"
+)
+
+// APIManagerRestoreSpec defines the desired state of APIManagerRestore
+type APIManagerRestoreSpec struct {
+	// APIManagerName is the name of the APIManager restored. The secrets are
+	// restored before it is deployed and the data stores once it is
+	// available
+	APIManagerName string `json:"apiManagerName"`
+	// RestoreSource is where the backup artifacts are read from
+	RestoreSource APIManagerRestoreSourceSpec `json:"restoreSource"`
+}
+
+// APIManagerRestoreSourceSpec defines where the backup artifacts are read
+// from
+type APIManagerRestoreSourceSpec struct {
+	// PersistentVolumeClaim reads the artifacts from a PersistentVolumeClaim
+	PersistentVolumeClaim PersistentVolumeClaimRestoreSourceSpec `json:"persistentVolumeClaim"`
+}
+
+// PersistentVolumeClaimRestoreSourceSpec defines the PersistentVolumeClaim
+// the backup artifacts are read from
+type PersistentVolumeClaimRestoreSourceSpec struct {
+	// ClaimName is the name of the PersistentVolumeClaim with the artifacts
+	// of an APIManagerBackup
+	ClaimName string `json:"claimName"`
+}
+
+// APIManagerRestoreStatus defines the observed state of APIManagerRestore
+type APIManagerRestoreStatus struct {
+	// Phase is one of Pending, Running, Completed or Failed
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// CurrentStep is the step being run
+	// +optional
+	CurrentStep string `json:"currentStep,omitempty"`
+	// Message gives details about the phase, like the failure cause
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Artifacts are the data store dumps restored
+	// +optional
+	Artifacts []BackupArtifact `json:"artifacts,omitempty"`
+	// ComponentsScaledDown is true while the components writing to the
+	// data stores are paused and scaled down
+	// +optional
+	ComponentsScaledDown bool `json:"componentsScaledDown,omitempty"`
+	// PausedComponents are the APIManager components paused by the restore.
+	// The components already paused are left paused once it completes
+	// +optional
+	PausedComponents []string `json:"pausedComponents,omitempty"`
+	// ScaledDownDeployments are the deployments scaled down by the restore
+	// and the replicas they are scaled back up to
+	// +optional
+	ScaledDownDeployments []ScaledDownDeployment `json:"scaledDownDeployments,omitempty"`
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// ScaledDownDeployment is a deployment scaled down while the data stores
+// are restored
+type ScaledDownDeployment struct {
+	// Name of the DeploymentConfig, or of the Deployment in Kubernetes mode
+	Name string `json:"name"`
+	// Replicas of the deployment before it was scaled down
+	Replicas int32 `json:"replicas"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// APIManagerRestore is the Schema for the apimanagerrestores API. It
+// restores the data stores and secrets of an APIManager from the artifacts
+// of an APIManagerBackup
+// +kubebuilder:subresource:status
+type APIManagerRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APIManagerRestoreSpec   `json:"spec,omitempty"`
+	Status APIManagerRestoreStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// APIManagerRestoreList contains a list of APIManagerRestore
+type APIManagerRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIManagerRestore `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&APIManagerRestore{}, &APIManagerRestoreList{})
+}
+
+// Validate checks the APIManagerRestore spec
+func (restore *APIManagerRestore) Validate() error {
+	if restore.Spec.APIManagerName == "" {
+		return fmt.Errorf("Invalid APIManagerRestore. apiManagerName is required")
+	}
+	if restore.Spec.RestoreSource.PersistentVolumeClaim.ClaimName == "" {
+		return fmt.Errorf("Invalid APIManagerRestore. restoreSource persistentVolumeClaim claimName is required")
+	}
+	return nil
+}
+
+// IsFinished returns true when the restore completed or failed
+func (restore *APIManagerRestore) IsFinished() bool {
+	return restore.Status.Phase == BackupPhaseCompleted || restore.Status.Phase == BackupPhaseFailed
+}
@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -36,6 +37,132 @@ func (in *APIManager) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackup) DeepCopyInto(out *APIManagerBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackup.
+func (in *APIManagerBackup) DeepCopy() *APIManagerBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIManagerBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupDestinationSpec) DeepCopyInto(out *APIManagerBackupDestinationSpec) {
+	*out = *in
+	in.PersistentVolumeClaim.DeepCopyInto(&out.PersistentVolumeClaim)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupDestinationSpec.
+func (in *APIManagerBackupDestinationSpec) DeepCopy() *APIManagerBackupDestinationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupDestinationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupList) DeepCopyInto(out *APIManagerBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIManagerBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupList.
+func (in *APIManagerBackupList) DeepCopy() *APIManagerBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIManagerBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupSpec) DeepCopyInto(out *APIManagerBackupSpec) {
+	*out = *in
+	in.BackupDestination.DeepCopyInto(&out.BackupDestination)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupSpec.
+func (in *APIManagerBackupSpec) DeepCopy() *APIManagerBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerBackupStatus) DeepCopyInto(out *APIManagerBackupStatus) {
+	*out = *in
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]BackupArtifact, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerBackupStatus.
+func (in *APIManagerBackupStatus) DeepCopy() *APIManagerBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerCommonSpec) DeepCopyInto(out *APIManagerCommonSpec) {
 	*out = *in
@@ -54,11 +181,36 @@ func (in *APIManagerCommonSpec) DeepCopyInto(out *APIManagerCommonSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ImageRegistryMirror != nil {
+		in, out := &in.ImageRegistryMirror, &out.ImageRegistryMirror
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImageDigestPinningEnabled != nil {
+		in, out := &in.ImageDigestPinningEnabled, &out.ImageDigestPinningEnabled
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ResourceRequirementsEnabled != nil {
 		in, out := &in.ResourceRequirementsEnabled, &out.ResourceRequirementsEnabled
 		*out = new(bool)
 		**out = **in
 	}
+	if in.WorkloadType != nil {
+		in, out := &in.WorkloadType, &out.WorkloadType
+		*out = new(string)
+		**out = **in
+	}
+	if in.ExposureType != nil {
+		in, out := &in.ExposureType, &out.ExposureType
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -72,9 +224,33 @@ func (in *APIManagerCommonSpec) DeepCopy() *APIManagerCommonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerComponentStatus) DeepCopyInto(out *APIManagerComponentStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]APIManagerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerComponentStatus.
+func (in *APIManagerComponentStatus) DeepCopy() *APIManagerComponentStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerComponentStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerCondition) DeepCopyInto(out *APIManagerCondition) {
 	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
@@ -88,6 +264,32 @@ func (in *APIManagerCondition) DeepCopy() *APIManagerCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerImagesStatus) DeepCopyInto(out *APIManagerImagesStatus) {
+	*out = *in
+	if in.ImageStreamTags != nil {
+		in, out := &in.ImageStreamTags, &out.ImageStreamTags
+		*out = make([]ImageStreamTagImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerImagesStatus.
+func (in *APIManagerImagesStatus) DeepCopy() *APIManagerImagesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerImagesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerList) DeepCopyInto(out *APIManagerList) {
 	*out = *in
@@ -121,6 +323,142 @@ func (in *APIManagerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerRestore) DeepCopyInto(out *APIManagerRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestore.
+func (in *APIManagerRestore) DeepCopy() *APIManagerRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIManagerRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerRestoreList) DeepCopyInto(out *APIManagerRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIManagerRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestoreList.
+func (in *APIManagerRestoreList) DeepCopy() *APIManagerRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIManagerRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerRestoreSourceSpec) DeepCopyInto(out *APIManagerRestoreSourceSpec) {
+	*out = *in
+	out.PersistentVolumeClaim = in.PersistentVolumeClaim
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestoreSourceSpec.
+func (in *APIManagerRestoreSourceSpec) DeepCopy() *APIManagerRestoreSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerRestoreSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerRestoreSpec) DeepCopyInto(out *APIManagerRestoreSpec) {
+	*out = *in
+	out.RestoreSource = in.RestoreSource
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestoreSpec.
+func (in *APIManagerRestoreSpec) DeepCopy() *APIManagerRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerRestoreStatus) DeepCopyInto(out *APIManagerRestoreStatus) {
+	*out = *in
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]BackupArtifact, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PausedComponents != nil {
+		in, out := &in.PausedComponents, &out.PausedComponents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScaledDownDeployments != nil {
+		in, out := &in.ScaledDownDeployments, &out.ScaledDownDeployments
+		*out = make([]ScaledDownDeployment, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerRestoreStatus.
+func (in *APIManagerRestoreStatus) DeepCopy() *APIManagerRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *APIManagerSpec) DeepCopyInto(out *APIManagerSpec) {
 	*out = *in
@@ -155,6 +493,31 @@ func (in *APIManagerSpec) DeepCopyInto(out *APIManagerSpec) {
 		*out = new(PodDisruptionBudgetSpec)
 		**out = **in
 	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradeSafetyGate != nil {
+		in, out := &in.UpgradeSafetyGate, &out.UpgradeSafetyGate
+		*out = new(UpgradeSafetyGateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -174,11 +537,40 @@ func (in *APIManagerStatus) DeepCopyInto(out *APIManagerStatus) {
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]APIManagerCondition, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Components != nil {
+		in, out := &in.Components, &out.Components
+		*out = make([]APIManagerComponentStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	in.Deployments.DeepCopyInto(&out.Deployments)
-	return
-}
+	if in.BackendWorkerQueueScaling != nil {
+		in, out := &in.BackendWorkerQueueScaling, &out.BackendWorkerQueueScaling
+		*out = new(BackendWorkerQueueScalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(APIManagerUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = new(APIManagerImagesStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialRotation != nil {
+		in, out := &in.CredentialRotation, &out.CredentialRotation
+		*out = new(CredentialRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerStatus.
 func (in *APIManagerStatus) DeepCopy() *APIManagerStatus {
@@ -190,6 +582,233 @@ func (in *APIManagerStatus) DeepCopy() *APIManagerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIManagerUpgradeStatus) DeepCopyInto(out *APIManagerUpgradeStatus) {
+	*out = *in
+	if in.CompletedSteps != nil {
+		in, out := &in.CompletedSteps, &out.CompletedSteps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIManagerUpgradeStatus.
+func (in *APIManagerUpgradeStatus) DeepCopy() *APIManagerUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIManagerUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIcast) DeepCopyInto(out *APIcast) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIcast.
+func (in *APIcast) DeepCopy() *APIcast {
+	if in == nil {
+		return nil
+	}
+	out := new(APIcast)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIcast) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIcastExposedHostSpec) DeepCopyInto(out *APIcastExposedHostSpec) {
+	*out = *in
+	if in.ExposureType != nil {
+		in, out := &in.ExposureType, &out.ExposureType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(IngressSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIcastExposedHostSpec.
+func (in *APIcastExposedHostSpec) DeepCopy() *APIcastExposedHostSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIcastExposedHostSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIcastList) DeepCopyInto(out *APIcastList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]APIcast, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIcastList.
+func (in *APIcastList) DeepCopy() *APIcastList {
+	if in == nil {
+		return nil
+	}
+	out := new(APIcastList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *APIcastList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIcastSpec) DeepCopyInto(out *APIcastSpec) {
+	*out = *in
+	out.AdminPortalCredentialsRef = in.AdminPortalCredentialsRef
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.DeploymentEnvironment != nil {
+		in, out := &in.DeploymentEnvironment, &out.DeploymentEnvironment
+		*out = new(string)
+		**out = **in
+	}
+	if in.OpenSSLVerify != nil {
+		in, out := &in.OpenSSLVerify, &out.OpenSSLVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExposedHost != nil {
+		in, out := &in.ExposedHost, &out.ExposedHost
+		*out = new(APIcastExposedHostSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomPolicies != nil {
+		in, out := &in.CustomPolicies, &out.CustomPolicies
+		*out = make([]CustomPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPS != nil {
+		in, out := &in.HTTPS, &out.HTTPS
+		*out = new(ApicastHTTPSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ApicastRuntimeSpec.DeepCopyInto(&out.ApicastRuntimeSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIcastSpec.
+func (in *APIcastSpec) DeepCopy() *APIcastSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(APIcastSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIcastStatus) DeepCopyInto(out *APIcastStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]APIManagerCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIcastStatus.
+func (in *APIcastStatus) DeepCopy() *APIcastStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(APIcastStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApicastHTTPSSpec) DeepCopyInto(out *ApicastHTTPSSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	out.CertificateSecretRef = in.CertificateSecretRef
+	if in.RouteTermination != nil {
+		in, out := &in.RouteTermination, &out.RouteTermination
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastHTTPSSpec.
+func (in *ApicastHTTPSSpec) DeepCopy() *ApicastHTTPSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApicastHTTPSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApicastProductionSpec) DeepCopyInto(out *ApicastProductionSpec) {
 	*out = *in
@@ -198,240 +817,868 @@ func (in *ApicastProductionSpec) DeepCopyInto(out *ApicastProductionSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ApicastRuntimeSpec.DeepCopyInto(&out.ApicastRuntimeSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastProductionSpec.
+func (in *ApicastProductionSpec) DeepCopy() *ApicastProductionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApicastProductionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApicastRuntimeSpec) DeepCopyInto(out *ApicastRuntimeSpec) {
+	*out = *in
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(string)
+		**out = **in
+	}
+	if in.Workers != nil {
+		in, out := &in.Workers, &out.Workers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConfigurationLoader != nil {
+		in, out := &in.ConfigurationLoader, &out.ConfigurationLoader
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigurationCacheTTL != nil {
+		in, out := &in.ConfigurationCacheTTL, &out.ConfigurationCacheTTL
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PathRouting != nil {
+		in, out := &in.PathRouting, &out.PathRouting
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServicesFilterByURL != nil {
+		in, out := &in.ServicesFilterByURL, &out.ServicesFilterByURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServicesList != nil {
+		in, out := &in.ServicesList, &out.ServicesList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CustomEnvironments != nil {
+		in, out := &in.CustomEnvironments, &out.CustomEnvironments
+		*out = make([]CustomEnvironmentSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastRuntimeSpec.
+func (in *ApicastRuntimeSpec) DeepCopy() *ApicastRuntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApicastRuntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApicastSpec) DeepCopyInto(out *ApicastSpec) {
+	*out = *in
+	if in.ApicastManagementAPI != nil {
+		in, out := &in.ApicastManagementAPI, &out.ApicastManagementAPI
+		*out = new(string)
+		**out = **in
+	}
+	if in.OpenSSLVerify != nil {
+		in, out := &in.OpenSSLVerify, &out.OpenSSLVerify
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IncludeResponseCodes != nil {
+		in, out := &in.IncludeResponseCodes, &out.IncludeResponseCodes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RegistryURL != nil {
+		in, out := &in.RegistryURL, &out.RegistryURL
+		*out = new(string)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.ProductionSpec != nil {
+		in, out := &in.ProductionSpec, &out.ProductionSpec
+		*out = new(ApicastProductionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StagingSpec != nil {
+		in, out := &in.StagingSpec, &out.StagingSpec
+		*out = new(ApicastStagingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomPolicies != nil {
+		in, out := &in.CustomPolicies, &out.CustomPolicies
+		*out = make([]CustomPolicySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HTTPS != nil {
+		in, out := &in.HTTPS, &out.HTTPS
+		*out = new(ApicastHTTPSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastSpec.
+func (in *ApicastSpec) DeepCopy() *ApicastSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApicastSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApicastStagingSpec) DeepCopyInto(out *ApicastStagingSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ApicastRuntimeSpec.DeepCopyInto(&out.ApicastRuntimeSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastStagingSpec.
+func (in *ApicastStagingSpec) DeepCopy() *ApicastStagingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApicastStagingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingSpec) DeepCopyInto(out *AutoscalingSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingSpec.
+func (in *AutoscalingSpec) DeepCopy() *AutoscalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendCronSpec) DeepCopyInto(out *BackendCronSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendCronSpec.
+func (in *BackendCronSpec) DeepCopy() *BackendCronSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendCronSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendListenerSpec) DeepCopyInto(out *BackendListenerSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendListenerSpec.
+func (in *BackendListenerSpec) DeepCopy() *BackendListenerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendListenerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
+	*out = *in
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.RedisImage != nil {
+		in, out := &in.RedisImage, &out.RedisImage
+		*out = new(string)
+		**out = **in
+	}
+	if in.RedisResources != nil {
+		in, out := &in.RedisResources, &out.RedisResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RedisPlacement != nil {
+		in, out := &in.RedisPlacement, &out.RedisPlacement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ListenerSpec != nil {
+		in, out := &in.ListenerSpec, &out.ListenerSpec
+		*out = new(BackendListenerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkerSpec != nil {
+		in, out := &in.WorkerSpec, &out.WorkerSpec
+		*out = new(BackendWorkerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CronSpec != nil {
+		in, out := &in.CronSpec, &out.CronSpec
+		*out = new(BackendCronSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSpec.
+func (in *BackendSpec) DeepCopy() *BackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendWorkerQueueScalingSpec) DeepCopyInto(out *BackendWorkerQueueScalingSpec) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetQueueLength != nil {
+		in, out := &in.TargetQueueLength, &out.TargetQueueLength
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SyncPeriodSeconds != nil {
+		in, out := &in.SyncPeriodSeconds, &out.SyncPeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendWorkerQueueScalingSpec.
+func (in *BackendWorkerQueueScalingSpec) DeepCopy() *BackendWorkerQueueScalingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendWorkerQueueScalingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendWorkerQueueScalingStatus) DeepCopyInto(out *BackendWorkerQueueScalingStatus) {
+	*out = *in
+	in.LastObservedTime.DeepCopyInto(&out.LastObservedTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendWorkerQueueScalingStatus.
+func (in *BackendWorkerQueueScalingStatus) DeepCopy() *BackendWorkerQueueScalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendWorkerQueueScalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendWorkerSpec) DeepCopyInto(out *BackendWorkerSpec) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QueueScaling != nil {
+		in, out := &in.QueueScaling, &out.QueueScaling
+		*out = new(BackendWorkerQueueScalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendWorkerSpec.
+func (in *BackendWorkerSpec) DeepCopy() *BackendWorkerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendWorkerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupArtifact) DeepCopyInto(out *BackupArtifact) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupArtifact.
+func (in *BackupArtifact) DeepCopy() *BackupArtifact {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupArtifact)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerImageStatus) DeepCopyInto(out *ContainerImageStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerImageStatus.
+func (in *ContainerImageStatus) DeepCopy() *ContainerImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotationSpec) DeepCopyInto(out *CredentialRotationSpec) {
+	*out = *in
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationSpec.
+func (in *CredentialRotationSpec) DeepCopy() *CredentialRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialRotationStatus) DeepCopyInto(out *CredentialRotationStatus) {
+	*out = *in
+	if in.RolledOut != nil {
+		in, out := &in.RolledOut, &out.RolledOut
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = make([]RotatedCredentialStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialRotationStatus.
+func (in *CredentialRotationStatus) DeepCopy() *CredentialRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomEnvironmentSpec) DeepCopyInto(out *CustomEnvironmentSpec) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomEnvironmentSpec.
+func (in *CustomEnvironmentSpec) DeepCopy() *CustomEnvironmentSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomEnvironmentSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomPolicySpec) DeepCopyInto(out *CustomPolicySpec) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImagePath != nil {
+		in, out := &in.ImagePath, &out.ImagePath
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomPolicySpec.
+func (in *CustomPolicySpec) DeepCopy() *CustomPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeprecatedSystemS3Spec) DeepCopyInto(out *DeprecatedSystemS3Spec) {
+	*out = *in
+	out.AWSCredentials = in.AWSCredentials
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeprecatedSystemS3Spec.
+func (in *DeprecatedSystemS3Spec) DeepCopy() *DeprecatedSystemS3Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeprecatedSystemS3Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HighAvailabilitySpec) DeepCopyInto(out *HighAvailabilitySpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HighAvailabilitySpec.
+func (in *HighAvailabilitySpec) DeepCopy() *HighAvailabilitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HighAvailabilitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageStreamTagImageStatus) DeepCopyInto(out *ImageStreamTagImageStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageStreamTagImageStatus.
+func (in *ImageStreamTagImageStatus) DeepCopy() *ImageStreamTagImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageStreamTagImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLSSecretRef != nil {
+		in, out := &in.TLSSecretRef, &out.TLSSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressSpec.
+func (in *IngressSpec) DeepCopy() *IngressSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastProductionSpec.
-func (in *ApicastProductionSpec) DeepCopy() *ApicastProductionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ApicastProductionSpec)
+	out := new(MonitoringSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApicastSpec) DeepCopyInto(out *ApicastSpec) {
+func (in *PersistentVolumeClaimBackupDestinationSpec) DeepCopyInto(out *PersistentVolumeClaimBackupDestinationSpec) {
 	*out = *in
-	if in.ApicastManagementAPI != nil {
-		in, out := &in.ApicastManagementAPI, &out.ApicastManagementAPI
+	if in.ClaimName != nil {
+		in, out := &in.ClaimName, &out.ClaimName
 		*out = new(string)
 		**out = **in
 	}
-	if in.OpenSSLVerify != nil {
-		in, out := &in.OpenSSLVerify, &out.OpenSSLVerify
-		*out = new(bool)
-		**out = **in
-	}
-	if in.IncludeResponseCodes != nil {
-		in, out := &in.IncludeResponseCodes, &out.IncludeResponseCodes
-		*out = new(bool)
-		**out = **in
-	}
-	if in.RegistryURL != nil {
-		in, out := &in.RegistryURL, &out.RegistryURL
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
 		*out = new(string)
 		**out = **in
 	}
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
+	if in.VolumeSize != nil {
+		in, out := &in.VolumeSize, &out.VolumeSize
 		*out = new(string)
 		**out = **in
 	}
-	if in.ProductionSpec != nil {
-		in, out := &in.ProductionSpec, &out.ProductionSpec
-		*out = new(ApicastProductionSpec)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.StagingSpec != nil {
-		in, out := &in.StagingSpec, &out.StagingSpec
-		*out = new(ApicastStagingSpec)
-		(*in).DeepCopyInto(*out)
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastSpec.
-func (in *ApicastSpec) DeepCopy() *ApicastSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimBackupDestinationSpec.
+func (in *PersistentVolumeClaimBackupDestinationSpec) DeepCopy() *PersistentVolumeClaimBackupDestinationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ApicastSpec)
+	out := new(PersistentVolumeClaimBackupDestinationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ApicastStagingSpec) DeepCopyInto(out *ApicastStagingSpec) {
+func (in *PersistentVolumeClaimRestoreSourceSpec) DeepCopyInto(out *PersistentVolumeClaimRestoreSourceSpec) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int64)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApicastStagingSpec.
-func (in *ApicastStagingSpec) DeepCopy() *ApicastStagingSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeClaimRestoreSourceSpec.
+func (in *PersistentVolumeClaimRestoreSourceSpec) DeepCopy() *PersistentVolumeClaimRestoreSourceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ApicastStagingSpec)
+	out := new(PersistentVolumeClaimRestoreSourceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BackendCronSpec) DeepCopyInto(out *BackendCronSpec) {
+func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int64)
-		**out = **in
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendCronSpec.
-func (in *BackendCronSpec) DeepCopy() *BackendCronSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
+func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(BackendCronSpec)
+	out := new(PodDisruptionBudgetSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BackendListenerSpec) DeepCopyInto(out *BackendListenerSpec) {
+func (in *PodPlacementSpec) DeepCopyInto(out *PodPlacementSpec) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int64)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(v1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PriorityClassName != nil {
+		in, out := &in.PriorityClassName, &out.PriorityClassName
+		*out = new(string)
 		**out = **in
 	}
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendListenerSpec.
-func (in *BackendListenerSpec) DeepCopy() *BackendListenerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodPlacementSpec.
+func (in *PodPlacementSpec) DeepCopy() *PodPlacementSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(BackendListenerSpec)
+	out := new(PodPlacementSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BackendSpec) DeepCopyInto(out *BackendSpec) {
+func (in *RedisSentinelSpec) DeepCopyInto(out *RedisSentinelSpec) {
 	*out = *in
-	if in.Image != nil {
-		in, out := &in.Image, &out.Image
-		*out = new(string)
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
 		**out = **in
 	}
-	if in.RedisImage != nil {
-		in, out := &in.RedisImage, &out.RedisImage
-		*out = new(string)
+	if in.SentinelReplicas != nil {
+		in, out := &in.SentinelReplicas, &out.SentinelReplicas
+		*out = new(int32)
 		**out = **in
 	}
-	if in.ListenerSpec != nil {
-		in, out := &in.ListenerSpec, &out.ListenerSpec
-		*out = new(BackendListenerSpec)
-		(*in).DeepCopyInto(*out)
+	if in.Quorum != nil {
+		in, out := &in.Quorum, &out.Quorum
+		*out = new(int32)
+		**out = **in
 	}
-	if in.WorkerSpec != nil {
-		in, out := &in.WorkerSpec, &out.WorkerSpec
-		*out = new(BackendWorkerSpec)
+	if in.SentinelResources != nil {
+		in, out := &in.SentinelResources, &out.SentinelResources
+		*out = new(v1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.CronSpec != nil {
-		in, out := &in.CronSpec, &out.CronSpec
-		*out = new(BackendCronSpec)
+	if in.SentinelPlacement != nil {
+		in, out := &in.SentinelPlacement, &out.SentinelPlacement
+		*out = new(PodPlacementSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSpec.
-func (in *BackendSpec) DeepCopy() *BackendSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSentinelSpec.
+func (in *RedisSentinelSpec) DeepCopy() *RedisSentinelSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(BackendSpec)
+	out := new(RedisSentinelSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BackendWorkerSpec) DeepCopyInto(out *BackendWorkerSpec) {
+func (in *RedisSpec) DeepCopyInto(out *RedisSpec) {
 	*out = *in
-	if in.Replicas != nil {
-		in, out := &in.Replicas, &out.Replicas
-		*out = new(int64)
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(string)
 		**out = **in
 	}
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendWorkerSpec.
-func (in *BackendWorkerSpec) DeepCopy() *BackendWorkerSpec {
-	if in == nil {
-		return nil
+	if in.Sentinel != nil {
+		in, out := &in.Sentinel, &out.Sentinel
+		*out = new(RedisSentinelSpec)
+		(*in).DeepCopyInto(*out)
 	}
-	out := new(BackendWorkerSpec)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeprecatedSystemS3Spec) DeepCopyInto(out *DeprecatedSystemS3Spec) {
-	*out = *in
-	out.AWSCredentials = in.AWSCredentials
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeprecatedSystemS3Spec.
-func (in *DeprecatedSystemS3Spec) DeepCopy() *DeprecatedSystemS3Spec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSpec.
+func (in *RedisSpec) DeepCopy() *RedisSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DeprecatedSystemS3Spec)
+	out := new(RedisSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HighAvailabilitySpec) DeepCopyInto(out *HighAvailabilitySpec) {
+func (in *RotatedCredentialStatus) DeepCopyInto(out *RotatedCredentialStatus) {
 	*out = *in
+	in.LastRotationTime.DeepCopyInto(&out.LastRotationTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HighAvailabilitySpec.
-func (in *HighAvailabilitySpec) DeepCopy() *HighAvailabilitySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotatedCredentialStatus.
+func (in *RotatedCredentialStatus) DeepCopy() *RotatedCredentialStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(HighAvailabilitySpec)
+	out := new(RotatedCredentialStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
+func (in *ScaledDownDeployment) DeepCopyInto(out *ScaledDownDeployment) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSpec.
-func (in *PodDisruptionBudgetSpec) DeepCopy() *PodDisruptionBudgetSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledDownDeployment.
+func (in *ScaledDownDeployment) DeepCopy() *ScaledDownDeployment {
 	if in == nil {
 		return nil
 	}
-	out := new(PodDisruptionBudgetSpec)
+	out := new(ScaledDownDeployment)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -444,6 +1691,31 @@ func (in *SystemAppSpec) DeepCopyInto(out *SystemAppSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MasterContainerResources != nil {
+		in, out := &in.MasterContainerResources, &out.MasterContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProviderContainerResources != nil {
+		in, out := &in.ProviderContainerResources, &out.ProviderContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeveloperContainerResources != nil {
+		in, out := &in.DeveloperContainerResources, &out.DeveloperContainerResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -522,6 +1794,16 @@ func (in *SystemMySQLSpec) DeepCopyInto(out *SystemMySQLSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -564,6 +1846,16 @@ func (in *SystemPostgreSQLSpec) DeepCopyInto(out *SystemPostgreSQLSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -594,6 +1886,47 @@ func (in *SystemS3Spec) DeepCopy() *SystemS3Spec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemSMTPSpec) DeepCopyInto(out *SystemSMTPSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Domain != nil {
+		in, out := &in.Domain, &out.Domain
+		*out = new(string)
+		**out = **in
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(string)
+		**out = **in
+	}
+	if in.OpenSSLVerifyMode != nil {
+		in, out := &in.OpenSSLVerifyMode, &out.OpenSSLVerifyMode
+		*out = new(string)
+		**out = **in
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemSMTPSpec.
+func (in *SystemSMTPSpec) DeepCopy() *SystemSMTPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemSMTPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SystemSidekiqSpec) DeepCopyInto(out *SystemSidekiqSpec) {
 	*out = *in
@@ -602,6 +1935,16 @@ func (in *SystemSidekiqSpec) DeepCopyInto(out *SystemSidekiqSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -628,11 +1971,31 @@ func (in *SystemSpec) DeepCopyInto(out *SystemSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.MemcachedResources != nil {
+		in, out := &in.MemcachedResources, &out.MemcachedResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MemcachedPlacement != nil {
+		in, out := &in.MemcachedPlacement, &out.MemcachedPlacement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RedisImage != nil {
 		in, out := &in.RedisImage, &out.RedisImage
 		*out = new(string)
 		**out = **in
 	}
+	if in.RedisResources != nil {
+		in, out := &in.RedisResources, &out.RedisResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RedisPlacement != nil {
+		in, out := &in.RedisPlacement, &out.RedisPlacement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.FileStorageSpec != nil {
 		in, out := &in.FileStorageSpec, &out.FileStorageSpec
 		*out = new(SystemFileStorageSpec)
@@ -653,6 +2016,16 @@ func (in *SystemSpec) DeepCopyInto(out *SystemSpec) {
 		*out = new(SystemSidekiqSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SphinxSpec != nil {
+		in, out := &in.SphinxSpec, &out.SphinxSpec
+		*out = new(SystemSphinxSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SMTPSpec != nil {
+		in, out := &in.SMTPSpec, &out.SMTPSpec
+		*out = new(SystemSMTPSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -666,6 +2039,79 @@ func (in *SystemSpec) DeepCopy() *SystemSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemSphinxSpec) DeepCopyInto(out *SystemSphinxSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemSphinxSpec.
+func (in *SystemSphinxSpec) DeepCopy() *SystemSphinxSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemSphinxSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologySpreadConstraint) DeepCopyInto(out *TopologySpreadConstraint) {
+	*out = *in
+	if in.MaxSkew != nil {
+		in, out := &in.MaxSkew, &out.MaxSkew
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WhenUnsatisfiable != nil {
+		in, out := &in.WhenUnsatisfiable, &out.WhenUnsatisfiable
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologySpreadConstraint.
+func (in *TopologySpreadConstraint) DeepCopy() *TopologySpreadConstraint {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologySpreadConstraint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeSafetyGateSpec) DeepCopyInto(out *UpgradeSafetyGateSpec) {
+	*out = *in
+	if in.RollbackTimeoutSeconds != nil {
+		in, out := &in.RollbackTimeoutSeconds, &out.RollbackTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeSafetyGateSpec.
+func (in *UpgradeSafetyGateSpec) DeepCopy() *UpgradeSafetyGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeSafetyGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ZyncAppSpec) DeepCopyInto(out *ZyncAppSpec) {
 	*out = *in
@@ -674,6 +2120,21 @@ func (in *ZyncAppSpec) DeepCopyInto(out *ZyncAppSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -695,6 +2156,16 @@ func (in *ZyncQueSpec) DeepCopyInto(out *ZyncQueSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -721,6 +2192,16 @@ func (in *ZyncSpec) DeepCopyInto(out *ZyncSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.DatabaseResources != nil {
+		in, out := &in.DatabaseResources, &out.DatabaseResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabasePlacement != nil {
+		in, out := &in.DatabasePlacement, &out.DatabasePlacement
+		*out = new(PodPlacementSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AppSpec != nil {
 		in, out := &in.AppSpec, &out.AppSpec
 		*out = new(ZyncAppSpec)
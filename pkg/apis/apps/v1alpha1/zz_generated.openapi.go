@@ -92,12 +92,51 @@ func schema_pkg_apis_apps_v1alpha1_APIManagerSpec(ref common.ReferenceCallback)
 							Format: "",
 						},
 					},
+					"imageRegistryMirror": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Registry, and optional repository path, replacing the registry of every image. Eg. mirror.example.com/3scale pulls quay.io/3scale/porta:nightly from mirror.example.com/3scale/3scale/porta:nightly",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"imagePullSecrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secrets added to the image pull secrets of the amp and zync-que-sa service accounts",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/api/core/v1.LocalObjectReference"),
+									},
+								},
+							},
+						},
+					},
+					"imageDigestPinningEnabled": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pin the ImageStream tags to the digest of the images they resolved to. Only supported with the DeploymentConfig workload type",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 					"resourceRequirementsEnabled": {
 						SchemaProps: spec.SchemaProps{
 							Type:   []string{"boolean"},
 							Format: "",
 						},
 					},
+					"workloadType": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
+					"exposureType": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"string"},
+							Format: "",
+						},
+					},
 					"apicast": {
 						SchemaProps: spec.SchemaProps{
 							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ApicastSpec"),
@@ -128,12 +167,37 @@ func schema_pkg_apis_apps_v1alpha1_APIManagerSpec(ref common.ReferenceCallback)
 							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PodDisruptionBudgetSpec"),
 						},
 					},
+					"ingress": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.IngressSpec"),
+						},
+					},
+					"redis": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSpec"),
+						},
+					},
+					"monitoring": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.MonitoringSpec"),
+						},
+					},
+					"upgradeSafetyGate": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.UpgradeSafetyGateSpec"),
+						},
+					},
+					"credentialRotation": {
+						SchemaProps: spec.SchemaProps{
+							Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationSpec"),
+						},
+					},
 				},
 				Required: []string{"wildcardDomain"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ApicastSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.BackendSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.HighAvailabilitySpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PodDisruptionBudgetSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.SystemSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ZyncSpec"},
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ApicastSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.BackendSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.HighAvailabilitySpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.IngressSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.MonitoringSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.PodDisruptionBudgetSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.RedisSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.SystemSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.UpgradeSafetyGateSpec", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.ZyncSpec", "k8s.io/api/core/v1.LocalObjectReference"},
 	}
 }
 
@@ -145,7 +209,8 @@ func schema_pkg_apis_apps_v1alpha1_APIManagerStatus(ref common.ReferenceCallback
 				Properties: map[string]spec.Schema{
 					"conditions": {
 						SchemaProps: spec.SchemaProps{
-							Type: []string{"array"},
+							Description: "Conditions describe the state of the APIManager as a whole",
+							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
@@ -155,16 +220,53 @@ func schema_pkg_apis_apps_v1alpha1_APIManagerStatus(ref common.ReferenceCallback
 							},
 						},
 					},
+					"components": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Components describe the state of each one of the APIManager components",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerComponentStatus"),
+									},
+								},
+							},
+						},
+					},
 					"deployments": {
 						SchemaProps: spec.SchemaProps{
 							Ref: ref("github.com/RHsyseng/operator-utils/pkg/olm.DeploymentStatus"),
 						},
 					},
+					"backendWorkerQueueScaling": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BackendWorkerQueueScaling describes the last backend-worker queue scaling decision",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.BackendWorkerQueueScalingStatus"),
+						},
+					},
+					"upgrade": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Upgrade describes the progress of the last upgrade between operator versions",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerUpgradeStatus"),
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images describes the images the ImageStream tags resolved to and the images the DeploymentConfig containers run",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerImagesStatus"),
+						},
+					},
+					"credentialRotation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialRotation describes the rotation in progress and the last rotation of each credential",
+							Ref:         ref("github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationStatus"),
+						},
+					},
 				},
 				Required: []string{"deployments"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerCondition", "github.com/RHsyseng/operator-utils/pkg/olm.DeploymentStatus"},
+			"github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerComponentStatus", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerCondition", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerImagesStatus", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.APIManagerUpgradeStatus", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.BackendWorkerQueueScalingStatus", "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1.CredentialRotationStatus", "github.com/RHsyseng/operator-utils/pkg/olm.DeploymentStatus"},
 	}
 }
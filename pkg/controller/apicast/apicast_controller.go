@@ -0,0 +1,229 @@
+package apicast
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	routev1 "github.com/openshift/api/route/v1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_apicast")
+
+// Add creates a new APIcast Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	baseReconciler := operator.NewBaseReconciler(mgr.GetClient(), mgr.GetClient(), mgr.GetScheme(), log)
+	return &ReconcileAPIcast{
+		BaseControllerReconciler: operator.NewBaseControllerReconciler(baseReconciler),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("apicast-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource APIcast
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.APIcast{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	ownerHandler := &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appsv1alpha1.APIcast{},
+	}
+
+	err = c.Watch(&source.Kind{Type: &k8sappsv1.Deployment{}}, ownerHandler)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &v1.Service{}}, ownerHandler)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &extensions.Ingress{}}, ownerHandler)
+	if err != nil {
+		return err
+	}
+
+	// Routes are only available in OpenShift
+	routeAvailable, err := k8sutil.ResourceExists(discoveryClient, "route.openshift.io/v1", "Route")
+	if err != nil {
+		return err
+	}
+	if routeAvailable {
+		err = c.Watch(&source.Kind{Type: &routev1.Route{}}, ownerHandler)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Watch for changes to the custom policy and custom environment
+	// ConfigMaps to roll out the gateways. They are not owned by the APIcast
+	err = c.Watch(&source.Kind{Type: &v1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &apicastConfigMapMapper{client: mgr.GetClient()},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// apicastConfigMapMapper maps a ConfigMap to the APIcasts using it as
+// custom policy or custom environment source
+type apicastConfigMapMapper struct {
+	client client.Client
+}
+
+func (m *apicastConfigMapMapper) Map(obj handler.MapObject) []reconcile.Request {
+	apicastList := &appsv1alpha1.APIcastList{}
+	err := m.client.List(context.TODO(), &client.ListOptions{Namespace: obj.Meta.GetNamespace()}, apicastList)
+	if err != nil {
+		log.Error(err, "Failed to list APIcasts")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, apicast := range apicastList.Items {
+		for _, configMapName := range apicastConfigMapNames(&apicast.Spec) {
+			if configMapName == obj.Meta.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: apicast.Name, Namespace: apicast.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+func apicastConfigMapNames(spec *appsv1alpha1.APIcastSpec) []string {
+	names := []string{}
+	for _, policy := range spec.CustomPolicies {
+		if policy.ConfigMapRef != nil {
+			names = append(names, policy.ConfigMapRef.Name)
+		}
+	}
+	for _, customEnvironment := range spec.CustomEnvironments {
+		names = append(names, customEnvironment.ConfigMapRef.Name)
+	}
+	return names
+}
+
+// blank assignment to verify that ReconcileAPIcast implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAPIcast{}
+
+// ReconcileAPIcast reconciles a APIcast object
+type ReconcileAPIcast struct {
+	operator.BaseControllerReconciler
+}
+
+// Reconcile reads that state of the cluster for a APIcast object and makes changes based on the state read
+// and what is in the APIcast.Spec
+func (r *ReconcileAPIcast) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := r.Logger().WithValues("namespace", request.Namespace, "name", request.Name)
+	logger.Info("ReconcileAPIcast")
+
+	instance := &appsv1alpha1.APIcast{}
+	err := r.Client().Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("resource not found. Ignoring since object must have been deleted")
+			return reconcile.Result{}, nil
+		}
+		logger.Error(err, "Error fetching apicast instance")
+		return reconcile.Result{}, err
+	}
+
+	changed, err := instance.SetDefaults()
+	if err != nil {
+		logger.Error(err, "Error")
+		statusErr := r.reconcileAPIcastStatus(instance, err)
+		if statusErr != nil {
+			logger.Error(statusErr, "Error updating status")
+		}
+		return reconcile.Result{}, err
+	}
+	if changed {
+		err = r.Client().Update(context.TODO(), instance)
+		if err != nil {
+			logger.Error(err, "Error updating APIcast defaults")
+			return reconcile.Result{}, err
+		}
+		logger.Info("Defaults set for APIcast resource")
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	reconciler := operator.NewStandaloneApicastReconciler(operator.NewBaseLogicReconciler(r.BaseReconciler), instance)
+	result, reconcileErr := reconciler.Reconcile()
+	// Status is updated even when reconciliation fails so the
+	// conditions report the failure
+	err = r.reconcileAPIcastStatus(instance, reconcileErr)
+	if reconcileErr != nil {
+		logger.Error(reconcileErr, "Error during reconciliation")
+		return result, reconcileErr
+	}
+	if err != nil {
+		logger.Error(err, "Error updating status")
+		return reconcile.Result{}, err
+	}
+
+	return result, nil
+}
+
+func (r *ReconcileAPIcast) reconcileAPIcastStatus(instance *appsv1alpha1.APIcast, reconcileErr error) error {
+	var deployment *k8sappsv1.Deployment
+	existing := &k8sappsv1.Deployment{}
+	err := r.Client().Get(context.TODO(), types.NamespacedName{Name: "apicast-" + instance.Name, Namespace: instance.Namespace}, existing)
+	if err == nil {
+		deployment = existing
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	newStatus := instance.Status.DeepCopy()
+	setAPIcastStatus(newStatus, deployment, reconcileErr)
+
+	if !reflect.DeepEqual(instance.Status, *newStatus) {
+		instance.Status = *newStatus
+		err = r.Client().Status().Update(context.TODO(), instance)
+		if err != nil {
+			r.Logger().Error(err, "Failed to update APIcast status")
+			return err
+		}
+	}
+	return nil
+}
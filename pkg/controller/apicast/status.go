@@ -0,0 +1,89 @@
+package apicast
+
+import (
+	"fmt"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Condition reasons set on the APIcast conditions
+const (
+	ReasonDeploymentReady    = "DeploymentReady"
+	ReasonDeploymentStarting = "DeploymentStarting"
+	ReasonDeploymentStopped  = "DeploymentStopped"
+	ReasonDeploymentMissing  = "DeploymentMissing"
+	ReasonDeploymentFailed   = "DeploymentFailed"
+	ReasonReconcileFailed    = "ReconcileFailed"
+)
+
+// setAPIcastStatus updates the given status from the gateway Deployment,
+// nil when it does not exist, and the reconciliation error
+func setAPIcastStatus(status *appsv1alpha1.APIcastStatus, deployment *k8sappsv1.Deployment, reconcileErr error) {
+	available := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerAvailable,
+		Status:  v1.ConditionTrue,
+		Reason:  ReasonDeploymentReady,
+		Message: "Deployment is ready",
+	}
+	progressing := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonDeploymentReady,
+		Message: "Deployment is ready",
+	}
+	degraded := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerDegraded,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonDeploymentReady,
+		Message: "Deployment is ready",
+	}
+
+	status.Replicas, status.ReadyReplicas = 0, 0
+	if deployment == nil {
+		available.Status, available.Reason, available.Message = v1.ConditionFalse, ReasonDeploymentMissing, "Deployment not found"
+		progressing.Status, progressing.Reason, progressing.Message = v1.ConditionTrue, ReasonDeploymentMissing, available.Message
+	} else {
+		status.Replicas = deployment.Status.Replicas
+		status.ReadyReplicas = deployment.Status.ReadyReplicas
+
+		switch {
+		case deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0:
+			available.Status, available.Reason, available.Message = v1.ConditionFalse, ReasonDeploymentStopped, "Deployment scaled to zero replicas"
+			degraded.Status, degraded.Reason, degraded.Message = v1.ConditionTrue, ReasonDeploymentStopped, available.Message
+		// A Deployment without replicas yet is being rolled out for the first time
+		case deployment.Status.Replicas == 0, deployment.Status.ReadyReplicas < deployment.Status.Replicas:
+			replicas := deployment.Status.Replicas
+			if replicas == 0 && deployment.Spec.Replicas != nil {
+				replicas = *deployment.Spec.Replicas
+			}
+			message := fmt.Sprintf("Deployment waiting for ready replicas: %d of %d ready", deployment.Status.ReadyReplicas, replicas)
+			available.Status, available.Reason, available.Message = v1.ConditionFalse, ReasonDeploymentStarting, message
+			progressing.Status, progressing.Reason, progressing.Message = v1.ConditionTrue, ReasonDeploymentStarting, message
+		}
+
+		if deploymentProgressDeadlineExceeded(deployment) {
+			degraded.Status, degraded.Reason, degraded.Message = v1.ConditionTrue, ReasonDeploymentFailed, "Deployment exceeded its progress deadline"
+		}
+	}
+
+	if reconcileErr != nil {
+		degraded.Status, degraded.Reason, degraded.Message = v1.ConditionTrue, ReasonReconcileFailed, reconcileErr.Error()
+	}
+
+	for _, condition := range []appsv1alpha1.APIManagerCondition{available, progressing, degraded} {
+		appsv1alpha1.SetCondition(&status.Conditions, condition)
+	}
+}
+
+func deploymentProgressDeadlineExceeded(deployment *k8sappsv1.Deployment) bool {
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == k8sappsv1.DeploymentProgressing &&
+			condition.Status == v1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package apicast
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testDeployment(replicas, readyReplicas int32) *k8sappsv1.Deployment {
+	return &k8sappsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "apicast-example-apicast"},
+		Spec:       k8sappsv1.DeploymentSpec{Replicas: &replicas},
+		Status: k8sappsv1.DeploymentStatus{
+			Replicas:      replicas,
+			ReadyReplicas: readyReplicas,
+		},
+	}
+}
+
+func TestAPIcastConditions(t *testing.T) {
+	deadlineExceeded := testDeployment(1, 0)
+	deadlineExceeded.Status.Conditions = []k8sappsv1.DeploymentCondition{
+		{Type: k8sappsv1.DeploymentProgressing, Status: v1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+	}
+
+	firstRollout := testDeployment(1, 0)
+	firstRollout.Status.Replicas = 0
+
+	cases := []struct {
+		testName            string
+		deployment          *k8sappsv1.Deployment
+		reconcileErr        error
+		expectedAvailable   v1.ConditionStatus
+		expectedProgressing v1.ConditionStatus
+		expectedDegraded    v1.ConditionStatus
+		expectedReason      string
+	}{
+		{"ready", testDeployment(2, 2), nil, v1.ConditionTrue, v1.ConditionFalse, v1.ConditionFalse, ReasonDeploymentReady},
+		{"starting", testDeployment(2, 1), nil, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, ReasonDeploymentStarting},
+		{"firstRollout", firstRollout, nil, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, ReasonDeploymentStarting},
+		{"stopped", testDeployment(0, 0), nil, v1.ConditionFalse, v1.ConditionFalse, v1.ConditionTrue, ReasonDeploymentStopped},
+		{"missing", nil, nil, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, ReasonDeploymentMissing},
+		{"deadlineExceeded", deadlineExceeded, nil, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionTrue, ReasonDeploymentStarting},
+		{"reconcileFailed", testDeployment(1, 1), fmt.Errorf("some error"), v1.ConditionTrue, v1.ConditionFalse, v1.ConditionTrue, ReasonDeploymentReady},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			status := &appsv1alpha1.APIcastStatus{}
+			setAPIcastStatus(status, tc.deployment, tc.reconcileErr)
+
+			available := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerAvailable)
+			progressing := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerProgressing)
+			degraded := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerDegraded)
+			if available == nil || progressing == nil || degraded == nil {
+				subT.Fatalf("missing conditions: %v", status.Conditions)
+			}
+			if available.Status != tc.expectedAvailable {
+				subT.Errorf("unexpected available status. Expected: %s, got: %s", tc.expectedAvailable, available.Status)
+			}
+			if progressing.Status != tc.expectedProgressing {
+				subT.Errorf("unexpected progressing status. Expected: %s, got: %s", tc.expectedProgressing, progressing.Status)
+			}
+			if degraded.Status != tc.expectedDegraded {
+				subT.Errorf("unexpected degraded status. Expected: %s, got: %s", tc.expectedDegraded, degraded.Status)
+			}
+			if available.Reason != tc.expectedReason {
+				subT.Errorf("unexpected available reason. Expected: %s, got: %s", tc.expectedReason, available.Reason)
+			}
+			if tc.deployment != nil && status.ReadyReplicas != tc.deployment.Status.ReadyReplicas {
+				subT.Errorf("unexpected ready replicas. Expected: %d, got: %d", tc.deployment.Status.ReadyReplicas, status.ReadyReplicas)
+			}
+		})
+	}
+}
@@ -3,20 +3,30 @@ package apimanager
 import (
 	"context"
 	"fmt"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	extensions "k8s.io/api/extensions/v1beta1"
 	"k8s.io/api/policy/v1beta1"
 	"reflect"
+	"time"
 
 	"github.com/3scale/3scale-operator/version"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/product"
 
 	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 
 	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
 	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/pkg/metrics"
 	"github.com/RHsyseng/operator-utils/pkg/olm"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -73,6 +83,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes to primary resource APIManager
 	err = c.Watch(&source.Kind{Type: &appsv1alpha1.APIManager{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -84,7 +99,24 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		IsController: true,
 		OwnerType:    &appsv1alpha1.APIManager{},
 	}
-	err = c.Watch(&source.Kind{Type: &appsv1.DeploymentConfig{}}, ownerHandler)
+	// DeploymentConfigs are only available in OpenShift
+	dcAvailable, err := k8sutil.ResourceExists(discoveryClient, "apps.openshift.io/v1", "DeploymentConfig")
+	if err != nil {
+		return err
+	}
+	if dcAvailable {
+		err = c.Watch(&source.Kind{Type: &appsv1.DeploymentConfig{}}, ownerHandler)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = c.Watch(&source.Kind{Type: &k8sappsv1.Deployment{}}, ownerHandler)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &k8sappsv1.StatefulSet{}}, ownerHandler)
 	if err != nil {
 		return err
 	}
@@ -94,9 +126,92 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	err = c.Watch(&source.Kind{Type: &extensions.Ingress{}}, ownerHandler)
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &autoscalingv2beta1.HorizontalPodAutoscaler{}}, ownerHandler)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the APIcast custom policy and custom environment
+	// ConfigMaps to roll out APIcast. They are not owned by the APIManager
+	err = c.Watch(&source.Kind{Type: &v1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &apicastConfigMapMapper{client: mgr.GetClient()},
+	})
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// apicastConfigMapMapper maps a ConfigMap to the APIManagers using it as
+// APIcast custom policy or custom environment source
+type apicastConfigMapMapper struct {
+	client client.Client
+}
+
+func (m *apicastConfigMapMapper) Map(obj handler.MapObject) []reconcile.Request {
+	apiManagerList := &appsv1alpha1.APIManagerList{}
+	err := m.client.List(context.TODO(), &client.ListOptions{Namespace: obj.Meta.GetNamespace()}, apiManagerList)
+	if err != nil {
+		log.Error(err, "Failed to list APIManagers")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, apiManager := range apiManagerList.Items {
+		for _, configMapName := range apicastConfigMapNames(apiManager.Spec.Apicast) {
+			if configMapName == obj.Meta.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: apiManager.Name, Namespace: apiManager.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+func apicastConfigMapNames(apicast *appsv1alpha1.ApicastSpec) []string {
+	names := []string{}
+	if apicast == nil {
+		return names
+	}
+
+	for _, policy := range apicast.CustomPolicies {
+		if policy.ConfigMapRef != nil {
+			names = append(names, policy.ConfigMapRef.Name)
+		}
+	}
+	for _, runtimeSpec := range []*appsv1alpha1.ApicastRuntimeSpec{apicastProductionRuntimeSpec(apicast), apicastStagingRuntimeSpec(apicast)} {
+		if runtimeSpec == nil {
+			continue
+		}
+		for _, customEnvironment := range runtimeSpec.CustomEnvironments {
+			names = append(names, customEnvironment.ConfigMapRef.Name)
+		}
+	}
+	return names
+}
+
+func apicastProductionRuntimeSpec(apicast *appsv1alpha1.ApicastSpec) *appsv1alpha1.ApicastRuntimeSpec {
+	if apicast.ProductionSpec == nil {
+		return nil
+	}
+	return &apicast.ProductionSpec.ApicastRuntimeSpec
+}
+
+func apicastStagingRuntimeSpec(apicast *appsv1alpha1.ApicastSpec) *appsv1alpha1.ApicastRuntimeSpec {
+	if apicast.StagingSpec == nil {
+		return nil
+	}
+	return &apicast.StagingSpec.ApicastRuntimeSpec
+}
+
 // blank assignment to verify that ReconcileAPIManager implements reconcile.Reconciler
 var _ reconcile.Reconciler = &ReconcileAPIManager{}
 
@@ -115,8 +230,8 @@ func (r *ReconcileAPIManager) updateVersionAnnotations(cr *appsv1alpha1.APIManag
 }
 
 func (r *ReconcileAPIManager) upgradeAPIManager(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
-	// The object to instantiate would change in every release of the operator
-	// that upgrades the threescale version
+	// The steps run are looked up in the upgrade registry from the operator
+	// version annotation
 	upgradeApiManager := &operator.UpgradeApiManager{
 		Client:          r.Client(),
 		ApiClientReader: r.APIClientReader(),
@@ -127,6 +242,35 @@ func (r *ReconcileAPIManager) upgradeAPIManager(cr *appsv1alpha1.APIManager) (re
 	return upgradeApiManager.Upgrade()
 }
 
+// upgradeRollback rolls the last upgrade back when the upgrade safety gate
+// is enabled and the components are not available after the upgrade
+func (r *ReconcileAPIManager) upgradeRollback(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
+	upgradeApiManager := &operator.UpgradeApiManager{
+		Client:          r.Client(),
+		ApiClientReader: r.APIClientReader(),
+		Scheme:          r.Scheme(),
+		Cr:              cr,
+		Logger:          r.Logger(),
+	}
+	return upgradeApiManager.ReconcileRollback()
+}
+
+// rotateCredentials rotates the credentials requested with the rotation
+// annotation or due by the rotation schedule
+func (r *ReconcileAPIManager) rotateCredentials(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
+	baseLogicReconciler := operator.NewBaseLogicReconciler(r.BaseReconciler)
+	rotator := operator.NewCredentialRotator(operator.NewBaseAPIManagerLogicReconciler(baseLogicReconciler, cr))
+	return rotator.Reconcile()
+}
+
+// minRequeueAfter returns the shortest non zero delay
+func minRequeueAfter(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
 // Reconcile reads that state of the cluster for a APIManager object and makes changes based on the state read
 // and what is in the APIManager.Spec
 // TODO(user): Modify this Reconcile function to implement your Controller logic.  This example creates
@@ -149,20 +293,61 @@ func (r *ReconcileAPIManager) Reconcile(request reconcile.Request) (reconcile.Re
 		return reconcile.Result{}, nil
 	}
 
-	res, err := r.setAPIManagerDefaults(instance)
-	if err != nil {
-		logger.Error(err, "Error")
-		return reconcile.Result{}, err
+	// Defaults are computed to report the status but they are not stored
+	// while the reconciliation is paused
+	changed, specErr := instance.SetDefaults()
+	if specErr != nil {
+		// Requeueing does not fix the spec, it is reconciled again when
+		// the APIManager is updated
+		logger.Info(fmt.Sprintf("Invalid APIManager spec: %s", specErr))
+		err = r.reconcileInvalidSpecStatus(instance, specErr)
+		if err != nil {
+			logger.Error(err, "Error updating status")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if instance.IsPaused() {
+		logger.Info(fmt.Sprintf("Reconciliation paused. Remove the %s annotation to resume", appsv1alpha1.PausedAnnotation))
+		err = r.reconcileAPIManagerStatus(instance, nil)
+		if err != nil {
+			logger.Error(err, "Error updating status")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
 	}
-	if res.Requeue {
+
+	if changed {
+		err = r.Client().Update(context.TODO(), instance)
+		if err != nil {
+			logger.Error(err, "Error setting the defaults")
+			return reconcile.Result{}, err
+		}
 		logger.Info("Defaults set for APIManager resource")
-		return res, nil
+		return reconcile.Result{Requeue: true}, nil
 	}
 
 	if instance.Annotations[appsv1alpha1.OperatorVersionAnnotation] != version.Version {
 		logger.Info(fmt.Sprintf("Upgrade %s -> %s", instance.Annotations[appsv1alpha1.OperatorVersionAnnotation], version.Version))
-		// TODO add logic to check that only immediate consecutive installs
-		// are possible?
+		err = r.reconcileAPIManagerStatus(instance, nil)
+		if err != nil {
+			logger.Error(err, "Error updating status")
+			return reconcile.Result{}, err
+		}
+
+		if instance.Annotations[appsv1alpha1.UpgradeRolledBackAnnotation] == version.Version {
+			logger.Info(fmt.Sprintf("Upgrade to %s rolled back. Remove the %s annotation to retry", version.Version, appsv1alpha1.UpgradeRolledBackAnnotation))
+			return reconcile.Result{}, nil
+		}
+
+		// Upgrade steps change all the components
+		if instance.IsReconciliationPaused() {
+			logger.Info(fmt.Sprintf("Upgrade waiting for the components paused with the %s annotation", appsv1alpha1.PausedComponentsAnnotation))
+			return reconcile.Result{}, nil
+		}
+
+		// Upgrades skipping minor versions are refused by the upgrade
 		res, err := r.upgradeAPIManager(instance)
 		if err != nil {
 			logger.Error(err, "Error upgrading APIManager")
@@ -181,23 +366,52 @@ func (r *ReconcileAPIManager) Reconcile(request reconcile.Request) (reconcile.Re
 		return reconcile.Result{Requeue: true}, nil
 	}
 
-	result, err := r.reconcileAPIManagerLogic(instance)
+	result, reconcileErr := r.reconcileAPIManagerLogic(instance)
+	// Status is updated even when reconciliation fails so the
+	// conditions report the failure
+	err = r.reconcileAPIManagerStatus(instance, reconcileErr)
+	if reconcileErr != nil {
+		logger.Error(reconcileErr, "Error during reconciliation")
+		return result, reconcileErr
+	}
 	if err != nil {
-		logger.Error(err, "Error during reconciliation")
-		return result, err
+		logger.Error(err, "Error updating status")
+		return reconcile.Result{}, err
 	}
 	if result.Requeue {
 		logger.Info("Reconciling not finished. Requeueing.")
 		return result, nil
 	}
 
-	err = r.reconcileAPIManagerStatus(instance)
+	// Rollbacks and credential rotations roll out components, they wait
+	// until all the components are resumed
+	if instance.IsReconciliationPaused() {
+		logger.Info("Reconciliation of some components paused. Upgrade rollbacks and credential rotations deferred")
+		return reconcile.Result{RequeueAfter: result.RequeueAfter}, nil
+	}
+
+	rollbackResult, err := r.upgradeRollback(instance)
 	if err != nil {
-		logger.Error(err, "Error updating status")
+		logger.Error(err, "Error rolling back the upgrade")
 		return reconcile.Result{}, err
 	}
+	if rollbackResult.Requeue {
+		logger.Info("Upgrade rolled back. Requeueing.")
+		return rollbackResult, nil
+	}
+
+	rotationResult, err := r.rotateCredentials(instance)
+	if err != nil {
+		logger.Error(err, "Error rotating credentials")
+		return reconcile.Result{}, err
+	}
+	if rotationResult.Requeue {
+		logger.Info("Rotating credentials. Requeueing.")
+		return rotationResult, nil
+	}
 
-	return reconcile.Result{}, nil
+	requeueAfter := minRequeueAfter(result.RequeueAfter, rollbackResult.RequeueAfter)
+	return reconcile.Result{RequeueAfter: minRequeueAfter(requeueAfter, rotationResult.RequeueAfter)}, nil
 }
 
 func (r *ReconcileAPIManager) apiManagerInstance(namespacedName types.NamespacedName) (*appsv1alpha1.APIManager, error) {
@@ -217,32 +431,20 @@ func (r *ReconcileAPIManager) apiManagerInstance(namespacedName types.Namespaced
 	return instance, nil
 }
 
-func (r *ReconcileAPIManager) setAPIManagerDefaults(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
-	changed, err := cr.SetDefaults() // TODO check where to put this
-	if err != nil {
-		return reconcile.Result{}, err
-	}
-
-	if changed {
-		err = r.Client().Update(context.TODO(), cr)
-	}
-
-	return reconcile.Result{Requeue: changed}, err
-}
-
 func (r *ReconcileAPIManager) reconcileAPIManagerLogic(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
-	result, err := r.reconcileAMPImagesLogic(cr)
+	// The ImageStreams of the paused components are skipped
+	result, err := r.observeReconcile("ampimages", r.reconcileAMPImagesLogic, cr)
 	if err != nil || result.Requeue {
 		return result, err
 	}
 
 	if !cr.IsExternalDatabaseEnabled() {
-		result, err = r.reconcileRedisLogic(cr)
+		result, err = r.reconcileComponent(appsv1alpha1.APIManagerRedisComponent, "redis", r.reconcileRedisLogic, cr)
 		if err != nil || result.Requeue {
 			return result, err
 		}
 
-		result, err = r.reconcileSystemDatabaseLogic(cr)
+		result, err = r.reconcileComponent(appsv1alpha1.APIManagerDatabasesComponent, "system-database", r.reconcileSystemDatabaseLogic, cr)
 		if err != nil || result.Requeue {
 			return result, err
 		}
@@ -255,32 +457,59 @@ func (r *ReconcileAPIManager) reconcileAPIManagerLogic(cr *appsv1alpha1.APIManag
 		}
 	}
 
-	result, err = r.reconcileBackendLogic(cr)
+	result, err = r.reconcileComponent(appsv1alpha1.APIManagerBackendComponent, "backend", r.reconcileBackendLogic, cr)
+	if err != nil || result.Requeue {
+		return result, err
+	}
+	// backend-worker queue scaling requests periodic reconciliations
+	requeueAfter := result.RequeueAfter
+
+	// system-memcache is part of the system component
+	result, err = r.reconcileComponent(appsv1alpha1.APIManagerSystemComponent, "memcached", r.reconcileMemcached, cr)
 	if err != nil || result.Requeue {
 		return result, err
 	}
 
-	result, err = r.reconcileMemcached(cr)
+	result, err = r.reconcileComponent(appsv1alpha1.APIManagerSystemComponent, "system", r.reconcileSystem, cr)
 	if err != nil || result.Requeue {
 		return result, err
 	}
 
-	result, err = r.reconcileSystem(cr)
+	result, err = r.reconcileComponent(appsv1alpha1.APIManagerZyncComponent, "zync", r.reconcileZync, cr)
 	if err != nil || result.Requeue {
 		return result, err
 	}
 
-	result, err = r.reconcileZync(cr)
+	result, err = r.reconcileComponent(appsv1alpha1.APIManagerApicastComponent, "apicast", r.reconcileApicast, cr)
 	if err != nil || result.Requeue {
 		return result, err
 	}
 
-	result, err = r.reconcileApicast(cr)
+	result, err = r.observeReconcile("monitoring", r.reconcileMonitoring, cr)
 	if err != nil || result.Requeue {
 		return result, err
 	}
 
-	return reconcile.Result{}, nil
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// reconcileComponent runs the given component reconciler unless the
+// reconciliation of the component is paused
+func (r *ReconcileAPIManager) reconcileComponent(component appsv1alpha1.APIManagerComponentName, reconciler string, reconcileFn func(*appsv1alpha1.APIManager) (reconcile.Result, error), cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
+	if cr.IsComponentPaused(component) {
+		r.Logger().Info(fmt.Sprintf("Reconciliation of %s paused, skipping %s", component, reconciler))
+		return reconcile.Result{}, nil
+	}
+	return r.observeReconcile(reconciler, reconcileFn, cr)
+}
+
+// observeReconcile runs the given component reconciler recording its
+// duration and result
+func (r *ReconcileAPIManager) observeReconcile(reconciler string, reconcileFn func(*appsv1alpha1.APIManager) (reconcile.Result, error), cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
+	start := time.Now()
+	result, err := reconcileFn(cr)
+	metrics.ObserveReconcile("apimanager", reconciler, start, result, err)
+	return result, err
 }
 
 func (r *ReconcileAPIManager) reconcileAMPImagesLogic(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
@@ -291,6 +520,11 @@ func (r *ReconcileAPIManager) reconcileAMPImagesLogic(cr *appsv1alpha1.APIManage
 
 func (r *ReconcileAPIManager) reconcileRedisLogic(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
 	baseLogicReconciler := operator.NewBaseLogicReconciler(r.BaseReconciler)
+	if cr.IsRedisSentinelEnabled() {
+		reconciler := operator.NewRedisSentinelReconciler(operator.NewBaseAPIManagerLogicReconciler(baseLogicReconciler, cr))
+		return reconciler.Reconcile()
+	}
+
 	reconciler := operator.NewRedisReconciler(operator.NewBaseAPIManagerLogicReconciler(baseLogicReconciler, cr))
 	return reconciler.Reconcile()
 }
@@ -360,17 +594,94 @@ func (r *ReconcileAPIManager) reconcileApicast(cr *appsv1alpha1.APIManager) (rec
 	return reconciler.Reconcile()
 }
 
-func (r *ReconcileAPIManager) reconcileAPIManagerStatus(cr *appsv1alpha1.APIManager) error {
-	return r.setDeploymentStatus(cr)
+func (r *ReconcileAPIManager) reconcileMonitoring(cr *appsv1alpha1.APIManager) (reconcile.Result, error) {
+	baseLogicReconciler := operator.NewBaseLogicReconciler(r.BaseReconciler)
+	reconciler := operator.NewMonitoringReconciler(operator.NewBaseAPIManagerLogicReconciler(baseLogicReconciler, cr))
+	return reconciler.Reconcile()
+}
+
+func (r *ReconcileAPIManager) reconcileAPIManagerStatus(cr *appsv1alpha1.APIManager, reconcileErr error) error {
+	var dcs []appsv1.DeploymentConfig
+	var err error
+	if cr.IsKubernetesDeploymentEnabled() {
+		dcs, err = r.ownedWorkloads(cr)
+	} else {
+		dcs, err = r.ownedDeploymentConfigs(cr)
+		if err == nil && cr.IsRedisSentinelEnabled() {
+			// The redis sentinel topology is deployed with StatefulSets
+			var statefulSets []k8sappsv1.StatefulSet
+			statefulSets, err = r.ownedStatefulSets(cr)
+			dcs = append(dcs, workloadDeploymentConfigs(nil, statefulSets)...)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	var externalDatabaseErr error
+	if cr.IsExternalDatabaseEnabled() {
+		externalDatabaseErr = r.externalDatabasesCheck(cr)
+	}
+
+	missingConfigMaps, err := operator.MissingCustomPolicyConfigMaps(r.Client(), cr.Namespace, cr.Spec.Apicast.CustomPolicies)
+	if err != nil {
+		return err
+	}
+
+	var imageStreams []imagev1.ImageStream
+	if !cr.IsKubernetesDeploymentEnabled() {
+		imageStreams, err = r.ownedImageStreams(cr)
+		if err != nil {
+			return err
+		}
+	}
+
+	newStatus := cr.Status.DeepCopy()
+	newStatus.Deployments = olm.GetDeploymentConfigStatus(dcs)
+	setComponentsStatus(cr, newStatus, dcs)
+	setAPIManagerConditions(cr, newStatus, reconcileErr, externalDatabaseErr)
+	setApicastCustomPoliciesCondition(newStatus, missingConfigMaps)
+	setImagesStatus(newStatus, imageStreams, dcs)
+
+	if !reflect.DeepEqual(cr.Status, *newStatus) {
+		r.Logger().Info("APIManager status will be updated")
+		cr.Status = *newStatus
+		err = r.Client().Status().Update(context.TODO(), cr)
+		if err != nil {
+			r.Logger().Error(err, "Failed to update API Manager status")
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileInvalidSpecStatus reports the validation error of the APIManager
+// spec. The component statuses are left as they are, they cannot be
+// computed without a valid spec
+func (r *ReconcileAPIManager) reconcileInvalidSpecStatus(cr *appsv1alpha1.APIManager, specErr error) error {
+	newStatus := cr.Status.DeepCopy()
+	setInvalidSpecCondition(newStatus, specErr)
+	appsv1alpha1.SetCondition(&newStatus.Conditions, pausedCondition(cr))
+
+	if !reflect.DeepEqual(cr.Status, *newStatus) {
+		r.Logger().Info("APIManager status will be updated")
+		cr.Status = *newStatus
+		err := r.Client().Status().Update(context.TODO(), cr)
+		if err != nil {
+			r.Logger().Error(err, "Failed to update API Manager status")
+			return err
+		}
+	}
+	return nil
 }
 
-func (r *ReconcileAPIManager) setDeploymentStatus(instance *appsv1alpha1.APIManager) error {
+func (r *ReconcileAPIManager) ownedDeploymentConfigs(instance *appsv1alpha1.APIManager) ([]appsv1.DeploymentConfig, error) {
 	listOps := &client.ListOptions{Namespace: instance.Namespace}
 	dcList := &appsv1.DeploymentConfigList{}
 	err := r.Client().List(context.TODO(), listOps, dcList)
 	if err != nil {
 		r.Logger().Error(err, "Failed to list deployment configs")
-		return err
+		return nil, err
 	}
 	var dcs []appsv1.DeploymentConfig
 	for _, dc := range dcList.Items {
@@ -381,18 +692,75 @@ func (r *ReconcileAPIManager) setDeploymentStatus(instance *appsv1alpha1.APIMana
 			}
 		}
 	}
+	return dcs, nil
+}
 
-	deploymentStatus := olm.GetDeploymentConfigStatus(dcs)
-	if !reflect.DeepEqual(instance.Status.Deployments, deploymentStatus) {
-		r.Logger().Info("Deployment status will be updated")
-		instance.Status.Deployments = deploymentStatus
-		err = r.Client().Status().Update(context.TODO(), instance)
-		if err != nil {
-			r.Logger().Error(err, "Failed to update API Manager deployment status")
-			return err
+// ownedWorkloads returns the DeploymentConfig view of the Deployments and
+// StatefulSets owned by the APIManager
+func (r *ReconcileAPIManager) ownedWorkloads(instance *appsv1alpha1.APIManager) ([]appsv1.DeploymentConfig, error) {
+	listOps := &client.ListOptions{Namespace: instance.Namespace}
+	deploymentList := &k8sappsv1.DeploymentList{}
+	err := r.Client().List(context.TODO(), listOps, deploymentList)
+	if err != nil {
+		r.Logger().Error(err, "Failed to list deployments")
+		return nil, err
+	}
+	var deployments []k8sappsv1.Deployment
+	for _, deployment := range deploymentList.Items {
+		if isOwnedBy(&deployment, instance) {
+			deployments = append(deployments, deployment)
 		}
 	}
-	return nil
+
+	statefulSets, err := r.ownedStatefulSets(instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return workloadDeploymentConfigs(deployments, statefulSets), nil
+}
+
+func (r *ReconcileAPIManager) ownedStatefulSets(instance *appsv1alpha1.APIManager) ([]k8sappsv1.StatefulSet, error) {
+	listOps := &client.ListOptions{Namespace: instance.Namespace}
+	statefulSetList := &k8sappsv1.StatefulSetList{}
+	err := r.Client().List(context.TODO(), listOps, statefulSetList)
+	if err != nil {
+		r.Logger().Error(err, "Failed to list stateful sets")
+		return nil, err
+	}
+	var statefulSets []k8sappsv1.StatefulSet
+	for _, statefulSet := range statefulSetList.Items {
+		if isOwnedBy(&statefulSet, instance) {
+			statefulSets = append(statefulSets, statefulSet)
+		}
+	}
+	return statefulSets, nil
+}
+
+func (r *ReconcileAPIManager) ownedImageStreams(instance *appsv1alpha1.APIManager) ([]imagev1.ImageStream, error) {
+	listOps := &client.ListOptions{Namespace: instance.Namespace}
+	imageStreamList := &imagev1.ImageStreamList{}
+	err := r.Client().List(context.TODO(), listOps, imageStreamList)
+	if err != nil {
+		r.Logger().Error(err, "Failed to list image streams")
+		return nil, err
+	}
+	var imageStreams []imagev1.ImageStream
+	for _, imageStream := range imageStreamList.Items {
+		if isOwnedBy(&imageStream, instance) {
+			imageStreams = append(imageStreams, imageStream)
+		}
+	}
+	return imageStreams, nil
+}
+
+func isOwnedBy(obj metav1.Object, instance *appsv1alpha1.APIManager) bool {
+	for _, ownerRef := range obj.GetOwnerReferences() {
+		if ownerRef.UID == instance.UID {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *ReconcileAPIManager) externalDatabasesCheck(cr *appsv1alpha1.APIManager) error {
@@ -12,6 +12,8 @@ import (
 	appsv1 "github.com/openshift/api/apps/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -124,7 +126,7 @@ func TestAPIManagerControllerUpgrade(t *testing.T) {
 			Name:      name,
 			Namespace: namespace,
 			Annotations: map[string]string{
-				appsv1alpha1.OperatorVersionAnnotation:   fmt.Sprintf("not_%s", version.Version),
+				appsv1alpha1.OperatorVersionAnnotation:   previousOperatorVersion(),
 				appsv1alpha1.ThreescaleVersionAnnotation: "something",
 			},
 		},
@@ -215,3 +217,273 @@ func TestAPIManagerControllerUpgrade(t *testing.T) {
 		t.Errorf("APIManager cr OperatorVersionAnnotation value (%s) not the expected (%s)", operatorVersion, version.Version)
 	}
 }
+
+func TestAPIManagerControllerPaused(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+	)
+
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.OperatorVersionAnnotation:   previousOperatorVersion(),
+				appsv1alpha1.ThreescaleVersionAnnotation: "something",
+				appsv1alpha1.PausedComponentsAnnotation:  "backend",
+			},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "test.3scale.net",
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatalf("Unable to add Apps scheme: (%v)", err)
+	}
+	err = imagev1.AddToScheme(s)
+	if err != nil {
+		t.Fatalf("Unable to add Image scheme: (%v)", err)
+	}
+	err = routev1.AddToScheme(s)
+	if err != nil {
+		t.Fatalf("Unable to add Route scheme: (%v)", err)
+	}
+
+	cl := fake.NewFakeClient(apimanager)
+	baseReconciler := operator.NewBaseReconciler(cl, cl, s, log)
+	r := ReconcileAPIManager{
+		BaseControllerReconciler: operator.NewBaseControllerReconciler(baseReconciler),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	getAPIManager := func() *appsv1alpha1.APIManager {
+		cr := &appsv1alpha1.APIManager{}
+		err := cl.Get(context.TODO(), req.NamespacedName, cr)
+		if err != nil {
+			t.Fatalf("get APIManager: (%v)", err)
+		}
+		return cr
+	}
+	reconcileUntilDone := func() {
+		for i := 0; i < 5; i++ {
+			res, err := r.Reconcile(req)
+			if err != nil {
+				t.Fatalf("reconcile: (%v)", err)
+			}
+			if !res.Requeue {
+				return
+			}
+		}
+		t.Fatal("reconcile did not finish")
+	}
+
+	// The upgrade waits for the paused components
+	reconcileUntilDone()
+	cr := getAPIManager()
+	if cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] == version.Version {
+		t.Fatal("APIManager upgraded with paused components")
+	}
+	if !appsv1alpha1.IsConditionTrue(cr.Status.Conditions, appsv1alpha1.APIManagerPaused) {
+		t.Errorf("Expected condition %s to be true", appsv1alpha1.APIManagerPaused)
+	}
+
+	// Paused components are not reconciled
+	cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] = version.Version
+	err = cl.Update(context.TODO(), cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileUntilDone()
+	dc := &appsv1.DeploymentConfig{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "backend-listener", Namespace: namespace}, dc)
+	if !errors.IsNotFound(err) {
+		t.Errorf("paused backend reconciled: (%v)", err)
+	}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "system-app", Namespace: namespace}, dc)
+	if err != nil {
+		t.Errorf("system not reconciled: (%v)", err)
+	}
+
+	// Nothing is reconciled with the whole APIManager paused
+	cr = getAPIManager()
+	cr.Annotations[appsv1alpha1.PausedAnnotation] = "true"
+	delete(cr.Annotations, appsv1alpha1.PausedComponentsAnnotation)
+	err = cl.Update(context.TODO(), cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileUntilDone()
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "backend-listener", Namespace: namespace}, dc)
+	if !errors.IsNotFound(err) {
+		t.Errorf("paused APIManager reconciled: (%v)", err)
+	}
+
+	cr = getAPIManager()
+	delete(cr.Annotations, appsv1alpha1.PausedAnnotation)
+	err = cl.Update(context.TODO(), cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reconcileUntilDone()
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "backend-listener", Namespace: namespace}, dc)
+	if err != nil {
+		t.Errorf("resumed backend not reconciled: (%v)", err)
+	}
+	if appsv1alpha1.IsConditionTrue(getAPIManager().Status.Conditions, appsv1alpha1.APIManagerPaused) {
+		t.Errorf("Expected condition %s to be false", appsv1alpha1.APIManagerPaused)
+	}
+}
+
+func TestAPIManagerControllerInvalidSpec(t *testing.T) {
+	var (
+		name         = "example-apimanager"
+		namespace    = "operator-unittest"
+		workloadType = appsv1alpha1.WorkloadTypeDeployment
+	)
+
+	// Deployments are only exposed with Ingresses
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.PausedAnnotation: "true",
+			},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "test.3scale.net",
+				WorkloadType:   &workloadType,
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+
+	cl := fake.NewFakeClient(apimanager)
+	baseReconciler := operator.NewBaseReconciler(cl, cl, s, log)
+	r := ReconcileAPIManager{
+		BaseControllerReconciler: operator.NewBaseControllerReconciler(baseReconciler),
+	}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+
+	for _, paused := range []bool{true, false} {
+		cr := &appsv1alpha1.APIManager{}
+		err := cl.Get(context.TODO(), req.NamespacedName, cr)
+		if err != nil {
+			t.Fatalf("get APIManager: (%v)", err)
+		}
+		if !paused {
+			delete(cr.Annotations, appsv1alpha1.PausedAnnotation)
+			err = cl.Update(context.TODO(), cr)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Invalid specs are reported in the status instead of requeued
+		res, err := r.Reconcile(req)
+		if err != nil {
+			t.Fatalf("reconcile with paused %t: (%v)", paused, err)
+		}
+		if res.Requeue {
+			t.Errorf("reconcile with paused %t requeued", paused)
+		}
+
+		err = cl.Get(context.TODO(), req.NamespacedName, cr)
+		if err != nil {
+			t.Fatalf("get APIManager: (%v)", err)
+		}
+		degraded := appsv1alpha1.FindCondition(cr.Status.Conditions, appsv1alpha1.APIManagerDegraded)
+		if degraded == nil || degraded.Status != v1.ConditionTrue || degraded.Reason != ReasonInvalidSpec {
+			t.Errorf("Expected condition %s with reason %s with paused %t. Got: %v", appsv1alpha1.APIManagerDegraded, ReasonInvalidSpec, paused, degraded)
+		}
+		if appsv1alpha1.IsConditionTrue(cr.Status.Conditions, appsv1alpha1.APIManagerPaused) != paused {
+			t.Errorf("Expected condition %s to be %t", appsv1alpha1.APIManagerPaused, paused)
+		}
+	}
+}
+
+// previousOperatorVersion returns the first version of the previous minor
+// version, the only one the APIManager can be upgraded from
+func previousOperatorVersion() string {
+	return fmt.Sprintf("%s.0", operator.UpgradePaths[len(operator.UpgradePaths)-1].From)
+}
+
+func TestAPIManagerControllerUnsupportedUpgrade(t *testing.T) {
+	var (
+		name      = "example-apimanager"
+		namespace = "operator-unittest"
+	)
+
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Annotations: map[string]string{
+				appsv1alpha1.OperatorVersionAnnotation:   "0.1.0",
+				appsv1alpha1.ThreescaleVersionAnnotation: "2.4",
+			},
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "test.3scale.net",
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(appsv1alpha1.SchemeGroupVersion, apimanager)
+	err := appsv1.AddToScheme(s)
+	if err != nil {
+		t.Fatalf("Unable to add Apps scheme: (%v)", err)
+	}
+	err = imagev1.AddToScheme(s)
+	if err != nil {
+		t.Fatalf("Unable to add Image scheme: (%v)", err)
+	}
+
+	cl := fake.NewFakeClient(apimanager)
+	baseReconciler := operator.NewBaseReconciler(cl, cl, s, log)
+	r := ReconcileAPIManager{
+		BaseControllerReconciler: operator.NewBaseControllerReconciler(baseReconciler),
+	}
+
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+
+	// Defaults
+	_, err = r.Reconcile(req)
+	if err != nil {
+		t.Fatalf("reconcile: (%v)", err)
+	}
+
+	_, err = r.Reconcile(req)
+	if err == nil {
+		t.Fatal("upgrade skipping versions should fail")
+	}
+
+	finalAPIManager := &appsv1alpha1.APIManager{}
+	err = cl.Get(context.TODO(), req.NamespacedName, finalAPIManager)
+	if err != nil {
+		t.Fatalf("get APIManager: (%v)", err)
+	}
+
+	if finalAPIManager.Annotations[appsv1alpha1.OperatorVersionAnnotation] != "0.1.0" {
+		t.Errorf("OperatorVersionAnnotation updated by a refused upgrade: %s", finalAPIManager.Annotations[appsv1alpha1.OperatorVersionAnnotation])
+	}
+
+	upgradeStatus := finalAPIManager.Status.Upgrade
+	if upgradeStatus == nil || upgradeStatus.Message == "" {
+		t.Fatalf("refused upgrade not reported in status: %v", upgradeStatus)
+	}
+	if len(upgradeStatus.CompletedSteps) != 0 {
+		t.Errorf("refused upgrade ran steps: %v", upgradeStatus.CompletedSteps)
+	}
+}
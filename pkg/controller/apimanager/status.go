@@ -0,0 +1,526 @@
+package apimanager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/component"
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/version"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Condition reasons set on the APIManager and component conditions
+const (
+	ReasonDeploymentsReady           = "DeploymentsReady"
+	ReasonDeploymentsStarting        = "DeploymentsStarting"
+	ReasonDeploymentsStopped         = "DeploymentsStopped"
+	ReasonDeploymentsMissing         = "DeploymentsMissing"
+	ReasonDeploymentsFailed          = "DeploymentsFailed"
+	ReasonExternallyManaged          = "ExternallyManaged"
+	ReasonAllComponentsAvailable     = "AllComponentsAvailable"
+	ReasonComponentsUnavailable      = "ComponentsUnavailable"
+	ReasonComponentsProgressing      = "ComponentsProgressing"
+	ReasonComponentsDegraded         = "ComponentsDegraded"
+	ReasonReconcileFailed            = "ReconcileFailed"
+	ReasonInvalidSpec                = "InvalidSpec"
+	ReasonReconcileSucceeded         = "ReconcileSucceeded"
+	ReasonUpgradeInProgress          = "UpgradeInProgress"
+	ReasonUpgradeCompleted           = "UpgradeCompleted"
+	ReasonUpgradeStopped             = "UpgradeStopped"
+	ReasonUpgradeRolledBack          = "UpgradeRolledBack"
+	ReasonExternalDatabaseInvalid    = "ExternalDatabaseSecretsInvalid"
+	ReasonExternalDatabaseValid      = "ExternalDatabaseSecretsValid"
+	ReasonExternalDatabaseNotEnabled = "HighAvailabilityNotEnabled"
+	ReasonImagesMatch                = "ImagesMatch"
+	ReasonImagesDiverged             = "ImagesDiverged"
+	ReasonReconcilePaused            = "ReconcilePaused"
+	ReasonReconcileActive            = "ReconcileActive"
+	ReasonCustomPoliciesValid        = "CustomPolicyConfigMapsFound"
+	ReasonCustomPoliciesInvalid      = "CustomPolicyConfigMapsMissing"
+)
+
+// apiManagerComponentDeploymentConfigs returns, for each one of the APIManager
+// components, the names of the DeploymentConfigs expected to be deployed
+func apiManagerComponentDeploymentConfigs(cr *appsv1alpha1.APIManager) map[appsv1alpha1.APIManagerComponentName][]string {
+	components := map[appsv1alpha1.APIManagerComponentName][]string{
+		appsv1alpha1.APIManagerSystemComponent:    []string{"system-app", "system-sidekiq", "system-sphinx", "system-memcache"},
+		appsv1alpha1.APIManagerBackendComponent:   []string{"backend-listener", "backend-worker", "backend-cron"},
+		appsv1alpha1.APIManagerApicastComponent:   []string{"apicast-staging", "apicast-production"},
+		appsv1alpha1.APIManagerZyncComponent:      []string{"zync", "zync-que"},
+		appsv1alpha1.APIManagerRedisComponent:     []string{},
+		appsv1alpha1.APIManagerDatabasesComponent: []string{},
+	}
+
+	// zync-database is reconciled, and paused, with zync
+	if !cr.IsExternalZyncDatabaseEnabled() {
+		components[appsv1alpha1.APIManagerZyncComponent] = append(components[appsv1alpha1.APIManagerZyncComponent], "zync-database")
+	}
+
+	if !cr.IsExternalDatabaseEnabled() {
+		components[appsv1alpha1.APIManagerRedisComponent] = []string{"backend-redis", "system-redis"}
+		if cr.IsRedisSentinelEnabled() {
+			components[appsv1alpha1.APIManagerRedisComponent] = append(components[appsv1alpha1.APIManagerRedisComponent], component.RedisSentinelName)
+		}
+
+		systemDatabaseDC := "system-mysql"
+		if cr.Spec.System.DatabaseSpec != nil && cr.Spec.System.DatabaseSpec.PostgreSQL != nil {
+			systemDatabaseDC = "system-postgresql"
+		}
+		components[appsv1alpha1.APIManagerDatabasesComponent] = append(components[appsv1alpha1.APIManagerDatabasesComponent], systemDatabaseDC)
+	}
+
+	return components
+}
+
+// apiManagerComponentNames returns the APIManager component names in the
+// order they are shown in the status
+func apiManagerComponentNames() []appsv1alpha1.APIManagerComponentName {
+	return []appsv1alpha1.APIManagerComponentName{
+		appsv1alpha1.APIManagerSystemComponent,
+		appsv1alpha1.APIManagerBackendComponent,
+		appsv1alpha1.APIManagerApicastComponent,
+		appsv1alpha1.APIManagerZyncComponent,
+		appsv1alpha1.APIManagerRedisComponent,
+		appsv1alpha1.APIManagerDatabasesComponent,
+	}
+}
+
+// componentConditions computes the Available, Progressing and Degraded
+// conditions of a component from its expected DeploymentConfigs
+func componentConditions(expectedDCs []string, dcs map[string]*appsv1.DeploymentConfig) []appsv1alpha1.APIManagerCondition {
+	if len(expectedDCs) == 0 {
+		message := "Component is not deployed by the operator"
+		return []appsv1alpha1.APIManagerCondition{
+			{Type: appsv1alpha1.APIManagerAvailable, Status: v1.ConditionTrue, Reason: ReasonExternallyManaged, Message: message},
+			{Type: appsv1alpha1.APIManagerProgressing, Status: v1.ConditionFalse, Reason: ReasonExternallyManaged, Message: message},
+			{Type: appsv1alpha1.APIManagerDegraded, Status: v1.ConditionFalse, Reason: ReasonExternallyManaged, Message: message},
+		}
+	}
+
+	var missing, stopped, starting, failed []string
+	for _, dcName := range expectedDCs {
+		dc, ok := dcs[dcName]
+		if !ok {
+			missing = append(missing, dcName)
+			continue
+		}
+
+		if deploymentConfigProgressDeadlineExceeded(dc) {
+			failed = append(failed, dcName)
+		}
+
+		if dc.Spec.Replicas == 0 {
+			stopped = append(stopped, dcName)
+		} else if dc.Status.Replicas == 0 || dc.Status.ReadyReplicas < dc.Status.Replicas {
+			// A DeploymentConfig without replicas yet is being rolled out
+			// for the first time
+			starting = append(starting, dcName)
+		}
+	}
+
+	available := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerAvailable,
+		Status:  v1.ConditionTrue,
+		Reason:  ReasonDeploymentsReady,
+		Message: "All deployments are ready",
+	}
+	progressing := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonDeploymentsReady,
+		Message: "All deployments are ready",
+	}
+	degraded := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerDegraded,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonDeploymentsReady,
+		Message: "All deployments are ready",
+	}
+
+	switch {
+	case len(missing) > 0:
+		available.Status, available.Reason, available.Message = v1.ConditionFalse, ReasonDeploymentsMissing, fmt.Sprintf("Deployments not found: %s", strings.Join(missing, ", "))
+		progressing.Status, progressing.Reason, progressing.Message = v1.ConditionTrue, ReasonDeploymentsMissing, available.Message
+	case len(stopped) > 0:
+		available.Status, available.Reason, available.Message = v1.ConditionFalse, ReasonDeploymentsStopped, fmt.Sprintf("Deployments scaled to zero replicas: %s", strings.Join(stopped, ", "))
+	case len(starting) > 0:
+		available.Status, available.Reason, available.Message = v1.ConditionFalse, ReasonDeploymentsStarting, fmt.Sprintf("Deployments waiting for ready replicas: %s", strings.Join(starting, ", "))
+	}
+
+	if len(starting) > 0 && len(missing) == 0 {
+		progressing.Status, progressing.Reason, progressing.Message = v1.ConditionTrue, ReasonDeploymentsStarting, fmt.Sprintf("Deployments waiting for ready replicas: %s", strings.Join(starting, ", "))
+	}
+
+	if len(failed) > 0 {
+		degraded.Status, degraded.Reason, degraded.Message = v1.ConditionTrue, ReasonDeploymentsFailed, fmt.Sprintf("Deployments exceeded their progress deadline: %s", strings.Join(failed, ", "))
+	} else if len(stopped) > 0 {
+		degraded.Status, degraded.Reason, degraded.Message = v1.ConditionTrue, ReasonDeploymentsStopped, fmt.Sprintf("Deployments scaled to zero replicas: %s", strings.Join(stopped, ", "))
+	}
+
+	return []appsv1alpha1.APIManagerCondition{available, progressing, degraded}
+}
+
+func deploymentConfigProgressDeadlineExceeded(dc *appsv1.DeploymentConfig) bool {
+	for _, condition := range dc.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing &&
+			condition.Status == v1.ConditionFalse &&
+			condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
+// workloadDeploymentConfigs returns the DeploymentConfig view of the given
+// Deployments and StatefulSets so component conditions are computed the
+// same way regardless of the workload type. Only the fields used to compute
+// the status are set
+func workloadDeploymentConfigs(deployments []k8sappsv1.Deployment, statefulSets []k8sappsv1.StatefulSet) []appsv1.DeploymentConfig {
+	dcs := []appsv1.DeploymentConfig{}
+	for _, deployment := range deployments {
+		dc := appsv1.DeploymentConfig{ObjectMeta: deployment.ObjectMeta}
+		if deployment.Spec.Replicas != nil {
+			dc.Spec.Replicas = *deployment.Spec.Replicas
+		}
+		dc.Status.Replicas = deployment.Status.Replicas
+		dc.Status.ReadyReplicas = deployment.Status.ReadyReplicas
+		dc.Status.AvailableReplicas = deployment.Status.AvailableReplicas
+		for _, condition := range deployment.Status.Conditions {
+			dc.Status.Conditions = append(dc.Status.Conditions, appsv1.DeploymentCondition{
+				Type:    appsv1.DeploymentConditionType(condition.Type),
+				Status:  condition.Status,
+				Reason:  condition.Reason,
+				Message: condition.Message,
+			})
+		}
+		dcs = append(dcs, dc)
+	}
+
+	for _, statefulSet := range statefulSets {
+		dc := appsv1.DeploymentConfig{ObjectMeta: statefulSet.ObjectMeta}
+		if statefulSet.Spec.Replicas != nil {
+			dc.Spec.Replicas = *statefulSet.Spec.Replicas
+		}
+		dc.Status.Replicas = statefulSet.Status.Replicas
+		dc.Status.ReadyReplicas = statefulSet.Status.ReadyReplicas
+		dc.Status.AvailableReplicas = statefulSet.Status.ReadyReplicas
+		dcs = append(dcs, dc)
+	}
+
+	return dcs
+}
+
+// setComponentsStatus updates the component statuses of the given
+// APIManager status from the DeploymentConfigs owned by the APIManager
+func setComponentsStatus(cr *appsv1alpha1.APIManager, status *appsv1alpha1.APIManagerStatus, dcs []appsv1.DeploymentConfig) {
+	dcsByName := map[string]*appsv1.DeploymentConfig{}
+	for idx := range dcs {
+		dcsByName[dcs[idx].Name] = &dcs[idx]
+	}
+
+	expectedDCs := apiManagerComponentDeploymentConfigs(cr)
+	for _, componentName := range apiManagerComponentNames() {
+		componentStatus := status.FindComponentStatus(componentName)
+		if componentStatus == nil {
+			status.Components = append(status.Components, appsv1alpha1.APIManagerComponentStatus{Name: componentName})
+			componentStatus = &status.Components[len(status.Components)-1]
+		}
+
+		for _, condition := range componentConditions(expectedDCs[componentName], dcsByName) {
+			appsv1alpha1.SetCondition(&componentStatus.Conditions, condition)
+		}
+	}
+}
+
+// setAPIManagerConditions updates the APIManager level conditions of the
+// given status. Component statuses have to be already computed
+func setAPIManagerConditions(cr *appsv1alpha1.APIManager, status *appsv1alpha1.APIManagerStatus, reconcileErr, externalDatabaseErr error) {
+	var unavailable, progressing, degraded []string
+	for _, componentStatus := range status.Components {
+		name := string(componentStatus.Name)
+		if !appsv1alpha1.IsConditionTrue(componentStatus.Conditions, appsv1alpha1.APIManagerAvailable) {
+			unavailable = append(unavailable, name)
+		}
+		if appsv1alpha1.IsConditionTrue(componentStatus.Conditions, appsv1alpha1.APIManagerProgressing) {
+			progressing = append(progressing, name)
+		}
+		if appsv1alpha1.IsConditionTrue(componentStatus.Conditions, appsv1alpha1.APIManagerDegraded) {
+			degraded = append(degraded, name)
+		}
+	}
+	sort.Strings(unavailable)
+	sort.Strings(progressing)
+	sort.Strings(degraded)
+
+	available := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerAvailable,
+		Status:  v1.ConditionTrue,
+		Reason:  ReasonAllComponentsAvailable,
+		Message: "All components are available",
+	}
+	if len(unavailable) > 0 {
+		available.Status = v1.ConditionFalse
+		available.Reason = ReasonComponentsUnavailable
+		available.Message = fmt.Sprintf("Unavailable components: %s", strings.Join(unavailable, ", "))
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, available)
+
+	progressingCondition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerProgressing,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonAllComponentsAvailable,
+		Message: "All components are deployed",
+	}
+	if len(progressing) > 0 {
+		progressingCondition.Status = v1.ConditionTrue
+		progressingCondition.Reason = ReasonComponentsProgressing
+		progressingCondition.Message = fmt.Sprintf("Progressing components: %s", strings.Join(progressing, ", "))
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, progressingCondition)
+
+	degradedCondition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerDegraded,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonReconcileSucceeded,
+		Message: "All components reconciled successfully",
+	}
+	if reconcileErr != nil {
+		degradedCondition.Status = v1.ConditionTrue
+		degradedCondition.Reason = ReasonReconcileFailed
+		degradedCondition.Message = reconcileErr.Error()
+	} else if len(degraded) > 0 {
+		degradedCondition.Status = v1.ConditionTrue
+		degradedCondition.Reason = ReasonComponentsDegraded
+		degradedCondition.Message = fmt.Sprintf("Degraded components: %s", strings.Join(degraded, ", "))
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, degradedCondition)
+
+	upgradeCondition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerUpgradeInProgress,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonUpgradeCompleted,
+		Message: fmt.Sprintf("APIManager is managed by operator version %s", version.Version),
+	}
+	if currentVersion := cr.Annotations[appsv1alpha1.OperatorVersionAnnotation]; currentVersion != version.Version {
+		upgradeCondition.Status = v1.ConditionTrue
+		upgradeCondition.Reason = ReasonUpgradeInProgress
+		upgradeCondition.Message = fmt.Sprintf("Upgrading from operator version %s to %s", currentVersion, version.Version)
+		if cr.Status.Upgrade != nil && cr.Status.Upgrade.Message != "" {
+			upgradeCondition.Reason = ReasonUpgradeStopped
+			upgradeCondition.Message = cr.Status.Upgrade.Message
+		}
+		if cr.Annotations[appsv1alpha1.UpgradeRolledBackAnnotation] == version.Version {
+			upgradeCondition.Status = v1.ConditionFalse
+			upgradeCondition.Reason = ReasonUpgradeRolledBack
+		}
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, upgradeCondition)
+
+	externalDatabaseCondition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerExternalDatabaseInvalid,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonExternalDatabaseNotEnabled,
+		Message: "Databases are managed by the operator",
+	}
+	if cr.IsExternalDatabaseEnabled() {
+		externalDatabaseCondition.Reason = ReasonExternalDatabaseValid
+		externalDatabaseCondition.Message = "External database secrets are valid"
+		if externalDatabaseErr != nil {
+			externalDatabaseCondition.Status = v1.ConditionTrue
+			externalDatabaseCondition.Reason = ReasonExternalDatabaseInvalid
+			externalDatabaseCondition.Message = externalDatabaseErr.Error()
+		}
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, externalDatabaseCondition)
+
+	appsv1alpha1.SetCondition(&status.Conditions, pausedCondition(cr))
+}
+
+// setInvalidSpecCondition sets the Degraded condition reporting the
+// validation error of the APIManager spec
+func setInvalidSpecCondition(status *appsv1alpha1.APIManagerStatus, specErr error) {
+	appsv1alpha1.SetCondition(&status.Conditions, appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerDegraded,
+		Status:  v1.ConditionTrue,
+		Reason:  ReasonInvalidSpec,
+		Message: specErr.Error(),
+	})
+}
+
+// pausedCondition computes the Paused condition from the pause annotations
+// of the APIManager. Names of the paused components annotation that are not
+// components are reported in the message
+func pausedCondition(cr *appsv1alpha1.APIManager) appsv1alpha1.APIManagerCondition {
+	condition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerPaused,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonReconcileActive,
+		Message: "All components are reconciled",
+	}
+
+	paused, unknown := cr.PausedComponents()
+	messages := []string{}
+	if cr.IsPaused() {
+		messages = append(messages, "Reconciliation of the APIManager is paused")
+	} else if len(paused) > 0 {
+		names := []string{}
+		for _, name := range paused {
+			names = append(names, string(name))
+		}
+		messages = append(messages, fmt.Sprintf("Reconciliation of components is paused: %s", strings.Join(names, ", ")))
+	}
+	if len(messages) > 0 {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = ReasonReconcilePaused
+	}
+	if len(unknown) > 0 {
+		messages = append(messages, fmt.Sprintf("Unknown components not paused: %s", strings.Join(unknown, ", ")))
+	}
+	if len(messages) > 0 {
+		condition.Message = strings.Join(messages, ". ")
+	}
+
+	return condition
+}
+
+// setApicastCustomPoliciesCondition sets the ApicastCustomPoliciesInvalid
+// condition, listing the missing custom policy ConfigMaps
+func setApicastCustomPoliciesCondition(status *appsv1alpha1.APIManagerStatus, missingConfigMaps []string) {
+	condition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerApicastCustomPoliciesInvalid,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonCustomPoliciesValid,
+		Message: "All the custom policy ConfigMaps exist",
+	}
+	if len(missingConfigMaps) > 0 {
+		condition.Status = v1.ConditionTrue
+		condition.Reason = ReasonCustomPoliciesInvalid
+		condition.Message = fmt.Sprintf("Custom policy ConfigMaps not found, APIcast deployments not reconciled: %s", strings.Join(missingConfigMaps, ", "))
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, condition)
+}
+
+// setImagesStatus records the images the ImageStream tags resolved to and
+// the images the DeploymentConfig containers run. The ImagesDiverged
+// condition lists the containers not running the image their ImageStream
+// tag resolved to
+func setImagesStatus(status *appsv1alpha1.APIManagerStatus, imageStreams []imagev1.ImageStream, dcs []appsv1.DeploymentConfig) {
+	imagesStatus := &appsv1alpha1.APIManagerImagesStatus{}
+	imageStreamsByName := map[string]*imagev1.ImageStream{}
+	for idx := range imageStreams {
+		imageStream := &imageStreams[idx]
+		imageStreamsByName[imageStream.Name] = imageStream
+		for _, tag := range imageStream.Spec.Tags {
+			if tag.From == nil || tag.From.Kind != "DockerImage" {
+				continue
+			}
+			tagStatus := appsv1alpha1.ImageStreamTagImageStatus{
+				Name:  fmt.Sprintf("%s:%s", imageStream.Name, tag.Name),
+				Image: tag.From.Name,
+			}
+			if source := tag.Annotations[operator.ImageSourceAnnotation]; source != "" {
+				tagStatus.Image = source
+				tagStatus.Pinned = true
+			}
+			if image, imported := operator.ImageStreamTagImportedImage(imageStream, tag.Name); imported {
+				tagStatus.ResolvedImage = image
+			}
+			imagesStatus.ImageStreamTags = append(imagesStatus.ImageStreamTags, tagStatus)
+		}
+	}
+
+	var diverged []string
+	for _, dc := range dcs {
+		if dc.Spec.Template == nil {
+			continue
+		}
+		for _, trigger := range dc.Spec.Triggers {
+			params := trigger.ImageChangeParams
+			if trigger.Type != appsv1.DeploymentTriggerOnImageChange || params == nil || params.From.Kind != "ImageStreamTag" {
+				continue
+			}
+			for _, containerName := range params.ContainerNames {
+				image, ok := podTemplateContainerImage(&dc.Spec.Template.Spec, containerName)
+				if !ok {
+					continue
+				}
+				imagesStatus.Containers = append(imagesStatus.Containers, appsv1alpha1.ContainerImageStatus{
+					DeploymentConfig: dc.Name,
+					Container:        containerName,
+					ImageStreamTag:   params.From.Name,
+					Image:            image,
+				})
+
+				resolvedImage := imageStreamTagResolvedImage(imageStreamsByName, params.From.Name)
+				if imageDigest(image) != "" && imageDigest(resolvedImage) != "" && imageDigest(image) != imageDigest(resolvedImage) {
+					diverged = append(diverged, fmt.Sprintf("%s/%s", dc.Name, containerName))
+				}
+			}
+		}
+	}
+
+	sort.Slice(imagesStatus.ImageStreamTags, func(i, j int) bool {
+		return imagesStatus.ImageStreamTags[i].Name < imagesStatus.ImageStreamTags[j].Name
+	})
+	sort.Slice(imagesStatus.Containers, func(i, j int) bool {
+		a, b := imagesStatus.Containers[i], imagesStatus.Containers[j]
+		return a.DeploymentConfig < b.DeploymentConfig || (a.DeploymentConfig == b.DeploymentConfig && a.Container < b.Container)
+	})
+	status.Images = nil
+	if len(imagesStatus.ImageStreamTags) > 0 || len(imagesStatus.Containers) > 0 {
+		status.Images = imagesStatus
+	}
+
+	imagesCondition := appsv1alpha1.APIManagerCondition{
+		Type:    appsv1alpha1.APIManagerImagesDiverged,
+		Status:  v1.ConditionFalse,
+		Reason:  ReasonImagesMatch,
+		Message: "All containers run the images their ImageStream tags resolved to",
+	}
+	if len(diverged) > 0 {
+		sort.Strings(diverged)
+		imagesCondition.Status = v1.ConditionTrue
+		imagesCondition.Reason = ReasonImagesDiverged
+		imagesCondition.Message = fmt.Sprintf("Containers not running the image their ImageStream tag resolved to: %s", strings.Join(diverged, ", "))
+	}
+	appsv1alpha1.SetCondition(&status.Conditions, imagesCondition)
+}
+
+func podTemplateContainerImage(podSpec *v1.PodSpec, containerName string) (string, bool) {
+	for _, container := range append(podSpec.InitContainers, podSpec.Containers...) {
+		if container.Name == containerName {
+			return container.Image, true
+		}
+	}
+	return "", false
+}
+
+// imageStreamTagResolvedImage returns the image the ImageStreamTag, in
+// name:tag form, resolved to. Empty when not imported
+func imageStreamTagResolvedImage(imageStreams map[string]*imagev1.ImageStream, imageStreamTag string) string {
+	nameAndTag := strings.SplitN(imageStreamTag, ":", 2)
+	imageStream, ok := imageStreams[nameAndTag[0]]
+	if !ok || len(nameAndTag) != 2 {
+		return ""
+	}
+	image, imported := operator.ImageStreamTagImportedImage(imageStream, nameAndTag[1])
+	if !imported {
+		return ""
+	}
+	return image
+}
+
+// imageDigest returns the digest of an image referenced by digest
+func imageDigest(image string) string {
+	parts := strings.SplitN(image, "@", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	return parts[1]
+}
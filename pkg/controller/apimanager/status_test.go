@@ -0,0 +1,420 @@
+package apimanager
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	"github.com/3scale/3scale-operator/version"
+	appsv1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testStatusAPIManager() *appsv1alpha1.APIManager {
+	apimanager := &appsv1alpha1.APIManager{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-apimanager",
+			Namespace: "operator-unittest",
+		},
+		Spec: appsv1alpha1.APIManagerSpec{
+			APIManagerCommonSpec: appsv1alpha1.APIManagerCommonSpec{
+				WildcardDomain: "test.3scale.net",
+			},
+		},
+	}
+	apimanager.SetDefaults()
+	return apimanager
+}
+
+func testDeploymentConfig(name string, replicas, readyReplicas int32) appsv1.DeploymentConfig {
+	return appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       appsv1.DeploymentConfigSpec{Replicas: replicas},
+		Status: appsv1.DeploymentConfigStatus{
+			Replicas:      replicas,
+			ReadyReplicas: readyReplicas,
+		},
+	}
+}
+
+// firstRolloutDeploymentConfig returns a DeploymentConfig just created,
+// without replicas in its status yet
+func firstRolloutDeploymentConfig(name string) appsv1.DeploymentConfig {
+	dc := testDeploymentConfig(name, 1, 0)
+	dc.Status.Replicas = 0
+	return dc
+}
+
+func readyDeploymentConfigs(cr *appsv1alpha1.APIManager) []appsv1.DeploymentConfig {
+	dcs := []appsv1.DeploymentConfig{}
+	for _, dcNames := range apiManagerComponentDeploymentConfigs(cr) {
+		for _, dcName := range dcNames {
+			dcs = append(dcs, testDeploymentConfig(dcName, 1, 1))
+		}
+	}
+	return dcs
+}
+
+func TestComponentConditions(t *testing.T) {
+	cases := []struct {
+		testName            string
+		dcs                 []appsv1.DeploymentConfig
+		expectedAvailable   v1.ConditionStatus
+		expectedProgressing v1.ConditionStatus
+		expectedDegraded    v1.ConditionStatus
+		expectedReason      string
+	}{
+		{"AllReady", []appsv1.DeploymentConfig{testDeploymentConfig("zync", 1, 1), testDeploymentConfig("zync-que", 2, 2)}, v1.ConditionTrue, v1.ConditionFalse, v1.ConditionFalse, ReasonDeploymentsReady},
+		{"Starting", []appsv1.DeploymentConfig{testDeploymentConfig("zync", 1, 0), testDeploymentConfig("zync-que", 2, 2)}, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, ReasonDeploymentsStarting},
+		{"Missing", []appsv1.DeploymentConfig{testDeploymentConfig("zync", 1, 1)}, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, ReasonDeploymentsMissing},
+		{"FirstRollout", []appsv1.DeploymentConfig{firstRolloutDeploymentConfig("zync"), testDeploymentConfig("zync-que", 1, 1)}, v1.ConditionFalse, v1.ConditionTrue, v1.ConditionFalse, ReasonDeploymentsStarting},
+		{"Stopped", []appsv1.DeploymentConfig{testDeploymentConfig("zync", 0, 0), testDeploymentConfig("zync-que", 1, 1)}, v1.ConditionFalse, v1.ConditionFalse, v1.ConditionTrue, ReasonDeploymentsStopped},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			dcsByName := map[string]*appsv1.DeploymentConfig{}
+			for idx := range tc.dcs {
+				dcsByName[tc.dcs[idx].Name] = &tc.dcs[idx]
+			}
+
+			conditions := componentConditions([]string{"zync", "zync-que"}, dcsByName)
+			available := appsv1alpha1.FindCondition(conditions, appsv1alpha1.APIManagerAvailable)
+			progressing := appsv1alpha1.FindCondition(conditions, appsv1alpha1.APIManagerProgressing)
+			degraded := appsv1alpha1.FindCondition(conditions, appsv1alpha1.APIManagerDegraded)
+
+			if available.Status != tc.expectedAvailable {
+				subT.Errorf("Available status (%s) not the expected (%s)", available.Status, tc.expectedAvailable)
+			}
+			if available.Reason != tc.expectedReason {
+				subT.Errorf("Available reason (%s) not the expected (%s)", available.Reason, tc.expectedReason)
+			}
+			if progressing.Status != tc.expectedProgressing {
+				subT.Errorf("Progressing status (%s) not the expected (%s)", progressing.Status, tc.expectedProgressing)
+			}
+			if degraded.Status != tc.expectedDegraded {
+				subT.Errorf("Degraded status (%s) not the expected (%s)", degraded.Status, tc.expectedDegraded)
+			}
+		})
+	}
+}
+
+func TestAPIManagerConditionsAvailable(t *testing.T) {
+	cr := testStatusAPIManager()
+	status := &appsv1alpha1.APIManagerStatus{}
+
+	setComponentsStatus(cr, status, readyDeploymentConfigs(cr))
+	setAPIManagerConditions(cr, status, nil, nil)
+
+	if len(status.Components) != len(apiManagerComponentNames()) {
+		t.Errorf("Components status length (%d) not the expected (%d)", len(status.Components), len(apiManagerComponentNames()))
+	}
+
+	for _, conditionType := range []appsv1alpha1.APIManagerConditionType{
+		appsv1alpha1.APIManagerProgressing,
+		appsv1alpha1.APIManagerDegraded,
+		appsv1alpha1.APIManagerUpgradeInProgress,
+		appsv1alpha1.APIManagerExternalDatabaseInvalid,
+	} {
+		if appsv1alpha1.IsConditionTrue(status.Conditions, conditionType) {
+			t.Errorf("Expected condition %s to be false", conditionType)
+		}
+	}
+
+	if !appsv1alpha1.IsConditionTrue(status.Conditions, appsv1alpha1.APIManagerAvailable) {
+		t.Errorf("Expected condition %s to be true", appsv1alpha1.APIManagerAvailable)
+	}
+}
+
+func TestAPIManagerConditionsUnavailable(t *testing.T) {
+	cr := testStatusAPIManager()
+	cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] = fmt.Sprintf("not_%s", version.Version)
+	status := &appsv1alpha1.APIManagerStatus{}
+
+	dcs := []appsv1.DeploymentConfig{}
+	for _, dc := range readyDeploymentConfigs(cr) {
+		if dc.Name == "backend-worker" {
+			dc.Status.ReadyReplicas = 0
+		}
+		dcs = append(dcs, dc)
+	}
+
+	setComponentsStatus(cr, status, dcs)
+	setAPIManagerConditions(cr, status, fmt.Errorf("reconcile error"), nil)
+
+	available := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerAvailable)
+	if available.Status != v1.ConditionFalse || available.Reason != ReasonComponentsUnavailable {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerAvailable, available)
+	}
+
+	backendStatus := status.FindComponentStatus(appsv1alpha1.APIManagerBackendComponent)
+	if appsv1alpha1.IsConditionTrue(backendStatus.Conditions, appsv1alpha1.APIManagerAvailable) {
+		t.Errorf("Expected backend component not to be available")
+	}
+
+	degraded := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerDegraded)
+	if degraded.Status != v1.ConditionTrue || degraded.Reason != ReasonReconcileFailed {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerDegraded, degraded)
+	}
+
+	if !appsv1alpha1.IsConditionTrue(status.Conditions, appsv1alpha1.APIManagerUpgradeInProgress) {
+		t.Errorf("Expected condition %s to be true", appsv1alpha1.APIManagerUpgradeInProgress)
+	}
+}
+
+func TestAPIManagerConditionsUpgradeStopped(t *testing.T) {
+	cr := testStatusAPIManager()
+	cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] = "0.1.0"
+	cr.Status.Upgrade = &appsv1alpha1.APIManagerUpgradeStatus{
+		FromVersion: "0.1.0",
+		ToVersion:   version.Version,
+		Message:     "upgrade not supported",
+	}
+	status := cr.Status.DeepCopy()
+
+	setComponentsStatus(cr, status, readyDeploymentConfigs(cr))
+	setAPIManagerConditions(cr, status, nil, nil)
+
+	upgrade := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerUpgradeInProgress)
+	if upgrade.Status != v1.ConditionTrue || upgrade.Reason != ReasonUpgradeStopped || upgrade.Message != "upgrade not supported" {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerUpgradeInProgress, upgrade)
+	}
+}
+
+func TestAPIManagerConditionsUpgradeRolledBack(t *testing.T) {
+	cr := testStatusAPIManager()
+	cr.Annotations[appsv1alpha1.OperatorVersionAnnotation] = "0.1.0"
+	cr.Annotations[appsv1alpha1.UpgradeRolledBackAnnotation] = version.Version
+	cr.Status.Upgrade = &appsv1alpha1.APIManagerUpgradeStatus{
+		FromVersion: "0.1.0",
+		ToVersion:   version.Version,
+		Message:     "rolled back",
+	}
+	status := cr.Status.DeepCopy()
+
+	setComponentsStatus(cr, status, readyDeploymentConfigs(cr))
+	setAPIManagerConditions(cr, status, nil, nil)
+
+	upgrade := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerUpgradeInProgress)
+	if upgrade.Status != v1.ConditionFalse || upgrade.Reason != ReasonUpgradeRolledBack || upgrade.Message != "rolled back" {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerUpgradeInProgress, upgrade)
+	}
+}
+
+func TestAPIManagerConditionsPaused(t *testing.T) {
+	cr := testStatusAPIManager()
+	status := cr.Status.DeepCopy()
+
+	setComponentsStatus(cr, status, readyDeploymentConfigs(cr))
+	setAPIManagerConditions(cr, status, nil, nil)
+	if appsv1alpha1.IsConditionTrue(status.Conditions, appsv1alpha1.APIManagerPaused) {
+		t.Errorf("Expected condition %s to be false", appsv1alpha1.APIManagerPaused)
+	}
+
+	cr.Annotations[appsv1alpha1.PausedComponentsAnnotation] = "backend, apicast,unknown"
+	setAPIManagerConditions(cr, status, nil, nil)
+	paused := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerPaused)
+	expectedMessage := "Reconciliation of components is paused: backend, apicast. Unknown components not paused: unknown"
+	if paused.Status != v1.ConditionTrue || paused.Reason != ReasonReconcilePaused || paused.Message != expectedMessage {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerPaused, paused)
+	}
+
+	cr.Annotations[appsv1alpha1.PausedAnnotation] = "true"
+	setAPIManagerConditions(cr, status, nil, nil)
+	paused = appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerPaused)
+	if paused.Status != v1.ConditionTrue || !strings.HasPrefix(paused.Message, "Reconciliation of the APIManager is paused") {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerPaused, paused)
+	}
+}
+
+func TestApicastCustomPoliciesCondition(t *testing.T) {
+	status := &appsv1alpha1.APIManagerStatus{}
+
+	setApicastCustomPoliciesCondition(status, []string{})
+	condition := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerApicastCustomPoliciesInvalid)
+	if condition == nil || condition.Status != v1.ConditionFalse || condition.Reason != ReasonCustomPoliciesValid {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerApicastCustomPoliciesInvalid, condition)
+	}
+
+	setApicastCustomPoliciesCondition(status, []string{"my-policy", "other-policy"})
+	condition = appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerApicastCustomPoliciesInvalid)
+	if condition.Status != v1.ConditionTrue || condition.Reason != ReasonCustomPoliciesInvalid ||
+		!strings.HasSuffix(condition.Message, "my-policy, other-policy") {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerApicastCustomPoliciesInvalid, condition)
+	}
+}
+
+func TestAPIManagerConditionsExternalDatabaseInvalid(t *testing.T) {
+	cr := testStatusAPIManager()
+	cr.Spec.HighAvailability = &appsv1alpha1.HighAvailabilitySpec{Enabled: true}
+	status := &appsv1alpha1.APIManagerStatus{}
+
+	setComponentsStatus(cr, status, readyDeploymentConfigs(cr))
+	setAPIManagerConditions(cr, status, nil, fmt.Errorf("secret not found"))
+
+	externalDatabase := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerExternalDatabaseInvalid)
+	if externalDatabase.Status != v1.ConditionTrue || externalDatabase.Message != "secret not found" {
+		t.Errorf("Unexpected %s condition: %v", appsv1alpha1.APIManagerExternalDatabaseInvalid, externalDatabase)
+	}
+
+	redisStatus := status.FindComponentStatus(appsv1alpha1.APIManagerRedisComponent)
+	redisAvailable := appsv1alpha1.FindCondition(redisStatus.Conditions, appsv1alpha1.APIManagerAvailable)
+	if redisAvailable.Reason != ReasonExternallyManaged {
+		t.Errorf("Redis component available reason (%s) not the expected (%s)", redisAvailable.Reason, ReasonExternallyManaged)
+	}
+}
+
+func TestAPIManagerConditionsExternalZyncDatabase(t *testing.T) {
+	cr := testStatusAPIManager()
+	cr.Spec.HighAvailability = &appsv1alpha1.HighAvailabilitySpec{Enabled: true, ExternalZyncDatabaseEnabled: true}
+	status := &appsv1alpha1.APIManagerStatus{}
+
+	setComponentsStatus(cr, status, readyDeploymentConfigs(cr))
+
+	databasesStatus := status.FindComponentStatus(appsv1alpha1.APIManagerDatabasesComponent)
+	databasesAvailable := appsv1alpha1.FindCondition(databasesStatus.Conditions, appsv1alpha1.APIManagerAvailable)
+	if databasesAvailable.Reason != ReasonExternallyManaged {
+		t.Errorf("Databases component available reason (%s) not the expected (%s)", databasesAvailable.Reason, ReasonExternallyManaged)
+	}
+}
+
+func TestComponentDeploymentConfigsZyncDatabase(t *testing.T) {
+	cr := testStatusAPIManager()
+	components := apiManagerComponentDeploymentConfigs(cr)
+
+	if !reflect.DeepEqual(components[appsv1alpha1.APIManagerZyncComponent], []string{"zync", "zync-que", "zync-database"}) {
+		t.Errorf("Unexpected zync DeploymentConfigs: %v", components[appsv1alpha1.APIManagerZyncComponent])
+	}
+	for _, dcName := range components[appsv1alpha1.APIManagerDatabasesComponent] {
+		if dcName == "zync-database" {
+			t.Error("zync-database mapped to the databases component")
+		}
+	}
+}
+
+func TestWorkloadDeploymentConfigs(t *testing.T) {
+	replicas := int32(2)
+	deployments := []k8sappsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "zync"},
+			Spec:       k8sappsv1.DeploymentSpec{Replicas: &replicas},
+			Status: k8sappsv1.DeploymentStatus{
+				Replicas:      2,
+				ReadyReplicas: 1,
+				Conditions: []k8sappsv1.DeploymentCondition{
+					{Type: k8sappsv1.DeploymentProgressing, Status: v1.ConditionFalse, Reason: "ProgressDeadlineExceeded"},
+				},
+			},
+		},
+	}
+	statefulSets := []k8sappsv1.StatefulSet{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "zync-database"},
+			Spec:       k8sappsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     k8sappsv1.StatefulSetStatus{Replicas: 2, ReadyReplicas: 2},
+		},
+	}
+
+	dcs := workloadDeploymentConfigs(deployments, statefulSets)
+	if len(dcs) != 2 {
+		t.Fatalf("DeploymentConfigs length (%d) not the expected (2)", len(dcs))
+	}
+
+	dcsByName := map[string]*appsv1.DeploymentConfig{}
+	for idx := range dcs {
+		dcsByName[dcs[idx].Name] = &dcs[idx]
+	}
+
+	if !deploymentConfigProgressDeadlineExceeded(dcsByName["zync"]) {
+		t.Errorf("Expected zync progress deadline to be exceeded")
+	}
+
+	conditions := componentConditions([]string{"zync-database"}, dcsByName)
+	if !appsv1alpha1.IsConditionTrue(conditions, appsv1alpha1.APIManagerAvailable) {
+		t.Errorf("Expected zync-database to be available")
+	}
+}
+
+func TestSetImagesStatus(t *testing.T) {
+	var (
+		source      = "quay.io/3scale/porta:nightly"
+		pinnedImage = "quay.io/3scale/porta@sha256:new"
+		oldImage    = "quay.io/3scale/porta@sha256:old"
+	)
+	imageStreams := []imagev1.ImageStream{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "amp-system"},
+			Spec: imagev1.ImageStreamSpec{
+				Tags: []imagev1.TagReference{
+					{
+						Name:        "2.8",
+						Annotations: map[string]string{operator.ImageSourceAnnotation: source},
+						From:        &v1.ObjectReference{Kind: "DockerImage", Name: pinnedImage},
+					},
+					{Name: "latest", From: &v1.ObjectReference{Kind: "ImageStreamTag", Name: "amp-system:2.8"}},
+				},
+			},
+			Status: imagev1.ImageStreamStatus{
+				Tags: []imagev1.NamedTagEventList{
+					{Tag: "2.8", Items: []imagev1.TagEvent{{DockerImageReference: pinnedImage}}},
+					{Tag: "latest", Items: []imagev1.TagEvent{{DockerImageReference: pinnedImage}}},
+				},
+			},
+		},
+	}
+	systemApp := appsv1.DeploymentConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "system-app"},
+		Spec: appsv1.DeploymentConfigSpec{
+			Triggers: appsv1.DeploymentTriggerPolicies{
+				{
+					Type: appsv1.DeploymentTriggerOnImageChange,
+					ImageChangeParams: &appsv1.DeploymentTriggerImageChangeParams{
+						ContainerNames: []string{"system-master", "system-provider"},
+						From:           v1.ObjectReference{Kind: "ImageStreamTag", Name: "amp-system:latest"},
+					},
+				},
+			},
+			Template: &v1.PodTemplateSpec{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "system-provider", Image: oldImage},
+						{Name: "system-master", Image: pinnedImage},
+					},
+				},
+			},
+		},
+	}
+	status := &appsv1alpha1.APIManagerStatus{}
+
+	setImagesStatus(status, imageStreams, []appsv1.DeploymentConfig{systemApp})
+
+	if status.Images == nil || len(status.Images.ImageStreamTags) != 1 || len(status.Images.Containers) != 2 {
+		t.Fatalf("Unexpected images status: %v", status.Images)
+	}
+	tagStatus := status.Images.ImageStreamTags[0]
+	if tagStatus.Name != "amp-system:2.8" || tagStatus.Image != source || tagStatus.ResolvedImage != pinnedImage || !tagStatus.Pinned {
+		t.Errorf("Unexpected ImageStream tag image status: %v", tagStatus)
+	}
+	if status.Images.Containers[0].Container != "system-master" || status.Images.Containers[1].Image != oldImage {
+		t.Errorf("Unexpected container images status: %v", status.Images.Containers)
+	}
+	diverged := appsv1alpha1.FindCondition(status.Conditions, appsv1alpha1.APIManagerImagesDiverged)
+	if diverged == nil || diverged.Status != v1.ConditionTrue || diverged.Reason != ReasonImagesDiverged {
+		t.Fatalf("Expected ImagesDiverged condition to be true: %v", diverged)
+	}
+	if diverged.Message != "Containers not running the image their ImageStream tag resolved to: system-app/system-provider" {
+		t.Errorf("Unexpected ImagesDiverged message: %s", diverged.Message)
+	}
+
+	systemApp.Spec.Template.Spec.Containers[0].Image = pinnedImage
+	setImagesStatus(status, imageStreams, []appsv1.DeploymentConfig{systemApp})
+	if appsv1alpha1.IsConditionTrue(status.Conditions, appsv1alpha1.APIManagerImagesDiverged) {
+		t.Error("Expected ImagesDiverged condition to be false")
+	}
+}
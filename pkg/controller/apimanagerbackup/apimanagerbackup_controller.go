@@ -0,0 +1,128 @@
+package apimanagerbackup
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_apimanagerbackup")
+
+// Add creates a new APIManagerBackup Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	baseReconciler := operator.NewBaseReconciler(mgr.GetClient(), mgr.GetClient(), mgr.GetScheme(), log)
+	return &ReconcileAPIManagerBackup{
+		BaseControllerReconciler: operator.NewBaseControllerReconciler(baseReconciler),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("apimanagerbackup-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource APIManagerBackup
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.APIManagerBackup{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the Jobs running the backup steps
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appsv1alpha1.APIManagerBackup{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// blank assignment to verify that ReconcileAPIManagerBackup implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAPIManagerBackup{}
+
+// ReconcileAPIManagerBackup reconciles a APIManagerBackup object
+type ReconcileAPIManagerBackup struct {
+	operator.BaseControllerReconciler
+}
+
+// Reconcile reads that state of the cluster for a APIManagerBackup object and makes changes based on the state read
+// and what is in the APIManagerBackup.Spec
+func (r *ReconcileAPIManagerBackup) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := r.Logger().WithValues("namespace", request.Namespace, "name", request.Name)
+	logger.Info("ReconcileAPIManagerBackup")
+
+	instance := &appsv1alpha1.APIManagerBackup{}
+	err := r.Client().Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("resource not found. Ignoring since object must have been deleted")
+			return reconcile.Result{}, nil
+		}
+		logger.Error(err, "Error fetching apimanagerbackup instance")
+		return reconcile.Result{}, err
+	}
+
+	if instance.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	changed, err := instance.SetDefaults()
+	if err != nil {
+		logger.Error(err, "Error")
+		instance.Status.Phase = appsv1alpha1.BackupPhaseFailed
+		instance.Status.Message = err.Error()
+		statusErr := r.Client().Status().Update(context.TODO(), instance)
+		if statusErr != nil {
+			logger.Error(statusErr, "Error updating status")
+		}
+		return reconcile.Result{}, nil
+	}
+	if changed {
+		err = r.Client().Update(context.TODO(), instance)
+		if err != nil {
+			logger.Error(err, "Error updating APIManagerBackup defaults")
+			return reconcile.Result{}, err
+		}
+		logger.Info("Defaults set for APIManagerBackup resource")
+		return reconcile.Result{Requeue: true}, nil
+	}
+
+	originalStatus := instance.Status.DeepCopy()
+	reconciler := operator.NewAPIManagerBackupReconciler(operator.NewBaseLogicReconciler(r.BaseReconciler), instance)
+	result, reconcileErr := reconciler.Reconcile()
+	// The progress is recorded even when a step fails to be reconciled
+	if !reflect.DeepEqual(*originalStatus, instance.Status) {
+		err = r.Client().Status().Update(context.TODO(), instance)
+		if err != nil {
+			logger.Error(err, "Error updating status")
+			return reconcile.Result{}, err
+		}
+	}
+	if reconcileErr != nil {
+		logger.Error(reconcileErr, "Error during reconciliation")
+		return result, reconcileErr
+	}
+
+	return result, nil
+}
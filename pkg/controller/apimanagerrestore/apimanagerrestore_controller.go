@@ -0,0 +1,155 @@
+package apimanagerrestore
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/3scale/3scale-operator/pkg/3scale/amp/operator"
+	appsv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/apps/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+var log = logf.Log.WithName("controller_apimanagerrestore")
+
+// Add creates a new APIManagerRestore Controller and adds it to the Manager. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+	baseReconciler := operator.NewBaseReconciler(mgr.GetClient(), mgr.GetClient(), mgr.GetScheme(), log)
+	return &ReconcileAPIManagerRestore{
+		BaseControllerReconciler: operator.NewBaseControllerReconciler(baseReconciler),
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("apimanagerrestore-controller", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to primary resource APIManagerRestore
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.APIManagerRestore{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the Jobs running the restore steps
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &appsv1alpha1.APIManagerRestore{},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the restored APIManager. The data stores are
+	// restored once it is available
+	err = c.Watch(&source.Kind{Type: &appsv1alpha1.APIManager{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &apimanagerRestoreMapper{client: mgr.GetClient()},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// apimanagerRestoreMapper maps an APIManager to the unfinished restores
+// targeting it
+type apimanagerRestoreMapper struct {
+	client client.Client
+}
+
+func (m *apimanagerRestoreMapper) Map(obj handler.MapObject) []reconcile.Request {
+	restoreList := &appsv1alpha1.APIManagerRestoreList{}
+	err := m.client.List(context.TODO(), &client.ListOptions{Namespace: obj.Meta.GetNamespace()}, restoreList)
+	if err != nil {
+		log.Error(err, "Failed to list APIManagerRestores")
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, restore := range restoreList.Items {
+		if restore.Spec.APIManagerName == obj.Meta.GetName() && !restore.IsFinished() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: restore.Name, Namespace: restore.Namespace},
+			})
+		}
+	}
+	return requests
+}
+
+// blank assignment to verify that ReconcileAPIManagerRestore implements reconcile.Reconciler
+var _ reconcile.Reconciler = &ReconcileAPIManagerRestore{}
+
+// ReconcileAPIManagerRestore reconciles a APIManagerRestore object
+type ReconcileAPIManagerRestore struct {
+	operator.BaseControllerReconciler
+}
+
+// Reconcile reads that state of the cluster for a APIManagerRestore object and makes changes based on the state read
+// and what is in the APIManagerRestore.Spec
+func (r *ReconcileAPIManagerRestore) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	logger := r.Logger().WithValues("namespace", request.Namespace, "name", request.Name)
+	logger.Info("ReconcileAPIManagerRestore")
+
+	instance := &appsv1alpha1.APIManagerRestore{}
+	err := r.Client().Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("resource not found. Ignoring since object must have been deleted")
+			return reconcile.Result{}, nil
+		}
+		logger.Error(err, "Error fetching apimanagerrestore instance")
+		return reconcile.Result{}, err
+	}
+
+	if instance.IsFinished() {
+		return reconcile.Result{}, nil
+	}
+
+	err = instance.Validate()
+	if err != nil {
+		logger.Error(err, "Error")
+		instance.Status.Phase = appsv1alpha1.BackupPhaseFailed
+		instance.Status.Message = err.Error()
+		statusErr := r.Client().Status().Update(context.TODO(), instance)
+		if statusErr != nil {
+			logger.Error(statusErr, "Error updating status")
+		}
+		return reconcile.Result{}, nil
+	}
+
+	originalStatus := instance.Status.DeepCopy()
+	reconciler := operator.NewAPIManagerRestoreReconciler(operator.NewBaseLogicReconciler(r.BaseReconciler), instance)
+	result, reconcileErr := reconciler.Reconcile()
+	// The progress is recorded even when a step fails to be reconciled
+	if !reflect.DeepEqual(*originalStatus, instance.Status) {
+		err = r.Client().Status().Update(context.TODO(), instance)
+		if err != nil {
+			logger.Error(err, "Error updating status")
+			return reconcile.Result{}, err
+		}
+	}
+	if reconcileErr != nil {
+		logger.Error(reconcileErr, "Error during reconciliation")
+		return result, reconcileErr
+	}
+
+	return result, nil
+}
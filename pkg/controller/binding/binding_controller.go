@@ -4,6 +4,7 @@ import (
 	"context"
 	apiv1alpha1 "github.com/3scale/3scale-operator/pkg/apis/capabilities/v1alpha1"
 	"github.com/3scale/3scale-operator/pkg/helper"
+	"github.com/3scale/3scale-operator/pkg/metrics"
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -157,10 +158,18 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		return reconcile.Result{Requeue: true}, err
 	}
 
+	// SyncFailed marks the synchronization with 3scale as failed in the metrics
+	SyncFailed := false
+	syncStart := time.Now()
+	defer func() {
+		observeBindingSync(binding, syncStart, SyncFailed)
+	}()
+
 	// Get the current state in the binding object
 	initialState, err := binding.GetCurrentState()
 	if err != nil {
 		log.Error(err, "Error getting the initial state binding")
+		SyncFailed = true
 		return reconcile.Result{RequeueAfter: 1 * time.Minute, Requeue: true}, err
 	}
 
@@ -168,6 +177,7 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 	currentState, err := binding.NewCurrentState(c)
 	if err != nil {
 		log.Error(err, "Error getting current state from binding status")
+		SyncFailed = true
 		return reconcile.Result{RequeueAfter: 1 * time.Minute, Requeue: true}, err
 
 	}
@@ -199,6 +209,7 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 	if err != nil {
 		log.Error(err, "Error Reconciling APIs")
 	}
+	setManagedObjects(binding, *desiredState)
 
 	// Reconcile the previousState, usually to remove a non existant API
 	previousState, _ := binding.GetPreviousState()
@@ -236,6 +247,7 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		err = apisDiff.ReconcileWith3scale(desiredState.Credentials)
 		if err != nil {
 			log.Error(err, "Error Reconciling APIs")
+			SyncFailed = true
 		}
 
 		// Refresh the current State
@@ -251,6 +263,8 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		if binding.StateInSync() {
 			// Update the LastSync field.
 			binding.SetLastSuccessfulSync()
+		} else {
+			SyncFailed = true
 		}
 		UpdateRequired = true
 
@@ -262,9 +276,39 @@ func ReconcileBindingFunc(binding apiv1alpha1.Binding, c client.Client, log logr
 		err = binding.UpdateStatus(c)
 		if err != nil {
 			log.Error(err, "Failed to update status of binding object")
+			SyncFailed = true
 			return reconcile.Result{Requeue: true}, err
 		}
 	}
 
 	return reconcile.Result{RequeueAfter: 1 * time.Minute, Requeue: true}, nil
 }
+
+// observeBindingSync records the duration and the outcome of the
+// synchronization of the binding started at start
+func observeBindingSync(binding apiv1alpha1.Binding, start time.Time, failed bool) {
+	metrics.BindingSyncDuration.WithLabelValues(binding.Namespace, binding.Name).Observe(time.Since(start).Seconds())
+	if failed {
+		metrics.BindingSyncFailuresTotal.WithLabelValues(binding.Namespace, binding.Name).Inc()
+		return
+	}
+	metrics.BindingLastSuccessfulSync.WithLabelValues(binding.Namespace, binding.Name).SetToCurrentTime()
+}
+
+// setManagedObjects records the number of 3scale objects in the desired
+// state of the binding
+func setManagedObjects(binding apiv1alpha1.Binding, state apiv1alpha1.State) {
+	plans, metricsCount, limits := 0, 0, 0
+	for _, api := range state.APIs {
+		metricsCount += len(api.Metrics)
+		plans += len(api.Plans)
+		for _, plan := range api.Plans {
+			limits += len(plan.Limits)
+		}
+	}
+
+	metrics.ManagedObjects.WithLabelValues(binding.Namespace, binding.Name, "api").Set(float64(len(state.APIs)))
+	metrics.ManagedObjects.WithLabelValues(binding.Namespace, binding.Name, "plan").Set(float64(plans))
+	metrics.ManagedObjects.WithLabelValues(binding.Namespace, binding.Name, "metric").Set(float64(metricsCount))
+	metrics.ManagedObjects.WithLabelValues(binding.Namespace, binding.Name, "limit").Set(float64(limits))
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/3scale/3scale-operator/pkg/common"
+	"github.com/3scale/3scale-operator/pkg/metrics"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -35,7 +36,7 @@ func PortaClient(url *url.URL, masterAccessToken string) (*client.ThreeScaleClie
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
 
-	return client.NewThreeScale(adminPortal, masterAccessToken, &http.Client{Transport: tr}), nil
+	return client.NewThreeScale(adminPortal, masterAccessToken, &http.Client{Transport: metrics.InstrumentedRoundTripper(tr)}), nil
 }
 
 // PortFromURL infers port number if it is not explict
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcile results
+const (
+	ReconcileResultSuccess = "success"
+	ReconcileResultRequeue = "requeue"
+	ReconcileResultError   = "error"
+)
+
+var (
+	// ReconcileDuration observes the duration of the reconciliations of
+	// each component reconciler
+	ReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "threescale_operator_reconcile_duration_seconds",
+			Help: "Duration of the reconciliations by controller and component reconciler",
+		},
+		[]string{"controller", "reconciler"},
+	)
+
+	// ReconcileTotal counts the reconciliations of each component
+	// reconciler by result
+	ReconcileTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "threescale_operator_reconcile_total",
+			Help: "Reconciliations by controller, component reconciler and result",
+		},
+		[]string{"controller", "reconciler", "result"},
+	)
+
+	// BindingSyncDuration observes the duration of the synchronizations of
+	// the capabilities of a Binding with 3scale
+	BindingSyncDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "threescale_operator_binding_sync_duration_seconds",
+			Help: "Duration of the synchronizations of the Binding capabilities with 3scale",
+		},
+		[]string{"namespace", "binding"},
+	)
+
+	// BindingSyncFailuresTotal counts the failed synchronizations of a
+	// Binding
+	BindingSyncFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "threescale_operator_binding_sync_failures_total",
+			Help: "Failed synchronizations of the Binding capabilities with 3scale",
+		},
+		[]string{"namespace", "binding"},
+	)
+
+	// BindingLastSuccessfulSync is the timestamp of the last synchronization
+	// leaving the Binding in sync with 3scale
+	BindingLastSuccessfulSync = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "threescale_operator_binding_last_successful_sync_timestamp_seconds",
+			Help: "Unix time of the last successful synchronization of the Binding capabilities with 3scale",
+		},
+		[]string{"namespace", "binding"},
+	)
+
+	// ManagedObjects is the number of APIs, plans, metrics and limits
+	// desired by a Binding
+	ManagedObjects = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "threescale_operator_managed_objects",
+			Help: "3scale objects under management of the Binding by kind",
+		},
+		[]string{"namespace", "binding", "kind"},
+	)
+
+	// PortaClientRequestsTotal counts the requests of the 3scale API
+	// client
+	PortaClientRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "threescale_operator_porta_client_requests_total",
+			Help: "Requests to the 3scale API by endpoint, method and status code",
+		},
+		[]string{"endpoint", "method", "code"},
+	)
+
+	// PortaClientRequestDuration observes the latency of the requests of
+	// the 3scale API client
+	PortaClientRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "threescale_operator_porta_client_request_duration_seconds",
+			Help: "Latency of the requests to the 3scale API by endpoint and method",
+		},
+		[]string{"endpoint", "method"},
+	)
+)
+
+func init() {
+	// Register custom metrics with the global prometheus registry
+	crmetrics.Registry.MustRegister(
+		ReconcileDuration,
+		ReconcileTotal,
+		BindingSyncDuration,
+		BindingSyncFailuresTotal,
+		BindingLastSuccessfulSync,
+		ManagedObjects,
+		PortaClientRequestsTotal,
+		PortaClientRequestDuration,
+	)
+}
+
+// ObserveReconcile records the duration and the result of a reconciliation
+// started at start
+func ObserveReconcile(controller, reconciler string, start time.Time, result reconcile.Result, err error) {
+	ReconcileDuration.WithLabelValues(controller, reconciler).Observe(time.Since(start).Seconds())
+	ReconcileTotal.WithLabelValues(controller, reconciler, reconcileResult(result, err)).Inc()
+}
+
+func reconcileResult(result reconcile.Result, err error) string {
+	switch {
+	case err != nil:
+		return ReconcileResultError
+	case result.Requeue:
+		return ReconcileResultRequeue
+	default:
+		return ReconcileResultSuccess
+	}
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestObserveReconcile(t *testing.T) {
+	cases := []struct {
+		testName string
+		result   reconcile.Result
+		err      error
+		expected string
+	}{
+		{"Success", reconcile.Result{}, nil, ReconcileResultSuccess},
+		{"RequeueAfter", reconcile.Result{RequeueAfter: time.Minute}, nil, ReconcileResultSuccess},
+		{"Requeue", reconcile.Result{Requeue: true}, nil, ReconcileResultRequeue},
+		{"Error", reconcile.Result{Requeue: true}, errors.New("failed"), ReconcileResultError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.testName, func(subT *testing.T) {
+			reconciler := "test-" + tc.testName
+			ObserveReconcile("apimanager", reconciler, time.Now(), tc.result, tc.err)
+			if count := testutil.ToFloat64(ReconcileTotal.WithLabelValues("apimanager", reconciler, tc.expected)); count != 1 {
+				subT.Errorf("reconcile with result %s not counted: %v", tc.expected, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Path segments holding the ids of the 3scale objects, the ids would make
+// the endpoint label unbounded
+var portaIDSegment = regexp.MustCompile(`/\d+(/|\.json$|\.xml$|$)`)
+
+// PortaEndpoint returns the path of a 3scale API request with the object ids
+// replaced by ":id"
+func PortaEndpoint(path string) string {
+	// Consecutive ids share a slash, two passes replace all of them
+	for i := 0; i < 2; i++ {
+		path = portaIDSegment.ReplaceAllString(path, "/:id$1")
+	}
+	return path
+}
+
+type instrumentedRoundTripper struct {
+	next http.RoundTripper
+}
+
+// InstrumentedRoundTripper returns a RoundTripper recording the count and
+// the latency of the requests sent through next
+func InstrumentedRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &instrumentedRoundTripper{next: next}
+}
+
+func (t *instrumentedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := PortaEndpoint(req.URL.Path)
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	PortaClientRequestDuration.WithLabelValues(endpoint, req.Method).Observe(time.Since(start).Seconds())
+
+	// Connection errors have no status code
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	PortaClientRequestsTotal.WithLabelValues(endpoint, req.Method, code).Inc()
+
+	return resp, err
+}
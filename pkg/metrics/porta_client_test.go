@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPortaEndpoint(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/admin/api/services.json", "/admin/api/services.json"},
+		{"/admin/api/services/12.json", "/admin/api/services/:id.json"},
+		{"/admin/api/services/12/metrics/34.xml", "/admin/api/services/:id/metrics/:id.xml"},
+		{"/admin/api/application_plans/5/metrics/6/limits.json", "/admin/api/application_plans/:id/metrics/:id/limits.json"},
+		{"/master/api/providers/7", "/master/api/providers/:id"},
+		{"/admin/api/accounts/1/2/3.json", "/admin/api/accounts/:id/:id/:id.json"},
+		{"/admin/api/v2/services.json", "/admin/api/v2/services.json"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(subT *testing.T) {
+			if endpoint := PortaEndpoint(tc.path); endpoint != tc.expected {
+				subT.Errorf("expected: %s, got: %s", tc.expected, endpoint)
+			}
+		})
+	}
+}
+
+func TestInstrumentedRoundTripper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin/api/services/1.json" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: InstrumentedRoundTripper(http.DefaultTransport)}
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL + "/admin/api/services.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	resp, err := client.Get(server.URL + "/admin/api/services/1.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if count := testutil.ToFloat64(PortaClientRequestsTotal.WithLabelValues("/admin/api/services.json", "GET", "200")); count != 2 {
+		t.Errorf("unexpected requests count: %v", count)
+	}
+	if count := testutil.ToFloat64(PortaClientRequestsTotal.WithLabelValues("/admin/api/services/:id.json", "GET", "404")); count != 1 {
+		t.Errorf("unexpected not found requests count: %v", count)
+	}
+
+	server.Close()
+	_, err = client.Get(server.URL + "/admin/api/services.json")
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+	if count := testutil.ToFloat64(PortaClientRequestsTotal.WithLabelValues("/admin/api/services.json", "GET", "error")); count != 1 {
+		t.Errorf("unexpected failed requests count: %v", count)
+	}
+}